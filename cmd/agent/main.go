@@ -16,7 +16,7 @@ var (
 func main() {
 	util.PrintBuildInfo(buildVersion, buildDate, buildCommit)
 
-	if err := app.StartAgent(); err != nil {
+	if err := app.StartAgent(util.NewBuildInfo(buildVersion, buildDate, buildCommit)); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,269 @@
+// Команда keygen генерирует ключевой материал для агента и сервера метрик:
+// пары ключей RSA и Ed25519, HMAC секреты и сертификаты для mTLS.
+// После генерации печатает фрагменты configs/agent.json и configs/server.json.
+// Существующие файлы не перезаписываются без флага -force.
+//
+// Использование:
+//
+//	keygen rsa [-out dir] [-name name] [-bits 4096] [-id key-id] [-force]
+//	keygen ed25519 [-out dir] [-name name] [-agent-id id] [-force]
+//	keygen hmac [-out dir] [-name name] [-bytes 32] [-id key-id] [-force]
+//	keygen certs [-out dir] [-hosts localhost,127.0.0.1] [-client metrics-agent] [-days 365] [-force]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+	"github.com/NoobyTheTurtle/metrics/internal/keyring"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+)
+
+const usage = "usage: keygen <rsa|ed25519|hmac|certs> [flags]"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	command, args := args[0], args[1:]
+
+	var err error
+	switch command {
+	case "rsa":
+		err = generateRSA(args, out)
+	case "ed25519":
+		err = generateEd25519(args, out)
+	case "hmac":
+		err = generateHMAC(args, out)
+	case "certs":
+		err = generateCerts(args, out)
+	default:
+		return fmt.Errorf("unknown command: %s\n%s", command, usage)
+	}
+
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w; use -force to overwrite", err)
+	}
+	return err
+}
+
+func fileOptions(force bool) []cryptoutil.FileOption {
+	if force {
+		return []cryptoutil.FileOption{cryptoutil.WithOverwrite()}
+	}
+	return nil
+}
+
+func generateRSA(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("rsa", flag.ContinueOnError)
+	dir := fs.String("out", "keys", "Output directory")
+	name := fs.String("name", "rsa", "Base name of key files")
+	bits := fs.Int("bits", 4096, "RSA key size in bits")
+	id := fs.String("id", "", "Key ID for rotation via keys file")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *bits < 2048 {
+		return fmt.Errorf("rsa key size must be at least 2048 bits, got %d", *bits)
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	privateKeyPath := filepath.Join(*dir, *name+".pem")
+	publicKeyPath := filepath.Join(*dir, *name+".pub")
+
+	if err := cryptoutil.GenerateKeyPair(privateKeyPath, publicKeyPath, *bits, fileOptions(*force)...); err != nil {
+		return err
+	}
+
+	printFiles(out, privateKeyPath, publicKeyPath)
+
+	agent := map[string]any{"crypto_key": publicKeyPath}
+	if *id == "" {
+		printSnippet(out, "configs/agent.json", agent)
+		printSnippet(out, "configs/server.json", map[string]any{"crypto_key": privateKeyPath})
+		return nil
+	}
+
+	agent["crypto_key_id"] = *id
+	printSnippet(out, "configs/agent.json", agent)
+	printSnippet(out, "keys file (keys_file in configs/server.json)", keyring.Config{
+		CryptoKeys: []keyring.CryptoKey{{ID: *id, PrivateKey: privateKeyPath}},
+	})
+
+	return nil
+}
+
+func generateEd25519(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("ed25519", flag.ContinueOnError)
+	dir := fs.String("out", "keys", "Output directory")
+	name := fs.String("name", "ed25519", "Base name of key files")
+	agentID := fs.String("agent-id", "", "Agent ID for request signing, defaults to key name")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	privateKeyPath := filepath.Join(*dir, *name+".pem")
+	publicKeyPath := filepath.Join(*dir, *name+".pub")
+
+	if err := cryptoutil.GenerateEd25519KeyPair(privateKeyPath, publicKeyPath, fileOptions(*force)...); err != nil {
+		return err
+	}
+
+	if *agentID == "" {
+		*agentID = *name
+	}
+
+	printFiles(out, privateKeyPath, publicKeyPath)
+	printSnippet(out, "configs/agent.json", map[string]any{
+		"agent_id":    *agentID,
+		"signing_key": privateKeyPath,
+	})
+	printSnippet(out, "agent keys file (agent_keys_file in configs/server.json)", map[string]any{
+		"agents": []signing.Agent{{ID: *agentID, PublicKey: publicKeyPath}},
+	})
+
+	return nil
+}
+
+func generateHMAC(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("hmac", flag.ContinueOnError)
+	dir := fs.String("out", "keys", "Output directory")
+	name := fs.String("name", "hmac", "Base name of secret file")
+	size := fs.Int("bytes", 32, "Secret size in bytes")
+	id := fs.String("id", "", "Key ID for rotation via keys file")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	secret, err := cryptoutil.GenerateHMACSecret(*size)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	secretPath := filepath.Join(*dir, *name+".secret")
+	if err := cryptoutil.WriteSecretFile(secretPath, secret, fileOptions(*force)...); err != nil {
+		return err
+	}
+
+	printFiles(out, secretPath)
+
+	agent := map[string]any{"key": secret}
+	if *id == "" {
+		printSnippet(out, "configs/agent.json", agent)
+		printSnippet(out, "configs/server.json", map[string]any{"key": secret})
+		return nil
+	}
+
+	agent["key_id"] = *id
+	printSnippet(out, "configs/agent.json", agent)
+	printSnippet(out, "keys file (keys_file in configs/server.json)", keyring.Config{
+		HMACKeys: []keyring.HMACKey{{ID: *id, Secret: secret}},
+	})
+
+	return nil
+}
+
+func generateCerts(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("certs", flag.ContinueOnError)
+	dir := fs.String("out", "keys", "Output directory")
+	hosts := fs.String("hosts", "localhost,127.0.0.1", "Comma separated DNS names and IP addresses of the server")
+	client := fs.String("client", "metrics-agent", "Common name of the client certificate")
+	days := fs.Int("days", 365, "Certificate validity in days")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var hostList []string
+	for _, h := range strings.Split(*hosts, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hostList = append(hostList, h)
+		}
+	}
+
+	err := cryptoutil.GenerateCertificates(*dir, cryptoutil.CertificateOptions{
+		Hosts:      hostList,
+		ClientName: *client,
+		ValidFor:   time.Duration(*days) * 24 * time.Hour,
+		Overwrite:  *force,
+	})
+	if err != nil {
+		return err
+	}
+
+	caCert := filepath.Join(*dir, cryptoutil.CACertFile)
+	serverCert := filepath.Join(*dir, cryptoutil.ServerCertFile)
+	serverKey := filepath.Join(*dir, cryptoutil.ServerKeyFile)
+	clientCert := filepath.Join(*dir, cryptoutil.ClientCertFile)
+	clientKey := filepath.Join(*dir, cryptoutil.ClientKeyFile)
+
+	printFiles(out, caCert, filepath.Join(*dir, cryptoutil.CAKeyFile), serverCert, serverKey, clientCert, clientKey)
+	printSnippet(out, "configs/agent.json", map[string]any{
+		"tls_ca":   caCert,
+		"tls_cert": clientCert,
+		"tls_key":  clientKey,
+	})
+	printSnippet(out, "configs/server.json", map[string]any{
+		"tls_cert":      serverCert,
+		"tls_key":       serverKey,
+		"tls_client_ca": caCert,
+	})
+
+	return nil
+}
+
+func printFiles(out io.Writer, paths ...string) {
+	fmt.Fprintln(out, "Generated files:")
+	for _, path := range paths {
+		mode := "?"
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm().String()
+		}
+		fmt.Fprintf(out, "  %s %s\n", mode, path)
+	}
+}
+
+func printSnippet(out io.Writer, title string, v any) {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		fmt.Fprintf(out, "\nfailed to render %s snippet: %v\n", title, err)
+		return
+	}
+
+	fmt.Fprintf(out, "\n%s:\n%s\n", title, data)
+}
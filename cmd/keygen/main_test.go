@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Commands(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		files    []string
+		snippets []string
+	}{
+		{
+			name:     "rsa",
+			args:     []string{"rsa", "-bits", "2048"},
+			files:    []string{"rsa.pem", "rsa.pub"},
+			snippets: []string{"configs/agent.json", "configs/server.json", `"crypto_key"`},
+		},
+		{
+			name:     "rsa with key id",
+			args:     []string{"rsa", "-bits", "2048", "-id", "rsa-1"},
+			files:    []string{"rsa.pem", "rsa.pub"},
+			snippets: []string{`"crypto_key_id": "rsa-1"`, "keys file", `"crypto_keys"`},
+		},
+		{
+			name:     "ed25519",
+			args:     []string{"ed25519", "-name", "agent", "-agent-id", "agent-1"},
+			files:    []string{"agent.pem", "agent.pub"},
+			snippets: []string{`"agent_id": "agent-1"`, `"signing_key"`, "agent keys file"},
+		},
+		{
+			name:     "hmac",
+			args:     []string{"hmac", "-id", "hmac-1"},
+			files:    []string{"hmac.secret"},
+			snippets: []string{`"key_id": "hmac-1"`, `"hmac_keys"`},
+		},
+		{
+			name: "certs",
+			args: []string{"certs", "-hosts", "localhost"},
+			files: []string{
+				cryptoutil.CACertFile, cryptoutil.CAKeyFile,
+				cryptoutil.ServerCertFile, cryptoutil.ServerKeyFile,
+				cryptoutil.ClientCertFile, cryptoutil.ClientKeyFile,
+			},
+			snippets: []string{`"tls_ca"`, `"tls_cert"`, `"tls_key"`, `"tls_client_ca"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var out bytes.Buffer
+
+			require.NoError(t, run(append(tt.args, "-out", dir), &out))
+
+			for _, name := range tt.files {
+				assert.FileExists(t, filepath.Join(dir, name))
+				assert.Contains(t, out.String(), filepath.Join(dir, name))
+			}
+			for _, snippet := range tt.snippets {
+				assert.Contains(t, out.String(), snippet)
+			}
+		})
+	}
+}
+
+func TestRun_ExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	args := []string{"ed25519", "-out", dir, "-name", "agent"}
+
+	require.NoError(t, run(args, &bytes.Buffer{}))
+	before, err := os.ReadFile(filepath.Join(dir, "agent.pem"))
+	require.NoError(t, err)
+
+	err = run(args, &bytes.Buffer{})
+	assert.ErrorIs(t, err, os.ErrExist)
+	assert.ErrorContains(t, err, "use -force to overwrite")
+
+	after, err := os.ReadFile(filepath.Join(dir, "agent.pem"))
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "existing key must not be overwritten")
+
+	require.NoError(t, run(append(args, "-force"), &bytes.Buffer{}))
+
+	_, err = signing.LoadSigner(filepath.Join(dir, "agent.pem"))
+	assert.NoError(t, err)
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{name: "no command", args: nil, expectedErr: "usage: keygen"},
+		{name: "unknown command", args: []string{"dsa"}, expectedErr: "unknown command: dsa"},
+		{name: "weak rsa key", args: []string{"rsa", "-bits", "1024"}, expectedErr: "at least 2048 bits"},
+		{name: "empty hmac secret", args: []string{"hmac", "-bytes", "0"}, expectedErr: "size must be positive"},
+		{name: "no hosts", args: []string{"certs", "-hosts", " , "}, expectedErr: "at least one server host is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if len(args) > 0 {
+				args = append(args, "-out", t.TempDir())
+			}
+
+			err := run(args, &bytes.Buffer{})
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
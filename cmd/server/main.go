@@ -19,7 +19,7 @@ func main() {
 
 	ctx := context.Background()
 
-	if err := app.StartServer(ctx); err != nil {
+	if err := app.StartServer(ctx, util.NewBuildInfo(buildVersion, buildDate, buildCommit)); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/config"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/admin"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/util"
+)
+
+// adminServer - служебный HTTP интерфейс на отдельном адресе.
+type adminServer struct {
+	server *http.Server
+	log    *logger.ZapLogger
+}
+
+// startAdmin запускает служебный интерфейс, если задан его адрес.
+// cfg возвращает действующую конфигурацию, секреты в ответе маскируются.
+func startAdmin(addr, token string, log *logger.ZapLogger, cfg func() any, buildInfo util.BuildInfo) *adminServer {
+	if addr == "" {
+		return nil
+	}
+
+	configSource := func() map[string]any {
+		return config.Redact(cfg())
+	}
+	h := admin.NewHandler(log, configSource, buildInfo, admin.WithToken(token))
+
+	a := &adminServer{
+		server: &http.Server{
+			Addr:    addr,
+			Handler: h.Routes(),
+		},
+		log: log,
+	}
+
+	go func() {
+		log.Info("Starting admin listener on %s", addr)
+		if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Admin listener error: %v", err)
+		}
+	}()
+
+	return a
+}
+
+// Shutdown останавливает служебный интерфейс. Безопасен для nil.
+func (a *adminServer) Shutdown(ctx context.Context) {
+	if a == nil {
+		return
+	}
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		a.log.Error("Error during admin listener shutdown: %v", err)
+	}
+}
@@ -14,11 +14,14 @@ import (
 	"github.com/NoobyTheTurtle/metrics/internal/logger"
 	"github.com/NoobyTheTurtle/metrics/internal/metric"
 	"github.com/NoobyTheTurtle/metrics/internal/reporter"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
+	"github.com/NoobyTheTurtle/metrics/internal/util"
 )
 
 const gracefulShutdownTimeout = 30 * time.Second
 
-func StartAgent() error {
+func StartAgent(buildInfo util.BuildInfo) error {
 	c, err := config.NewAgentConfig()
 	if err != nil {
 		return err
@@ -32,34 +35,115 @@ func StartAgent() error {
 	}
 	defer l.Sync()
 
-	var encrypter metric.Encrypter
-	if c.CryptoKey != "" {
-		encrypter, err = cryptoutil.NewPublicKeyProvider(c.CryptoKey)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "metrics-agent",
+		Exporter:    c.TraceExporter,
+		Endpoint:    c.TraceEndpoint,
+		FilePath:    c.TraceFile,
+		SampleRatio: c.TraceSampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("app.StartAgent: failed to set up tracing: %w", err)
+	}
+
+	encrypter, err := newEncrypter(c.CryptoKey)
+	if err != nil {
+		return fmt.Errorf("app.StartAgent: failed to create encrypter: %w", err)
+	}
+
+	metricOpts := []metric.Option{
+		metric.WithAPIKey(c.APIKey),
+		metric.WithAuthToken(c.AuthToken),
+		metric.WithKeyID(c.KeyID),
+		metric.WithCryptoKeyID(c.CryptoKeyID),
+	}
+	if c.SignedHash {
+		metricOpts = append(metricOpts, metric.WithSignedHash())
+	}
+	if c.SigningKey != "" {
+		if c.AgentID == "" {
+			return fmt.Errorf("app.StartAgent: agent id is required for request signing")
+		}
+		signer, err := signing.LoadSigner(c.SigningKey)
 		if err != nil {
-			return fmt.Errorf("app.StartAgent: failed to create encrypter: %w", err)
+			return fmt.Errorf("app.StartAgent: failed to load signing key: %w", err)
 		}
+		metricOpts = append(metricOpts, metric.WithSigner(c.AgentID, signer))
 	}
 
-	metrics := metric.NewMetrics(c.ServerAddress, l, !isDev, c.Key, encrypter)
+	// Настройки TLS включают https и в режиме разработки.
+	useTLS := !isDev
+	if c.TLSCA != "" || c.TLSCert != "" {
+		tlsConfig, err := cryptoutil.ClientTLSConfig(c.TLSCA, c.TLSCert, c.TLSKey)
+		if err != nil {
+			return fmt.Errorf("app.StartAgent: failed to configure TLS: %w", err)
+		}
+		metricOpts = append(metricOpts, metric.WithTLSConfig(tlsConfig))
+		useTLS = true
+	}
 
-	metricCollector := collector.NewCollector(metrics, l, c.PollInterval)
-	gopsutilCollector := collector.NewGopsutilCollector(metrics, l, c.PollInterval)
-	metricReporter := reporter.NewReporter(metrics, l, c.ReportInterval, c.RateLimit)
+	metrics := metric.NewMetrics(c.ServerAddress, l, useTLS, c.Key, encrypter, metricOpts...)
+
+	collectors, err := collector.NewDefaultRegistry().Build(c.Collectors)
+	if err != nil {
+		return fmt.Errorf("app.StartAgent: failed to create collectors: %w", err)
+	}
+	collectorRunner := collector.NewRunner(metrics, l, c.PollInterval.Duration(), collectors...)
+	metricReporter := reporter.NewReporter(metrics, l, c.ReportInterval.Duration(), c.RateLimit)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer cancel()
 
-	var wg sync.WaitGroup
-	wg.Add(3)
+	agentConfig := &configReloader[config.AgentConfig]{
+		current: c,
+		load:    config.NewAgentConfig,
+		live: []string{
+			"PollInterval", "ReportInterval", "RateLimit", "LogLevel",
+			"Key", "KeyID", "CryptoKey", "CryptoKeyID",
+		},
+		// Сначала готовится все, что может завершиться ошибкой, и только затем
+		// изменения применяются вместе: при ошибке агент продолжает работать
+		// со старой конфигурацией целиком, а не с ее частью.
+		apply: func(old, next *config.AgentConfig) error {
+			cryptoChanged := next.CryptoKey != old.CryptoKey || next.CryptoKeyID != old.CryptoKeyID
+			var encrypter metric.Encrypter
+			if cryptoChanged {
+				var err error
+				encrypter, err = newEncrypter(next.CryptoKey)
+				if err != nil {
+					return err
+				}
+			}
+			// уровень меняется первым: если он не подошел, остальное еще не применено
+			if err := l.SetLevel(next.LogLevel); err != nil {
+				return err
+			}
+
+			if cryptoChanged {
+				metrics.SetEncrypter(encrypter, next.CryptoKeyID)
+			}
+			metrics.SetHashKey(next.Key, next.KeyID)
+			if next.PollInterval != old.PollInterval {
+				collectorRunner.SetPollInterval(next.PollInterval.Duration())
+			}
+			if next.ReportInterval != old.ReportInterval {
+				metricReporter.SetReportInterval(next.ReportInterval.Duration())
+			}
+			if next.RateLimit != old.RateLimit {
+				metricReporter.SetRateLimit(next.RateLimit)
+			}
+			return nil
+		},
+		log: l,
+	}
+	go reloadOnSIGHUP(ctx, l, agentConfig)
 
-	go func() {
-		defer wg.Done()
-		metricCollector.RunWithContext(ctx)
-	}()
+	var wg sync.WaitGroup
+	wg.Add(2)
 
 	go func() {
 		defer wg.Done()
-		gopsutilCollector.RunWithContext(ctx)
+		collectorRunner.RunWithContext(ctx)
 	}()
 
 	go func() {
@@ -67,6 +151,8 @@ func StartAgent() error {
 		metricReporter.RunWithContext(ctx)
 	}()
 
+	adminSrv := startAdmin(c.AdminAddress, c.AdminToken, l, func() any { return agentConfig.Config() }, buildInfo)
+
 	l.Info("Starting agent...")
 
 	<-ctx.Done()
@@ -88,5 +174,20 @@ func StartAgent() error {
 		l.Info("Graceful shutdown timed out, forcing exit")
 	}
 
+	adminSrv.Shutdown(shutdownCtx)
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		l.Error("Failed to flush traces: %v", err)
+	}
+
 	return nil
 }
+
+// newEncrypter загружает публичный ключ шифрования. Пустой путь отключает шифрование.
+func newEncrypter(path string) (metric.Encrypter, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	return cryptoutil.NewPublicKeyProvider(path)
+}
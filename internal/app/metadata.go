@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/storage/adapter"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+// metadataFile объявляет метаданные метрик из файла настроек
+// и повторяет объявления при перечитывании по SIGHUP.
+type metadataFile struct {
+	ctx     context.Context
+	path    string
+	storage *adapter.MetricStorage
+}
+
+// Reload перечитывает файл метаданных и объявляет метрики заново.
+// Объявления, конфликтующие с уже закрепленными типами, отклоняются.
+func (f *metadataFile) Reload() error {
+	decls, err := metadata.LoadDeclarations(f.path)
+	if err != nil {
+		return fmt.Errorf("app.metadataFile.Reload: %w", err)
+	}
+
+	for _, d := range decls {
+		ctx := tenant.WithID(f.ctx, d.Tenant)
+		if _, err := f.storage.DeclareMetadata(ctx, d.Metadata()); err != nil {
+			return fmt.Errorf("app.metadataFile.Reload: %w", err)
+		}
+	}
+
+	return nil
+}
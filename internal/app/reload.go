@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
+	"github.com/NoobyTheTurtle/metrics/internal/config"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+var errRestartRequired = errors.New("changes require restart")
+
+// reloader перечитывает настройки из файлов.
+type reloader interface {
+	Reload() error
+}
+
+var (
+	_ reloader = (*tenant.Registry)(nil)
+	_ reloader = (*auth.Authenticator)(nil)
+	_ reloader = (*signing.Registry)(nil)
+	_ reloader = (*configReloader[config.ServerConfig])(nil)
+)
+
+// reloadOnSIGHUP перечитывает конфигурацию и ключи при получении сигнала SIGHUP.
+func reloadOnSIGHUP(ctx context.Context, log *logger.ZapLogger, reloaders ...reloader) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	reloadOnSignal(ctx, log, hup, reloaders...)
+}
+
+// reloadOnSignal вызывает все reloaders на каждый сигнал из канала signals.
+func reloadOnSignal(ctx context.Context, log *logger.ZapLogger, signals <-chan os.Signal, reloaders ...reloader) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			log.Info("Received SIGHUP, reloading configuration")
+			for _, r := range reloaders {
+				if err := r.Reload(); err != nil {
+					log.Error("Reload failed, keeping previous settings: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// configReloader перечитывает конфигурацию и применяет изменения полей из live
+// без перезапуска. Если изменились другие поля, например адрес или тип хранилища,
+// новая конфигурация отклоняется целиком, чтобы процесс не работал
+// с частично примененными настройками.
+type configReloader[T any] struct {
+	mu      sync.RWMutex
+	current *T
+
+	load  func() (*T, error)
+	live  []string
+	apply func(old, next *T) error
+	log   *logger.ZapLogger
+}
+
+// Reload перечитывает конфигурацию и применяет изменения.
+func (r *configReloader[T]) Reload() error {
+	next, err := r.load()
+	if err != nil {
+		return fmt.Errorf("app.configReloader.Reload: %w", err)
+	}
+
+	current := r.Config()
+	changed := config.Changed(current, next)
+
+	var restart []string
+	for _, field := range changed {
+		if !slices.Contains(r.live, field) {
+			restart = append(restart, field)
+		}
+	}
+	if len(restart) > 0 {
+		return fmt.Errorf("app.configReloader.Reload: %w: %s", errRestartRequired, strings.Join(restart, ", "))
+	}
+
+	if len(changed) == 0 {
+		r.log.Info("Configuration is unchanged")
+		return nil
+	}
+
+	if err := r.apply(current, next); err != nil {
+		return fmt.Errorf("app.configReloader.Reload: %w", err)
+	}
+
+	r.mu.Lock()
+	r.current = next
+	r.mu.Unlock()
+
+	r.log.Info("Configuration reloaded, changed fields: %s", strings.Join(changed, ", "))
+	return nil
+}
+
+// Config возвращает действующую конфигурацию.
+func (r *configReloader[T]) Config() *T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current
+}
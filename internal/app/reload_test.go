@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
+	"github.com/NoobyTheTurtle/metrics/internal/config"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadOnSignal_TenantsAndAuth(t *testing.T) {
+	dir := t.TempDir()
+	tenantsPath := filepath.Join(dir, "tenants.json")
+	authPath := filepath.Join(dir, "auth.json")
+	require.NoError(t, os.WriteFile(tenantsPath, []byte(`{"tenants":[{"id":"team-a","api_keys":["key-a"]}]}`), 0o600))
+	require.NoError(t, os.WriteFile(authPath, []byte(`{"tokens":[{"token":"old","scopes":["metrics:read"]}]}`), 0o600))
+
+	tenants, err := tenant.LoadRegistry(tenantsPath)
+	require.NoError(t, err)
+	authenticator, err := auth.LoadAuthenticator(authPath)
+	require.NoError(t, err)
+	log, err := logger.NewZapLogger("error", false)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	go reloadOnSignal(ctx, log, signals, tenants, authenticator)
+
+	require.NoError(t, os.WriteFile(tenantsPath, []byte(`{"tenants":[{"id":"team-b","api_keys":["key-b"]}]}`), 0o600))
+	require.NoError(t, os.WriteFile(authPath, []byte(`{"tokens":[{"token":"new","scopes":["metrics:write"]}]}`), 0o600))
+	signals <- syscall.SIGHUP
+
+	assert.Eventually(t, func() bool {
+		_, tenantReloaded := tenants.Lookup("key-b")
+		_, authErr := authenticator.Authenticate("new")
+		return tenantReloaded && authErr == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, ok := tenants.Lookup("key-a")
+	assert.False(t, ok)
+	_, err = authenticator.Authenticate("old")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestConfigReloader_KeysFileRequiresRestart(t *testing.T) {
+	log, err := logger.NewZapLogger("error", false)
+	require.NoError(t, err)
+
+	current := &config.ServerConfig{LogLevel: "info", KeysFile: "keys.json", Key: "old"}
+	next := &config.ServerConfig{LogLevel: "debug", KeysFile: "keys.json", Key: "new"}
+	applied := false
+	reloader := &configReloader[config.ServerConfig]{
+		current: current,
+		load:    func() (*config.ServerConfig, error) { return next, nil },
+		live:    serverLiveFields(current),
+		apply: func(old, next *config.ServerConfig) error {
+			applied = true
+			return nil
+		},
+		log: log,
+	}
+
+	err = reloader.Reload()
+	assert.ErrorIs(t, err, errRestartRequired)
+	assert.ErrorContains(t, err, "Key")
+	assert.False(t, applied)
+	assert.Equal(t, "old", reloader.Config().Key)
+
+	next.Key = "old"
+	require.NoError(t, reloader.Reload())
+	assert.True(t, applied)
+	assert.Equal(t, "debug", reloader.Config().LogLevel)
+}
+
+func TestServerLiveFields(t *testing.T) {
+	assert.Contains(t, serverLiveFields(&config.ServerConfig{}), "Key")
+	assert.Contains(t, serverLiveFields(&config.ServerConfig{}), "CryptoKey")
+	assert.NotContains(t, serverLiveFields(&config.ServerConfig{KeysFile: "keys.json"}), "Key")
+	assert.NotContains(t, serverLiveFields(&config.ServerConfig{KeysFile: "keys.json"}), "CryptoKey")
+}
@@ -9,18 +9,31 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
 	"github.com/NoobyTheTurtle/metrics/internal/config"
 	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
 	"github.com/NoobyTheTurtle/metrics/internal/database/postgres"
 	"github.com/NoobyTheTurtle/metrics/internal/handler"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/middleware"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/openapi"
+	"github.com/NoobyTheTurtle/metrics/internal/keyring"
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
 	"github.com/NoobyTheTurtle/metrics/internal/logger"
 	"github.com/NoobyTheTurtle/metrics/internal/persister"
+	"github.com/NoobyTheTurtle/metrics/internal/replay"
+	"github.com/NoobyTheTurtle/metrics/internal/retry"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
 	"github.com/NoobyTheTurtle/metrics/internal/storage"
 	"github.com/NoobyTheTurtle/metrics/internal/storage/adapter"
+	"github.com/NoobyTheTurtle/metrics/internal/subnet"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
+	"github.com/NoobyTheTurtle/metrics/internal/util"
 	"github.com/jmoiron/sqlx"
 )
 
-func StartServer(ctx context.Context) error {
+func StartServer(ctx context.Context, buildInfo util.BuildInfo) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
 	defer cancel()
 
@@ -37,36 +50,197 @@ func StartServer(ctx context.Context) error {
 	}
 	defer log.Sync()
 
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName: "metrics-server",
+		Exporter:    c.TraceExporter,
+		Endpoint:    c.TraceEndpoint,
+		FilePath:    c.TraceFile,
+		SampleRatio: c.TraceSampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("app.StartServer: failed to set up tracing: %w", err)
+	}
+
 	dbClient, err := postgres.NewClient(ctx, c.DatabaseDSN)
 	if err != nil {
 		return fmt.Errorf("app.StartServer: failed to connect to database (DSN: '%s'): %w", c.DatabaseDSN, err)
 	}
 	defer dbClient.Close()
 
-	metricStorage, persisterDone, err := initMetricStorage(ctx, c, dbClient.DB, log)
+	metrics := selfmetrics.NewRegistry()
+	retry.SetRetryCounter(metrics.Counter("retry.attempts"))
+
+	metricStorage, persisterDone, err := initMetricStorage(ctx, c, dbClient.DB, log, metrics)
 	if err != nil {
 		return fmt.Errorf("app.StartServer: failed to create metric storage: %w", err)
 	}
 
-	var decrypter handler.Decrypter
-	if c.CryptoKey != "" {
-		decrypter, err = cryptoutil.NewPrivateKeyProvider(c.CryptoKey)
+	if c.MaxMetricNames > 0 || c.MaxMetricNamesPerTenant > 0 || c.MaxMetricNamesPerAgent > 0 {
+		cardinality := limits.NewCardinality(limits.CardinalityConfig{
+			MaxNames:          int(c.MaxMetricNames),
+			MaxNamesPerTenant: int(c.MaxMetricNamesPerTenant),
+			MaxNamesPerAgent:  int(c.MaxMetricNamesPerAgent),
+		}, metrics)
+		if err := metricStorage.LimitCardinality(ctx, cardinality); err != nil {
+			return fmt.Errorf("app.StartServer: failed to count stored metric names: %w", err)
+		}
+	}
+
+	maxSkew := middleware.DefaultMaxClockSkew
+	if c.HashMaxSkew > 0 {
+		maxSkew = time.Duration(c.HashMaxSkew) * time.Second
+	}
+	hashOpts := []middleware.HashOption{
+		// nonce достаточно помнить в пределах окна допустимых меток времени в обе стороны
+		middleware.WithReplayProtection(maxSkew, replay.NewCache(int(c.NonceCacheSize), 2*maxSkew)),
+	}
+	if c.HashStrict {
+		hashOpts = append(hashOpts, middleware.WithStrictHash())
+	}
+
+	// Ключи из конфигурации хранятся в keyring.Single, чтобы их можно было заменить по SIGHUP.
+	// Файл ключей имеет приоритет над ключами из конфигурации, поэтому он обязан
+	// содержать ключи каждого вида, включенного в конфигурации, а изменение
+	// ключей в конфигурации при заданном файле ключей требует перезапуска.
+	var (
+		decrypter  handler.Decrypter
+		configKeys *keyring.Single
+		reloaders  []reloader
+	)
+	if c.KeysFile != "" {
+		var keyOpts []keyring.Option
+		if c.Key != "" {
+			keyOpts = append(keyOpts, keyring.RequireHashKeys())
+		}
+		if c.CryptoKey != "" {
+			keyOpts = append(keyOpts, keyring.RequireCryptoKeys())
+		}
+
+		keys, err := keyring.Load(c.KeysFile, keyOpts...)
+		if err != nil {
+			return fmt.Errorf("app.StartServer: failed to load keys: %w", err)
+		}
+		decrypter = keys
+		hashOpts = append(hashOpts, middleware.WithHashKeys(keys))
+		reloaders = append(reloaders, keys)
+	} else {
+		configKeys, err = keyring.NewSingle(c.Key, c.CryptoKey)
 		if err != nil {
 			return fmt.Errorf("app.StartServer: failed to create decrypter: %w", err)
 		}
+		decrypter = configKeys
+		hashOpts = append(hashOpts, middleware.WithHashKeys(configKeys))
+	}
+
+	routerOpts := []handler.RouterOption{
+		handler.WithHashOptions(hashOpts...),
+		handler.WithSelfMetrics(metrics),
+		handler.WithBodyLimit(int64(c.MaxBodySize)),
+		handler.WithMaxBatchSize(int(c.MaxBatchSize)),
+	}
+	if c.RateLimit > 0 {
+		routerOpts = append(routerOpts, handler.WithRateLimit(limits.NewRateLimiter(c.RateLimit, int(c.RateBurst), metrics)))
+	}
+	if c.AgentKeysFile != "" {
+		agents, err := signing.LoadRegistry(c.AgentKeysFile)
+		if err != nil {
+			return fmt.Errorf("app.StartServer: failed to load agent keys: %w", err)
+		}
+		signatureOpts := []middleware.SignatureOption{
+			middleware.WithSignatureReplayProtection(maxSkew, replay.NewCache(int(c.NonceCacheSize), 2*maxSkew)),
+		}
+		if c.SignatureRequired {
+			signatureOpts = append(signatureOpts, middleware.WithRequiredSignature())
+		}
+		routerOpts = append(routerOpts, handler.WithSignatureVerification(agents, signatureOpts...))
+		reloaders = append(reloaders, agents)
+	} else if c.SignatureRequired {
+		return fmt.Errorf("app.StartServer: agent keys file is required when signatures are required")
 	}
 
-	router := handler.NewRouter(metricStorage, log, dbClient, c.Key, decrypter)
+	if c.MetadataFile != "" {
+		declarations := &metadataFile{ctx: ctx, path: c.MetadataFile, storage: metricStorage}
+		if err := declarations.Reload(); err != nil {
+			return fmt.Errorf("app.StartServer: failed to load metric metadata: %w", err)
+		}
+		reloaders = append(reloaders, declarations)
+	}
+
+	if c.TenantsFile != "" {
+		tenants, err := tenant.LoadRegistry(c.TenantsFile)
+		if err != nil {
+			return fmt.Errorf("app.StartServer: failed to load tenants: %w", err)
+		}
+		routerOpts = append(routerOpts, handler.WithTenants(tenants))
+		reloaders = append(reloaders, tenants)
+	}
+	if c.AuthFile != "" {
+		authenticator, err := auth.LoadAuthenticator(c.AuthFile)
+		if err != nil {
+			return fmt.Errorf("app.StartServer: failed to load auth config: %w", err)
+		}
+		routerOpts = append(routerOpts, handler.WithAuth(authenticator))
+		reloaders = append(reloaders, authenticator)
+	}
+	trusted, err := subnet.New(c.TrustedSubnet)
+	if err != nil {
+		return fmt.Errorf("app.StartServer: failed to parse trusted subnet: %w", err)
+	}
+	routerOpts = append(routerOpts, handler.WithTrustedSubnet(trusted))
+	if c.ValidateRequests {
+		validator, err := openapi.NewValidator()
+		if err != nil {
+			return fmt.Errorf("app.StartServer: failed to load OpenAPI spec: %w", err)
+		}
+		routerOpts = append(routerOpts, handler.WithRequestValidation(validator))
+	}
+
+	serverConfig := &configReloader[config.ServerConfig]{
+		current: c,
+		load:    config.NewServerConfig,
+		live:    serverLiveFields(c),
+		apply: func(old, next *config.ServerConfig) error {
+			if next.Key != old.Key || next.CryptoKey != old.CryptoKey {
+				if err := configKeys.Set(next.Key, next.CryptoKey); err != nil {
+					return err
+				}
+			}
+			if err := trusted.Set(next.TrustedSubnet); err != nil {
+				return err
+			}
+			return log.SetLevel(next.LogLevel)
+		},
+		log: log,
+	}
+	go reloadOnSIGHUP(ctx, log, append([]reloader{serverConfig}, reloaders...)...)
+
+	go metrics.Run(ctx, metricStorage, selfmetrics.DefaultFlushInterval, log)
+
+	router := handler.NewRouter(metricStorage, log, dbClient, c.Key, decrypter, routerOpts...)
 
 	server := &http.Server{
 		Addr:    c.ServerAddress,
 		Handler: router.Handler(),
 	}
+	if c.TLSCert != "" {
+		server.TLSConfig, err = cryptoutil.ServerTLSConfig(c.TLSCert, c.TLSKey, c.TLSClientCA)
+		if err != nil {
+			return fmt.Errorf("app.StartServer: failed to configure TLS: %w", err)
+		}
+	}
+	// Потоковые подписки не завершаются сами, поэтому закрываем их при остановке сервера.
+	server.RegisterOnShutdown(metricStorage.CloseSubscriptions)
+
+	adminSrv := startAdmin(c.AdminAddress, c.AdminToken, log, func() any { return serverConfig.Config() }, buildInfo)
 
 	serverErr := make(chan error, 1)
 	go func() {
 		log.Info("Starting server on %s", c.ServerAddress)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		listen := server.ListenAndServe
+		if server.TLSConfig != nil {
+			listen = func() error { return server.ListenAndServeTLS("", "") }
+		}
+		if err := listen(); err != nil && err != http.ErrServerClosed {
 			serverErr <- err
 		}
 	}()
@@ -88,6 +262,8 @@ func StartServer(ctx context.Context) error {
 		log.Info("HTTP server stopped")
 	}
 
+	adminSrv.Shutdown(shutdownCtx)
+
 	if persisterDone != nil {
 		log.Info("Waiting for persister to finish...")
 		select {
@@ -98,11 +274,15 @@ func StartServer(ctx context.Context) error {
 		}
 	}
 
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("Failed to flush traces: %v", err)
+	}
+
 	log.Info("Graceful shutdown completed")
 	return nil
 }
 
-func initMetricStorage(ctx context.Context, c *config.ServerConfig, db *sqlx.DB, log *logger.ZapLogger) (*adapter.MetricStorage, chan struct{}, error) {
+func initMetricStorage(ctx context.Context, c *config.ServerConfig, db *sqlx.DB, log *logger.ZapLogger, metrics *selfmetrics.Registry) (*adapter.MetricStorage, chan struct{}, error) {
 	var storageType storage.StorageType
 
 	if c.DatabaseDSN != "" && db != nil {
@@ -124,11 +304,12 @@ func initMetricStorage(ctx context.Context, c *config.ServerConfig, db *sqlx.DB,
 	if err != nil {
 		return nil, nil, err
 	}
+	metricStorage.Instrument(metrics, string(storageType))
 
 	var persisterDone chan struct{}
 	if c.StoreInterval > 0 && storageType == storage.FileStorage {
 		persisterDone = make(chan struct{})
-		p := persister.NewPersister(metricStorage, log, c.StoreInterval)
+		p := persister.NewPersister(metricStorage, log, c.StoreInterval.Duration(), persister.WithMetrics(metrics))
 		go func() {
 			defer close(persisterDone)
 			p.Run(ctx)
@@ -137,3 +318,13 @@ func initMetricStorage(ctx context.Context, c *config.ServerConfig, db *sqlx.DB,
 
 	return metricStorage, persisterDone, nil
 }
+
+// serverLiveFields возвращает поля конфигурации сервера, которые применяются по SIGHUP.
+// Ключи из конфигурации заменяются без перезапуска, только если не задан файл ключей.
+func serverLiveFields(c *config.ServerConfig) []string {
+	live := []string{"LogLevel", "TrustedSubnet"}
+	if c.KeysFile == "" {
+		live = append(live, "Key", "CryptoKey")
+	}
+	return live
+}
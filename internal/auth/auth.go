@@ -0,0 +1,248 @@
+// Package auth реализует аутентификацию клиентов сервера метрик по bearer токенам.
+// Поддерживаются статические токены и JWT, подписанные HS256 или RS256,
+// с правами доступа в claim "scope".
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ScopeRead  = "metrics:read"
+	ScopeWrite = "metrics:write"
+)
+
+// TokenCookie - cookie с bearer токеном для запросов на чтение из браузера.
+const TokenCookie = "access_token"
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// StaticToken описывает статический токен и выданные ему права.
+type StaticToken struct {
+	Token   string   `json:"token"`
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+}
+
+// JWTConfig описывает параметры проверки JWT.
+type JWTConfig struct {
+	HS256Secret    string `json:"hs256_secret"`
+	RS256PublicKey string `json:"rs256_public_key"`
+	Issuer         string `json:"issuer"`
+	Audience       string `json:"audience"`
+}
+
+// Config описывает файл настроек аутентификации.
+type Config struct {
+	Tokens []StaticToken `json:"tokens"`
+	JWT    JWTConfig     `json:"jwt"`
+}
+
+// Principal описывает аутентифицированного клиента.
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope проверяет наличие права у клиента.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// Authenticator проверяет bearer токены.
+type Authenticator struct {
+	// mu защищает настройки, которые заменяются при Reload
+	mu   sync.RWMutex
+	path string
+
+	tokens       map[string]Principal
+	hmacSecret   []byte
+	rsaKey       *rsa.PublicKey
+	parserOpts   []jwt.ParserOption
+	validMethods []string
+}
+
+// LoadAuthenticator создает аутентификатор из JSON файла настроек.
+func LoadAuthenticator(path string) (*Authenticator, error) {
+	a, err := readAuthenticator(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth.LoadAuthenticator: %w", err)
+	}
+	a.path = path
+
+	return a, nil
+}
+
+// Reload перечитывает файл, из которого был загружен аутентификатор.
+// При ошибке текущие настройки не меняются.
+func (a *Authenticator) Reload() error {
+	if a.path == "" {
+		return nil
+	}
+
+	next, err := readAuthenticator(a.path)
+	if err != nil {
+		return fmt.Errorf("auth.Authenticator.Reload: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.tokens = next.tokens
+	a.hmacSecret = next.hmacSecret
+	a.rsaKey = next.rsaKey
+	a.parserOpts = next.parserOpts
+	a.validMethods = next.validMethods
+
+	return nil
+}
+
+func readAuthenticator(path string) (*Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth file '%s': %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse auth file '%s': %w", path, err)
+	}
+
+	return NewAuthenticator(cfg)
+}
+
+// NewAuthenticator создает аутентификатор из настроек.
+func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{
+		tokens: make(map[string]Principal, len(cfg.Tokens)),
+	}
+
+	for _, t := range cfg.Tokens {
+		if t.Token == "" {
+			return nil, errors.New("auth.NewAuthenticator: static token must not be empty")
+		}
+		a.tokens[t.Token] = Principal{Subject: t.Subject, Scopes: t.Scopes}
+	}
+
+	if cfg.JWT.HS256Secret != "" {
+		a.hmacSecret = []byte(cfg.JWT.HS256Secret)
+		a.validMethods = append(a.validMethods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWT.RS256PublicKey != "" {
+		key, err := readRSAPublicKey(cfg.JWT.RS256PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("auth.NewAuthenticator: %w", err)
+		}
+		a.rsaKey = key
+		a.validMethods = append(a.validMethods, jwt.SigningMethodRS256.Alg())
+	}
+
+	a.parserOpts = []jwt.ParserOption{
+		jwt.WithValidMethods(a.validMethods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWT.Issuer != "" {
+		a.parserOpts = append(a.parserOpts, jwt.WithIssuer(cfg.JWT.Issuer))
+	}
+	if cfg.JWT.Audience != "" {
+		a.parserOpts = append(a.parserOpts, jwt.WithAudience(cfg.JWT.Audience))
+	}
+
+	return a, nil
+}
+
+// Authenticate проверяет токен и возвращает описание клиента.
+// Сначала токен ищется среди статических, затем проверяется как JWT.
+func (a *Authenticator) Authenticate(token string) (*Principal, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if p, ok := a.tokens[token]; ok {
+		return &p, nil
+	}
+
+	if len(a.validMethods) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &scopeClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, a.keyFunc, a.parserOpts...); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return &Principal{
+		Subject: claims.Subject,
+		Scopes:  claims.scopes(),
+	}, nil
+}
+
+func (a *Authenticator) keyFunc(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		return a.rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// scopeClaims поддерживает claim "scope" как строку через пробел (RFC 8693)
+// и как массив строк.
+type scopeClaims struct {
+	jwt.RegisteredClaims
+	Scope any `json:"scope"`
+}
+
+func (c *scopeClaims) scopes() []string {
+	switch v := c.Scope.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		scopes := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				scopes = append(scopes, str)
+			}
+		}
+		return scopes
+	default:
+		return nil
+	}
+}
+
+func readRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RS256 public key '%s': %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block from file '%s'", path)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RS256 public key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("RS256 public key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
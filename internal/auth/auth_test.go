@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+func signHS256(t *testing.T, claims jwt.MapClaims, secret string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func writeRSAPublicKey(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwt_public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+	return path
+}
+
+func TestAuthenticator_StaticTokens(t *testing.T) {
+	a, err := NewAuthenticator(Config{
+		Tokens: []StaticToken{
+			{Token: "writer", Subject: "agent", Scopes: []string{ScopeWrite}},
+			{Token: "reader", Subject: "dashboard", Scopes: []string{ScopeRead}},
+		},
+	})
+	require.NoError(t, err)
+
+	p, err := a.Authenticate("writer")
+	require.NoError(t, err)
+	assert.Equal(t, "agent", p.Subject)
+	assert.True(t, p.HasScope(ScopeWrite))
+	assert.False(t, p.HasScope(ScopeRead))
+
+	p, err = a.Authenticate("reader")
+	require.NoError(t, err)
+	assert.True(t, p.HasScope(ScopeRead))
+
+	_, err = a.Authenticate("unknown")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthenticator_EmptyStaticToken(t *testing.T) {
+	_, err := NewAuthenticator(Config{Tokens: []StaticToken{{Token: ""}}})
+	assert.Error(t, err)
+}
+
+func TestAuthenticator_HS256(t *testing.T) {
+	a, err := NewAuthenticator(Config{
+		JWT: JWTConfig{HS256Secret: testSecret, Issuer: "metrics", Audience: "server"},
+	})
+	require.NoError(t, err)
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedErr    bool
+		expectedScopes []string
+	}{
+		{
+			name: "space separated scope",
+			token: signHS256(t, jwt.MapClaims{
+				"sub": "agent-1", "iss": "metrics", "aud": "server", "exp": exp,
+				"scope": "metrics:write metrics:read",
+			}, testSecret),
+			expectedScopes: []string{ScopeWrite, ScopeRead},
+		},
+		{
+			name: "array scope",
+			token: signHS256(t, jwt.MapClaims{
+				"sub": "agent-1", "iss": "metrics", "aud": "server", "exp": exp,
+				"scope": []string{ScopeRead},
+			}, testSecret),
+			expectedScopes: []string{ScopeRead},
+		},
+		{
+			name: "wrong secret",
+			token: signHS256(t, jwt.MapClaims{
+				"sub": "agent-1", "iss": "metrics", "aud": "server", "exp": exp,
+			}, "other-secret"),
+			expectedErr: true,
+		},
+		{
+			name: "expired token",
+			token: signHS256(t, jwt.MapClaims{
+				"sub": "agent-1", "iss": "metrics", "aud": "server", "exp": time.Now().Add(-time.Hour).Unix(),
+			}, testSecret),
+			expectedErr: true,
+		},
+		{
+			name: "missing expiration",
+			token: signHS256(t, jwt.MapClaims{
+				"sub": "agent-1", "iss": "metrics", "aud": "server",
+			}, testSecret),
+			expectedErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: signHS256(t, jwt.MapClaims{
+				"sub": "agent-1", "iss": "other", "aud": "server", "exp": exp,
+			}, testSecret),
+			expectedErr: true,
+		},
+		{
+			name:        "malformed token",
+			token:       "not-a-jwt",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(tt.token)
+
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "agent-1", p.Subject)
+			assert.Equal(t, tt.expectedScopes, p.Scopes)
+		})
+	}
+}
+
+func TestAuthenticator_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	a, err := NewAuthenticator(Config{JWT: JWTConfig{RS256PublicKey: writeRSAPublicKey(t, key)}})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub":   "agent-2",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": ScopeWrite,
+	}).SignedString(key)
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(token)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-2", p.Subject)
+	assert.True(t, p.HasScope(ScopeWrite))
+
+	hsToken := signHS256(t, jwt.MapClaims{"sub": "agent-2", "exp": time.Now().Add(time.Hour).Unix()}, testSecret)
+	_, err = a.Authenticate(hsToken)
+	assert.ErrorIs(t, err, ErrInvalidToken, "HS256 must be rejected when only RS256 is configured")
+}
+
+func TestAuthenticator_NoJWTConfigured(t *testing.T) {
+	a, err := NewAuthenticator(Config{})
+	require.NoError(t, err)
+
+	token := signHS256(t, jwt.MapClaims{"sub": "agent", "exp": time.Now().Add(time.Hour).Unix()}, testSecret)
+	_, err = a.Authenticate(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestLoadAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"tokens":[{"token":"t1","scopes":["metrics:read"]}]}`), 0o600))
+
+	a, err := LoadAuthenticator(path)
+	require.NoError(t, err)
+
+	p, err := a.Authenticate("t1")
+	require.NoError(t, err)
+	assert.True(t, p.HasScope(ScopeRead))
+
+	_, err = LoadAuthenticator("/path/that/does/not/exist.json")
+	assert.ErrorContains(t, err, "failed to read auth file")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	_, err = LoadAuthenticator(path)
+	assert.ErrorContains(t, err, "failed to parse auth file")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jwt":{"rs256_public_key":"/missing.pem"}}`), 0o600))
+	_, err = LoadAuthenticator(path)
+	assert.ErrorContains(t, err, "failed to read RS256 public key")
+}
+
+func TestAuthenticator_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"tokens":[{"token":"t1","scopes":["metrics:read"]}]}`), 0o600))
+
+	a, err := LoadAuthenticator(path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"tokens":[{"token":"t2","scopes":["metrics:write"]}],"jwt":{"hs256_secret":"`+testSecret+`"}}`), 0o600))
+	require.NoError(t, a.Reload())
+
+	_, err = a.Authenticate("t1")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	p, err := a.Authenticate("t2")
+	require.NoError(t, err)
+	assert.True(t, p.HasScope(ScopeWrite))
+
+	token := signHS256(t, jwt.MapClaims{"sub": "agent", "scope": ScopeRead, "exp": time.Now().Add(time.Hour).Unix()}, testSecret)
+	p, err = a.Authenticate(token)
+	require.NoError(t, err, "reload should enable JWT verification")
+	assert.Equal(t, "agent", p.Subject)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	assert.Error(t, a.Reload())
+
+	_, err = a.Authenticate("t2")
+	assert.NoError(t, err, "failed reload must keep previous settings")
+
+	static, err := NewAuthenticator(Config{})
+	require.NoError(t, err)
+	assert.NoError(t, static.Reload(), "authenticator without file has nothing to reload")
+}
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	p := &Principal{Subject: "agent"}
+	got, ok := PrincipalFromContext(WithPrincipal(context.Background(), p))
+	assert.True(t, ok)
+	assert.Equal(t, p, got)
+}
@@ -0,0 +1,252 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+)
+
+const CgroupName = "cgroup"
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	selfCgroup = "/proc/self/cgroup"
+)
+
+// счетчики из cpu.stat и io.stat, которые передаются как counter метрики
+var (
+	cgroupCPUCounters = []string{"usage_usec", "user_usec", "system_usec", "nr_periods", "nr_throttled", "throttled_usec"}
+	cgroupIOCounters  = []string{"rbytes", "wbytes", "rios", "wios"}
+)
+
+// CgroupOptions - настройки сборщика cgroup. Path задает каталог cgroup v2,
+// по умолчанию используется cgroup самого агента.
+type CgroupOptions struct {
+	Path string `json:"path"`
+}
+
+// CgroupCollector собирает потребление ресурсов cgroup v2: память и число процессов
+// в gauge метриках cgroup.memory.* и cgroup.pids.current, процессорное время и ввод-вывод
+// в counter метриках cgroup.cpu.* и cgroup.io.* с приращением между сборами,
+// загрузку процессора в cgroup.cpu.usage_percent. Файлы отключенных контроллеров пропускаются.
+type CgroupCollector struct {
+	interval time.Duration
+	path     string
+	now      func() time.Time
+
+	prevCounters map[string]uint64
+	prevTime     time.Time
+}
+
+func NewCgroupCollector(interval time.Duration, opts CgroupOptions) (*CgroupCollector, error) {
+	path := opts.Path
+	if path == "" {
+		var err error
+		path, err = resolveCgroupPath(cgroupRoot, selfCgroup)
+		if err != nil {
+			return nil, fmt.Errorf("collector.NewCgroupCollector: %w", err)
+		}
+	}
+
+	return &CgroupCollector{
+		interval: interval,
+		path:     path,
+		now:      time.Now,
+	}, nil
+}
+
+// resolveCgroupPath находит каталог cgroup v2 процесса по строке "0::<путь>" из файла self.
+func resolveCgroupPath(root, self string) (string, error) {
+	data, err := os.ReadFile(self)
+	if err != nil {
+		return "", fmt.Errorf("failed to read own cgroup: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return filepath.Join(root, path), nil
+		}
+	}
+
+	return "", fmt.Errorf("cgroup v2 is not found in '%s'", self)
+}
+
+func (c *CgroupCollector) Name() string {
+	return CgroupName
+}
+
+func (c *CgroupCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *CgroupCollector) Collect(_ context.Context, sink Sink) error {
+	if _, err := os.Stat(c.path); err != nil {
+		return fmt.Errorf("collector.CgroupCollector.Collect: %w", err)
+	}
+	now := c.now()
+
+	var errs []error
+	collectErr := func(err error) {
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("collector.CgroupCollector.Collect: %w", err))
+		}
+	}
+
+	current, currentErr := c.readValue("memory.current")
+	collectErr(currentErr)
+	if currentErr == nil {
+		sink.SetGauge("cgroup.memory.current_bytes", float64(current))
+	}
+
+	limit, err := c.readValue("memory.max")
+	collectErr(err)
+	if err == nil && limit > 0 {
+		sink.SetGauge("cgroup.memory.max_bytes", float64(limit))
+		if currentErr == nil {
+			sink.SetGauge("cgroup.memory.used_percent", float64(current)/float64(limit)*100)
+		}
+	}
+
+	pids, err := c.readValue("pids.current")
+	collectErr(err)
+	if err == nil {
+		sink.SetGauge("cgroup.pids.current", float64(pids))
+	}
+
+	counters := make(map[string]uint64, len(cgroupCPUCounters)+len(cgroupIOCounters))
+
+	cpuStat, err := c.readKeyValues("cpu.stat")
+	collectErr(err)
+	for _, name := range cgroupCPUCounters {
+		if v, ok := cpuStat[name]; ok {
+			counters["cgroup.cpu."+name] = v
+		}
+	}
+
+	ioStat, err := c.readIOStat()
+	collectErr(err)
+	for _, name := range cgroupIOCounters {
+		if v, ok := ioStat[name]; ok {
+			counters["cgroup.io."+name] = v
+		}
+	}
+
+	c.addCounters(sink, counters, now)
+
+	return errors.Join(errs...)
+}
+
+// addCounters передает приращения счетчиков с предыдущего сбора и загрузку процессора.
+func (c *CgroupCollector) addCounters(sink Sink, counters map[string]uint64, now time.Time) {
+	elapsed := now.Sub(c.prevTime)
+	if c.prevCounters != nil && elapsed > 0 {
+		for name, cur := range counters {
+			prev, ok := c.prevCounters[name]
+			// счетчик уменьшился после пересоздания cgroup, приращение неизвестно
+			if !ok || cur < prev {
+				continue
+			}
+			sink.AddCounter(metric.CounterMetric(name), int64(cur-prev))
+		}
+
+		prev, hadPrev := c.prevCounters["cgroup.cpu.usage_usec"]
+		cur, hasCur := counters["cgroup.cpu.usage_usec"]
+		if hadPrev && hasCur && cur >= prev {
+			sink.SetGauge("cgroup.cpu.usage_percent", float64(cur-prev)/float64(elapsed.Microseconds())*100)
+		}
+	}
+
+	c.prevCounters = counters
+	c.prevTime = now
+}
+
+// readValue читает файл с одним числом. Значение "max" означает отсутствие ограничения и возвращается как ноль.
+func (c *CgroupCollector) readValue(name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(c.path, name))
+	if err != nil {
+		return 0, err
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' in %s", value, name)
+	}
+
+	return v, nil
+}
+
+// readKeyValues читает файл из строк "<ключ> <число>", например cpu.stat.
+func (c *CgroupCollector) readKeyValues(name string) (map[string]uint64, error) {
+	values := make(map[string]uint64)
+
+	err := c.scanLines(name, func(fields []string) error {
+		if len(fields) != 2 {
+			return nil
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' of %s in %s", fields[1], fields[0], name)
+		}
+		values[fields[0]] = v
+		return nil
+	})
+
+	return values, err
+}
+
+// readIOStat суммирует по устройствам значения io.stat из строк "<major:minor> <ключ>=<число> ...".
+func (c *CgroupCollector) readIOStat() (map[string]uint64, error) {
+	values := make(map[string]uint64)
+
+	err := c.scanLines("io.stat", func(fields []string) error {
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value '%s' of %s in io.stat", value, key)
+			}
+			values[key] += v
+		}
+		return nil
+	})
+
+	return values, err
+}
+
+func (c *CgroupCollector) scanLines(name string, parse func(fields []string) error) error {
+	f, err := os.Open(filepath.Join(c.path, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if err := parse(fields); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
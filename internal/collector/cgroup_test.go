@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureCgroupCollector возвращает сборщик, который читает каталог testdata/cgroup/<dir>.
+func newFixtureCgroupCollector(t *testing.T, dir string, clock *fakeClock) *CgroupCollector {
+	t.Helper()
+
+	c, err := NewCgroupCollector(0, CgroupOptions{Path: "testdata/cgroup/" + dir})
+	require.NoError(t, err)
+	c.now = clock.Now
+
+	return c
+}
+
+func TestCgroupCollector_Collect(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureCgroupCollector(t, "first", clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	sent := sink.send(t)
+	assert.Equal(t, map[string]float64{
+		"cgroup.memory.current_bytes": 104857600,
+		"cgroup.memory.max_bytes":     419430400,
+		"cgroup.memory.used_percent":  25,
+		"cgroup.pids.current":         7,
+	}, sent.gauges())
+	assert.Empty(t, sent.counters(), "counters need two polls")
+
+	c.path = "testdata/cgroup/second"
+	clock.Advance(time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	sent = sink.send(t)
+	gauges := sent.gauges()
+	assert.Equal(t, 50.0, gauges["cgroup.memory.used_percent"])
+	assert.Equal(t, 9.0, gauges["cgroup.pids.current"])
+	assert.InDelta(t, 50.0, gauges["cgroup.cpu.usage_percent"], 1e-9)
+	assert.Equal(t, map[string]int64{
+		"cgroup.cpu.usage_usec":     500000,
+		"cgroup.cpu.user_usec":      300000,
+		"cgroup.cpu.system_usec":    200000,
+		"cgroup.cpu.nr_periods":     10,
+		"cgroup.cpu.nr_throttled":   1,
+		"cgroup.cpu.throttled_usec": 10000,
+		"cgroup.io.rbytes":          5120,
+		"cgroup.io.wbytes":          8192,
+		"cgroup.io.rios":            2,
+		"cgroup.io.wios":            2,
+	}, sent.counters())
+
+	c.path = "testdata/cgroup/first"
+	clock.Advance(time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	assert.Empty(t, sink.send(t).counters(), "counter reset should not add delta")
+}
+
+func TestCgroupCollector_Collect_SendsDeltas(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureCgroupCollector(t, "first", clock)
+	sink := newTestSink(t)
+
+	var sent []int64
+	for _, dir := range []string{"second", "first", "second"} {
+		require.NoError(t, c.Collect(context.Background(), sink))
+		c.path = "testdata/cgroup/" + dir
+		clock.Advance(time.Second)
+		require.NoError(t, c.Collect(context.Background(), sink))
+
+		sent = append(sent, sink.send(t).counters()["cgroup.cpu.usage_usec"])
+	}
+
+	// сервер прибавляет каждое значение к счетчику, поэтому повторная отправка
+	// уже отправленного приращения завысила бы итог
+	assert.Equal(t, []int64{500000, 0, 500000}, sent)
+}
+
+func TestCgroupCollector_Collect_UnlimitedAndMissingControllers(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureCgroupCollector(t, "unlimited", clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+	clock.Advance(time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	sent := sink.send(t)
+	assert.Equal(t, map[string]float64{
+		"cgroup.memory.current_bytes": 52428800,
+	}, sent.gauges())
+	assert.Empty(t, sent.counters())
+}
+
+func TestCgroupCollector_Collect_Errors(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	sink := newTestSink(t)
+
+	err := newFixtureCgroupCollector(t, "invalid", clock).Collect(context.Background(), sink)
+	assert.ErrorContains(t, err, "invalid value 'abc' in memory.current")
+	assert.ErrorContains(t, err, "invalid value 'x' of usage_usec in cpu.stat")
+
+	err = newFixtureCgroupCollector(t, "missing", clock).Collect(context.Background(), sink)
+	assert.Error(t, err)
+}
+
+func TestResolveCgroupPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		self     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "cgroup v2", self: "v2", expected: "/sys/fs/cgroup/system.slice/agent.service"},
+		{name: "hybrid", self: "hybrid", expected: "/sys/fs/cgroup"},
+		{name: "cgroup v1 only", self: "v1", wantErr: true},
+		{name: "missing file", self: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := resolveCgroupPath("/sys/fs/cgroup", "testdata/cgroup/self/"+tt.self)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
@@ -1,40 +1,154 @@
 package collector
 
 import (
-	"context"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/config"
 )
 
-type Collector struct {
-	metrics      MetricsCollector
-	logger       CollectorLogger
-	pollInterval time.Duration
+var ErrUnknownCollector = errors.New("unknown collector")
+
+// Settings - настройки, с которыми создается сборщик.
+type Settings struct {
+	Interval time.Duration
+	Options  json.RawMessage
+}
+
+// DecodeOptions разбирает собственные настройки сборщика в v.
+// Незнакомые поля считаются ошибкой, отсутствие настроек оставляет v без изменений.
+func (s Settings) DecodeOptions(v any) error {
+	if len(s.Options) == 0 || string(s.Options) == "null" {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(s.Options))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	return nil
 }
 
-func NewCollector(metrics MetricsCollector, logger CollectorLogger, pollInterval uint) *Collector {
-	if pollInterval == 0 {
-		pollInterval = 1
+// Factory создает сборщик с заданными настройками.
+type Factory func(settings Settings) (Collector, error)
+
+type registration struct {
+	factory Factory
+	enabled bool
+}
+
+// Registry хранит фабрики сборщиков по именам.
+type Registry struct {
+	collectors map[string]registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]registration),
 	}
+}
+
+// NewDefaultRegistry возвращает реестр со встроенными сборщиками агента.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	r.Register(MemStatsName, true, func(s Settings) (Collector, error) {
+		return NewMemStatsCollector(s.Interval), nil
+	})
+	r.Register(RandomName, true, func(s Settings) (Collector, error) {
+		return NewRandomCollector(s.Interval), nil
+	})
+	r.Register(GopsutilName, true, func(s Settings) (Collector, error) {
+		return NewGopsutilCollector(s.Interval), nil
+	})
+	r.Register(DiskName, false, func(s Settings) (Collector, error) {
+		var opts DiskOptions
+		if err := s.DecodeOptions(&opts); err != nil {
+			return nil, err
+		}
+		return NewDiskCollector(s.Interval, opts)
+	})
+	r.Register(NetName, false, func(s Settings) (Collector, error) {
+		var opts NetOptions
+		if err := s.DecodeOptions(&opts); err != nil {
+			return nil, err
+		}
+		return NewNetCollector(s.Interval, opts)
+	})
+	r.Register(ProcessName, false, func(s Settings) (Collector, error) {
+		var opts ProcessOptions
+		if err := s.DecodeOptions(&opts); err != nil {
+			return nil, err
+		}
+		return NewProcessCollector(s.Interval, opts)
+	})
+	r.Register(CgroupName, false, func(s Settings) (Collector, error) {
+		var opts CgroupOptions
+		if err := s.DecodeOptions(&opts); err != nil {
+			return nil, err
+		}
+		return NewCgroupCollector(s.Interval, opts)
+	})
 
-	return &Collector{
-		metrics:      metrics,
-		logger:       logger,
-		pollInterval: time.Duration(pollInterval) * time.Second,
+	return r
+}
+
+// Register добавляет сборщик в реестр. enabled определяет, работает ли сборщик,
+// если конфигурация не включает и не отключает его явно.
+func (r *Registry) Register(name string, enabled bool, factory Factory) {
+	r.collectors[name] = registration{
+		factory: factory,
+		enabled: enabled,
 	}
 }
 
-func (c *Collector) RunWithContext(ctx context.Context) {
-	ticker := time.NewTicker(c.pollInterval)
-	defer ticker.Stop()
+// Names возвращает отсортированные имена зарегистрированных сборщиков.
+func (r *Registry) Names() []string {
+	return slices.Sorted(maps.Keys(r.collectors))
+}
+
+// Build создает включенные сборщики по настройкам из конфигурации агента.
+// Настройки для незарегистрированных имен считаются ошибкой.
+func (r *Registry) Build(configs map[string]config.CollectorConfig) ([]Collector, error) {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		if _, ok := r.collectors[name]; !ok {
+			errs = append(errs, fmt.Errorf("%w '%s'", ErrUnknownCollector, name))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("collector.Registry.Build: %w", err)
+	}
+
+	var collectors []Collector
+	for _, name := range r.Names() {
+		reg := r.collectors[name]
+		cfg := configs[name]
 
-	for {
-		select {
-		case <-ctx.Done():
-			c.logger.Info("Collector stopping due to context cancellation")
-			return
-		case <-ticker.C:
-			c.metrics.UpdateMetrics()
-			c.logger.Info("Metrics updated")
+		enabled := reg.enabled
+		if cfg.Enabled != nil {
+			enabled = *cfg.Enabled
 		}
+		if !enabled {
+			continue
+		}
+
+		c, err := reg.factory(Settings{
+			Interval: cfg.Interval.Duration(),
+			Options:  cfg.Options,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("collector.Registry.Build: collector '%s': %w", name, err)
+		}
+		collectors = append(collectors, c)
 	}
+
+	return collectors, nil
 }
@@ -5,319 +5,177 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/config"
 	"github.com/stretchr/testify/assert"
-	"go.uber.org/mock/gomock"
+	"github.com/stretchr/testify/require"
 )
 
-func TestNewCollector(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	collector := NewCollector(mockMetrics, mockLogger, 5)
-
-	assert.NotNil(t, collector)
-	assert.Equal(t, mockMetrics, collector.metrics)
-	assert.Equal(t, mockLogger, collector.logger)
-	assert.Equal(t, 5*time.Second, collector.pollInterval)
-}
-
-func TestNewCollector_ZeroPollInterval(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	collector := NewCollector(mockMetrics, mockLogger, 0)
-
-	assert.NotNil(t, collector)
-	assert.Equal(t, mockMetrics, collector.metrics)
-	assert.Equal(t, mockLogger, collector.logger)
-	assert.Equal(t, 1*time.Second, collector.pollInterval)
+type stubCollector struct {
+	name     string
+	interval time.Duration
 }
 
-func TestCollector_GopsutilMetrics(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-
-	t.Run("CollectGopsutilMetrics success", func(t *testing.T) {
-		mockMetrics.EXPECT().CollectGopsutilMetrics().Return(nil).Times(1)
+func (c *stubCollector) Name() string                        { return c.name }
+func (c *stubCollector) Interval() time.Duration             { return c.interval }
+func (c *stubCollector) Collect(context.Context, Sink) error { return nil }
 
-		err := mockMetrics.CollectGopsutilMetrics()
-		assert.NoError(t, err)
+func newStubRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("on", true, func(s Settings) (Collector, error) {
+		return &stubCollector{name: "on", interval: s.Interval}, nil
 	})
-
-	t.Run("CollectGopsutilMetrics error", func(t *testing.T) {
-		mockMetrics.EXPECT().CollectGopsutilMetrics().Return(assert.AnError).Times(1)
-
-		err := mockMetrics.CollectGopsutilMetrics()
-		assert.Error(t, err)
-	})
-
-	t.Run("InitGopsutilMetrics success", func(t *testing.T) {
-		pollInterval := 5 * time.Second
-		mockMetrics.EXPECT().InitGopsutilMetrics(pollInterval).Return(nil).Times(1)
-
-		err := mockMetrics.InitGopsutilMetrics(pollInterval)
-		assert.NoError(t, err)
-	})
-
-	t.Run("InitGopsutilMetrics error", func(t *testing.T) {
-		pollInterval := 5 * time.Second
-		mockMetrics.EXPECT().InitGopsutilMetrics(pollInterval).Return(assert.AnError).Times(1)
-
-		err := mockMetrics.InitGopsutilMetrics(pollInterval)
-		assert.Error(t, err)
-	})
-}
-
-func TestCollector_RunWithContext(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	t.Run("context cancellation", func(t *testing.T) {
-		collector := NewCollector(mockMetrics, mockLogger, 1)
-
-		mockMetrics.EXPECT().UpdateMetrics().AnyTimes()
-		mockLogger.EXPECT().Info("Metrics updated").AnyTimes()
-		mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-		defer cancel()
-
-		done := make(chan bool)
-		go func() {
-			collector.RunWithContext(ctx)
-			done <- true
-		}()
-
-		select {
-		case <-done:
-		case <-time.After(2 * time.Second):
-			t.Fatal("RunWithContext did not stop after context cancellation")
-		}
+	r.Register("off", false, func(s Settings) (Collector, error) {
+		return &stubCollector{name: "off", interval: s.Interval}, nil
 	})
-
-	t.Run("immediate cancellation", func(t *testing.T) {
-		collector := NewCollector(mockMetrics, mockLogger, 10)
-
-		mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
-
-		ctx, cancel := context.WithCancel(context.Background())
-		cancel()
-
-		done := make(chan bool)
-		go func() {
-			collector.RunWithContext(ctx)
-			done <- true
-		}()
-
-		select {
-		case <-done:
-		case <-time.After(1 * time.Second):
-			t.Fatal("RunWithContext did not stop immediately after context cancellation")
-		}
+	r.Register("broken", false, func(s Settings) (Collector, error) {
+		return nil, assert.AnError
 	})
+	return r
 }
 
-func TestCollector_Run_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	collector := NewCollector(mockMetrics, mockLogger, 1)
-
-	mockMetrics.EXPECT().UpdateMetrics().AnyTimes()
-	mockLogger.EXPECT().Info("Metrics updated").AnyTimes()
-	mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
-	defer cancel()
-
-	done := make(chan bool)
-	go func() {
-		defer func() { done <- true }()
-
-		collector.RunWithContext(ctx)
-	}()
-
-	select {
-	case <-done:
-	case <-time.After(1 * time.Second):
-		t.Fatal("Collector did not stop within reasonable time")
+func TestRegistry_Build(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	tests := []struct {
+		name      string
+		configs   map[string]config.CollectorConfig
+		expected  []string
+		intervals []time.Duration
+		wantErr   error
+	}{
+		{
+			name:      "defaults",
+			expected:  []string{"on"},
+			intervals: []time.Duration{0},
+		},
+		{
+			name: "enable and disable",
+			configs: map[string]config.CollectorConfig{
+				"on":  {Enabled: &disabled},
+				"off": {Enabled: &enabled, Interval: config.Duration(5 * time.Second)},
+			},
+			expected:  []string{"off"},
+			intervals: []time.Duration{5 * time.Second},
+		},
+		{
+			name: "interval without enabled keeps default",
+			configs: map[string]config.CollectorConfig{
+				"on": {Interval: config.Duration(time.Minute)},
+			},
+			expected:  []string{"on"},
+			intervals: []time.Duration{time.Minute},
+		},
+		{
+			name: "unknown collector",
+			configs: map[string]config.CollectorConfig{
+				"missing": {Enabled: &enabled},
+			},
+			wantErr: ErrUnknownCollector,
+		},
+		{
+			name: "factory error",
+			configs: map[string]config.CollectorConfig{
+				"broken": {Enabled: &enabled},
+			},
+			wantErr: assert.AnError,
+		},
 	}
-}
 
-func TestCollector_RunWithContext_MultipleIterations(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	t.Run("multiple update calls", func(t *testing.T) {
-		collector := NewCollector(mockMetrics, mockLogger, 1)
-
-		mockMetrics.EXPECT().UpdateMetrics().Times(2)
-		mockLogger.EXPECT().Info("Metrics updated").Times(2)
-		mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 2100*time.Millisecond)
-		defer cancel()
-
-		done := make(chan bool)
-		go func() {
-			collector.RunWithContext(ctx)
-			done <- true
-		}()
-
-		select {
-		case <-done:
-		case <-time.After(3 * time.Second):
-			t.Fatal("RunWithContext did not stop after context cancellation")
-		}
-	})
-}
-
-func TestCollector_RunWithContext_ConcurrentAccess(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	t.Run("concurrent collectors", func(t *testing.T) {
-		collector1 := NewCollector(mockMetrics, mockLogger, 1)
-		collector2 := NewCollector(mockMetrics, mockLogger, 1)
-
-		mockMetrics.EXPECT().UpdateMetrics().AnyTimes()
-		mockLogger.EXPECT().Info("Metrics updated").AnyTimes()
-		mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(2)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-		defer cancel()
-
-		done1 := make(chan bool)
-		done2 := make(chan bool)
-
-		go func() {
-			collector1.RunWithContext(ctx)
-			done1 <- true
-		}()
-
-		go func() {
-			collector2.RunWithContext(ctx)
-			done2 <- true
-		}()
-
-		select {
-		case <-done1:
-		case <-time.After(2 * time.Second):
-			t.Fatal("Collector1 did not stop after context cancellation")
-		}
-
-		select {
-		case <-done2:
-		case <-time.After(2 * time.Second):
-			t.Fatal("Collector2 did not stop after context cancellation")
-		}
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collectors, err := newStubRegistry().Build(tt.configs)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			var intervals []time.Duration
+			for _, c := range collectors {
+				names = append(names, c.Name())
+				intervals = append(intervals, c.Interval())
+			}
+			assert.Equal(t, tt.expected, names)
+			assert.Equal(t, tt.intervals, intervals)
+		})
+	}
 }
 
-func TestCollector_EdgeCases(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestNewDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry()
 
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
+	assert.Equal(t, []string{CgroupName, DiskName, GopsutilName, MemStatsName, NetName, ProcessName, RandomName}, r.Names())
 
-	t.Run("edge case poll intervals", func(t *testing.T) {
-		collectorMax := NewCollector(mockMetrics, mockLogger, ^uint(0))
-		assert.NotNil(t, collectorMax)
+	collectors, err := r.Build(nil)
+	require.NoError(t, err)
 
-		collector1 := NewCollector(mockMetrics, mockLogger, 1)
-		assert.Equal(t, 1*time.Second, collector1.pollInterval)
-
-		collectorDirect := &Collector{
-			metrics:      mockMetrics,
-			logger:       mockLogger,
-			pollInterval: 500 * time.Millisecond,
-		}
-		assert.NotNil(t, collectorDirect)
-		assert.Equal(t, 500*time.Millisecond, collectorDirect.pollInterval)
-	})
+	var names []string
+	for _, c := range collectors {
+		names = append(names, c.Name())
+	}
+	assert.Equal(t, []string{GopsutilName, MemStatsName, RandomName}, names, "only built-in runtime collectors are enabled by default")
 }
 
-func TestCollector_RunWithContext_EdgeCases(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	t.Run("pre-cancelled context", func(t *testing.T) {
-		collector := NewCollector(mockMetrics, mockLogger, 5)
-
-		ctx, cancel := context.WithCancel(context.Background())
-		cancel()
-
-		mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
-
-		done := make(chan bool)
-		go func() {
-			collector.RunWithContext(ctx)
-			done <- true
-		}()
+func TestNewDefaultRegistry_DiskOptions(t *testing.T) {
+	enabled := true
+
+	tests := []struct {
+		name    string
+		options string
+		wantErr bool
+	}{
+		{name: "valid", options: `{"exclude_devices": ["loop*"]}`},
+		{name: "unknown option", options: `{"mounts": ["/"]}`, wantErr: true},
+		{name: "invalid pattern", options: `{"include_mounts": ["["]}`, wantErr: true},
+	}
 
-		select {
-		case <-done:
-		case <-time.After(500 * time.Millisecond):
-			t.Fatal("RunWithContext should return immediately with cancelled context")
-		}
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collectors, err := NewDefaultRegistry().Build(map[string]config.CollectorConfig{
+				DiskName: {Enabled: &enabled, Options: []byte(tt.options)},
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, collectors, 4)
+		})
+	}
 }
 
-func TestCollector_TimingBehavior(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockMetrics := NewMockMetricsCollector(ctrl)
-	mockLogger := NewMockCollectorLogger(ctrl)
-
-	t.Run("fast poll interval", func(t *testing.T) {
-		collector := &Collector{
-			metrics:      mockMetrics,
-			logger:       mockLogger,
-			pollInterval: 50 * time.Millisecond,
-		}
-
-		mockMetrics.EXPECT().UpdateMetrics().MinTimes(2)
-		mockLogger.EXPECT().Info("Metrics updated").MinTimes(2)
-		mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
-		defer cancel()
+func TestSettings_DecodeOptions(t *testing.T) {
+	type options struct {
+		Path string `json:"path"`
+	}
 
-		done := make(chan bool)
-		go func() {
-			collector.RunWithContext(ctx)
-			done <- true
-		}()
+	tests := []struct {
+		name     string
+		raw      string
+		expected options
+		wantErr  bool
+	}{
+		{name: "empty", expected: options{Path: "default"}},
+		{name: "null", raw: "null", expected: options{Path: "default"}},
+		{name: "value", raw: `{"path": "/proc"}`, expected: options{Path: "/proc"}},
+		{name: "unknown field", raw: `{"other": 1}`, wantErr: true},
+	}
 
-		select {
-		case <-done:
-		case <-time.After(1 * time.Second):
-			t.Fatal("RunWithContext did not stop after context cancellation")
-		}
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options{Path: "default"}
+			err := Settings{Options: []byte(tt.raw)}.DecodeOptions(&opts)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, opts)
+		})
+	}
 }
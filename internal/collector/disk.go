@@ -0,0 +1,172 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+const DiskName = "disk"
+
+// DiskOptions - настройки сборщика дисков. Шаблоны имеют синтаксис path.Match,
+// например "/var/*" или "loop*". Пустой список include означает все значения.
+type DiskOptions struct {
+	IncludeMounts  []string `json:"include_mounts"`
+	ExcludeMounts  []string `json:"exclude_mounts"`
+	IncludeDevices []string `json:"include_devices"`
+	ExcludeDevices []string `json:"exclude_devices"`
+}
+
+// DiskCollector собирает заполненность файловых систем по точкам монтирования
+// в метриках disk.fs.<точка>.* и скорость ввода-вывода устройств в метриках disk.io.<устройство>.*.
+// Скорость считается по разнице счетчиков между сборами, поэтому появляется со второго сбора.
+type DiskCollector struct {
+	interval time.Duration
+	mounts   filter
+	devices  filter
+
+	partitions func(ctx context.Context) ([]disk.PartitionStat, error)
+	usage      func(ctx context.Context, path string) (*disk.UsageStat, error)
+	ioCounters func(ctx context.Context) (map[string]disk.IOCountersStat, error)
+	now        func() time.Time
+
+	prevCounters map[string]disk.IOCountersStat
+	prevTime     time.Time
+}
+
+func NewDiskCollector(interval time.Duration, opts DiskOptions) (*DiskCollector, error) {
+	mounts, err := newFilter(opts.IncludeMounts, opts.ExcludeMounts)
+	if err != nil {
+		return nil, fmt.Errorf("collector.NewDiskCollector: mounts: %w", err)
+	}
+	devices, err := newFilter(opts.IncludeDevices, opts.ExcludeDevices)
+	if err != nil {
+		return nil, fmt.Errorf("collector.NewDiskCollector: devices: %w", err)
+	}
+
+	return &DiskCollector{
+		interval: interval,
+		mounts:   mounts,
+		devices:  devices,
+		partitions: func(ctx context.Context) ([]disk.PartitionStat, error) {
+			return disk.PartitionsWithContext(ctx, false)
+		},
+		usage: disk.UsageWithContext,
+		ioCounters: func(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+			return disk.IOCountersWithContext(ctx)
+		},
+		now: time.Now,
+	}, nil
+}
+
+func (c *DiskCollector) Name() string {
+	return DiskName
+}
+
+func (c *DiskCollector) Interval() time.Duration {
+	return c.interval
+}
+
+// Collect собирает все доступные значения, даже если часть точек монтирования недоступна,
+// и возвращает объединенную ошибку.
+func (c *DiskCollector) Collect(ctx context.Context, sink Sink) error {
+	return errors.Join(c.collectUsage(ctx, sink), c.collectIO(ctx, sink))
+}
+
+func (c *DiskCollector) collectUsage(ctx context.Context, sink Sink) error {
+	partitions, err := c.partitions(ctx)
+	if err != nil {
+		return fmt.Errorf("collector.DiskCollector.Collect: failed to list partitions: %w", err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(partitions))
+	labels := make(labelSet, len(partitions))
+	for _, p := range partitions {
+		if seen[p.Mountpoint] || !c.mounts.match(p.Mountpoint) {
+			continue
+		}
+		seen[p.Mountpoint] = true
+
+		label, err := labels.label(p.Mountpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collector.DiskCollector.Collect: %w", err))
+			continue
+		}
+
+		usage, err := c.usage(ctx, p.Mountpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collector.DiskCollector.Collect: failed to get usage of '%s': %w", p.Mountpoint, err))
+			continue
+		}
+
+		prefix := "disk.fs." + label + "."
+		sink.SetGauge(metric.GaugeMetric(prefix+"total_bytes"), float64(usage.Total))
+		sink.SetGauge(metric.GaugeMetric(prefix+"used_bytes"), float64(usage.Used))
+		sink.SetGauge(metric.GaugeMetric(prefix+"free_bytes"), float64(usage.Free))
+		sink.SetGauge(metric.GaugeMetric(prefix+"used_percent"), usage.UsedPercent)
+		sink.SetGauge(metric.GaugeMetric(prefix+"inodes_total"), float64(usage.InodesTotal))
+		sink.SetGauge(metric.GaugeMetric(prefix+"inodes_used"), float64(usage.InodesUsed))
+		sink.SetGauge(metric.GaugeMetric(prefix+"inodes_free"), float64(usage.InodesFree))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *DiskCollector) collectIO(ctx context.Context, sink Sink) error {
+	counters, err := c.ioCounters(ctx)
+	if err != nil {
+		return fmt.Errorf("collector.DiskCollector.Collect: failed to get io counters: %w", err)
+	}
+	now := c.now()
+
+	var errs []error
+	elapsed := now.Sub(c.prevTime).Seconds()
+	labels := make(labelSet, len(counters))
+	// имена сортируются, чтобы при совпадении меток всегда выигрывало одно и то же устройство
+	for _, name := range slices.Sorted(maps.Keys(counters)) {
+		if !c.devices.match(name) {
+			continue
+		}
+
+		label, err := labels.label(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collector.DiskCollector.Collect: %w", err))
+			continue
+		}
+
+		prev, ok := c.prevCounters[name]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+
+		cur := counters[name]
+		prefix := "disk.io." + label + "."
+		setRate(sink, prefix+"read_bytes_per_sec", prev.ReadBytes, cur.ReadBytes, elapsed)
+		setRate(sink, prefix+"write_bytes_per_sec", prev.WriteBytes, cur.WriteBytes, elapsed)
+		setRate(sink, prefix+"reads_per_sec", prev.ReadCount, cur.ReadCount, elapsed)
+		setRate(sink, prefix+"writes_per_sec", prev.WriteCount, cur.WriteCount, elapsed)
+	}
+
+	c.prevCounters = counters
+	c.prevTime = now
+
+	return errors.Join(errs...)
+}
+
+// setRate записывает скорость роста счетчика в секунду. Если счетчик уменьшился,
+// например после переполнения или пересоздания устройства, скорость неизвестна
+// и записывается 0, чтобы не оставлять значение с прошлого сбора.
+func setRate(sink Sink, name string, prev, cur uint64, elapsed float64) {
+	if cur < prev {
+		sink.SetGauge(metric.GaugeMetric(name), 0)
+		return
+	}
+	sink.SetGauge(metric.GaugeMetric(name), float64(cur-prev)/elapsed)
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureDiskCollector возвращает сборщик, который читает данные из заданных значений.
+func newFixtureDiskCollector(t *testing.T, opts DiskOptions, counters *map[string]disk.IOCountersStat, clock *fakeClock) *DiskCollector {
+	t.Helper()
+
+	c, err := NewDiskCollector(0, opts)
+	require.NoError(t, err)
+
+	c.partitions = func(context.Context) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+			{Device: "/dev/sda2", Mountpoint: "/var/lib", Fstype: "ext4"},
+			{Device: "/dev/sda2", Mountpoint: "/var/lib", Fstype: "ext4"},
+			{Device: "/dev/loop0", Mountpoint: "/snap/core", Fstype: "squashfs"},
+		}, nil
+	}
+	c.usage = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		if path == "/snap/core" {
+			return nil, assert.AnError
+		}
+		return &disk.UsageStat{
+			Path: path, Total: 1000, Used: 250, Free: 750, UsedPercent: 25,
+			InodesTotal: 100, InodesUsed: 10, InodesFree: 90,
+		}, nil
+	}
+	c.ioCounters = func(context.Context) (map[string]disk.IOCountersStat, error) {
+		return *counters, nil
+	}
+	c.now = clock.Now
+
+	return c
+}
+
+func TestDiskCollector_Collect_Usage(t *testing.T) {
+	counters := map[string]disk.IOCountersStat{}
+	c := newFixtureDiskCollector(t, DiskOptions{}, &counters, newFakeClock(time.Unix(0, 0)))
+	sink := newTestSink(t)
+
+	err := c.Collect(context.Background(), sink)
+
+	assert.ErrorIs(t, err, assert.AnError, "unavailable mount should be reported")
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 1000.0, gauges["disk.fs.root.total_bytes"])
+	assert.Equal(t, 250.0, gauges["disk.fs.root.used_bytes"])
+	assert.Equal(t, 750.0, gauges["disk.fs.root.free_bytes"])
+	assert.Equal(t, 25.0, gauges["disk.fs.root.used_percent"])
+	assert.Equal(t, 100.0, gauges["disk.fs.root.inodes_total"])
+	assert.Equal(t, 10.0, gauges["disk.fs.root.inodes_used"])
+	assert.Equal(t, 90.0, gauges["disk.fs.root.inodes_free"])
+	assert.Contains(t, gauges, "disk.fs.var_lib.total_bytes")
+	assert.Len(t, gauges, 14)
+}
+
+func TestDiskCollector_Collect_MountFilter(t *testing.T) {
+	counters := map[string]disk.IOCountersStat{}
+	c := newFixtureDiskCollector(t, DiskOptions{
+		IncludeMounts: []string{"/var/*", "/snap/*"},
+		ExcludeMounts: []string{"/snap/*"},
+	}, &counters, newFakeClock(time.Unix(0, 0)))
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	gauges := sink.send(t).gauges()
+	assert.Contains(t, gauges, "disk.fs.var_lib.used_bytes")
+	assert.NotContains(t, gauges, "disk.fs.root.used_bytes")
+}
+
+func TestDiskCollector_Collect_LabelCollision(t *testing.T) {
+	counters := map[string]disk.IOCountersStat{
+		"dm.0": {Name: "dm.0", ReadBytes: 100},
+		"dm_0": {Name: "dm_0", ReadBytes: 200},
+	}
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureDiskCollector(t, DiskOptions{}, &counters, clock)
+	c.partitions = func(context.Context) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/"},
+			{Device: "/dev/sda2", Mountpoint: "/root"},
+			{Device: "/dev/sda3", Mountpoint: "/var/lib"},
+			{Device: "/dev/sda4", Mountpoint: "/var_lib"},
+		}, nil
+	}
+	sink := newTestSink(t)
+
+	err := c.Collect(context.Background(), sink)
+	assert.ErrorIs(t, err, ErrLabelCollision)
+	assert.ErrorContains(t, err, "'/' and '/root' both map to 'root'")
+	assert.ErrorContains(t, err, "'/var/lib' and '/var_lib' both map to 'var_lib'")
+
+	counters = map[string]disk.IOCountersStat{
+		"dm.0": {Name: "dm.0", ReadBytes: 300},
+		"dm_0": {Name: "dm_0", ReadBytes: 1200},
+	}
+	clock.Advance(time.Second)
+	err = c.Collect(context.Background(), sink)
+	assert.ErrorContains(t, err, "'dm.0' and 'dm_0' both map to 'dm_0'")
+
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 250.0, gauges["disk.fs.root.used_bytes"], "first mount keeps the label")
+	assert.Equal(t, 200.0, gauges["disk.io.dm_0.read_bytes_per_sec"], "the other device must not overwrite the rate")
+	assert.Len(t, gauges, 18)
+}
+
+func TestDiskCollector_Collect_IORates(t *testing.T) {
+	counters := map[string]disk.IOCountersStat{
+		"sda":   {Name: "sda", ReadBytes: 1000, WriteBytes: 2000, ReadCount: 10, WriteCount: 20},
+		"loop0": {Name: "loop0", ReadBytes: 1000},
+	}
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureDiskCollector(t, DiskOptions{
+		IncludeMounts:  []string{"/none"},
+		ExcludeDevices: []string{"loop*"},
+	}, &counters, clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+	assert.Empty(t, sink.send(t), "rates need two polls")
+
+	counters = map[string]disk.IOCountersStat{
+		"sda":   {Name: "sda", ReadBytes: 3000, WriteBytes: 2000, ReadCount: 30, WriteCount: 60},
+		"loop0": {Name: "loop0", ReadBytes: 5000},
+		"sdb":   {Name: "sdb", ReadBytes: 100},
+	}
+	clock.Advance(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	assert.Equal(t, map[string]float64{
+		"disk.io.sda.read_bytes_per_sec":  1000,
+		"disk.io.sda.write_bytes_per_sec": 0,
+		"disk.io.sda.reads_per_sec":       10,
+		"disk.io.sda.writes_per_sec":      20,
+	}, sink.send(t).gauges())
+
+	counters = map[string]disk.IOCountersStat{
+		"sda": {Name: "sda", ReadBytes: 10, WriteBytes: 4000, ReadCount: 30, WriteCount: 60},
+	}
+	clock.Advance(time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 0.0, gauges["disk.io.sda.read_bytes_per_sec"], "counter reset must not keep the previous rate")
+	assert.Equal(t, 2000.0, gauges["disk.io.sda.write_bytes_per_sec"])
+}
+
+func TestNewDiskCollector_InvalidPattern(t *testing.T) {
+	_, err := NewDiskCollector(0, DiskOptions{IncludeMounts: []string{"["}})
+	assert.Error(t, err)
+
+	_, err = NewDiskCollector(0, DiskOptions{ExcludeDevices: []string{"["}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// filter отбирает имена по шаблонам path.Match. Пустой список include
+// пропускает все имена, exclude имеет приоритет над include.
+type filter struct {
+	include []string
+	exclude []string
+}
+
+func newFilter(include, exclude []string) (filter, error) {
+	for _, pattern := range append(append([]string(nil), include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return filter{}, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+
+	return filter{include: include, exclude: exclude}, nil
+}
+
+func (f filter) match(name string) bool {
+	for _, pattern := range f.exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, pattern := range f.include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrLabelCollision означает, что разные имена превратились в одну метку метрики.
+var ErrLabelCollision = errors.New("metric label collision")
+
+var unsafeLabelChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// metricLabel превращает имя устройства или путь в часть имени метрики.
+// Корневой путь становится "root", остальные символы кроме букв, цифр, "_" и "-" заменяются на "_".
+func metricLabel(name string) string {
+	label := unsafeLabelChars.ReplaceAllString(name, "_")
+	label = strings.Trim(label, "_")
+	if label == "" {
+		return "root"
+	}
+	return label
+}
+
+// labelSet выдает метки метрик за один сбор и не дает двум разным именам
+// получить одну метку, например "/var/lib" и "/var_lib".
+type labelSet map[string]string
+
+// label возвращает метку для name или ErrLabelCollision, если метку уже получило другое имя.
+func (s labelSet) label(name string) (string, error) {
+	label := metricLabel(name)
+	if other, ok := s[label]; ok && other != name {
+		return "", fmt.Errorf("%w: '%s' and '%s' both map to '%s'", ErrLabelCollision, other, name, label)
+	}
+	s[label] = name
+	return label, nil
+}
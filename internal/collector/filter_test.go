@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		value    string
+		expected bool
+	}{
+		{name: "no patterns", value: "/var", expected: true},
+		{name: "included", include: []string{"/var*"}, value: "/var", expected: true},
+		{name: "not included", include: []string{"/var*"}, value: "/home", expected: false},
+		{name: "excluded", exclude: []string{"loop*"}, value: "loop0", expected: false},
+		{name: "exclude wins", include: []string{"sd*"}, exclude: []string{"sda"}, value: "sda", expected: false},
+		{name: "include after exclude", include: []string{"sd*"}, exclude: []string{"sda"}, value: "sdb", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newFilter(tt.include, tt.exclude)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, f.match(tt.value))
+		})
+	}
+}
+
+func TestNewFilter_InvalidPattern(t *testing.T) {
+	_, err := newFilter([]string{"["}, nil)
+	assert.Error(t, err)
+
+	_, err = newFilter(nil, []string{"[a-"})
+	assert.Error(t, err)
+}
+
+func TestMetricLabel(t *testing.T) {
+	tests := map[string]string{
+		"/":            "root",
+		"/var/lib":     "var_lib",
+		"/mnt/my disk": "mnt_my_disk",
+		"sda1":         "sda1",
+		"nvme0n1":      "nvme0n1",
+		"eth0.100":     "eth0_100",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, metricLabel(name), name)
+	}
+}
+
+func TestLabelSet(t *testing.T) {
+	labels := make(labelSet)
+
+	label, err := labels.label("/var/lib")
+	require.NoError(t, err)
+	assert.Equal(t, "var_lib", label)
+
+	label, err = labels.label("/var/lib")
+	require.NoError(t, err, "the same name may be labeled again")
+	assert.Equal(t, "var_lib", label)
+
+	_, err = labels.label("/var_lib")
+	assert.ErrorIs(t, err, ErrLabelCollision)
+}
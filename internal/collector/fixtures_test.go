@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock - часы сборщика, которые тест переводит вручную.
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// Advance переводит часы вперед на d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// testSink - метрики агента, которые отправляются на тестовый сервер.
+// Сборщики пишут в него как в обычный Sink, а send возвращает пакет,
+// который получил сервер, поэтому тесты проверяют то, что уходит с агента.
+type testSink struct {
+	*metric.Metrics
+
+	mu    sync.Mutex
+	batch model.Metrics
+}
+
+func newTestSink(t *testing.T) *testSink {
+	t.Helper()
+
+	log, err := logger.NewZapLogger("fatal", false)
+	require.NoError(t, err)
+
+	s := &testSink{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var batch model.Metrics
+		if err := json.NewDecoder(gz).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.batch = batch
+	}))
+	t.Cleanup(server.Close)
+
+	s.Metrics = metric.NewMetrics(strings.TrimPrefix(server.URL, "http://"), log, false, "", nil)
+	return s
+}
+
+// sentBatch - отправленный пакет метрик по именам.
+type sentBatch map[string]model.Metric
+
+func (b sentBatch) gauges() map[string]float64 {
+	gauges := make(map[string]float64)
+	for id, m := range b {
+		if m.MType == metric.Gauge {
+			gauges[id] = *m.Value
+		}
+	}
+	return gauges
+}
+
+func (b sentBatch) counters() map[string]int64 {
+	counters := make(map[string]int64)
+	for id, m := range b {
+		if m.MType == metric.Counter {
+			counters[id] = *m.Delta
+		}
+	}
+	return counters
+}
+
+// send отправляет накопленные метрики и возвращает полученный сервером пакет.
+// Если отправлять нечего, возвращает пустой пакет.
+func (s *testSink) send(t *testing.T) sentBatch {
+	t.Helper()
+
+	s.mu.Lock()
+	s.batch = nil
+	s.mu.Unlock()
+
+	s.SendMetrics()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sent := make(sentBatch, len(s.batch))
+	for _, m := range s.batch {
+		require.NotContains(t, sent, m.ID, "metric sent twice in one batch")
+		sent[m.ID] = m
+	}
+	return sent
+}
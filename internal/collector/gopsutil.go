@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+const GopsutilName = "gopsutil"
+
+// GopsutilCollector собирает объем памяти системы и загрузку каждого процессора.
+// Загрузка считается с предыдущего сбора, первый сбор возвращает загрузку с момента старта системы.
+type GopsutilCollector struct {
+	interval time.Duration
+}
+
+func NewGopsutilCollector(interval time.Duration) *GopsutilCollector {
+	return &GopsutilCollector{interval: interval}
+}
+
+func (c *GopsutilCollector) Name() string {
+	return GopsutilName
+}
+
+func (c *GopsutilCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *GopsutilCollector) Collect(ctx context.Context, sink Sink) error {
+	vmStat, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("collector.GopsutilCollector.Collect: failed to get virtual memory stats: %w", err)
+	}
+	sink.SetGauge(metric.GaugeMetric("TotalMemory"), float64(vmStat.Total))
+	sink.SetGauge(metric.GaugeMetric("FreeMemory"), float64(vmStat.Free))
+
+	cpuPercentages, err := cpu.PercentWithContext(ctx, 0, true)
+	if err != nil {
+		return fmt.Errorf("collector.GopsutilCollector.Collect: failed to get cpu percent: %w", err)
+	}
+	for i, cpuPercent := range cpuPercentages {
+		sink.SetGauge(metric.GaugeMetric(fmt.Sprintf("CPUutilization%d", i+1)), cpuPercent)
+	}
+
+	return nil
+}
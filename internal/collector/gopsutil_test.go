@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGopsutilCollector_Collect(t *testing.T) {
+	metrics := metric.NewMetrics("localhost:8080", nil, false, "", nil)
+	c := NewGopsutilCollector(0)
+
+	require.NoError(t, c.Collect(context.Background(), metrics))
+
+	totalMemory, exists := metrics.Gauges[metric.GaugeMetric("TotalMemory")]
+	assert.True(t, exists, "TotalMemory should be collected")
+	assert.Greater(t, totalMemory, 0.0)
+
+	_, exists = metrics.Gauges[metric.GaugeMetric("FreeMemory")]
+	assert.True(t, exists, "FreeMemory should be collected")
+
+	_, exists = metrics.Gauges[metric.GaugeMetric("CPUutilization1")]
+	assert.True(t, exists, "CPUutilization1 should be collected")
+}
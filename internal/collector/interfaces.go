@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"time"
 
 	"github.com/NoobyTheTurtle/metrics/internal/logger"
@@ -9,19 +10,35 @@ import (
 
 type CollectorLogger interface {
 	Info(format string, args ...any)
+	Error(format string, args ...any)
 }
 
-type GopsutilMetrics interface {
-	CollectGopsutilMetrics() error
-	InitGopsutilMetrics(pollInterval time.Duration) error
+// Sink принимает значения, собранные сборщиками.
+type Sink interface {
+	SetGauge(name metric.GaugeMetric, value float64)
+	AddCounter(name metric.CounterMetric, delta int64)
+	Observe(name metric.HistogramMetric, value float64)
 }
 
-type MetricsCollector interface {
-	GopsutilMetrics
-	UpdateMetrics()
+// Collector - подключаемый сборщик метрик агента.
+type Collector interface {
+	// Name возвращает имя сборщика, под которым он задается в конфигурации.
+	Name() string
+	// Interval возвращает период сбора. Ноль означает период опроса агента.
+	Interval() time.Duration
+	// Collect собирает значения и записывает их в sink.
+	Collect(ctx context.Context, sink Sink) error
 }
 
 var (
-	_ CollectorLogger  = (*logger.ZapLogger)(nil)
-	_ MetricsCollector = (*metric.Metrics)(nil)
+	_ CollectorLogger = (*logger.ZapLogger)(nil)
+	_ Sink            = (*metric.Metrics)(nil)
+
+	_ Collector = (*MemStatsCollector)(nil)
+	_ Collector = (*RandomCollector)(nil)
+	_ Collector = (*GopsutilCollector)(nil)
+	_ Collector = (*DiskCollector)(nil)
+	_ Collector = (*NetCollector)(nil)
+	_ Collector = (*ProcessCollector)(nil)
+	_ Collector = (*CgroupCollector)(nil)
 )
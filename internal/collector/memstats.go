@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+)
+
+const MemStatsName = "memstats"
+
+// MemStatsCollector собирает статистику runtime.MemStats и считает опросы в PollCount.
+type MemStatsCollector struct {
+	interval time.Duration
+}
+
+func NewMemStatsCollector(interval time.Duration) *MemStatsCollector {
+	return &MemStatsCollector{interval: interval}
+}
+
+func (c *MemStatsCollector) Name() string {
+	return MemStatsName
+}
+
+func (c *MemStatsCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *MemStatsCollector) Collect(_ context.Context, sink Sink) error {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	for _, m := range metric.MemStatsMetrics {
+		sink.SetGauge(m.Metric, m.GetValue(&memStats))
+	}
+	sink.AddCounter(metric.PollCount, 1)
+
+	return nil
+}
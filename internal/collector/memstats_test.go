@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemStatsCollector_Collect(t *testing.T) {
+	metrics := metric.NewMetrics("localhost:8080", nil, false, "", nil)
+	c := NewMemStatsCollector(0)
+
+	require.NoError(t, c.Collect(context.Background(), metrics))
+	require.NoError(t, c.Collect(context.Background(), metrics))
+
+	requiredGauges := []metric.GaugeMetric{
+		metric.Alloc, metric.BuckHashSys, metric.Frees, metric.GCCPUFraction, metric.GCSys, metric.HeapAlloc,
+		metric.HeapIdle, metric.HeapInuse, metric.HeapObjects, metric.HeapReleased, metric.HeapSys,
+		metric.LastGC, metric.Lookups, metric.MCacheInuse, metric.MCacheSys, metric.MSpanInuse,
+		metric.MSpanSys, metric.Mallocs, metric.NextGC, metric.NumForcedGC, metric.NumGC, metric.OtherSys,
+		metric.PauseTotalNs, metric.StackInuse, metric.StackSys, metric.Sys, metric.TotalAlloc,
+	}
+	for _, name := range requiredGauges {
+		_, exists := metrics.Gauges[name]
+		assert.True(t, exists, "%s should exist after Collect", name)
+	}
+
+	_, exists := metrics.Gauges[metric.RandomValue]
+	assert.False(t, exists, "RandomValue should not be set by memstats collector")
+	assert.Equal(t, int64(2), metrics.Counters[metric.PollCount])
+	assert.Equal(t, MemStatsName, c.Name())
+}
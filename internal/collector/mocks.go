@@ -10,9 +10,11 @@
 package collector
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
+	metric "github.com/NoobyTheTurtle/metrics/internal/metric"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,6 +42,23 @@ func (m *MockCollectorLogger) EXPECT() *MockCollectorLoggerMockRecorder {
 	return m.recorder
 }
 
+// Error mocks base method.
+func (m *MockCollectorLogger) Error(format string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockCollectorLoggerMockRecorder) Error(format any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockCollectorLogger)(nil).Error), varargs...)
+}
+
 // Info mocks base method.
 func (m *MockCollectorLogger) Info(format string, args ...any) {
 	m.ctrl.T.Helper()
@@ -57,118 +76,128 @@ func (mr *MockCollectorLoggerMockRecorder) Info(format any, args ...any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockCollectorLogger)(nil).Info), varargs...)
 }
 
-// MockGopsutilMetrics is a mock of GopsutilMetrics interface.
-type MockGopsutilMetrics struct {
+// MockSink is a mock of Sink interface.
+type MockSink struct {
 	ctrl     *gomock.Controller
-	recorder *MockGopsutilMetricsMockRecorder
+	recorder *MockSinkMockRecorder
 	isgomock struct{}
 }
 
-// MockGopsutilMetricsMockRecorder is the mock recorder for MockGopsutilMetrics.
-type MockGopsutilMetricsMockRecorder struct {
-	mock *MockGopsutilMetrics
+// MockSinkMockRecorder is the mock recorder for MockSink.
+type MockSinkMockRecorder struct {
+	mock *MockSink
 }
 
-// NewMockGopsutilMetrics creates a new mock instance.
-func NewMockGopsutilMetrics(ctrl *gomock.Controller) *MockGopsutilMetrics {
-	mock := &MockGopsutilMetrics{ctrl: ctrl}
-	mock.recorder = &MockGopsutilMetricsMockRecorder{mock}
+// NewMockSink creates a new mock instance.
+func NewMockSink(ctrl *gomock.Controller) *MockSink {
+	mock := &MockSink{ctrl: ctrl}
+	mock.recorder = &MockSinkMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockGopsutilMetrics) EXPECT() *MockGopsutilMetricsMockRecorder {
+func (m *MockSink) EXPECT() *MockSinkMockRecorder {
 	return m.recorder
 }
 
-// CollectGopsutilMetrics mocks base method.
-func (m *MockGopsutilMetrics) CollectGopsutilMetrics() error {
+// AddCounter mocks base method.
+func (m *MockSink) AddCounter(name metric.CounterMetric, delta int64) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CollectGopsutilMetrics")
-	ret0, _ := ret[0].(error)
-	return ret0
+	m.ctrl.Call(m, "AddCounter", name, delta)
 }
 
-// CollectGopsutilMetrics indicates an expected call of CollectGopsutilMetrics.
-func (mr *MockGopsutilMetricsMockRecorder) CollectGopsutilMetrics() *gomock.Call {
+// AddCounter indicates an expected call of AddCounter.
+func (mr *MockSinkMockRecorder) AddCounter(name, delta any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGopsutilMetrics", reflect.TypeOf((*MockGopsutilMetrics)(nil).CollectGopsutilMetrics))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCounter", reflect.TypeOf((*MockSink)(nil).AddCounter), name, delta)
 }
 
-// InitGopsutilMetrics mocks base method.
-func (m *MockGopsutilMetrics) InitGopsutilMetrics(pollInterval time.Duration) error {
+// Observe mocks base method.
+func (m *MockSink) Observe(name metric.HistogramMetric, value float64) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InitGopsutilMetrics", pollInterval)
-	ret0, _ := ret[0].(error)
-	return ret0
+	m.ctrl.Call(m, "Observe", name, value)
 }
 
-// InitGopsutilMetrics indicates an expected call of InitGopsutilMetrics.
-func (mr *MockGopsutilMetricsMockRecorder) InitGopsutilMetrics(pollInterval any) *gomock.Call {
+// Observe indicates an expected call of Observe.
+func (mr *MockSinkMockRecorder) Observe(name, value any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitGopsutilMetrics", reflect.TypeOf((*MockGopsutilMetrics)(nil).InitGopsutilMetrics), pollInterval)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockSink)(nil).Observe), name, value)
+}
+
+// SetGauge mocks base method.
+func (m *MockSink) SetGauge(name metric.GaugeMetric, value float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetGauge", name, value)
 }
 
-// MockMetricsCollector is a mock of MetricsCollector interface.
-type MockMetricsCollector struct {
+// SetGauge indicates an expected call of SetGauge.
+func (mr *MockSinkMockRecorder) SetGauge(name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGauge", reflect.TypeOf((*MockSink)(nil).SetGauge), name, value)
+}
+
+// MockCollector is a mock of Collector interface.
+type MockCollector struct {
 	ctrl     *gomock.Controller
-	recorder *MockMetricsCollectorMockRecorder
+	recorder *MockCollectorMockRecorder
 	isgomock struct{}
 }
 
-// MockMetricsCollectorMockRecorder is the mock recorder for MockMetricsCollector.
-type MockMetricsCollectorMockRecorder struct {
-	mock *MockMetricsCollector
+// MockCollectorMockRecorder is the mock recorder for MockCollector.
+type MockCollectorMockRecorder struct {
+	mock *MockCollector
 }
 
-// NewMockMetricsCollector creates a new mock instance.
-func NewMockMetricsCollector(ctrl *gomock.Controller) *MockMetricsCollector {
-	mock := &MockMetricsCollector{ctrl: ctrl}
-	mock.recorder = &MockMetricsCollectorMockRecorder{mock}
+// NewMockCollector creates a new mock instance.
+func NewMockCollector(ctrl *gomock.Controller) *MockCollector {
+	mock := &MockCollector{ctrl: ctrl}
+	mock.recorder = &MockCollectorMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockMetricsCollector) EXPECT() *MockMetricsCollectorMockRecorder {
+func (m *MockCollector) EXPECT() *MockCollectorMockRecorder {
 	return m.recorder
 }
 
-// CollectGopsutilMetrics mocks base method.
-func (m *MockMetricsCollector) CollectGopsutilMetrics() error {
+// Collect mocks base method.
+func (m *MockCollector) Collect(ctx context.Context, sink Sink) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CollectGopsutilMetrics")
+	ret := m.ctrl.Call(m, "Collect", ctx, sink)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CollectGopsutilMetrics indicates an expected call of CollectGopsutilMetrics.
-func (mr *MockMetricsCollectorMockRecorder) CollectGopsutilMetrics() *gomock.Call {
+// Collect indicates an expected call of Collect.
+func (mr *MockCollectorMockRecorder) Collect(ctx, sink any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGopsutilMetrics", reflect.TypeOf((*MockMetricsCollector)(nil).CollectGopsutilMetrics))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockCollector)(nil).Collect), ctx, sink)
 }
 
-// InitGopsutilMetrics mocks base method.
-func (m *MockMetricsCollector) InitGopsutilMetrics(pollInterval time.Duration) error {
+// Interval mocks base method.
+func (m *MockCollector) Interval() time.Duration {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InitGopsutilMetrics", pollInterval)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "Interval")
+	ret0, _ := ret[0].(time.Duration)
 	return ret0
 }
 
-// InitGopsutilMetrics indicates an expected call of InitGopsutilMetrics.
-func (mr *MockMetricsCollectorMockRecorder) InitGopsutilMetrics(pollInterval any) *gomock.Call {
+// Interval indicates an expected call of Interval.
+func (mr *MockCollectorMockRecorder) Interval() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitGopsutilMetrics", reflect.TypeOf((*MockMetricsCollector)(nil).InitGopsutilMetrics), pollInterval)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Interval", reflect.TypeOf((*MockCollector)(nil).Interval))
 }
 
-// UpdateMetrics mocks base method.
-func (m *MockMetricsCollector) UpdateMetrics() {
+// Name mocks base method.
+func (m *MockCollector) Name() string {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "UpdateMetrics")
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
 }
 
-// UpdateMetrics indicates an expected call of UpdateMetrics.
-func (mr *MockMetricsCollectorMockRecorder) UpdateMetrics() *gomock.Call {
+// Name indicates an expected call of Name.
+func (mr *MockCollectorMockRecorder) Name() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetrics", reflect.TypeOf((*MockMetricsCollector)(nil).UpdateMetrics))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockCollector)(nil).Name))
 }
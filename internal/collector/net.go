@@ -0,0 +1,211 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+const NetName = "net"
+
+// tcpStates - имена состояний TCP по кодам из /proc/net/tcp.
+var tcpStates = map[uint64]string{
+	0x01: "established",
+	0x02: "syn_sent",
+	0x03: "syn_recv",
+	0x04: "fin_wait1",
+	0x05: "fin_wait2",
+	0x06: "time_wait",
+	0x07: "close",
+	0x08: "close_wait",
+	0x09: "last_ack",
+	0x0A: "listen",
+	0x0B: "closing",
+}
+
+// NetOptions - настройки сетевого сборщика. Шаблоны интерфейсов имеют синтаксис path.Match.
+// ProcPath задает корень procfs, из которого читаются счетчики интерфейсов в Linux
+// и состояния TCP соединений.
+type NetOptions struct {
+	IncludeInterfaces []string `json:"include_interfaces"`
+	ExcludeInterfaces []string `json:"exclude_interfaces"`
+	ProcPath          string   `json:"proc_path"`
+}
+
+// NetCollector собирает счетчики сетевых интерфейсов и число TCP соединений по состояниям.
+// Счетчики интерфейсов net.if.<интерфейс>.* передаются как counter метрики с приращением
+// между сборами и как gauge метрики со скоростью в секунду с суффиксом _per_sec.
+// Число соединений передается в gauge метриках net.tcp.<состояние>.
+type NetCollector struct {
+	interval   time.Duration
+	interfaces filter
+	procPath   string
+
+	ioCounters func(ctx context.Context) ([]net.IOCountersStat, error)
+	now        func() time.Time
+
+	prevCounters map[string]net.IOCountersStat
+	prevTime     time.Time
+}
+
+func NewNetCollector(interval time.Duration, opts NetOptions) (*NetCollector, error) {
+	interfaces, err := newFilter(opts.IncludeInterfaces, opts.ExcludeInterfaces)
+	if err != nil {
+		return nil, fmt.Errorf("collector.NewNetCollector: interfaces: %w", err)
+	}
+
+	procPath := opts.ProcPath
+	if procPath == "" {
+		procPath = "/proc"
+	}
+
+	c := &NetCollector{
+		interval:   interval,
+		interfaces: interfaces,
+		procPath:   procPath,
+		now:        time.Now,
+	}
+	c.ioCounters = func(ctx context.Context) ([]net.IOCountersStat, error) {
+		return net.IOCountersByFileWithContext(ctx, true, filepath.Join(c.procPath, "net", "dev"))
+	}
+
+	return c, nil
+}
+
+func (c *NetCollector) Name() string {
+	return NetName
+}
+
+func (c *NetCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *NetCollector) Collect(ctx context.Context, sink Sink) error {
+	return errors.Join(c.collectInterfaces(ctx, sink), c.collectTCP(sink))
+}
+
+func (c *NetCollector) collectInterfaces(ctx context.Context, sink Sink) error {
+	stats, err := c.ioCounters(ctx)
+	if err != nil {
+		return fmt.Errorf("collector.NetCollector.Collect: failed to get interface counters: %w", err)
+	}
+	now := c.now()
+
+	var errs []error
+	elapsed := now.Sub(c.prevTime).Seconds()
+	counters := make(map[string]net.IOCountersStat, len(stats))
+	labels := make(labelSet, len(stats))
+	for _, cur := range stats {
+		if !c.interfaces.match(cur.Name) {
+			continue
+		}
+		counters[cur.Name] = cur
+
+		label, err := labels.label(cur.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collector.NetCollector.Collect: %w", err))
+			continue
+		}
+
+		prev, ok := c.prevCounters[cur.Name]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+
+		prefix := "net.if." + label + "."
+		for _, v := range []struct {
+			name      string
+			prev, cur uint64
+		}{
+			{"bytes_sent", prev.BytesSent, cur.BytesSent},
+			{"bytes_recv", prev.BytesRecv, cur.BytesRecv},
+			{"packets_sent", prev.PacketsSent, cur.PacketsSent},
+			{"packets_recv", prev.PacketsRecv, cur.PacketsRecv},
+			{"errors_in", prev.Errin, cur.Errin},
+			{"errors_out", prev.Errout, cur.Errout},
+			{"drops_in", prev.Dropin, cur.Dropin},
+			{"drops_out", prev.Dropout, cur.Dropout},
+		} {
+			// счетчик уменьшился после пересоздания интерфейса, приращение неизвестно
+			if v.cur >= v.prev {
+				sink.AddCounter(metric.CounterMetric(prefix+v.name), int64(v.cur-v.prev))
+			}
+			setRate(sink, prefix+v.name+"_per_sec", v.prev, v.cur, elapsed)
+		}
+	}
+
+	c.prevCounters = counters
+	c.prevTime = now
+
+	return errors.Join(errs...)
+}
+
+// collectTCP считает TCP соединения IPv4 и IPv6 по состояниям.
+// Отсутствующие файлы пропускаются: tcp6 нет при отключенном IPv6, procfs нет вне Linux.
+func (c *NetCollector) collectTCP(sink Sink) error {
+	counts := make(map[string]int, len(tcpStates))
+	found := false
+
+	for _, name := range []string{"tcp", "tcp6"} {
+		err := countTCPStates(filepath.Join(c.procPath, "net", name), counts)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("collector.NetCollector.Collect: %w", err)
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+
+	for _, state := range tcpStates {
+		sink.SetGauge(metric.GaugeMetric("net.tcp."+state), float64(counts[state]))
+	}
+
+	return nil
+}
+
+// countTCPStates разбирает таблицу соединений в формате /proc/net/tcp.
+func countTCPStates(path string, counts map[string]int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// первая строка - заголовок таблицы
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		code, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return fmt.Errorf("invalid state '%s' in '%s': %w", fields[3], path, err)
+		}
+		if state, ok := tcpStates[code]; ok {
+			counts[state]++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package collector
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureNetCollector возвращает сборщик, который читает procfs из testdata/net/<root>.
+func newFixtureNetCollector(t *testing.T, opts NetOptions, root string, clock *fakeClock) *NetCollector {
+	t.Helper()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("interface counters are read from procfs only on linux")
+	}
+
+	opts.ProcPath = "testdata/net/" + root
+	c, err := NewNetCollector(0, opts)
+	require.NoError(t, err)
+	c.now = clock.Now
+
+	return c
+}
+
+func TestNetCollector_Collect_Interfaces(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureNetCollector(t, NetOptions{ExcludeInterfaces: []string{"lo"}}, "first", clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+	assert.Empty(t, sink.send(t).counters(), "counters need two polls")
+
+	c.procPath = "testdata/net/second"
+	clock.Advance(2 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	batch := sink.send(t)
+	assert.Equal(t, map[string]int64{
+		"net.if.eth0.bytes_sent":   4000,
+		"net.if.eth0.bytes_recv":   20000,
+		"net.if.eth0.packets_sent": 10,
+		"net.if.eth0.packets_recv": 200,
+		"net.if.eth0.errors_in":    0,
+		"net.if.eth0.errors_out":   0,
+		"net.if.eth0.drops_in":     4,
+		"net.if.eth0.drops_out":    0,
+	}, batch.counters())
+
+	gauges := batch.gauges()
+	assert.Equal(t, 2000.0, gauges["net.if.eth0.bytes_sent_per_sec"])
+	assert.Equal(t, 10000.0, gauges["net.if.eth0.bytes_recv_per_sec"])
+	assert.Equal(t, 5.0, gauges["net.if.eth0.packets_sent_per_sec"])
+	assert.Equal(t, 100.0, gauges["net.if.eth0.packets_recv_per_sec"])
+	assert.Equal(t, 2.0, gauges["net.if.eth0.drops_in_per_sec"])
+	assert.NotContains(t, gauges, "net.if.lo.bytes_sent_per_sec")
+	assert.NotContains(t, gauges, "net.if.wlan0.bytes_sent_per_sec", "new interface needs two polls")
+
+	c.procPath = "testdata/net/first"
+	clock.Advance(time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	batch = sink.send(t)
+	assert.NotContains(t, batch.counters(), "net.if.eth0.bytes_sent", "counter reset should not add delta")
+	assert.Equal(t, 0.0, batch.gauges()["net.if.eth0.bytes_sent_per_sec"], "counter reset must not keep the previous rate")
+}
+
+func TestNetCollector_Collect_SendsDeltas(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	c := newFixtureNetCollector(t, NetOptions{IncludeInterfaces: []string{"eth0"}}, "first", clock)
+	sink := newTestSink(t)
+
+	var sent []int64
+	for _, root := range []string{"second", "first", "second"} {
+		require.NoError(t, c.Collect(context.Background(), sink))
+		c.procPath = "testdata/net/" + root
+		clock.Advance(time.Second)
+		require.NoError(t, c.Collect(context.Background(), sink))
+
+		sent = append(sent, sink.send(t).counters()["net.if.eth0.bytes_sent"])
+	}
+
+	// сервер прибавляет каждое значение к счетчику, поэтому повторная отправка
+	// уже отправленного приращения завысила бы итог
+	assert.Equal(t, []int64{4000, 0, 4000}, sent)
+}
+
+func TestNetCollector_Collect_TCPStates(t *testing.T) {
+	c := newFixtureNetCollector(t, NetOptions{}, "first", newFakeClock(time.Unix(100, 0)))
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 3.0, gauges["net.tcp.listen"])
+	assert.Equal(t, 2.0, gauges["net.tcp.established"])
+	assert.Equal(t, 1.0, gauges["net.tcp.time_wait"])
+	assert.Equal(t, 1.0, gauges["net.tcp.close_wait"])
+	assert.Equal(t, 0.0, gauges["net.tcp.syn_sent"], "all states should be reported")
+	assert.Contains(t, gauges, "net.tcp.closing")
+
+	c.procPath = "testdata/net/second"
+	require.NoError(t, c.Collect(context.Background(), sink), "missing tcp6 should be skipped")
+	assert.Equal(t, 2.0, sink.send(t).gauges()["net.tcp.listen"])
+}
+
+func TestNetCollector_Collect_Errors(t *testing.T) {
+	c := newFixtureNetCollector(t, NetOptions{}, "invalid", newFakeClock(time.Unix(100, 0)))
+	sink := newTestSink(t)
+
+	err := c.Collect(context.Background(), sink)
+
+	assert.ErrorContains(t, err, "failed to get interface counters")
+	assert.ErrorContains(t, err, "invalid state 'ZZ'")
+}
+
+func TestNewNetCollector(t *testing.T) {
+	c, err := NewNetCollector(time.Second, NetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "/proc", c.procPath)
+	assert.Equal(t, NetName, c.Name())
+	assert.Equal(t, time.Second, c.Interval())
+
+	_, err = NewNetCollector(0, NetOptions{IncludeInterfaces: []string{"["}})
+	assert.Error(t, err)
+}
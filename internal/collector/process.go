@@ -0,0 +1,340 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+const ProcessName = "process"
+
+var ErrInvalidProcessGroup = errors.New("invalid process group")
+
+// ProcessGroup описывает группу процессов. Процессы выбираются ровно одним способом:
+// по имени исполняемого файла, по регулярному выражению для командной строки или по pid файлу.
+type ProcessGroup struct {
+	Name        string `json:"name"`
+	ProcessName string `json:"process_name"`
+	Cmdline     string `json:"cmdline"`
+	Pidfile     string `json:"pidfile"`
+}
+
+// ProcessOptions - настройки сборщика процессов.
+type ProcessOptions struct {
+	Groups []ProcessGroup `json:"groups"`
+}
+
+// processStats - снимок потребления ресурсов процессом.
+// fds равно -1, если агенту не хватает прав прочитать дескрипторы процесса.
+type processStats struct {
+	createTime time.Time
+	cpuTime    float64
+	rss        uint64
+	fds        int32
+	threads    int32
+}
+
+// pidfileClockSkew - допустимое опережение времени запуска процесса относительно
+// записи pid файла. Время запуска процесса известно с точностью до секунды.
+const pidfileClockSkew = 2 * time.Second
+
+// processReader читает сведения о процессах системы.
+type processReader interface {
+	Pids(ctx context.Context) ([]int32, error)
+	Name(ctx context.Context, pid int32) (string, error)
+	Cmdline(ctx context.Context, pid int32) (string, error)
+	Stats(ctx context.Context, pid int32) (processStats, error)
+}
+
+type processMatcher struct {
+	name    string
+	label   string
+	process string
+	cmdline *regexp.Regexp
+	pidfile string
+}
+
+type cpuSample struct {
+	createTime time.Time
+	cpuTime    float64
+	at         time.Time
+}
+
+// ProcessCollector собирает потребление ресурсов группами процессов в метриках
+// process.<группа>.count, cpu_percent, rss_bytes, open_fds, threads и uptime_seconds.
+// Значения процессов группы складываются, uptime_seconds берется по самому старому процессу.
+// Процессы выбираются заново при каждом сборе, поэтому перезапуск с новым PID
+// не требует настройки. Загрузка процессора считается с предыдущего сбора, для нового
+// процесса - со времени его запуска. open_fds учитывает только процессы,
+// дескрипторы которых агент может прочитать. Процесс из pid файла, запущенный
+// после записи файла, считается чужим: его PID освободился и был занят заново.
+type ProcessCollector struct {
+	interval time.Duration
+	groups   []processMatcher
+	reader   processReader
+	now      func() time.Time
+
+	samples map[int32]cpuSample
+}
+
+func NewProcessCollector(interval time.Duration, opts ProcessOptions) (*ProcessCollector, error) {
+	groups := make([]processMatcher, 0, len(opts.Groups))
+	seen := make(map[string]bool, len(opts.Groups))
+	var errs []error
+
+	for i, g := range opts.Groups {
+		m, err := newProcessMatcher(g)
+		if err == nil && seen[m.label] {
+			err = fmt.Errorf("%w: duplicate name '%s'", ErrInvalidProcessGroup, g.Name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("group %d: %w", i, err))
+			continue
+		}
+		seen[m.label] = true
+		groups = append(groups, m)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("collector.NewProcessCollector: %w", err)
+	}
+
+	return &ProcessCollector{
+		interval: interval,
+		groups:   groups,
+		reader:   gopsutilProcesses{},
+		now:      time.Now,
+		samples:  make(map[int32]cpuSample),
+	}, nil
+}
+
+func newProcessMatcher(g ProcessGroup) (processMatcher, error) {
+	if g.Name == "" {
+		return processMatcher{}, fmt.Errorf("%w: name is required", ErrInvalidProcessGroup)
+	}
+
+	selectors := 0
+	for _, s := range []string{g.ProcessName, g.Cmdline, g.Pidfile} {
+		if s != "" {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		return processMatcher{}, fmt.Errorf("%w: '%s' must set exactly one of process_name, cmdline or pidfile", ErrInvalidProcessGroup, g.Name)
+	}
+
+	m := processMatcher{
+		name:    g.Name,
+		label:   metricLabel(g.Name),
+		process: g.ProcessName,
+		pidfile: g.Pidfile,
+	}
+	if g.Cmdline != "" {
+		re, err := regexp.Compile(g.Cmdline)
+		if err != nil {
+			return processMatcher{}, fmt.Errorf("%w: '%s' cmdline: %w", ErrInvalidProcessGroup, g.Name, err)
+		}
+		m.cmdline = re
+	}
+
+	return m, nil
+}
+
+func (c *ProcessCollector) Name() string {
+	return ProcessName
+}
+
+func (c *ProcessCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *ProcessCollector) Collect(ctx context.Context, sink Sink) error {
+	now := c.now()
+
+	var pids []int32
+	if c.needsScan() {
+		var err error
+		pids, err = c.reader.Pids(ctx)
+		if err != nil {
+			return fmt.Errorf("collector.ProcessCollector.Collect: failed to list processes: %w", err)
+		}
+	}
+
+	var errs []error
+	samples := make(map[int32]cpuSample, len(c.samples))
+	for _, g := range c.groups {
+		matched, startedBefore, err := c.match(ctx, g, pids)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collector.ProcessCollector.Collect: group '%s': %w", g.name, err))
+		}
+
+		var count, fds, threads int64
+		var cpuPercent, rss, uptime float64
+		for _, pid := range matched {
+			stats, err := c.reader.Stats(ctx, pid)
+			if isProcessGone(err) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("collector.ProcessCollector.Collect: group '%s': pid %d: %w", g.name, pid, err))
+				continue
+			}
+			if !startedBefore.IsZero() && stats.createTime.After(startedBefore) {
+				continue
+			}
+
+			count++
+			cpuPercent += c.cpuPercent(pid, stats, now)
+			rss += float64(stats.rss)
+			if stats.fds >= 0 {
+				fds += int64(stats.fds)
+			}
+			threads += int64(stats.threads)
+			uptime = max(uptime, now.Sub(stats.createTime).Seconds())
+			samples[pid] = cpuSample{createTime: stats.createTime, cpuTime: stats.cpuTime, at: now}
+		}
+
+		prefix := "process." + g.label + "."
+		sink.SetGauge(metric.GaugeMetric(prefix+"count"), float64(count))
+		sink.SetGauge(metric.GaugeMetric(prefix+"cpu_percent"), cpuPercent)
+		sink.SetGauge(metric.GaugeMetric(prefix+"rss_bytes"), rss)
+		sink.SetGauge(metric.GaugeMetric(prefix+"open_fds"), float64(fds))
+		sink.SetGauge(metric.GaugeMetric(prefix+"threads"), float64(threads))
+		sink.SetGauge(metric.GaugeMetric(prefix+"uptime_seconds"), uptime)
+	}
+	// завершившиеся процессы не попадают в новый набор снимков
+	c.samples = samples
+
+	return errors.Join(errs...)
+}
+
+func (c *ProcessCollector) needsScan() bool {
+	for _, g := range c.groups {
+		if g.pidfile == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// match возвращает PID процессов группы. Отсутствующий pid файл означает, что процесс не запущен.
+// Для pid файла также возвращается время, позже которого процесс группы запуститься не мог.
+func (c *ProcessCollector) match(ctx context.Context, g processMatcher, pids []int32) ([]int32, time.Time, error) {
+	if g.pidfile != "" {
+		info, err := os.Stat(g.pidfile)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, nil
+		}
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		data, err := os.ReadFile(g.pidfile)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		pid, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+		if err != nil || pid <= 0 {
+			return nil, time.Time{}, fmt.Errorf("invalid pid in '%s'", g.pidfile)
+		}
+		return []int32{int32(pid)}, info.ModTime().Add(pidfileClockSkew), nil
+	}
+
+	var matched []int32
+	for _, pid := range pids {
+		var ok bool
+		if g.cmdline != nil {
+			cmdline, err := c.reader.Cmdline(ctx, pid)
+			ok = err == nil && g.cmdline.MatchString(cmdline)
+		} else {
+			name, err := c.reader.Name(ctx, pid)
+			ok = err == nil && name == g.process
+		}
+		if ok {
+			matched = append(matched, pid)
+		}
+	}
+
+	return matched, time.Time{}, nil
+}
+
+// cpuPercent считает загрузку процессора с предыдущего сбора. Если PID принадлежит
+// другому процессу или процесс встречается впервые, загрузка считается с его запуска.
+func (c *ProcessCollector) cpuPercent(pid int32, stats processStats, now time.Time) float64 {
+	since, cpuTime := stats.createTime, 0.0
+	if prev, ok := c.samples[pid]; ok && prev.createTime.Equal(stats.createTime) {
+		since, cpuTime = prev.at, prev.cpuTime
+	}
+
+	elapsed := now.Sub(since).Seconds()
+	if elapsed <= 0 || stats.cpuTime < cpuTime {
+		return 0
+	}
+
+	return (stats.cpuTime - cpuTime) / elapsed * 100
+}
+
+func isProcessGone(err error) bool {
+	return errors.Is(err, process.ErrorProcessNotRunning) || errors.Is(err, fs.ErrNotExist)
+}
+
+// gopsutilProcesses читает процессы через gopsutil.
+type gopsutilProcesses struct{}
+
+func (gopsutilProcesses) Pids(ctx context.Context) ([]int32, error) {
+	return process.PidsWithContext(ctx)
+}
+
+func (gopsutilProcesses) Name(ctx context.Context, pid int32) (string, error) {
+	return (&process.Process{Pid: pid}).NameWithContext(ctx)
+}
+
+func (gopsutilProcesses) Cmdline(ctx context.Context, pid int32) (string, error) {
+	return (&process.Process{Pid: pid}).CmdlineWithContext(ctx)
+}
+
+func (gopsutilProcesses) Stats(ctx context.Context, pid int32) (processStats, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	createTime, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		return processStats{}, err
+	}
+	times, err := p.TimesWithContext(ctx)
+	if err != nil {
+		return processStats{}, err
+	}
+	memory, err := p.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return processStats{}, err
+	}
+	fds, err := p.NumFDsWithContext(ctx)
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, process.ErrorNotPermitted) {
+		fds = -1
+	} else if err != nil {
+		return processStats{}, err
+	}
+	threads, err := p.NumThreadsWithContext(ctx)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	return processStats{
+		createTime: time.UnixMilli(createTime),
+		cpuTime:    times.User + times.System,
+		rss:        memory.RSS,
+		fds:        fds,
+		threads:    threads,
+	}, nil
+}
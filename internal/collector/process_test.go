@@ -0,0 +1,280 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProcess struct {
+	name    string
+	cmdline string
+	stats   processStats
+	err     error
+}
+
+// fakeProcesses - таблица процессов для тестов.
+type fakeProcesses map[int32]fakeProcess
+
+func (f fakeProcesses) Pids(context.Context) ([]int32, error) {
+	pids := make([]int32, 0, len(f))
+	for pid := range f {
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+func (f fakeProcesses) Name(_ context.Context, pid int32) (string, error) {
+	p, ok := f[pid]
+	if !ok {
+		return "", process.ErrorProcessNotRunning
+	}
+	return p.name, nil
+}
+
+func (f fakeProcesses) Cmdline(_ context.Context, pid int32) (string, error) {
+	p, ok := f[pid]
+	if !ok {
+		return "", process.ErrorProcessNotRunning
+	}
+	return p.cmdline, nil
+}
+
+func (f fakeProcesses) Stats(_ context.Context, pid int32) (processStats, error) {
+	p, ok := f[pid]
+	if !ok {
+		return processStats{}, process.ErrorProcessNotRunning
+	}
+	return p.stats, p.err
+}
+
+var processStart = time.Unix(1000, 0)
+
+// newFixtureProcessCollector возвращает сборщик, который читает заданную таблицу процессов.
+func newFixtureProcessCollector(t *testing.T, opts ProcessOptions, processes *fakeProcesses, clock *fakeClock) *ProcessCollector {
+	t.Helper()
+
+	c, err := NewProcessCollector(0, opts)
+	require.NoError(t, err)
+	c.reader = processes
+	c.now = clock.Now
+
+	return c
+}
+
+func writePidfile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// writeStalePidfile пишет pid файл, измененный в момент modTime.
+func writeStalePidfile(t *testing.T, content string, modTime time.Time) string {
+	t.Helper()
+
+	path := writePidfile(t, content)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func TestProcessCollector_Collect(t *testing.T) {
+	processes := fakeProcesses{
+		10: {name: "nginx", cmdline: "nginx: master process", stats: processStats{createTime: processStart, cpuTime: 5, rss: 1000, fds: 10, threads: 1}},
+		11: {name: "nginx", cmdline: "nginx: worker process", stats: processStats{createTime: processStart.Add(50 * time.Second), cpuTime: 1, rss: 500, fds: 5, threads: 2}},
+		20: {name: "java", cmdline: "java -jar /opt/app/server.jar", stats: processStats{createTime: processStart, cpuTime: 10, rss: 4000, fds: 100, threads: 40}},
+		30: {name: "redis-server", cmdline: "redis-server *:6379", stats: processStats{createTime: processStart, cpuTime: 2, rss: 200, fds: 8, threads: 4}},
+	}
+	clock := newFakeClock(processStart.Add(100 * time.Second))
+	c := newFixtureProcessCollector(t, ProcessOptions{Groups: []ProcessGroup{
+		{Name: "nginx", ProcessName: "nginx"},
+		{Name: "app", Cmdline: `server\.jar`},
+		{Name: "redis", Pidfile: writePidfile(t, "30\n")},
+		{Name: "postgres", ProcessName: "postgres"},
+	}}, &processes, clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 2.0, gauges["process.nginx.count"])
+	assert.Equal(t, 1500.0, gauges["process.nginx.rss_bytes"])
+	assert.Equal(t, 15.0, gauges["process.nginx.open_fds"])
+	assert.Equal(t, 3.0, gauges["process.nginx.threads"])
+	assert.Equal(t, 100.0, gauges["process.nginx.uptime_seconds"], "uptime of the oldest process")
+	assert.InDelta(t, 7.0, gauges["process.nginx.cpu_percent"], 1e-9, "5s over 100s plus 1s over 50s")
+
+	assert.Equal(t, 1.0, gauges["process.app.count"])
+	assert.Equal(t, 40.0, gauges["process.app.threads"])
+	assert.InDelta(t, 10.0, gauges["process.app.cpu_percent"], 1e-9)
+
+	assert.Equal(t, 1.0, gauges["process.redis.count"])
+	assert.Equal(t, 200.0, gauges["process.redis.rss_bytes"])
+
+	assert.Equal(t, 0.0, gauges["process.postgres.count"])
+	assert.Contains(t, gauges, "process.postgres.cpu_percent", "empty group should be reported with zeros")
+	assert.Len(t, gauges, 24)
+}
+
+func TestProcessCollector_Collect_CPUAndRestart(t *testing.T) {
+	processes := fakeProcesses{
+		10: {name: "daemon", stats: processStats{createTime: processStart, cpuTime: 10}},
+	}
+	clock := newFakeClock(processStart.Add(100 * time.Second))
+	c := newFixtureProcessCollector(t, ProcessOptions{Groups: []ProcessGroup{
+		{Name: "daemon", ProcessName: "daemon"},
+	}}, &processes, clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+	assert.InDelta(t, 10.0, sink.send(t).gauges()["process.daemon.cpu_percent"], 1e-9)
+
+	processes[10] = fakeProcess{name: "daemon", stats: processStats{createTime: processStart, cpuTime: 15}}
+	clock.Advance(10 * time.Second)
+	require.NoError(t, c.Collect(context.Background(), sink))
+	assert.InDelta(t, 50.0, sink.send(t).gauges()["process.daemon.cpu_percent"], 1e-9, "cpu since previous poll")
+
+	processes = fakeProcesses{
+		42: {name: "daemon", stats: processStats{createTime: clock.Now().Add(-2 * time.Second), cpuTime: 1}},
+	}
+	require.NoError(t, c.Collect(context.Background(), sink))
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 1.0, gauges["process.daemon.count"])
+	assert.InDelta(t, 50.0, gauges["process.daemon.cpu_percent"], 1e-9, "new pid measured since its start")
+	assert.Equal(t, 2.0, gauges["process.daemon.uptime_seconds"])
+	assert.NotContains(t, c.samples, int32(10), "exited process should be forgotten")
+
+	processes = fakeProcesses{
+		42: {name: "daemon", stats: processStats{createTime: clock.Now().Add(-time.Second), cpuTime: 0.5}},
+	}
+	require.NoError(t, c.Collect(context.Background(), sink))
+	assert.InDelta(t, 50.0, sink.send(t).gauges()["process.daemon.cpu_percent"], 1e-9, "reused pid measured since its start")
+}
+
+func TestProcessCollector_Collect_Pidfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		pidfile func(t *testing.T) string
+		count   float64
+		wantErr bool
+	}{
+		{name: "missing pidfile", pidfile: func(t *testing.T) string { return filepath.Join(t.TempDir(), "none.pid") }},
+		{name: "stale pid", pidfile: func(t *testing.T) string { return writePidfile(t, "99") }},
+		{name: "running", pidfile: func(t *testing.T) string { return writePidfile(t, " 30 ") }, count: 1},
+		{name: "written after start", pidfile: func(t *testing.T) string {
+			return writeStalePidfile(t, "30", processStart.Add(time.Second))
+		}, count: 1},
+		{name: "reused pid", pidfile: func(t *testing.T) string {
+			return writeStalePidfile(t, "30", processStart.Add(-time.Hour))
+		}},
+		{name: "invalid pid", pidfile: func(t *testing.T) string { return writePidfile(t, "abc") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processes := fakeProcesses{
+				30: {name: "redis-server", stats: processStats{createTime: processStart}},
+			}
+			clock := newFakeClock(processStart.Add(time.Minute))
+			c := newFixtureProcessCollector(t, ProcessOptions{Groups: []ProcessGroup{
+				{Name: "redis", Pidfile: tt.pidfile(t)},
+			}}, &processes, clock)
+			sink := newTestSink(t)
+
+			err := c.Collect(context.Background(), sink)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.count, sink.send(t).gauges()["process.redis.count"])
+		})
+	}
+}
+
+func TestProcessCollector_Collect_StatsError(t *testing.T) {
+	processes := fakeProcesses{
+		10: {name: "daemon", stats: processStats{createTime: processStart, rss: 100}},
+		11: {name: "daemon", err: process.ErrorNotPermitted},
+	}
+	clock := newFakeClock(processStart.Add(time.Minute))
+	c := newFixtureProcessCollector(t, ProcessOptions{Groups: []ProcessGroup{
+		{Name: "daemon", ProcessName: "daemon"},
+	}}, &processes, clock)
+	sink := newTestSink(t)
+
+	err := c.Collect(context.Background(), sink)
+
+	assert.ErrorIs(t, err, process.ErrorNotPermitted)
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 1.0, gauges["process.daemon.count"])
+	assert.Equal(t, 100.0, gauges["process.daemon.rss_bytes"])
+}
+
+func TestProcessCollector_Collect_FDsNotPermitted(t *testing.T) {
+	processes := fakeProcesses{
+		10: {name: "daemon", stats: processStats{createTime: processStart, rss: 100, fds: 7, threads: 1}},
+		11: {name: "daemon", stats: processStats{createTime: processStart, rss: 200, fds: -1, threads: 3}},
+	}
+	clock := newFakeClock(processStart.Add(time.Minute))
+	c := newFixtureProcessCollector(t, ProcessOptions{Groups: []ProcessGroup{
+		{Name: "daemon", ProcessName: "daemon"},
+	}}, &processes, clock)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 2.0, gauges["process.daemon.count"], "process without readable fds is still counted")
+	assert.Equal(t, 300.0, gauges["process.daemon.rss_bytes"])
+	assert.Equal(t, 4.0, gauges["process.daemon.threads"])
+	assert.Equal(t, 7.0, gauges["process.daemon.open_fds"])
+}
+
+func TestNewProcessCollector_InvalidGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []ProcessGroup
+	}{
+		{name: "missing name", groups: []ProcessGroup{{ProcessName: "nginx"}}},
+		{name: "no selector", groups: []ProcessGroup{{Name: "nginx"}}},
+		{name: "several selectors", groups: []ProcessGroup{{Name: "nginx", ProcessName: "nginx", Pidfile: "/run/nginx.pid"}}},
+		{name: "invalid regexp", groups: []ProcessGroup{{Name: "app", Cmdline: "("}}},
+		{name: "duplicate", groups: []ProcessGroup{{Name: "app", ProcessName: "a"}, {Name: "app", ProcessName: "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProcessCollector(0, ProcessOptions{Groups: tt.groups})
+			assert.ErrorIs(t, err, ErrInvalidProcessGroup)
+		})
+	}
+}
+
+func TestProcessCollector_Collect_Self(t *testing.T) {
+	if testing.Short() {
+		t.Skip("reads host processes")
+	}
+
+	c, err := NewProcessCollector(0, ProcessOptions{Groups: []ProcessGroup{
+		{Name: "self", Pidfile: writePidfile(t, fmt.Sprint(os.Getpid()))},
+	}})
+	require.NoError(t, err)
+	sink := newTestSink(t)
+
+	require.NoError(t, c.Collect(context.Background(), sink))
+
+	gauges := sink.send(t).gauges()
+	assert.Equal(t, 1.0, gauges["process.self.count"])
+	assert.Greater(t, gauges["process.self.rss_bytes"], 0.0)
+	assert.Greater(t, gauges["process.self.threads"], 0.0)
+}
@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+)
+
+const RandomName = "random"
+
+// RandomCollector записывает случайное число в RandomValue.
+type RandomCollector struct {
+	interval time.Duration
+	random   *rand.Rand
+}
+
+func NewRandomCollector(interval time.Duration) *RandomCollector {
+	return &RandomCollector{
+		interval: interval,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (c *RandomCollector) Name() string {
+	return RandomName
+}
+
+func (c *RandomCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *RandomCollector) Collect(_ context.Context, sink Sink) error {
+	sink.SetGauge(metric.RandomValue, c.random.Float64())
+	return nil
+}
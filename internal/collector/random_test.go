@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomCollector_Collect(t *testing.T) {
+	metrics := metric.NewMetrics("localhost:8080", nil, false, "", nil)
+	c := NewRandomCollector(time.Second)
+
+	require.NoError(t, c.Collect(context.Background(), metrics))
+	first, exists := metrics.Gauges[metric.RandomValue]
+	assert.True(t, exists, "RandomValue should exist after Collect")
+	assert.GreaterOrEqual(t, first, 0.0)
+	assert.Less(t, first, 1.0)
+
+	require.NoError(t, c.Collect(context.Background(), metrics))
+	assert.NotEqual(t, first, metrics.Gauges[metric.RandomValue], "RandomValue should change between calls")
+
+	assert.Equal(t, RandomName, c.Name())
+	assert.Equal(t, time.Second, c.Interval())
+}
@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+)
+
+var ErrCollectorPanic = errors.New("collector panicked")
+
+// Runner запускает сборщики в отдельных горутинах и следит за их работой:
+// восстанавливается после паники сборщика, замеряет длительность сбора
+// и считает ошибки в метриках agent.collector.<имя>.duration и agent.collector.<имя>.errors.
+type Runner struct {
+	sink         Sink
+	logger       CollectorLogger
+	pollInterval time.Duration
+	collectors   []Collector
+	// intervals содержит каналы смены периода для сборщиков без собственного периода
+	intervals []chan time.Duration
+}
+
+func NewRunner(sink Sink, logger CollectorLogger, pollInterval time.Duration, collectors ...Collector) *Runner {
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	intervals := make([]chan time.Duration, len(collectors))
+	for i, c := range collectors {
+		if c.Interval() <= 0 {
+			intervals[i] = make(chan time.Duration, 1)
+		}
+	}
+
+	return &Runner{
+		sink:         sink,
+		logger:       logger,
+		pollInterval: pollInterval,
+		collectors:   collectors,
+		intervals:    intervals,
+	}
+}
+
+// SetPollInterval меняет период опроса для сборщиков, которые работают с периодом агента.
+// Значения меньше или равные нулю заменяются на одну секунду.
+func (r *Runner) SetPollInterval(pollInterval time.Duration) {
+	for _, intervals := range r.intervals {
+		if intervals != nil {
+			setInterval(intervals, pollInterval)
+		}
+	}
+}
+
+func (r *Runner) RunWithContext(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i, c := range r.collectors {
+		interval := c.Interval()
+		if interval <= 0 {
+			interval = r.pollInterval
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.supervise(ctx, c, interval, r.intervals[i])
+		}()
+	}
+	wg.Wait()
+
+	r.logger.Info("Collector stopping due to context cancellation")
+}
+
+func (r *Runner) supervise(ctx context.Context, c Collector, interval time.Duration, intervals <-chan time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-intervals:
+			interval = d
+			ticker.Reset(d)
+			r.logger.Info("Collector %s poll interval changed to %s", c.Name(), d)
+		case <-ticker.C:
+			r.collect(ctx, c, interval)
+		}
+	}
+}
+
+// collect выполняет один сбор. Сбор не может длиться дольше своего периода.
+func (r *Runner) collect(ctx context.Context, c Collector, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	prefix := "agent.collector." + c.Name()
+
+	start := time.Now()
+	err := safeCollect(ctx, c, r.sink)
+	r.sink.Observe(metric.HistogramMetric(prefix+".duration"), time.Since(start).Seconds())
+
+	if err != nil {
+		r.sink.AddCounter(metric.CounterMetric(prefix+".errors"), 1)
+		r.logger.Error("collector.Runner: collector %s failed: %v", c.Name(), err)
+	}
+}
+
+// safeCollect вызывает сборщик и превращает его панику в ошибку.
+func safeCollect(ctx context.Context, c Collector, sink Sink) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrCollectorPanic, p, debug.Stack())
+		}
+	}()
+
+	return c.Collect(ctx, sink)
+}
+
+// setInterval передает новый период опроса в цикл сбора, заменяя еще не примененный.
+// Вызовы для одного сборщика не должны выполняться параллельно.
+func setInterval(intervals chan time.Duration, d time.Duration) {
+	if d <= 0 {
+		d = time.Second
+	}
+
+	select {
+	case <-intervals:
+	default:
+	}
+	intervals <- d
+}
@@ -0,0 +1,152 @@
+package collector
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metric"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// runFor запускает runner и останавливает его после первого сигнала из done.
+func runFor(t *testing.T, runner *Runner, done <-chan struct{}) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		runner.RunWithContext(ctx)
+		close(stopped)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("collector was not called")
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("RunWithContext did not stop after context cancellation")
+	}
+}
+
+// signal возвращает функцию, которая один раз закрывает канал.
+func signal() (chan struct{}, func()) {
+	ch := make(chan struct{})
+	var once atomic.Bool
+	return ch, func() {
+		if once.CompareAndSwap(false, true) {
+			close(ch)
+		}
+	}
+}
+
+func TestRunner_RunWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := NewMockCollectorLogger(ctrl)
+	mockSink := NewMockSink(ctrl)
+	mockCollector := NewMockCollector(ctrl)
+
+	done, notify := signal()
+	mockCollector.EXPECT().Name().Return("test").AnyTimes()
+	mockCollector.EXPECT().Interval().Return(10 * time.Millisecond).AnyTimes()
+	mockCollector.EXPECT().Collect(gomock.Any(), mockSink).DoAndReturn(func(context.Context, Sink) error {
+		notify()
+		return nil
+	}).MinTimes(1)
+	mockSink.EXPECT().Observe(metric.HistogramMetric("agent.collector.test.duration"), gomock.Any()).MinTimes(1)
+	mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
+
+	runFor(t, NewRunner(mockSink, mockLogger, time.Hour, mockCollector), done)
+}
+
+func TestRunner_RunWithContext_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		collect func(context.Context, Sink) error
+		wantErr error
+	}{
+		{
+			name:    "error",
+			collect: func(context.Context, Sink) error { return assert.AnError },
+			wantErr: assert.AnError,
+		},
+		{
+			name:    "panic",
+			collect: func(context.Context, Sink) error { panic("boom") },
+			wantErr: ErrCollectorPanic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLogger := NewMockCollectorLogger(ctrl)
+			mockSink := NewMockSink(ctrl)
+			mockCollector := NewMockCollector(ctrl)
+
+			done, notify := signal()
+			mockCollector.EXPECT().Name().Return("broken").AnyTimes()
+			mockCollector.EXPECT().Interval().Return(10 * time.Millisecond).AnyTimes()
+			mockCollector.EXPECT().Collect(gomock.Any(), mockSink).DoAndReturn(tt.collect).MinTimes(1)
+			mockSink.EXPECT().Observe(metric.HistogramMetric("agent.collector.broken.duration"), gomock.Any()).MinTimes(1)
+			mockSink.EXPECT().AddCounter(metric.CounterMetric("agent.collector.broken.errors"), int64(1)).MinTimes(1)
+			mockLogger.EXPECT().Error("collector.Runner: collector %s failed: %v", "broken", gomock.Any()).
+				Do(func(_ string, args ...any) {
+					assert.ErrorIs(t, args[1].(error), tt.wantErr)
+					notify()
+				}).MinTimes(1)
+			mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
+
+			runFor(t, NewRunner(mockSink, mockLogger, time.Hour, mockCollector), done)
+		})
+	}
+}
+
+func TestRunner_SetPollInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := NewMockCollectorLogger(ctrl)
+	mockSink := NewMockSink(ctrl)
+	following := NewMockCollector(ctrl)
+	fixed := NewMockCollector(ctrl)
+
+	done, notify := signal()
+	following.EXPECT().Name().Return("following").AnyTimes()
+	following.EXPECT().Interval().Return(time.Duration(0)).AnyTimes()
+	following.EXPECT().Collect(gomock.Any(), mockSink).DoAndReturn(func(context.Context, Sink) error {
+		notify()
+		return nil
+	}).MinTimes(1)
+	fixed.EXPECT().Name().Return("fixed").AnyTimes()
+	fixed.EXPECT().Interval().Return(time.Hour).AnyTimes()
+	mockSink.EXPECT().Observe(metric.HistogramMetric("agent.collector.following.duration"), gomock.Any()).MinTimes(1)
+	mockLogger.EXPECT().Info("Collector %s poll interval changed to %s", "following", 20*time.Millisecond).Times(1)
+	mockLogger.EXPECT().Info("Collector stopping due to context cancellation").Times(1)
+
+	runner := NewRunner(mockSink, mockLogger, time.Hour, following, fixed)
+	assert.Nil(t, runner.intervals[1], "collector with own interval should ignore poll interval")
+
+	runner.SetPollInterval(20 * time.Millisecond)
+	runFor(t, runner, done)
+}
+
+func TestNewRunner_ZeroPollInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runner := NewRunner(NewMockSink(ctrl), NewMockCollectorLogger(ctrl), 0)
+
+	assert.Equal(t, time.Second, runner.pollInterval)
+}
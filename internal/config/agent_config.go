@@ -1,23 +1,62 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-
-	"github.com/caarlos0/env/v11"
 )
 
 type AgentConfig struct {
-	ConfigPath     string
-	PollInterval   uint   `env:"POLL_INTERVAL"`
-	ReportInterval uint   `env:"REPORT_INTERVAL"`
-	ServerAddress  string `env:"ADDRESS"`
-	LogLevel       string `env:"LOG_LEVEL"`
-	AppEnv         string `env:"APP_ENV"`
-	Key            string `env:"KEY"`
-	RateLimit      uint   `env:"RATE_LIMIT"`
-	CryptoKey      string `env:"CRYPTO_KEY"`
+	ConfigPath     string   `json:"-"`
+	PollInterval   Duration `json:"poll_interval" env:"POLL_INTERVAL"`
+	ReportInterval Duration `json:"report_interval" env:"REPORT_INTERVAL"`
+	ServerAddress  string   `json:"server_address" env:"ADDRESS"`
+	LogLevel       string   `json:"log_level" env:"LOG_LEVEL"`
+	AppEnv         string   `json:"app_env" env:"APP_ENV"`
+	Key            string   `json:"key" env:"KEY" secret:"true"`
+	RateLimit      uint     `json:"rate_limit" env:"RATE_LIMIT"`
+	CryptoKey      string   `json:"crypto_key" env:"CRYPTO_KEY"`
+	APIKey         string   `json:"api_key" env:"API_KEY" secret:"true"`
+	AuthToken      string   `json:"auth_token" env:"AUTH_TOKEN" secret:"true"`
+	KeyID          string   `json:"key_id" env:"KEY_ID"`
+	CryptoKeyID    string   `json:"crypto_key_id" env:"CRYPTO_KEY_ID"`
+	AgentID        string   `json:"agent_id" env:"AGENT_ID"`
+	SigningKey     string   `json:"signing_key" env:"SIGNING_KEY"`
+
+	// SignedHash включает HMAC с меткой времени и nonce. Серверы старых версий отклоняют
+	// такие запросы, поэтому режим включается после обновления всех серверов.
+	SignedHash bool `json:"signed_hash" env:"SIGNED_HASH"`
+
+	// TLSCA задает CA для проверки сервера, TLSCert и TLSKey - сертификат агента для mTLS.
+	TLSCA   string `json:"tls_ca" env:"TLS_CA"`
+	TLSCert string `json:"tls_cert" env:"TLS_CERT"`
+	TLSKey  string `json:"tls_key" env:"TLS_KEY"`
+
+	TraceExporter    string  `json:"trace_exporter" env:"TRACE_EXPORTER"`
+	TraceEndpoint    string  `json:"trace_endpoint" env:"TRACE_ENDPOINT"`
+	TraceFile        string  `json:"trace_file" env:"TRACE_FILE"`
+	TraceSampleRatio float64 `json:"trace_sample_ratio" env:"TRACE_SAMPLE_RATIO"`
+
+	AdminAddress string `json:"admin_address" env:"ADMIN_ADDRESS"`
+	AdminToken   string `json:"admin_token" env:"ADMIN_TOKEN" secret:"true"`
+
+	// Collectors задает настройки сборщиков метрик по именам и задается только в файле конфигурации
+	Collectors map[string]CollectorConfig `json:"collectors"`
+
+	sources Sources
+}
+
+// CollectorConfig - настройки отдельного сборщика метрик агента.
+type CollectorConfig struct {
+	// Enabled включает или отключает сборщик. Если не задан, сборщик работает
+	// согласно своему значению по умолчанию.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Interval - период сбора. Ноль означает период опроса агента.
+	Interval Duration `json:"interval,omitempty"`
+	// Options - собственные настройки сборщика.
+	Options json.RawMessage `json:"options,omitempty"`
 }
 
 func NewAgentConfig() (*AgentConfig, error) {
@@ -25,66 +64,65 @@ func NewAgentConfig() (*AgentConfig, error) {
 		ConfigPath: "configs/agent.json",
 	}
 
-	if err := config.parseFlags(); err != nil {
-		return nil, err
-	}
-
-	defaultConfig, err := NewAgentDefaultConfig(config.ConfigPath)
+	sources, unknownFields, err := loadLayers(config)
 	if err != nil {
-		return nil, fmt.Errorf("config.NewAgentConfig: loading default config from '%s': %w", config.ConfigPath, err)
-	}
-
-	if config.PollInterval == 0 {
-		config.PollInterval = defaultConfig.PollInterval
-	}
-	if config.ReportInterval == 0 {
-		config.ReportInterval = defaultConfig.ReportInterval
-	}
-	if config.ServerAddress == "" {
-		config.ServerAddress = defaultConfig.ServerAddress
-	}
-	if config.LogLevel == "" {
-		config.LogLevel = defaultConfig.LogLevel
-	}
-	if config.AppEnv == "" {
-		config.AppEnv = defaultConfig.AppEnv
-	}
-	if config.Key == "" {
-		config.Key = defaultConfig.Key
-	}
-	if config.RateLimit == 0 {
-		config.RateLimit = defaultConfig.RateLimit
-	}
-	if config.CryptoKey == "" {
-		config.CryptoKey = defaultConfig.CryptoKey
+		return nil, fmt.Errorf("config.NewAgentConfig: %w", err)
 	}
+	config.sources = sources
 
-	if err := env.Parse(config); err != nil {
-		return nil, fmt.Errorf("config.NewAgentConfig: parsing environment variables: %w", err)
+	if err := errors.Join(unknownFieldsError(config.ConfigPath, unknownFields), config.Validate()); err != nil {
+		return nil, fmt.Errorf("config.NewAgentConfig: invalid configuration:\n%w", err)
 	}
 
 	return config, nil
 }
 
-func (c *AgentConfig) parseFlags() error {
+// Source возвращает слой, из которого взято значение поля конфигурации.
+func (c *AgentConfig) Source(field string) Source {
+	return c.sources.Source(field)
+}
+
+func (c *AgentConfig) configFile() string {
+	return c.ConfigPath
+}
+
+func (c *AgentConfig) parseFlags() (*flag.FlagSet, error) {
 	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
 
 	fs.StringVar(&c.ConfigPath, "c", c.ConfigPath, "Path to config file")
 	fs.StringVar(&c.ConfigPath, "config", c.ConfigPath, "Path to config file")
 	fs.StringVar(&c.ServerAddress, "a", c.ServerAddress, "Server address")
-	fs.UintVar(&c.PollInterval, "p", c.PollInterval, "Poll interval in seconds")
-	fs.UintVar(&c.ReportInterval, "r", c.ReportInterval, "Report interval in seconds")
+	fs.Var(&c.PollInterval, "p", "Poll interval in seconds or as duration like 500ms")
+	fs.Var(&c.ReportInterval, "r", "Report interval in seconds or as duration like 1m")
 	fs.StringVar(&c.Key, "k", c.Key, "Secret key for hashing")
 	fs.UintVar(&c.RateLimit, "l", c.RateLimit, "Rate limit for concurrent requests")
+	fs.StringVar(&c.KeyID, "key-id", c.KeyID, "ID of the secret key for hashing")
+	fs.BoolVar(&c.SignedHash, "signed-hash", c.SignedHash, "Include timestamp and nonce in the HMAC; enable after all servers are upgraded")
+	fs.StringVar(&c.TLSCA, "tls-ca", c.TLSCA, "Path to CA certificate for verifying the server")
+	fs.StringVar(&c.TLSCert, "tls-cert", c.TLSCert, "Path to agent TLS certificate for mutual TLS")
+	fs.StringVar(&c.TLSKey, "tls-key", c.TLSKey, "Path to agent TLS private key")
 	fs.StringVar(&c.CryptoKey, "crypto-key", c.CryptoKey, "Path to public key file for encryption")
+	fs.StringVar(&c.CryptoKeyID, "crypto-key-id", c.CryptoKeyID, "ID of the public key for encryption")
+	fs.StringVar(&c.AgentID, "agent-id", c.AgentID, "Agent ID for Ed25519 request signing")
+	fs.StringVar(&c.SigningKey, "signing-key", c.SigningKey, "Path to Ed25519 private key for request signing")
+	fs.StringVar(&c.APIKey, "api-key", c.APIKey, "Tenant API key")
+	fs.StringVar(&c.AuthToken, "auth-token", c.AuthToken, "Bearer token for server authentication")
+
+	fs.StringVar(&c.TraceExporter, "trace-exporter", c.TraceExporter, "Trace exporter: none, otlp, stdout or file")
+	fs.StringVar(&c.TraceEndpoint, "trace-endpoint", c.TraceEndpoint, "OTLP/HTTP collector endpoint")
+	fs.StringVar(&c.TraceFile, "trace-file", c.TraceFile, "Path to file for the file trace exporter")
+	fs.Float64Var(&c.TraceSampleRatio, "trace-sample-ratio", c.TraceSampleRatio, "Fraction of traces started by this process to record")
+
+	fs.StringVar(&c.AdminAddress, "admin-address", c.AdminAddress, "Admin listener address, empty to disable")
+	fs.StringVar(&c.AdminToken, "admin-token", c.AdminToken, "Bearer token required by the admin listener")
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
-		return fmt.Errorf("config.AgentConfig.parseFlags: %w", err)
+		return nil, fmt.Errorf("config.AgentConfig.parseFlags: %w", err)
 	}
 
 	if fs.NArg() > 0 {
-		return fmt.Errorf("config.AgentConfig.parseFlags: unknown command line arguments: %v", fs.Args())
+		return nil, fmt.Errorf("config.AgentConfig.parseFlags: unknown command line arguments: %v", fs.Args())
 	}
 
-	return nil
+	return fs, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -43,8 +44,8 @@ func TestNewAgentConfig(t *testing.T) {
 			args: []string{"test"},
 			expected: &AgentConfig{
 				ServerAddress:  "localhost:8080",
-				PollInterval:   2,
-				ReportInterval: 10,
+				PollInterval:   Duration(2 * time.Second),
+				ReportInterval: Duration(10 * time.Second),
 				LogLevel:       "info",
 				AppEnv:         "development",
 			},
@@ -54,8 +55,8 @@ func TestNewAgentConfig(t *testing.T) {
 			args: []string{"test", "-a", "localhost:9090", "-p", "5", "-r", "20"},
 			expected: &AgentConfig{
 				ServerAddress:  "localhost:9090",
-				PollInterval:   5,
-				ReportInterval: 20,
+				PollInterval:   Duration(5 * time.Second),
+				ReportInterval: Duration(20 * time.Second),
 				LogLevel:       "info",
 				AppEnv:         "development",
 			},
@@ -72,8 +73,8 @@ func TestNewAgentConfig(t *testing.T) {
 			},
 			expected: &AgentConfig{
 				ServerAddress:  "localhost:7070",
-				PollInterval:   3,
-				ReportInterval: 15,
+				PollInterval:   Duration(3 * time.Second),
+				ReportInterval: Duration(15 * time.Second),
 				LogLevel:       "debug",
 				AppEnv:         "test",
 			},
@@ -90,8 +91,8 @@ func TestNewAgentConfig(t *testing.T) {
 			},
 			expected: &AgentConfig{
 				ServerAddress:  "localhost:7070",
-				PollInterval:   3,
-				ReportInterval: 15,
+				PollInterval:   Duration(3 * time.Second),
+				ReportInterval: Duration(15 * time.Second),
 				LogLevel:       "debug",
 				AppEnv:         "test",
 			},
@@ -112,6 +113,30 @@ func TestNewAgentConfig(t *testing.T) {
 			},
 			expectedErrMsg: "config.NewAgentConfig: parsing environment variables",
 		},
+		{
+			name: "duration strings",
+			args: []string{"test", "-p", "500ms"},
+			envs: map[string]string{
+				"REPORT_INTERVAL": "1m",
+			},
+			expected: &AgentConfig{
+				ServerAddress:  "localhost:8080",
+				PollInterval:   Duration(500 * time.Millisecond),
+				ReportInterval: Duration(time.Minute),
+				LogLevel:       "info",
+				AppEnv:         "development",
+			},
+		},
+		{
+			name:           "report interval less than poll interval",
+			args:           []string{"test", "-p", "10s", "-r", "5s"},
+			expectedErrMsg: "report_interval 5s is less than poll_interval 10s",
+		},
+		{
+			name:           "invalid poll interval flag",
+			args:           []string{"test", "-p", "soon"},
+			expectedErrMsg: "config.AgentConfig.parseFlags",
+		},
 		{
 			name:           "unknown arguments",
 			args:           []string{"test", "unknown"},
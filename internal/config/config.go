@@ -1,58 +1,118 @@
+// Package config собирает конфигурацию сервера и агента из слоев, каждый следующий
+// слой переопределяет предыдущий: файл конфигурации < флаги командной строки <
+// переменные окружения. Слой меняет только явно заданные в нем поля, поэтому
+// явные нулевые значения (-i 0, RESTORE=false) переопределяют значения нижних слоев.
 package config
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// Source - слой, из которого взято значение поля конфигурации.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
 )
 
-type AgentDefaultConfig struct {
-	ServerAddress  string `json:"server_address"`
-	LogLevel       string `json:"log_level"`
-	AppEnv         string `json:"app_env"`
-	PollInterval   uint   `json:"poll_interval"`
-	ReportInterval uint   `json:"report_interval"`
-	Key            string `json:"key"`
-	RateLimit      uint   `json:"rate_limit"`
-	CryptoKey      string `json:"crypto_key"`
+// Sources хранит слои явно заданных полей конфигурации по именам полей.
+type Sources map[string]Source
+
+// Source возвращает слой, из которого взято значение поля.
+// Для полей, не заданных ни в одном слое, возвращает SourceDefault.
+func (s Sources) Source(field string) Source {
+	if source, ok := s[field]; ok {
+		return source
+	}
+	return SourceDefault
 }
 
-type ServerDefaultConfig struct {
-	ServerAddress   string `json:"server_address"`
-	LogLevel        string `json:"log_level"`
-	AppEnv          string `json:"app_env"`
-	Key             string `json:"key"`
-	CryptoKey       string `json:"crypto_key"`
-	StoreInterval   uint   `json:"store_interval"`
-	FileStoragePath string `json:"file_storage_path"`
-	Restore         bool   `json:"restore"`
-	DatabaseDSN     string `json:"database_dsn"`
+// layered - конфигурация, которую можно собрать из слоев.
+type layered interface {
+	parseFlags() (*flag.FlagSet, error)
+	configFile() string
 }
 
-func NewAgentDefaultConfig(configPath string) (*AgentDefaultConfig, error) {
-	data, err := os.ReadFile(configPath)
+// loadLayers загружает в cfg файл конфигурации, флаги и переменные окружения.
+// Флаги разбираются дважды: первый раз - чтобы узнать путь к файлу,
+// второй - чтобы переопределить значения из файла. Незаданные флаги
+// регистрируются с текущими значениями полей и ничего не меняют.
+// Возвращает слои явно заданных полей и имена неизвестных полей файла.
+func loadLayers(cfg layered) (Sources, []string, error) {
+	if _, err := cfg.parseFlags(); err != nil {
+		return nil, nil, err
+	}
+
+	fileFields, unknown, err := loadFile(cfg.configFile(), cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fs, err := cfg.parseFlags()
 	if err != nil {
-		return nil, fmt.Errorf("config.NewAgentDefaultConfig: error reading config file '%s': %w", configPath, err)
+		return nil, nil, err
 	}
 
-	var config AgentDefaultConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("config.NewAgentDefaultConfig: error parsing config file '%s': %w", configPath, err)
+	if err := env.Parse(cfg); err != nil {
+		return nil, nil, fmt.Errorf("parsing environment variables: %w", err)
 	}
 
-	return &config, nil
+	return explicitSources(cfg, fileFields, fs), unknown, nil
 }
 
-func NewServerDefaultConfig(configPath string) (*ServerDefaultConfig, error) {
-	data, err := os.ReadFile(configPath)
+// loadFile читает файл конфигурации в формате JSON, YAML или TOML в cfg.
+// Возвращает имена полей cfg, заданных в файле, и имена неизвестных полей файла.
+func loadFile(path string, cfg any) ([]string, []string, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("config.NewServerDefaultConfig: error reading config file '%s': %w", configPath, err)
+		return nil, nil, fmt.Errorf("error reading config file '%s': %w", path, err)
+	}
+
+	fields, unknown, err := decodeFile(path, data, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing config file '%s': %w", path, err)
+	}
+
+	return fields, unknown, nil
+}
+
+// explicitSources определяет слой каждого явно заданного поля.
+// Поле флага находится по адресу значения, в которое флаг записывает результат.
+func explicitSources(cfg any, fileFields []string, fs *flag.FlagSet) Sources {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	sources := make(Sources)
+	for _, field := range fileFields {
+		sources[field] = SourceFile
+	}
+
+	byAddr := make(map[uintptr]string, t.NumField())
+	for i := range t.NumField() {
+		byAddr[v.Field(i).Addr().Pointer()] = t.Field(i).Name
 	}
+	fs.Visit(func(f *flag.Flag) {
+		if name, ok := byAddr[reflect.ValueOf(f.Value).Pointer()]; ok {
+			sources[name] = SourceFlag
+		}
+	})
 
-	var config ServerDefaultConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("config.NewServerDefaultConfig: error parsing config file '%s': %w", configPath, err)
+	// Пустые переменные окружения env.Parse пропускает, поэтому они не считаются заданными.
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("env"), ",")
+		if name != "" && os.Getenv(name) != "" {
+			sources[t.Field(i).Name] = SourceEnv
+		}
 	}
 
-	return &config, nil
+	return sources
 }
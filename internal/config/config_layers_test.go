@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// layerCase описывает значения поля в каждом слое конфигурации.
+// Флаг задает нулевое значение, чтобы проверить явное переопределение файла нулем.
+type layerCase struct {
+	field    string
+	file     string
+	fromFile any
+	flag     string
+	env      string
+	fromEnv  any
+}
+
+var agentLayerCases = []layerCase{
+	{field: "PollInterval", file: `"2s"`, fromFile: Duration(2 * time.Second), flag: "-p=0", env: "3s", fromEnv: Duration(3 * time.Second)},
+	{field: "ReportInterval", file: `10`, fromFile: Duration(10 * time.Second), flag: "-r=0", env: "15s", fromEnv: Duration(15 * time.Second)},
+	{field: "ServerAddress", file: `"file:8080"`, fromFile: "file:8080", flag: "-a=", env: "env:8080", fromEnv: "env:8080"},
+	{field: "LogLevel", file: `"warn"`, fromFile: "warn", env: "debug", fromEnv: "debug"},
+	{field: "AppEnv", file: `"staging"`, fromFile: "staging", env: "test", fromEnv: "test"},
+	{field: "Key", file: `"file-key"`, fromFile: "file-key", flag: "-k=", env: "env-key", fromEnv: "env-key"},
+	{field: "RateLimit", file: `3`, fromFile: uint(3), flag: "-l=0", env: "5", fromEnv: uint(5)},
+	{field: "CryptoKey", file: `"file.pem"`, fromFile: "file.pem", flag: "-crypto-key=", env: "env.pem", fromEnv: "env.pem"},
+	{field: "APIKey", file: `"file-api"`, fromFile: "file-api", flag: "-api-key=", env: "env-api", fromEnv: "env-api"},
+	{field: "AuthToken", file: `"file-token"`, fromFile: "file-token", flag: "-auth-token=", env: "env-token", fromEnv: "env-token"},
+	{field: "KeyID", file: `"file-id"`, fromFile: "file-id", flag: "-key-id=", env: "env-id", fromEnv: "env-id"},
+	{field: "SignedHash", file: `true`, fromFile: true, flag: "-signed-hash=false", env: "true", fromEnv: true},
+	{field: "TLSCA", file: `"file-ca.pem"`, fromFile: "file-ca.pem", flag: "-tls-ca=", env: "env-ca.pem", fromEnv: "env-ca.pem"},
+	{field: "TLSCert", file: `"file.pem"`, fromFile: "file.pem", flag: "-tls-cert=", env: "env.pem", fromEnv: "env.pem"},
+	{field: "TLSKey", file: `"file-key.pem"`, fromFile: "file-key.pem", flag: "-tls-key=", env: "env-key.pem", fromEnv: "env-key.pem"},
+	{field: "CryptoKeyID", file: `"file-id"`, fromFile: "file-id", flag: "-crypto-key-id=", env: "env-id", fromEnv: "env-id"},
+	{field: "AgentID", file: `"file-agent"`, fromFile: "file-agent", flag: "-agent-id=", env: "env-agent", fromEnv: "env-agent"},
+	{field: "SigningKey", file: `"file.pem"`, fromFile: "file.pem", flag: "-signing-key=", env: "env.pem", fromEnv: "env.pem"},
+	{field: "TraceExporter", file: `"otlp"`, fromFile: "otlp", flag: "-trace-exporter=", env: "stdout", fromEnv: "stdout"},
+	{field: "TraceEndpoint", file: `"file:4318"`, fromFile: "file:4318", flag: "-trace-endpoint=", env: "env:4318", fromEnv: "env:4318"},
+	{field: "TraceFile", file: `"file.json"`, fromFile: "file.json", flag: "-trace-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "TraceSampleRatio", file: `0.5`, fromFile: 0.5, flag: "-trace-sample-ratio=0", env: "0.25", fromEnv: 0.25},
+	{field: "AdminAddress", file: `"file:9091"`, fromFile: "file:9091", flag: "-admin-address=", env: "env:9091", fromEnv: "env:9091"},
+	{field: "AdminToken", file: `"file-token"`, fromFile: "file-token", flag: "-admin-token=", env: "env-token", fromEnv: "env-token"},
+	{field: "Collectors", file: `{"random": {"enabled": false, "interval": "5s", "options": {"a": 1}}}`, fromFile: map[string]CollectorConfig{
+		"random": {Enabled: new(bool), Interval: Duration(5 * time.Second), Options: []byte(`{"a":1}`)},
+	}},
+}
+
+var serverLayerCases = []layerCase{
+	{field: "ServerAddress", file: `"file:8080"`, fromFile: "file:8080", flag: "-a=", env: "env:8080", fromEnv: "env:8080"},
+	{field: "LogLevel", file: `"warn"`, fromFile: "warn", env: "debug", fromEnv: "debug"},
+	{field: "AppEnv", file: `"staging"`, fromFile: "staging", env: "test", fromEnv: "test"},
+	{field: "Key", file: `"file-key"`, fromFile: "file-key", flag: "-k=", env: "env-key", fromEnv: "env-key"},
+	{field: "CryptoKey", file: `"file.pem"`, fromFile: "file.pem", flag: "-crypto-key=", env: "env.pem", fromEnv: "env.pem"},
+	{field: "KeysFile", file: `"file.json"`, fromFile: "file.json", flag: "-keys-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "AgentKeysFile", file: `"file.json"`, fromFile: "file.json", flag: "-agent-keys-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "SignatureRequired", file: `true`, fromFile: true, flag: "-signature-required=false", env: "true", fromEnv: true},
+	{field: "TLSCert", file: `"file.pem"`, fromFile: "file.pem", flag: "-tls-cert=", env: "env.pem", fromEnv: "env.pem"},
+	{field: "TLSKey", file: `"file-key.pem"`, fromFile: "file-key.pem", flag: "-tls-key=", env: "env-key.pem", fromEnv: "env-key.pem"},
+	{field: "TLSClientCA", file: `"file-ca.pem"`, fromFile: "file-ca.pem", flag: "-tls-client-ca=", env: "env-ca.pem", fromEnv: "env-ca.pem"},
+	{field: "HashStrict", file: `true`, fromFile: true, flag: "-hash-strict=false", env: "true", fromEnv: true},
+	{field: "HashMaxSkew", file: `300`, fromFile: uint(300), flag: "-hash-max-skew=0", env: "60", fromEnv: uint(60)},
+	{field: "NonceCacheSize", file: `100`, fromFile: uint(100), flag: "-nonce-cache-size=0", env: "50", fromEnv: uint(50)},
+	{field: "StoreInterval", file: `"5m"`, fromFile: Duration(5 * time.Minute), flag: "-i=0", env: "30", fromEnv: Duration(30 * time.Second)},
+	{field: "FileStoragePath", file: `"file.json"`, fromFile: "file.json", flag: "-f=", env: "env.json", fromEnv: "env.json"},
+	{field: "Restore", file: `true`, fromFile: true, flag: "-r=false", env: "true", fromEnv: true},
+	{field: "DatabaseDSN", file: `"postgres://file"`, fromFile: "postgres://file", flag: "-d=", env: "postgres://env", fromEnv: "postgres://env"},
+	{field: "TenantsFile", file: `"file.json"`, fromFile: "file.json", flag: "-tenants-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "AuthFile", file: `"file.json"`, fromFile: "file.json", flag: "-auth-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "ValidateRequests", file: `true`, fromFile: true, flag: "-validate-requests=false", env: "true", fromEnv: true},
+	{field: "MetadataFile", file: `"file.json"`, fromFile: "file.json", flag: "-metadata-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "TrustedSubnet", file: `"10.0.0.0/8"`, fromFile: "10.0.0.0/8", flag: "-t=", env: "192.168.0.0/16", fromEnv: "192.168.0.0/16"},
+	{field: "MaxMetricNames", file: `100`, fromFile: uint(100), flag: "-max-metric-names=0", env: "50", fromEnv: uint(50)},
+	{field: "MaxMetricNamesPerTenant", file: `100`, fromFile: uint(100), flag: "-max-metric-names-per-tenant=0", env: "50", fromEnv: uint(50)},
+	{field: "MaxMetricNamesPerAgent", file: `100`, fromFile: uint(100), flag: "-max-metric-names-per-agent=0", env: "50", fromEnv: uint(50)},
+	{field: "MaxBatchSize", file: `100`, fromFile: uint(100), flag: "-max-batch-size=0", env: "50", fromEnv: uint(50)},
+	{field: "MaxBodySize", file: `1024`, fromFile: uint(1024), flag: "-max-body-size=0", env: "512", fromEnv: uint(512)},
+	{field: "RateLimit", file: `20`, fromFile: float64(20), flag: "-rate-limit=0", env: "2.5", fromEnv: 2.5},
+	{field: "RateBurst", file: `40`, fromFile: uint(40), flag: "-rate-burst=0", env: "10", fromEnv: uint(10)},
+	{field: "TraceExporter", file: `"otlp"`, fromFile: "otlp", flag: "-trace-exporter=", env: "stdout", fromEnv: "stdout"},
+	{field: "TraceEndpoint", file: `"file:4318"`, fromFile: "file:4318", flag: "-trace-endpoint=", env: "env:4318", fromEnv: "env:4318"},
+	{field: "TraceFile", file: `"file.json"`, fromFile: "file.json", flag: "-trace-file=", env: "env.json", fromEnv: "env.json"},
+	{field: "TraceSampleRatio", file: `0.5`, fromFile: 0.5, flag: "-trace-sample-ratio=0", env: "0.25", fromEnv: 0.25},
+	{field: "AdminAddress", file: `"file:9090"`, fromFile: "file:9090", flag: "-admin-address=", env: "env:9090", fromEnv: "env:9090"},
+	{field: "AdminToken", file: `"file-token"`, fromFile: "file-token", flag: "-admin-token=", env: "env-token", fromEnv: "env-token"},
+}
+
+func TestLoadLayers_Agent(t *testing.T) {
+	testLoadLayers(t, func() layered { return &AgentConfig{} }, agentLayerCases)
+}
+
+func TestLoadLayers_Server(t *testing.T) {
+	testLoadLayers(t, func() layered { return &ServerConfig{} }, serverLayerCases)
+}
+
+// testLoadLayers проверяет каждое поле конфигурации в трех сценариях: значение
+// только в файле, флаг с нулевым значением поверх файла и переменная окружения поверх флага.
+func testLoadLayers(t *testing.T, newConfig func() layered, cases []layerCase) {
+	configType := reflect.TypeOf(newConfig()).Elem()
+
+	covered := make(map[string]bool, len(cases))
+	for _, tc := range cases {
+		covered[tc.field] = true
+	}
+	for i := range configType.NumField() {
+		field := configType.Field(i)
+		if field.IsExported() && field.Name != "ConfigPath" {
+			assert.True(t, covered[field.Name], "field %s has no layer test case", field.Name)
+		}
+	}
+
+	for _, tc := range cases {
+		field, ok := configType.FieldByName(tc.field)
+		require.True(t, ok, tc.field)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		envName, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"`+jsonName+`": `+tc.file+`}`), 0o644))
+
+		scenarios := []struct {
+			name     string
+			flag     bool
+			env      bool
+			expected any
+			source   Source
+		}{
+			{name: "file", expected: tc.fromFile, source: SourceFile},
+			{name: "flag overrides file", flag: true, expected: reflect.Zero(field.Type).Interface(), source: SourceFlag},
+			{name: "env overrides flag", flag: true, env: true, expected: tc.fromEnv, source: SourceEnv},
+		}
+
+		for _, sc := range scenarios {
+			t.Run(tc.field+"/"+sc.name, func(t *testing.T) {
+				if sc.flag && !sc.env && tc.flag == "" {
+					t.Skip("field has no command line flag")
+				}
+				if sc.env && tc.env == "" {
+					t.Skip("field has no environment variable")
+				}
+
+				clearConfigEnv(t, configType)
+				if sc.env {
+					t.Setenv(envName, tc.env)
+				}
+
+				args := []string{"test", "-c", path}
+				if sc.flag && tc.flag != "" {
+					args = append(args, tc.flag)
+				}
+				setArgs(t, args)
+
+				config := newConfig()
+				sources, unknown, err := loadLayers(config)
+
+				require.NoError(t, err)
+				assert.Empty(t, unknown)
+				assert.Equal(t, sc.expected, reflect.ValueOf(config).Elem().FieldByName(tc.field).Interface())
+				assert.Equal(t, sc.source, sources.Source(tc.field))
+			})
+		}
+	}
+}
+
+func TestLoadLayers_UnsetFieldsKeepDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"restore": false}`), 0o644))
+
+	clearConfigEnv(t, reflect.TypeOf(ServerConfig{}))
+	setArgs(t, []string{"test", "-c", path})
+
+	config := &ServerConfig{ServerAddress: "localhost:8080", Restore: true}
+	sources, _, err := loadLayers(config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8080", config.ServerAddress)
+	assert.Equal(t, SourceDefault, sources.Source("ServerAddress"))
+	assert.False(t, config.Restore)
+	assert.Equal(t, SourceFile, sources.Source("Restore"))
+	assert.Equal(t, SourceFlag, sources.Source("ConfigPath"))
+}
+
+func clearConfigEnv(t *testing.T, configType reflect.Type) {
+	t.Helper()
+
+	for i := range configType.NumField() {
+		if name, _, _ := strings.Cut(configType.Field(i).Tag.Get("env"), ","); name != "" {
+			t.Setenv(name, "")
+		}
+	}
+}
+
+func setArgs(t *testing.T, args []string) {
+	t.Helper()
+
+	oldArgs := os.Args
+	os.Args = args
+	t.Cleanup(func() { os.Args = oldArgs })
+}
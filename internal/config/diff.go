@@ -0,0 +1,26 @@
+package config
+
+import "reflect"
+
+// Changed возвращает имена экспортируемых полей, значения которых в old и next различаются.
+// old и next должны быть значениями или указателями одного типа структуры.
+func Changed(old, next any) []string {
+	a := reflect.Indirect(reflect.ValueOf(old))
+	b := reflect.Indirect(reflect.ValueOf(next))
+	if a.Kind() != reflect.Struct || a.Type() != b.Type() {
+		return nil
+	}
+
+	var fields []string
+	t := a.Type()
+	for i := range t.NumField() {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			fields = append(fields, t.Field(i).Name)
+		}
+	}
+
+	return fields
+}
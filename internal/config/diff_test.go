@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanged(t *testing.T) {
+	old := &ServerConfig{ServerAddress: "localhost:8080", LogLevel: "info", StoreInterval: Duration(time.Minute)}
+
+	tests := []struct {
+		name     string
+		next     any
+		expected []string
+	}{
+		{
+			name: "no changes",
+			next: &ServerConfig{ServerAddress: "localhost:8080", LogLevel: "info", StoreInterval: Duration(time.Minute)},
+		},
+		{
+			name:     "changed fields",
+			next:     ServerConfig{ServerAddress: "localhost:9090", LogLevel: "debug", StoreInterval: Duration(time.Minute), Restore: true},
+			expected: []string{"ServerAddress", "LogLevel", "Restore"},
+		},
+		{
+			name: "different types",
+			next: &AgentConfig{ServerAddress: "localhost:9090"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Changed(old, tt.next))
+		})
+	}
+}
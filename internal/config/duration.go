@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Duration - интервал в конфигурации. Принимает строку в формате time.ParseDuration
+// ("500ms", "1m") или целое число секунд, как в прежних версиях конфигурации.
+type Duration time.Duration
+
+// Duration возвращает интервал как time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Set разбирает значение флага командной строки.
+func (d *Duration) Set(s string) error {
+	if seconds, err := strconv.ParseUint(s, 10, 63); err == nil {
+		*d = Duration(time.Duration(seconds) * time.Second)
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration '%s': expected seconds or Go duration like 500ms or 1m", s)
+	}
+	if parsed < 0 {
+		return fmt.Errorf("invalid duration '%s': must not be negative", s)
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// UnmarshalText разбирает значение переменной окружения.
+func (d *Duration) UnmarshalText(text []byte) error {
+	return d.Set(string(text))
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON принимает число секунд или строку.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return d.Set(s)
+	}
+
+	var seconds uint64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("invalid duration %s: expected seconds or Go duration like 500ms or 1m", data)
+	}
+
+	*d = Duration(time.Duration(seconds) * time.Second)
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration_Set(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "seconds", value: "10", expected: 10 * time.Second},
+		{name: "zero", value: "0", expected: 0},
+		{name: "milliseconds", value: "500ms", expected: 500 * time.Millisecond},
+		{name: "minutes", value: "1m", expected: time.Minute},
+		{name: "negative", value: "-1s", wantErr: true},
+		{name: "invalid", value: "invalid", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+			err := d.Set(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d.Duration())
+		})
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var v struct {
+		Seconds  Duration `json:"seconds"`
+		Duration Duration `json:"duration"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"seconds":2,"duration":"1m30s"}`), &v))
+	assert.Equal(t, 2*time.Second, v.Seconds.Duration())
+	assert.Equal(t, 90*time.Second, v.Duration.Duration())
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"seconds":"2s","duration":"1m30s"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"seconds":-1}`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{"seconds":true}`), &v))
+}
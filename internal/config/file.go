@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeFile разбирает содержимое файла конфигурации в формате, который
+// определяется расширением: .yaml/.yml, .toml, остальные файлы читаются как JSON.
+// YAML и TOML приводятся к JSON, чтобы все форматы использовали одни теги полей
+// и одни правила разбора значений. Возвращает имена полей v, заданных в файле,
+// и имена полей файла, которых нет в v.
+func decodeFile(path string, data []byte, v any) ([]string, []string, error) {
+	var fields map[string]any
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &fields); err != nil {
+			return nil, nil, err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &fields); err != nil {
+			return nil, nil, err
+		}
+	default:
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(normalized, v); err != nil {
+		return nil, nil, err
+	}
+
+	known := jsonFieldNames(v)
+	var set, unknown []string
+	for name := range fields {
+		if field, ok := known[name]; ok {
+			set = append(set, field)
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(set)
+	slices.Sort(unknown)
+
+	return set, unknown, nil
+}
+
+// jsonFieldNames возвращает имена полей структуры по именам из тегов json.
+func jsonFieldNames(v any) map[string]string {
+	t := reflect.Indirect(reflect.ValueOf(v)).Type()
+
+	names := make(map[string]string, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = t.Field(i).Name
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile_Formats(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "json",
+			file: "agent.json",
+			data: `{"server_address": "localhost:9090", "poll_interval": "500ms", "report_interval": 10, "rate_limit": 3}`,
+		},
+		{
+			name: "yaml",
+			file: "agent.yaml",
+			data: "server_address: localhost:9090\npoll_interval: 500ms\nreport_interval: 10\nrate_limit: 3\n",
+		},
+		{
+			name: "yml",
+			file: "agent.yml",
+			data: "server_address: localhost:9090\npoll_interval: 500ms\nreport_interval: 10s\nrate_limit: 3\n",
+		},
+		{
+			name: "toml",
+			file: "agent.toml",
+			data: "server_address = \"localhost:9090\"\npoll_interval = \"500ms\"\nreport_interval = 10\nrate_limit = 3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0o644))
+
+			var config AgentConfig
+			fields, unknown, err := loadFile(path, &config)
+
+			require.NoError(t, err)
+			assert.Equal(t, "localhost:9090", config.ServerAddress)
+			assert.Equal(t, 500*time.Millisecond, config.PollInterval.Duration())
+			assert.Equal(t, 10*time.Second, config.ReportInterval.Duration())
+			assert.Equal(t, uint(3), config.RateLimit)
+			assert.Equal(t, []string{"PollInterval", "RateLimit", "ReportInterval", "ServerAddress"}, fields)
+			assert.Empty(t, unknown)
+		})
+	}
+}
+
+func TestLoadFile_UnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server_address: localhost:9090\nstore_intreval: 5m\nlog_lvl: debug\n"), 0o644))
+
+	var config ServerConfig
+	fields, unknown, err := loadFile(path, &config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:9090", config.ServerAddress)
+	assert.Equal(t, []string{"ServerAddress"}, fields)
+	assert.Equal(t, []string{"log_lvl", "store_intreval"}, unknown)
+}
+
+func TestLoadFile_InvalidYAML_Error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yml")
+	require.NoError(t, os.WriteFile(path, []byte("server_address: [localhost"), 0o644))
+
+	config, err := loadServerFile(path)
+
+	assert.Nil(t, config)
+	assert.ErrorContains(t, err, "error parsing config file")
+}
+
+func TestDefaultConfigFiles(t *testing.T) {
+	json, err := loadServerFile("../../configs/server.json")
+	require.NoError(t, err)
+
+	yaml, err := loadServerFile("../../configs/default.yml")
+	require.NoError(t, err)
+
+	assert.Equal(t, json, yaml)
+}
@@ -0,0 +1,34 @@
+package config
+
+import "reflect"
+
+// RedactedValue заменяет значения секретов в выводе конфигурации.
+const RedactedValue = "[REDACTED]"
+
+// Redact возвращает поля конфигурации по именам, заменяя значения полей
+// с тегом secret:"true" на RedactedValue. Незаданные секреты остаются пустыми,
+// чтобы по выводу было видно, задан ли секрет.
+func Redact(cfg any) map[string]any {
+	v := reflect.Indirect(reflect.ValueOf(cfg))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	fields := make(map[string]any, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Tag.Get("secret") == "true" && !value.IsZero() {
+			fields[field.Name] = RedactedValue
+			continue
+		}
+		fields[field.Name] = value.Interface()
+	}
+
+	return fields
+}
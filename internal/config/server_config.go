@@ -1,26 +1,70 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-
-	"github.com/caarlos0/env/v11"
 )
 
 type ServerConfig struct {
-	ConfigPath    string
-	ServerAddress string `env:"ADDRESS"`
-	LogLevel      string `env:"LOG_LEVEL"`
-	AppEnv        string `env:"APP_ENV"`
-	Key           string `env:"KEY"`
-	CryptoKey     string `env:"CRYPTO_KEY"`
-
-	StoreInterval   uint   `env:"STORE_INTERVAL"`
-	FileStoragePath string `env:"FILE_STORAGE_PATH"`
-	Restore         bool   `env:"RESTORE"`
-
-	DatabaseDSN string `env:"DATABASE_DSN"`
+	ConfigPath    string `json:"-"`
+	ServerAddress string `json:"server_address" env:"ADDRESS"`
+	LogLevel      string `json:"log_level" env:"LOG_LEVEL"`
+	AppEnv        string `json:"app_env" env:"APP_ENV"`
+	Key           string `json:"key" env:"KEY" secret:"true"`
+	CryptoKey     string `json:"crypto_key" env:"CRYPTO_KEY"`
+	KeysFile      string `json:"keys_file" env:"KEYS_FILE"`
+
+	AgentKeysFile     string `json:"agent_keys_file" env:"AGENT_KEYS_FILE"`
+	SignatureRequired bool   `json:"signature_required" env:"SIGNATURE_REQUIRED"`
+
+	// Если задан TLSClientCA, сервер принимает только агентов с сертификатом, подписанным этим CA.
+	TLSCert     string `json:"tls_cert" env:"TLS_CERT"`
+	TLSKey      string `json:"tls_key" env:"TLS_KEY"`
+	TLSClientCA string `json:"tls_client_ca" env:"TLS_CLIENT_CA"`
+
+	// Без строгого режима сервер принимает HMAC как от тела, так и с меткой времени и nonce.
+	// Порядок перехода: обновить серверы, включить signed_hash на агентах, затем включить hash_strict.
+	HashStrict     bool `json:"hash_strict" env:"HASH_STRICT"`
+	HashMaxSkew    uint `json:"hash_max_skew" env:"HASH_MAX_SKEW"`
+	NonceCacheSize uint `json:"nonce_cache_size" env:"NONCE_CACHE_SIZE"`
+
+	StoreInterval   Duration `json:"store_interval" env:"STORE_INTERVAL"`
+	FileStoragePath string   `json:"file_storage_path" env:"FILE_STORAGE_PATH"`
+	Restore         bool     `json:"restore" env:"RESTORE"`
+
+	DatabaseDSN string `json:"database_dsn" env:"DATABASE_DSN" secret:"true"`
+
+	// Из браузера страницы и поток метрик читаются с учетными данными в cookie
+	// api_key и access_token: браузер не может добавить к ним заголовки X-API-Key и Authorization.
+	// Cookie принимаются только для запросов GET и HEAD.
+	TenantsFile string `json:"tenants_file" env:"TENANTS_FILE"`
+	AuthFile    string `json:"auth_file" env:"AUTH_FILE"`
+
+	ValidateRequests bool `json:"validate_requests" env:"VALIDATE_REQUESTS"`
+
+	MetadataFile string `json:"metadata_file" env:"METADATA_FILE"`
+
+	TrustedSubnet string `json:"trusted_subnet" env:"TRUSTED_SUBNET"`
+
+	MaxMetricNames          uint    `json:"max_metric_names" env:"MAX_METRIC_NAMES"`
+	MaxMetricNamesPerTenant uint    `json:"max_metric_names_per_tenant" env:"MAX_METRIC_NAMES_PER_TENANT"`
+	MaxMetricNamesPerAgent  uint    `json:"max_metric_names_per_agent" env:"MAX_METRIC_NAMES_PER_AGENT"`
+	MaxBatchSize            uint    `json:"max_batch_size" env:"MAX_BATCH_SIZE"`
+	MaxBodySize             uint    `json:"max_body_size" env:"MAX_BODY_SIZE"`
+	RateLimit               float64 `json:"rate_limit" env:"RATE_LIMIT"`
+	RateBurst               uint    `json:"rate_burst" env:"RATE_BURST"`
+
+	TraceExporter    string  `json:"trace_exporter" env:"TRACE_EXPORTER"`
+	TraceEndpoint    string  `json:"trace_endpoint" env:"TRACE_ENDPOINT"`
+	TraceFile        string  `json:"trace_file" env:"TRACE_FILE"`
+	TraceSampleRatio float64 `json:"trace_sample_ratio" env:"TRACE_SAMPLE_RATIO"`
+
+	AdminAddress string `json:"admin_address" env:"ADMIN_ADDRESS"`
+	AdminToken   string `json:"admin_token" env:"ADMIN_TOKEN" secret:"true"`
+
+	sources Sources
 }
 
 func NewServerConfig() (*ServerConfig, error) {
@@ -28,70 +72,77 @@ func NewServerConfig() (*ServerConfig, error) {
 		ConfigPath: "configs/server.json",
 	}
 
-	if err := config.parseFlags(); err != nil {
-		return nil, err
-	}
-
-	defaultConfig, err := NewServerDefaultConfig(config.ConfigPath)
+	sources, unknownFields, err := loadLayers(config)
 	if err != nil {
-		return nil, fmt.Errorf("config.NewServerConfig: loading default config from '%s': %w", config.ConfigPath, err)
+		return nil, fmt.Errorf("config.NewServerConfig: %w", err)
 	}
+	config.sources = sources
 
-	if config.ServerAddress == "" {
-		config.ServerAddress = defaultConfig.ServerAddress
-	}
-	if config.LogLevel == "" {
-		config.LogLevel = defaultConfig.LogLevel
-	}
-	if config.AppEnv == "" {
-		config.AppEnv = defaultConfig.AppEnv
-	}
-	if config.Key == "" {
-		config.Key = defaultConfig.Key
-	}
-	if config.CryptoKey == "" {
-		config.CryptoKey = defaultConfig.CryptoKey
-	}
-	if config.StoreInterval == 0 {
-		config.StoreInterval = defaultConfig.StoreInterval
-	}
-	if config.FileStoragePath == "" {
-		config.FileStoragePath = defaultConfig.FileStoragePath
-	}
-	if !config.Restore {
-		config.Restore = defaultConfig.Restore
-	}
-	if config.DatabaseDSN == "" {
-		config.DatabaseDSN = defaultConfig.DatabaseDSN
-	}
-
-	if err := env.Parse(config); err != nil {
-		return nil, fmt.Errorf("config.NewServerConfig: parsing environment variables: %w", err)
+	if err := errors.Join(unknownFieldsError(config.ConfigPath, unknownFields), config.Validate()); err != nil {
+		return nil, fmt.Errorf("config.NewServerConfig: invalid configuration:\n%w", err)
 	}
 
 	return config, nil
 }
 
-func (c *ServerConfig) parseFlags() error {
+// Source возвращает слой, из которого взято значение поля конфигурации.
+func (c *ServerConfig) Source(field string) Source {
+	return c.sources.Source(field)
+}
+
+func (c *ServerConfig) configFile() string {
+	return c.ConfigPath
+}
+
+func (c *ServerConfig) parseFlags() (*flag.FlagSet, error) {
 	fs := flag.NewFlagSet("server", flag.ContinueOnError)
 
 	fs.StringVar(&c.ConfigPath, "c", c.ConfigPath, "Path to config file")
 	fs.StringVar(&c.ConfigPath, "config", c.ConfigPath, "Path to config file")
 	fs.StringVar(&c.ServerAddress, "a", c.ServerAddress, "Server address")
-	fs.UintVar(&c.StoreInterval, "i", c.StoreInterval, "Store interval in seconds")
+	fs.Var(&c.StoreInterval, "i", "Store interval in seconds or as duration like 5m, 0 for synchronous saving")
 	fs.StringVar(&c.FileStoragePath, "f", c.FileStoragePath, "File storage path")
 	fs.BoolVar(&c.Restore, "r", c.Restore, "Restore metrics from file storage")
 	fs.StringVar(&c.DatabaseDSN, "d", c.DatabaseDSN, "PostgreSQL DSN")
 	fs.StringVar(&c.Key, "k", c.Key, "Secret key for hashing")
+	fs.StringVar(&c.KeysFile, "keys-file", c.KeysFile, "Path to file with rotated HMAC and private keys")
+	fs.StringVar(&c.AgentKeysFile, "agent-keys-file", c.AgentKeysFile, "Path to registry of agent Ed25519 public keys")
+	fs.BoolVar(&c.SignatureRequired, "signature-required", c.SignatureRequired, "Require Ed25519 signatures on metric updates")
+	fs.StringVar(&c.TLSCert, "tls-cert", c.TLSCert, "Path to server TLS certificate, empty to serve plain HTTP")
+	fs.StringVar(&c.TLSKey, "tls-key", c.TLSKey, "Path to server TLS private key")
+	fs.StringVar(&c.TLSClientCA, "tls-client-ca", c.TLSClientCA, "Path to CA certificate that must sign agent certificates")
+	fs.BoolVar(&c.HashStrict, "hash-strict", c.HashStrict, "Require signed requests with timestamp and nonce")
+	fs.UintVar(&c.HashMaxSkew, "hash-max-skew", c.HashMaxSkew, "Allowed clock skew for signed requests in seconds")
+	fs.UintVar(&c.NonceCacheSize, "nonce-cache-size", c.NonceCacheSize, "Number of recently seen nonces to remember")
 	fs.StringVar(&c.CryptoKey, "crypto-key", c.CryptoKey, "Path to private key file for decryption")
+	fs.StringVar(&c.TenantsFile, "tenants-file", c.TenantsFile, "Path to tenants file with API keys")
+	fs.StringVar(&c.AuthFile, "auth-file", c.AuthFile, "Path to bearer token and JWT auth file")
+	fs.BoolVar(&c.ValidateRequests, "validate-requests", c.ValidateRequests, "Validate JSON requests against the OpenAPI specification")
+	fs.StringVar(&c.MetadataFile, "metadata-file", c.MetadataFile, "Path to file with metric types, units and descriptions")
+	fs.StringVar(&c.TrustedSubnet, "t", c.TrustedSubnet, "Trusted subnet in CIDR notation, empty to accept any client")
+	fs.UintVar(&c.MaxMetricNames, "max-metric-names", c.MaxMetricNames, "Maximum number of distinct metric names, 0 for no limit")
+	fs.UintVar(&c.MaxMetricNamesPerTenant, "max-metric-names-per-tenant", c.MaxMetricNamesPerTenant, "Maximum number of distinct metric names per tenant")
+	fs.UintVar(&c.MaxMetricNamesPerAgent, "max-metric-names-per-agent", c.MaxMetricNamesPerAgent, "Maximum number of distinct metric names per signed agent")
+	fs.UintVar(&c.MaxBatchSize, "max-batch-size", c.MaxBatchSize, "Maximum number of metrics in one /updates/ request")
+	fs.UintVar(&c.MaxBodySize, "max-body-size", c.MaxBodySize, "Maximum JSON request body size in bytes")
+	fs.Float64Var(&c.RateLimit, "rate-limit", c.RateLimit, "Allowed write requests per second from one source, 0 for no limit")
+	fs.UintVar(&c.RateBurst, "rate-burst", c.RateBurst, "Allowed burst of write requests from one source")
+
+	fs.StringVar(&c.TraceExporter, "trace-exporter", c.TraceExporter, "Trace exporter: none, otlp, stdout or file")
+	fs.StringVar(&c.TraceEndpoint, "trace-endpoint", c.TraceEndpoint, "OTLP/HTTP collector endpoint")
+	fs.StringVar(&c.TraceFile, "trace-file", c.TraceFile, "Path to file for the file trace exporter")
+	fs.Float64Var(&c.TraceSampleRatio, "trace-sample-ratio", c.TraceSampleRatio, "Fraction of traces started by this process to record")
+
+	fs.StringVar(&c.AdminAddress, "admin-address", c.AdminAddress, "Admin listener address, empty to disable")
+	fs.StringVar(&c.AdminToken, "admin-token", c.AdminToken, "Bearer token required by the admin listener")
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
-		return fmt.Errorf("config.ServerConfig.parseFlags: %w", err)
+		return nil, fmt.Errorf("config.ServerConfig.parseFlags: %w", err)
 	}
 
 	if fs.NArg() > 0 {
-		return fmt.Errorf("config.ServerConfig.parseFlags: unknown command line arguments: %v", fs.Args())
+		return nil, fmt.Errorf("config.ServerConfig.parseFlags: unknown command line arguments: %v", fs.Args())
 	}
 
-	return nil
+	return fs, nil
 }
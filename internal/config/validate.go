@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	ErrUnknownField = errors.New("unknown field")
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// Validate проверяет конфигурацию сервера и возвращает все найденные ошибки вместе.
+func (c *ServerConfig) Validate() error {
+	var clientCA error
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		clientCA = fmt.Errorf("%w: tls_client_ca requires tls_cert and tls_key", ErrInvalidValue)
+	}
+
+	return errors.Join(
+		validateLogLevel(c.LogLevel),
+		validateCIDR("trusted_subnet", c.TrustedSubnet),
+		validateFile("crypto_key", c.CryptoKey),
+		validateFile("keys_file", c.KeysFile),
+		validateFile("agent_keys_file", c.AgentKeysFile),
+		validatePair("tls_cert", c.TLSCert, "tls_key", c.TLSKey),
+		validateFile("tls_cert", c.TLSCert),
+		validateFile("tls_key", c.TLSKey),
+		validateFile("tls_client_ca", c.TLSClientCA),
+		clientCA,
+		validateFile("tenants_file", c.TenantsFile),
+		validateFile("auth_file", c.AuthFile),
+		validateFile("metadata_file", c.MetadataFile),
+	)
+}
+
+// Validate проверяет конфигурацию агента и возвращает все найденные ошибки вместе.
+func (c *AgentConfig) Validate() error {
+	var intervals error
+	if c.ReportInterval < c.PollInterval {
+		intervals = fmt.Errorf("%w: report_interval %s is less than poll_interval %s", ErrInvalidValue, c.ReportInterval, c.PollInterval)
+	}
+
+	return errors.Join(
+		validateLogLevel(c.LogLevel),
+		intervals,
+		validateFile("crypto_key", c.CryptoKey),
+		validateFile("signing_key", c.SigningKey),
+		validatePair("tls_cert", c.TLSCert, "tls_key", c.TLSKey),
+		validateFile("tls_ca", c.TLSCA),
+		validateFile("tls_cert", c.TLSCert),
+		validateFile("tls_key", c.TLSKey),
+	)
+}
+
+func unknownFieldsError(path string, fields []string) error {
+	errs := make([]error, 0, len(fields))
+	for _, field := range fields {
+		errs = append(errs, fmt.Errorf("%w '%s' in config file '%s'", ErrUnknownField, field, path))
+	}
+	return errors.Join(errs...)
+}
+
+func validateLogLevel(level string) error {
+	if _, err := zapcore.ParseLevel(level); err != nil {
+		return fmt.Errorf("%w: log_level '%s'", ErrInvalidValue, level)
+	}
+	return nil
+}
+
+func validateCIDR(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		return fmt.Errorf("%w: %s '%s' is not a CIDR subnet", ErrInvalidValue, field, value)
+	}
+	return nil
+}
+
+// validatePair проверяет, что связанные параметры заданы вместе.
+func validatePair(field, value, otherField, other string) error {
+	if (value == "") != (other == "") {
+		return fmt.Errorf("%w: %s and %s must be set together", ErrInvalidValue, field, otherField)
+	}
+	return nil
+}
+
+// validateFile проверяет, что файл, заданный в конфигурации, можно открыть.
+func validateFile(field, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %s file is not accessible: %w", ErrInvalidValue, field, err)
+	}
+	f.Close()
+
+	return nil
+}
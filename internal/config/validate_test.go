@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerConfig_Validate(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "private.pem")
+	require.NoError(t, os.WriteFile(keyFile, []byte("key"), 0o600))
+
+	valid := ServerConfig{LogLevel: "info", TrustedSubnet: "192.168.0.0/24", CryptoKey: keyFile}
+	assert.NoError(t, valid.Validate())
+
+	invalid := ServerConfig{
+		LogLevel:      "verbose",
+		TrustedSubnet: "192.168.0.0",
+		CryptoKey:     filepath.Join(t.TempDir(), "missing.pem"),
+		KeysFile:      filepath.Join(t.TempDir(), "missing.json"),
+		TLSKey:        keyFile,
+		TLSClientCA:   keyFile,
+	}
+	err := invalid.Validate()
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, "log_level 'verbose'")
+	assert.ErrorContains(t, err, "trusted_subnet '192.168.0.0' is not a CIDR subnet")
+	assert.ErrorContains(t, err, "crypto_key file is not accessible")
+	assert.ErrorContains(t, err, "keys_file file is not accessible")
+	assert.ErrorContains(t, err, "tls_cert and tls_key must be set together")
+	assert.ErrorContains(t, err, "tls_client_ca requires tls_cert and tls_key")
+}
+
+func TestAgentConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      AgentConfig
+		expectedErr []string
+	}{
+		{
+			name:   "valid",
+			config: AgentConfig{LogLevel: "info", PollInterval: Duration(2 * time.Second), ReportInterval: Duration(10 * time.Second)},
+		},
+		{
+			name:   "equal intervals",
+			config: AgentConfig{LogLevel: "info", PollInterval: Duration(time.Second), ReportInterval: Duration(time.Second)},
+		},
+		{
+			name: "all errors together",
+			config: AgentConfig{
+				LogLevel:       "info",
+				PollInterval:   Duration(10 * time.Second),
+				ReportInterval: Duration(500 * time.Millisecond),
+				SigningKey:     "/path/that/does/not/exist/agent.pem",
+				TLSCert:        "/path/that/does/not/exist/client.pem",
+			},
+			expectedErr: []string{
+				"report_interval 500ms is less than poll_interval 10s",
+				"signing_key file is not accessible",
+				"tls_cert and tls_key must be set together",
+				"tls_cert file is not accessible",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if len(tt.expectedErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidValue)
+			for _, msg := range tt.expectedErr {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
+
+func TestUnknownFieldsError(t *testing.T) {
+	assert.NoError(t, unknownFieldsError("server.json", nil))
+
+	err := unknownFieldsError("server.json", []string{"log_lvl", "store_intreval"})
+	assert.ErrorIs(t, err, ErrUnknownField)
+	assert.ErrorContains(t, err, "unknown field 'log_lvl' in config file 'server.json'")
+	assert.ErrorContains(t, err, "unknown field 'store_intreval' in config file 'server.json'")
+}
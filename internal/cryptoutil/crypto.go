@@ -9,6 +9,9 @@ import (
 	"os"
 )
 
+// KeyIDHeader - заголовок запроса с идентификатором ключа, которым зашифровано тело.
+const KeyIDHeader = "X-Crypto-Key-ID"
+
 // PublicKeyProvider реализует шифрование с помощью публичного ключа
 type PublicKeyProvider struct {
 	publicKey *rsa.PublicKey
@@ -96,25 +99,20 @@ func (p *PrivateKeyProvider) Decrypt(data []byte) ([]byte, error) {
 }
 
 // GenerateKeyPair генерирует новую пару RSA ключей и сохраняет их в файлы
-func GenerateKeyPair(privateKeyPath, publicKeyPath string, bits int) error {
+func GenerateKeyPair(privateKeyPath, publicKeyPath string, bits int, opts ...FileOption) error {
+	o := newFileOptions(opts)
+	if err := checkNotExist(o, privateKeyPath, publicKeyPath); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateKeyPair: %w", err)
+	}
+
 	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
 	if err != nil {
 		return fmt.Errorf("cryptoutil.GenerateKeyPair: failed to generate private key: %w", err)
 	}
 
 	// Сохраняем приватный ключ
-	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
-	if err != nil {
-		return fmt.Errorf("cryptoutil.GenerateKeyPair: failed to marshal private key: %w", err)
-	}
-
-	privateKeyPEM := &pem.Block{
-		Type:  "PRIVATE KEY",
-		Bytes: privateKeyBytes,
-	}
-
-	if err := os.WriteFile(privateKeyPath, pem.EncodeToMemory(privateKeyPEM), 0o600); err != nil {
-		return fmt.Errorf("cryptoutil.GenerateKeyPair: failed to write private key file: %w", err)
+	if err := writePrivateKey(privateKeyPath, privateKey, o); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateKeyPair: %w", err)
 	}
 
 	// Сохраняем публичный ключ
@@ -123,12 +121,7 @@ func GenerateKeyPair(privateKeyPath, publicKeyPath string, bits int) error {
 		return fmt.Errorf("cryptoutil.GenerateKeyPair: failed to marshal public key: %w", err)
 	}
 
-	publicKeyPEM := &pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: publicKeyBytes,
-	}
-
-	if err := os.WriteFile(publicKeyPath, pem.EncodeToMemory(publicKeyPEM), 0o644); err != nil {
+	if err := writePEM(publicKeyPath, "PUBLIC KEY", publicKeyBytes, PublicFileMode, o); err != nil {
 		return fmt.Errorf("cryptoutil.GenerateKeyPair: failed to write public key file: %w", err)
 	}
 
@@ -0,0 +1,302 @@
+package cryptoutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Права доступа к сгенерированным файлам
+const (
+	PrivateFileMode os.FileMode = 0o600
+	PublicFileMode  os.FileMode = 0o644
+)
+
+// Имена файлов, создаваемых GenerateCertificates
+const (
+	CACertFile     = "ca.pem"
+	CAKeyFile      = "ca-key.pem"
+	ServerCertFile = "server.pem"
+	ServerKeyFile  = "server-key.pem"
+	ClientCertFile = "client.pem"
+	ClientKeyFile  = "client-key.pem"
+)
+
+// CertificateOptions задает параметры генерации сертификатов для mTLS.
+type CertificateOptions struct {
+	// Hosts - DNS имена и IP адреса сервера, попадающие в SAN сертификата сервера
+	Hosts []string
+	// ClientName - CommonName сертификата клиента (агента)
+	ClientName string
+	// ValidFor - срок действия сертификатов
+	ValidFor time.Duration
+	// Overwrite разрешает перезаписать существующие файлы в каталоге
+	Overwrite bool
+}
+
+// FileOption задает поведение при записи файлов ключей.
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	overwrite bool
+}
+
+// WithOverwrite разрешает перезаписать существующие файлы ключей.
+// Без этой опции генерация завершается ошибкой, если хотя бы один файл уже есть.
+func WithOverwrite() FileOption {
+	return func(o *fileOptions) {
+		o.overwrite = true
+	}
+}
+
+func newFileOptions(opts []FileOption) fileOptions {
+	var o fileOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// GenerateEd25519KeyPair генерирует пару ключей Ed25519 и сохраняет их в файлы
+func GenerateEd25519KeyPair(privateKeyPath, publicKeyPath string, opts ...FileOption) error {
+	o := newFileOptions(opts)
+	if err := checkNotExist(o, privateKeyPath, publicKeyPath); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateEd25519KeyPair: %w", err)
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("cryptoutil.GenerateEd25519KeyPair: failed to generate key: %w", err)
+	}
+
+	if err := writePrivateKey(privateKeyPath, privateKey, o); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateEd25519KeyPair: %w", err)
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("cryptoutil.GenerateEd25519KeyPair: failed to marshal public key: %w", err)
+	}
+
+	if err := writePEM(publicKeyPath, "PUBLIC KEY", publicKeyBytes, PublicFileMode, o); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateEd25519KeyPair: failed to write public key file: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateHMACSecret возвращает случайный секрет длиной size байт в шестнадцатеричном виде
+func GenerateHMACSecret(size int) (string, error) {
+	if size <= 0 {
+		return "", errors.New("cryptoutil.GenerateHMACSecret: size must be positive")
+	}
+
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("cryptoutil.GenerateHMACSecret: %w", err)
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// WriteSecretFile сохраняет секрет в файл, доступный только владельцу
+func WriteSecretFile(path, secret string, opts ...FileOption) error {
+	if err := writeFile(path, []byte(secret+"\n"), PrivateFileMode, newFileOptions(opts)); err != nil {
+		return fmt.Errorf("cryptoutil.WriteSecretFile: failed to write secret file: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateCertificates создает в каталоге dir самоподписанный CA,
+// сертификат сервера и сертификат клиента, подписанные этим CA.
+func GenerateCertificates(dir string, opts CertificateOptions) error {
+	if len(opts.Hosts) == 0 {
+		return errors.New("cryptoutil.GenerateCertificates: at least one server host is required")
+	}
+	if opts.ValidFor <= 0 {
+		return errors.New("cryptoutil.GenerateCertificates: validity period must be positive")
+	}
+	if opts.ClientName == "" {
+		opts.ClientName = "metrics-agent"
+	}
+
+	o := fileOptions{overwrite: opts.Overwrite}
+	var paths []string
+	for _, name := range []string{CACertFile, CAKeyFile, ServerCertFile, ServerKeyFile, ClientCertFile, ClientKeyFile} {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	if err := checkNotExist(o, paths...); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateCertificates: %w", err)
+	}
+
+	notBefore := time.Now().Add(-time.Minute)
+	notAfter := notBefore.Add(opts.ValidFor)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("cryptoutil.GenerateCertificates: failed to generate CA key: %w", err)
+	}
+
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "metrics CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	caCert, err := issueCertificate(filepath.Join(dir, CACertFile), filepath.Join(dir, CAKeyFile), caTemplate, nil, caKey, caKey, o)
+	if err != nil {
+		return fmt.Errorf("cryptoutil.GenerateCertificates: CA: %w", err)
+	}
+
+	serverTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: opts.Hosts[0]},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range opts.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+
+	if err := issueLeaf(filepath.Join(dir, ServerCertFile), filepath.Join(dir, ServerKeyFile), serverTemplate, caCert, caKey, o); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateCertificates: server: %w", err)
+	}
+
+	clientTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: opts.ClientName},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if err := issueLeaf(filepath.Join(dir, ClientCertFile), filepath.Join(dir, ClientKeyFile), clientTemplate, caCert, caKey, o); err != nil {
+		return fmt.Errorf("cryptoutil.GenerateCertificates: client: %w", err)
+	}
+
+	return nil
+}
+
+func issueLeaf(certPath, keyPath string, template, parent *x509.Certificate, parentKey crypto.Signer, o fileOptions) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	_, err = issueCertificate(certPath, keyPath, template, parent, key, parentKey, o)
+	return err
+}
+
+// issueCertificate подписывает сертификат ключом parentKey и сохраняет его вместе с ключом key.
+// Если parent не задан, сертификат самоподписанный.
+func issueCertificate(certPath, keyPath string, template, parent *x509.Certificate, key, parentKey crypto.Signer, o fileOptions) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template.SerialNumber = serial
+
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	if err := writePrivateKey(keyPath, key, o); err != nil {
+		return nil, err
+	}
+
+	if err := writePEM(certPath, "CERTIFICATE", der, PublicFileMode, o); err != nil {
+		return nil, fmt.Errorf("failed to write certificate file: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+func writePrivateKey(path string, key any, o fileOptions) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	if err := writePEM(path, "PRIVATE KEY", der, PrivateFileMode, o); err != nil {
+		return fmt.Errorf("failed to write private key file: %w", err)
+	}
+
+	return nil
+}
+
+func writePEM(path, blockType string, der []byte, perm os.FileMode, o fileOptions) error {
+	return writeFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), perm, o)
+}
+
+// checkNotExist проверяет до генерации, что ни один из файлов не существует,
+// чтобы не оставить половину пары ключей при отказе.
+func checkNotExist(o fileOptions, paths ...string) error {
+	if o.overwrite {
+		return nil
+	}
+
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil {
+			return fmt.Errorf("file '%s' already exists: %w", path, os.ErrExist)
+		}
+	}
+
+	return nil
+}
+
+// writeFile создает файл с правами perm. Существующий файл перезаписывается только
+// с опцией WithOverwrite, при этом его права тоже приводятся к perm.
+func writeFile(path string, data []byte, perm os.FileMode, o fileOptions) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if o.overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(path, flags, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, perm)
+}
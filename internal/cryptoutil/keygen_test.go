@@ -0,0 +1,192 @@
+package cryptoutil
+
+import (
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertMode(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, mode, info.Mode().Perm(), path)
+}
+
+func readPEM(t *testing.T, path, blockType string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	assert.Equal(t, blockType, block.Type)
+
+	return block.Bytes
+}
+
+func TestGenerateEd25519KeyPair(t *testing.T) {
+	dir := t.TempDir()
+	privateKeyPath := filepath.Join(dir, "agent.pem")
+	publicKeyPath := filepath.Join(dir, "agent.pub")
+
+	require.NoError(t, GenerateEd25519KeyPair(privateKeyPath, publicKeyPath))
+
+	assertMode(t, privateKeyPath, PrivateFileMode)
+	assertMode(t, publicKeyPath, PublicFileMode)
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(readPEM(t, privateKeyPath, "PRIVATE KEY"))
+	require.NoError(t, err)
+	edPrivateKey, ok := privateKey.(ed25519.PrivateKey)
+	require.True(t, ok)
+
+	publicKey, err := x509.ParsePKIXPublicKey(readPEM(t, publicKeyPath, "PUBLIC KEY"))
+	require.NoError(t, err)
+	edPublicKey, ok := publicKey.(ed25519.PublicKey)
+	require.True(t, ok)
+
+	signature := ed25519.Sign(edPrivateKey, []byte("message"))
+	assert.True(t, ed25519.Verify(edPublicKey, []byte("message"), signature))
+}
+
+func TestGenerateEd25519KeyPair_ExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	privateKeyPath := filepath.Join(dir, "agent.pem")
+	publicKeyPath := filepath.Join(dir, "agent.pub")
+
+	require.NoError(t, os.WriteFile(privateKeyPath, []byte("old"), 0o666))
+
+	err := GenerateEd25519KeyPair(privateKeyPath, publicKeyPath)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	data, err := os.ReadFile(privateKeyPath)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data), "existing key must not be overwritten")
+	assert.NoFileExists(t, publicKeyPath, "no half of the pair must be written")
+
+	// существующий файл с широкими правами должен получить правильные права
+	require.NoError(t, GenerateEd25519KeyPair(privateKeyPath, publicKeyPath, WithOverwrite()))
+	assertMode(t, privateKeyPath, PrivateFileMode)
+	assertMode(t, publicKeyPath, PublicFileMode)
+	readPEM(t, privateKeyPath, "PRIVATE KEY")
+}
+
+func TestGenerateEd25519KeyPair_InvalidPath(t *testing.T) {
+	err := GenerateEd25519KeyPair("/nonexistent/directory/agent.pem", filepath.Join(t.TempDir(), "agent.pub"))
+	assert.ErrorContains(t, err, "failed to write private key file")
+}
+
+func TestGenerateHMACSecret(t *testing.T) {
+	secret, err := GenerateHMACSecret(32)
+	require.NoError(t, err)
+
+	raw, err := hex.DecodeString(secret)
+	require.NoError(t, err)
+	assert.Len(t, raw, 32)
+
+	other, err := GenerateHMACSecret(32)
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	_, err = GenerateHMACSecret(0)
+	assert.Error(t, err)
+}
+
+func TestGenerateCertificates(t *testing.T) {
+	dir := t.TempDir()
+
+	err := GenerateCertificates(dir, CertificateOptions{
+		Hosts:      []string{"metrics.local", "127.0.0.1"},
+		ClientName: "agent-1",
+		ValidFor:   24 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{CAKeyFile, ServerKeyFile, ClientKeyFile} {
+		assertMode(t, filepath.Join(dir, name), PrivateFileMode)
+	}
+	for _, name := range []string{CACertFile, ServerCertFile, ClientCertFile} {
+		assertMode(t, filepath.Join(dir, name), PublicFileMode)
+	}
+
+	caCert, err := x509.ParseCertificate(readPEM(t, filepath.Join(dir, CACertFile), "CERTIFICATE"))
+	require.NoError(t, err)
+	assert.True(t, caCert.IsCA)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	serverCert, err := x509.ParseCertificate(readPEM(t, filepath.Join(dir, ServerCertFile), "CERTIFICATE"))
+	require.NoError(t, err)
+	_, err = serverCert.Verify(x509.VerifyOptions{
+		DNSName:   "metrics.local",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	assert.NoError(t, err)
+	_, err = serverCert.Verify(x509.VerifyOptions{DNSName: "127.0.0.1", Roots: roots})
+	assert.NoError(t, err)
+
+	clientCert, err := x509.ParseCertificate(readPEM(t, filepath.Join(dir, ClientCertFile), "CERTIFICATE"))
+	require.NoError(t, err)
+	assert.Equal(t, "agent-1", clientCert.Subject.CommonName)
+	_, err = clientCert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NoError(t, err)
+
+	_, err = tls.LoadX509KeyPair(filepath.Join(dir, ServerCertFile), filepath.Join(dir, ServerKeyFile))
+	assert.NoError(t, err)
+	_, err = tls.LoadX509KeyPair(filepath.Join(dir, ClientCertFile), filepath.Join(dir, ClientKeyFile))
+	assert.NoError(t, err)
+}
+
+func TestGenerateCertificates_ExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	opts := CertificateOptions{Hosts: []string{"localhost"}, ValidFor: time.Hour}
+
+	require.NoError(t, GenerateCertificates(dir, opts))
+	caCert := readPEM(t, filepath.Join(dir, CACertFile), "CERTIFICATE")
+
+	err := GenerateCertificates(dir, opts)
+	assert.ErrorIs(t, err, os.ErrExist)
+	assert.Equal(t, caCert, readPEM(t, filepath.Join(dir, CACertFile), "CERTIFICATE"))
+
+	opts.Overwrite = true
+	require.NoError(t, GenerateCertificates(dir, opts))
+	assert.NotEqual(t, caCert, readPEM(t, filepath.Join(dir, CACertFile), "CERTIFICATE"))
+}
+
+func TestWriteSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hmac.secret")
+
+	require.NoError(t, WriteSecretFile(path, "one"))
+	assertMode(t, path, PrivateFileMode)
+
+	assert.ErrorIs(t, WriteSecretFile(path, "two"), os.ErrExist)
+
+	require.NoError(t, WriteSecretFile(path, "two", WithOverwrite()))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "two\n", string(data))
+}
+
+func TestGenerateCertificates_InvalidOptions(t *testing.T) {
+	err := GenerateCertificates(t.TempDir(), CertificateOptions{ValidFor: time.Hour})
+	assert.ErrorContains(t, err, "at least one server host is required")
+
+	err = GenerateCertificates(t.TempDir(), CertificateOptions{Hosts: []string{"localhost"}})
+	assert.ErrorContains(t, err, "validity period must be positive")
+}
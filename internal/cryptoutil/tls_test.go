@@ -0,0 +1,64 @@
+package cryptoutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, GenerateCertificates(dir, CertificateOptions{
+		Hosts:    []string{"127.0.0.1"},
+		ValidFor: time.Hour,
+	}))
+
+	serverCfg, err := ServerTLSConfig(filepath.Join(dir, ServerCertFile), filepath.Join(dir, ServerKeyFile), filepath.Join(dir, CACertFile))
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = serverCfg
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(caFile, certFile, keyFile string) error {
+		clientCfg, err := ClientTLSConfig(caFile, certFile, keyFile)
+		require.NoError(t, err)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientCfg}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		return nil
+	}
+
+	assert.NoError(t, get(filepath.Join(dir, CACertFile), filepath.Join(dir, ClientCertFile), filepath.Join(dir, ClientKeyFile)))
+	assert.Error(t, get(filepath.Join(dir, CACertFile), "", ""), "client certificate is required")
+	assert.Error(t, get("", filepath.Join(dir, ClientCertFile), filepath.Join(dir, ClientKeyFile)), "server must be verified by CA")
+}
+
+func TestTLSConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o644))
+
+	_, err := ServerTLSConfig(filepath.Join(dir, "missing.pem"), filepath.Join(dir, "missing-key.pem"), "")
+	assert.ErrorContains(t, err, "failed to load certificate")
+
+	_, err = ClientTLSConfig(notPEM, "", "")
+	assert.ErrorContains(t, err, "no certificates found")
+
+	_, err = ClientTLSConfig("", filepath.Join(dir, "client.pem"), "")
+	assert.ErrorContains(t, err, "failed to load certificate")
+}
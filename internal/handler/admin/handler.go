@@ -0,0 +1,133 @@
+// Package admin реализует служебный HTTP интерфейс сервера и агента:
+// уровень логирования, действующую конфигурацию и сведения о сборке.
+// Интерфейс слушает отдельный адрес и может требовать bearer токен.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/NoobyTheTurtle/metrics/internal/util"
+	"github.com/go-chi/chi/v5"
+)
+
+const ContentTypeValue = "application/json"
+
+// LogLevel - тело запроса и ответа /admin/loglevel.
+type LogLevel struct {
+	Level string `json:"level"`
+}
+
+// ConfigSource возвращает действующую конфигурацию с замаскированными секретами.
+type ConfigSource func() map[string]any
+
+type Handler struct {
+	logger    AdminLogger
+	config    ConfigSource
+	buildInfo util.BuildInfo
+	token     string
+}
+
+type Option func(*Handler)
+
+// WithToken требует заголовок Authorization: Bearer <token> во всех запросах.
+// Пустой токен отключает проверку.
+func WithToken(token string) Option {
+	return func(h *Handler) {
+		h.token = token
+	}
+}
+
+func NewHandler(logger AdminLogger, config ConfigSource, buildInfo util.BuildInfo, opts ...Option) *Handler {
+	h := &Handler{
+		logger:    logger,
+		config:    config,
+		buildInfo: buildInfo,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Routes возвращает маршруты служебного интерфейса.
+func (h *Handler) Routes() http.Handler {
+	r := chi.NewRouter()
+	if h.token != "" {
+		r.Use(h.authorize)
+	}
+
+	r.Get("/admin/loglevel", h.getLogLevel)
+	r.Put("/admin/loglevel", h.setLogLevel)
+	r.Get("/admin/config", h.getConfig)
+	r.Get("/admin/buildinfo", h.getBuildInfo)
+
+	return r
+}
+
+func (h *Handler) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) getLogLevel(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, LogLevel{Level: h.logger.Level()})
+}
+
+func (h *Handler) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var req LogLevel
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	previous := h.logger.Level()
+	if err := h.logger.SetLevel(req.Level); err != nil {
+		http.Error(w, "Unknown log level '"+req.Level+"'", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Info("Log level changed from %s to %s", previous, h.logger.Level())
+
+	writeJSON(w, http.StatusOK, LogLevel{Level: h.logger.Level()})
+}
+
+func (h *Handler) getConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.config())
+}
+
+func (h *Handler) getBuildInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.buildInfo)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeValue)
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_Routes(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		token          string
+		authorization  string
+		setupMocks     func(logger *MockAdminLogger)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "get log level",
+			method: http.MethodGet,
+			path:   "/admin/loglevel",
+			setupMocks: func(logger *MockAdminLogger) {
+				logger.EXPECT().Level().Return("info")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level":"info"}`,
+		},
+		{
+			name:   "set log level",
+			method: http.MethodPut,
+			path:   "/admin/loglevel",
+			body:   `{"level":"debug"}`,
+			setupMocks: func(logger *MockAdminLogger) {
+				gomock.InOrder(
+					logger.EXPECT().Level().Return("info"),
+					logger.EXPECT().SetLevel("debug").Return(nil),
+					logger.EXPECT().Level().Return("debug").Times(2),
+				)
+				logger.EXPECT().Info("Log level changed from %s to %s", "info", "debug")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level":"debug"}`,
+		},
+		{
+			name:   "set unknown log level",
+			method: http.MethodPut,
+			path:   "/admin/loglevel",
+			body:   `{"level":"verbose"}`,
+			setupMocks: func(logger *MockAdminLogger) {
+				logger.EXPECT().Level().Return("info")
+				logger.EXPECT().SetLevel("verbose").Return(errors.New("unrecognized level"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Unknown log level 'verbose'\n",
+		},
+		{
+			name:           "set log level invalid json",
+			method:         http.MethodPut,
+			path:           "/admin/loglevel",
+			body:           `{`,
+			setupMocks:     func(logger *MockAdminLogger) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON format\n",
+		},
+		{
+			name:           "config",
+			method:         http.MethodGet,
+			path:           "/admin/config",
+			setupMocks:     func(logger *MockAdminLogger) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"Key":"[REDACTED]","ServerAddress":"localhost:8080"}`,
+		},
+		{
+			name:           "build info",
+			method:         http.MethodGet,
+			path:           "/admin/buildinfo",
+			setupMocks:     func(logger *MockAdminLogger) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"version":"1.0.0","date":"N/A","commit":"abc123"}`,
+		},
+		{
+			name:           "missing token",
+			method:         http.MethodGet,
+			path:           "/admin/buildinfo",
+			token:          "secret",
+			setupMocks:     func(logger *MockAdminLogger) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Unauthorized\n",
+		},
+		{
+			name:           "wrong token",
+			method:         http.MethodGet,
+			path:           "/admin/buildinfo",
+			token:          "secret",
+			authorization:  "Bearer other",
+			setupMocks:     func(logger *MockAdminLogger) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Unauthorized\n",
+		},
+		{
+			name:           "valid token",
+			method:         http.MethodGet,
+			path:           "/admin/buildinfo",
+			token:          "secret",
+			authorization:  "Bearer secret",
+			setupMocks:     func(logger *MockAdminLogger) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"version":"1.0.0","date":"N/A","commit":"abc123"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			logger := NewMockAdminLogger(ctrl)
+			tt.setupMocks(logger)
+
+			config := func() map[string]any {
+				return map[string]any{"ServerAddress": "localhost:8080", "Key": "[REDACTED]"}
+			}
+			h := NewHandler(logger, config, util.NewBuildInfo("1.0.0", "", "abc123"), WithToken(tt.token))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			h.Routes().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package admin
+
+import (
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+)
+
+// AdminLogger - логгер с уровнем, который можно менять без перезапуска
+type AdminLogger interface {
+	Info(format string, args ...any)
+	Level() string
+	SetLevel(level string) error
+}
+
+var (
+	_ AdminLogger = (*logger.ZapLogger)(nil)
+	_ AdminLogger = (*MockAdminLogger)(nil)
+)
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/handler/admin/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/handler/admin/interfaces.go -destination=./internal/handler/admin/mocks.go -package=admin
+//
+
+// Package admin is a generated GoMock package.
+package admin
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdminLogger is a mock of AdminLogger interface.
+type MockAdminLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockAdminLoggerMockRecorder is the mock recorder for MockAdminLogger.
+type MockAdminLoggerMockRecorder struct {
+	mock *MockAdminLogger
+}
+
+// NewMockAdminLogger creates a new mock instance.
+func NewMockAdminLogger(ctrl *gomock.Controller) *MockAdminLogger {
+	mock := &MockAdminLogger{ctrl: ctrl}
+	mock.recorder = &MockAdminLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminLogger) EXPECT() *MockAdminLoggerMockRecorder {
+	return m.recorder
+}
+
+// Info mocks base method.
+func (m *MockAdminLogger) Info(format string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockAdminLoggerMockRecorder) Info(format any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockAdminLogger)(nil).Info), varargs...)
+}
+
+// Level mocks base method.
+func (m *MockAdminLogger) Level() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Level")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Level indicates an expected call of Level.
+func (mr *MockAdminLoggerMockRecorder) Level() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Level", reflect.TypeOf((*MockAdminLogger)(nil).Level))
+}
+
+// SetLevel mocks base method.
+func (m *MockAdminLogger) SetLevel(level string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLevel", level)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLevel indicates an expected call of SetLevel.
+func (mr *MockAdminLoggerMockRecorder) SetLevel(level any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLevel", reflect.TypeOf((*MockAdminLogger)(nil).SetLevel), level)
+}
@@ -0,0 +1,13 @@
+package api
+
+const (
+	ContentTypeValue        = "application/json"
+	ProblemContentTypeValue = "application/problem+json"
+
+	// DefaultLimit - размер страницы списка метрик по умолчанию.
+	DefaultLimit = 100
+	// MaxLimit - максимальный размер страницы списка метрик.
+	MaxLimit = 1000
+	// MaxBatchSize - максимальное количество метрик в запросе POST /api/v1/values.
+	MaxBatchSize = 1000
+)
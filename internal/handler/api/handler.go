@@ -0,0 +1,61 @@
+// Package api реализует версионированный REST API метрик (/api/v1):
+// список метрик с фильтрацией и постраничной выдачей, чтение одной метрики,
+// пакетное чтение значений и метаданные метрик. Ошибки возвращаются в формате RFC 7807.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type Handler struct {
+	storage HandlerStorage
+}
+
+func NewHandler(storage HandlerStorage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+func (h *Handler) ListHandler() http.HandlerFunc {
+	handler := newListHandler(h.storage)
+	return handler.ServeHTTP
+}
+
+func (h *Handler) MetricHandler() http.HandlerFunc {
+	handler := newMetricHandler(h.storage)
+	return handler.ServeHTTP
+}
+
+func (h *Handler) ValuesHandler() http.HandlerFunc {
+	handler := newValuesHandler(h.storage)
+	return handler.ServeHTTP
+}
+
+func (h *Handler) MetadataListHandler() http.HandlerFunc {
+	handler := newMetadataHandler(h.storage)
+	return handler.list
+}
+
+func (h *Handler) MetadataHandler() http.HandlerFunc {
+	handler := newMetadataHandler(h.storage)
+	return handler.get
+}
+
+func (h *Handler) DeclareMetadataHandler() http.HandlerFunc {
+	handler := newMetadataHandler(h.storage)
+	return handler.put
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		WriteProblem(w, http.StatusInternalServerError, "Failed to marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeValue)
+	w.WriteHeader(status)
+	w.Write(data)
+}
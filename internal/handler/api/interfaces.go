@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/storage/adapter"
+)
+
+type GaugesGetter interface {
+	GetAllGauges(ctx context.Context) (map[string]float64, error)
+}
+
+type CountersGetter interface {
+	GetAllCounters(ctx context.Context) (map[string]int64, error)
+}
+
+type HistogramsGetter interface {
+	GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error)
+}
+
+type GaugeGetter interface {
+	GetGauge(ctx context.Context, name string) (float64, bool)
+}
+
+type CounterGetter interface {
+	GetCounter(ctx context.Context, name string) (int64, bool)
+}
+
+type HistogramGetter interface {
+	GetHistogram(ctx context.Context, name string) (model.Histogram, bool)
+}
+
+type MetadataGetter interface {
+	Metadata(ctx context.Context, name string) (metadata.Metadata, bool)
+	ListMetadata(ctx context.Context) []metadata.Metadata
+}
+
+type MetadataDeclarer interface {
+	DeclareMetadata(ctx context.Context, m metadata.Metadata) (metadata.Metadata, error)
+}
+
+type MetadataStorage interface {
+	MetadataGetter
+	MetadataDeclarer
+}
+
+type HandlerStorage interface {
+	GaugesGetter
+	CountersGetter
+	HistogramsGetter
+	GaugeGetter
+	CounterGetter
+	HistogramGetter
+	MetadataStorage
+}
+
+var _ HandlerStorage = (*adapter.MetricStorage)(nil)
+var _ HandlerStorage = (*MockHandlerStorage)(nil)
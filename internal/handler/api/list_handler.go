@@ -0,0 +1,202 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
+
+// ListResponse - ответ GET /api/v1/metrics.
+// NextCursor передается в параметре cursor для получения следующей страницы
+// и отсутствует на последней странице.
+type ListResponse struct {
+	Metrics    model.Metrics `json:"metrics"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+type listQuery struct {
+	mType  model.MetricType
+	prefix string
+	regex  *regexp.Regexp
+	limit  int
+	after  *cursor
+}
+
+// cursor указывает на последнюю метрику предыдущей страницы.
+type cursor struct {
+	name  string
+	mType model.MetricType
+}
+
+func (c cursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(string(c.mType) + ":" + c.name))
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	mType, name, ok := strings.Cut(string(data), ":")
+	if !ok || !validType(model.MetricType(mType)) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor{name: name, mType: model.MetricType(mType)}, nil
+}
+
+// compareMetrics упорядочивает метрики по имени, затем по типу.
+func compareMetrics(a, b cursor) int {
+	if c := strings.Compare(a.name, b.name); c != 0 {
+		return c
+	}
+	return strings.Compare(string(a.mType), string(b.mType))
+}
+
+func validType(mType model.MetricType) bool {
+	return metadata.ValidType(mType)
+}
+
+func parseListQuery(r *http.Request) (listQuery, error) {
+	query := r.URL.Query()
+	q := listQuery{
+		mType:  model.MetricType(query.Get("type")),
+		prefix: query.Get("prefix"),
+		limit:  DefaultLimit,
+	}
+
+	if q.mType != "" && !validType(q.mType) {
+		return q, fmt.Errorf("unknown metric type '%s'", q.mType)
+	}
+
+	if expr := query.Get("regex"); expr != "" {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return q, fmt.Errorf("invalid regex: %v", err)
+		}
+		q.regex = re
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxLimit {
+			return q, fmt.Errorf("limit must be an integer between 1 and %d", MaxLimit)
+		}
+		q.limit = n
+	}
+
+	if c := query.Get("cursor"); c != "" {
+		after, err := decodeCursor(c)
+		if err != nil {
+			return q, err
+		}
+		q.after = after
+	}
+
+	return q, nil
+}
+
+func (q listQuery) match(name string) bool {
+	if !strings.HasPrefix(name, q.prefix) {
+		return false
+	}
+	return q.regex == nil || q.regex.MatchString(name)
+}
+
+type ListStorage interface {
+	GaugesGetter
+	CountersGetter
+	HistogramsGetter
+}
+
+type listHandler struct {
+	storage ListStorage
+}
+
+func newListHandler(storage ListStorage) *listHandler {
+	return &listHandler{
+		storage: storage,
+	}
+}
+
+// ServeHTTP возвращает страницу метрик, отсортированных по имени и типу.
+func (h *listHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r)
+	if err != nil {
+		WriteProblem(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var metrics model.Metrics
+
+	if q.mType == "" || q.mType == model.GaugeType {
+		gauges, err := h.storage.GetAllGauges(r.Context())
+		if err != nil {
+			WriteProblem(w, http.StatusInternalServerError, "Failed to get gauges")
+			return
+		}
+		for name, value := range gauges {
+			if q.match(name) {
+				metrics = append(metrics, model.Metric{ID: name, MType: model.GaugeType, Value: &value})
+			}
+		}
+	}
+
+	if q.mType == "" || q.mType == model.CounterType {
+		counters, err := h.storage.GetAllCounters(r.Context())
+		if err != nil {
+			WriteProblem(w, http.StatusInternalServerError, "Failed to get counters")
+			return
+		}
+		for name, value := range counters {
+			if q.match(name) {
+				metrics = append(metrics, model.Metric{ID: name, MType: model.CounterType, Delta: &value})
+			}
+		}
+	}
+
+	if q.mType == "" || q.mType == model.HistogramType {
+		histograms, err := h.storage.GetAllHistograms(r.Context())
+		if err != nil {
+			WriteProblem(w, http.StatusInternalServerError, "Failed to get histograms")
+			return
+		}
+		for name, value := range histograms {
+			if q.match(name) {
+				metrics = append(metrics, model.Metric{ID: name, MType: model.HistogramType, Histogram: &value})
+			}
+		}
+	}
+
+	slices.SortFunc(metrics, func(a, b model.Metric) int {
+		return compareMetrics(cursor{a.ID, a.MType}, cursor{b.ID, b.MType})
+	})
+
+	if q.after != nil {
+		start, _ := slices.BinarySearchFunc(metrics, *q.after, func(m model.Metric, c cursor) int {
+			return compareMetrics(cursor{m.ID, m.MType}, c)
+		})
+		if start < len(metrics) && compareMetrics(cursor{metrics[start].ID, metrics[start].MType}, *q.after) == 0 {
+			start++
+		}
+		metrics = metrics[start:]
+	}
+
+	resp := ListResponse{Metrics: model.Metrics{}}
+	if len(metrics) > q.limit {
+		last := metrics[q.limit-1]
+		resp.NextCursor = cursor{name: last.ID, mType: last.MType}.encode()
+		metrics = metrics[:q.limit]
+	}
+	resp.Metrics = append(resp.Metrics, metrics...)
+
+	writeJSON(w, http.StatusOK, resp)
+}
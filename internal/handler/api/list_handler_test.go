@@ -0,0 +1,202 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func listStorage(ctrl *gomock.Controller) *MockHandlerStorage {
+	storage := NewMockHandlerStorage(ctrl)
+	storage.EXPECT().GetAllGauges(gomock.Any()).Return(map[string]float64{
+		"HeapAlloc":       1,
+		"HeapSys":         2,
+		"CPUutilization1": 3,
+		"Alloc":           4,
+	}, nil).AnyTimes()
+	storage.EXPECT().GetAllCounters(gomock.Any()).Return(map[string]int64{
+		"PollCount": 5,
+		"Alloc":     6,
+	}, nil).AnyTimes()
+	storage.EXPECT().GetAllHistograms(gomock.Any()).Return(map[string]model.Histogram{
+		"RequestDuration": {Bounds: []float64{1}, Counts: []uint64{1, 0}, Count: 1, Sum: 0.5},
+	}, nil).AnyTimes()
+	return storage
+}
+
+func ids(metrics model.Metrics) []string {
+	result := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		result = append(result, string(m.MType)+":"+m.ID)
+	}
+	return result
+}
+
+func TestListHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []string
+		expectedNext   bool
+	}{
+		{
+			name:           "all metrics sorted by name and type",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedIDs: []string{
+				"counter:Alloc", "gauge:Alloc", "gauge:CPUutilization1",
+				"gauge:HeapAlloc", "gauge:HeapSys", "counter:PollCount",
+				"histogram:RequestDuration",
+			},
+		},
+		{
+			name:           "type filter",
+			query:          "?type=counter",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"counter:Alloc", "counter:PollCount"},
+		},
+		{
+			name:           "histogram type filter",
+			query:          "?type=histogram",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"histogram:RequestDuration"},
+		},
+		{
+			name:           "prefix filter",
+			query:          "?prefix=Heap",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"gauge:HeapAlloc", "gauge:HeapSys"},
+		},
+		{
+			name:           "regex filter",
+			query:          "?regex=%5ECPU.*%5Cd%24",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"gauge:CPUutilization1"},
+		},
+		{
+			name:           "limit returns next cursor",
+			query:          "?limit=2",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"counter:Alloc", "gauge:Alloc"},
+			expectedNext:   true,
+		},
+		{
+			name:           "no matches",
+			query:          "?prefix=Unknown",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{},
+		},
+		{
+			name:           "unknown type",
+			query:          "?type=summary",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid regex",
+			query:          "?regex=%28",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit above maximum",
+			query:          "?limit=1001",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid cursor",
+			query:          "?cursor=%21%21",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			w := httptest.NewRecorder()
+			NewHandler(listStorage(ctrl)).ListHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, ProblemContentTypeValue, w.Header().Get("Content-Type"))
+				var problem Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.expectedStatus, problem.Status)
+				assert.Equal(t, "about:blank", problem.Type)
+				assert.NotEmpty(t, problem.Detail)
+				return
+			}
+
+			var resp ListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedIDs, ids(resp.Metrics))
+			assert.Equal(t, tt.expectedNext, resp.NextCursor != "")
+		})
+	}
+}
+
+func TestListHandler_Pagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(listStorage(ctrl)).ListHandler()
+
+	var all []string
+	pages := 0
+	next := ""
+	for {
+		url := "/api/v1/metrics?limit=4"
+		if next != "" {
+			url += "&cursor=" + next
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		all = append(all, ids(resp.Metrics)...)
+		pages++
+
+		if resp.NextCursor == "" {
+			break
+		}
+		next = resp.NextCursor
+	}
+
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, []string{
+		"counter:Alloc", "gauge:Alloc", "gauge:CPUutilization1",
+		"gauge:HeapAlloc", "gauge:HeapSys", "counter:PollCount",
+		"histogram:RequestDuration",
+	}, all)
+}
+
+func TestListHandler_StorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := NewMockHandlerStorage(ctrl)
+	storage.EXPECT().GetAllCounters(gomock.Any()).Return(nil, errors.New("db error"))
+
+	w := httptest.NewRecorder()
+	NewHandler(storage).ListHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics?type=counter", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ProblemContentTypeValue, w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/go-chi/chi/v5"
+)
+
+// MetadataListResponse - ответ GET /api/v1/metadata.
+type MetadataListResponse struct {
+	Metrics []metadata.Metadata `json:"metrics"`
+}
+
+// MetadataRequest - тело запроса PUT /api/v1/metadata/{metricName}.
+type MetadataRequest struct {
+	Type        model.MetricType `json:"type"`
+	Unit        string           `json:"unit,omitempty"`
+	Description string           `json:"description,omitempty"`
+}
+
+type metadataHandler struct {
+	storage MetadataStorage
+}
+
+func newMetadataHandler(storage MetadataStorage) *metadataHandler {
+	return &metadataHandler{
+		storage: storage,
+	}
+}
+
+func (h *metadataHandler) list(w http.ResponseWriter, r *http.Request) {
+	list := h.storage.ListMetadata(r.Context())
+	if list == nil {
+		list = []metadata.Metadata{}
+	}
+
+	writeJSON(w, http.StatusOK, MetadataListResponse{Metrics: list})
+}
+
+func (h *metadataHandler) get(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "metricName")
+
+	m, ok := h.storage.Metadata(r.Context(), name)
+	if !ok {
+		WriteProblem(w, http.StatusNotFound, "Metadata for metric '"+name+"' not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (h *metadataHandler) put(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		WriteProblem(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	var req MetadataRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		WriteProblem(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if !validType(req.Type) {
+		WriteProblem(w, http.StatusBadRequest, "Unknown metric type '"+string(req.Type)+"'")
+		return
+	}
+
+	name := chi.URLParam(r, "metricName")
+	m, err := h.storage.DeclareMetadata(r.Context(), metadata.Metadata{
+		Name:        name,
+		Type:        req.Type,
+		Unit:        req.Unit,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, metadata.ErrTypeConflict) {
+			WriteProblem(w, http.StatusConflict, "Metric '"+name+"' is registered with another type")
+			return
+		}
+		WriteProblem(w, http.StatusInternalServerError, "Failed to declare metadata")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetadataHandlers(t *testing.T) {
+	seen := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	alloc := metadata.Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes", FirstSeen: seen, LastSeen: seen}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMocks     func(*MockHandlerStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/api/v1/metadata",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().ListMetadata(gomock.Any()).Return([]metadata.Metadata{alloc})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"metrics":[{"name":"Alloc","type":"gauge","unit":"bytes","first_seen":"2025-01-01T00:00:00Z","last_seen":"2025-01-01T00:00:00Z"}]}`,
+		},
+		{
+			name:   "empty list",
+			method: http.MethodGet,
+			path:   "/api/v1/metadata",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().ListMetadata(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"metrics":[]}`,
+		},
+		{
+			name:   "get",
+			method: http.MethodGet,
+			path:   "/api/v1/metadata/Alloc",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().Metadata(gomock.Any(), "Alloc").Return(alloc, true)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"Alloc","type":"gauge","unit":"bytes","first_seen":"2025-01-01T00:00:00Z","last_seen":"2025-01-01T00:00:00Z"}`,
+		},
+		{
+			name:   "get not found",
+			method: http.MethodGet,
+			path:   "/api/v1/metadata/Unknown",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().Metadata(gomock.Any(), "Unknown").Return(metadata.Metadata{}, false)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"Metadata for metric 'Unknown' not found"}`,
+		},
+		{
+			name:   "declare",
+			method: http.MethodPut,
+			path:   "/api/v1/metadata/Alloc",
+			body:   `{"type":"gauge","unit":"bytes","description":"Allocated heap"}`,
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().DeclareMetadata(gomock.Any(), metadata.Metadata{
+					Name: "Alloc", Type: model.GaugeType, Unit: "bytes", Description: "Allocated heap",
+				}).Return(metadata.Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes", Description: "Allocated heap"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"Alloc","type":"gauge","unit":"bytes","description":"Allocated heap"}`,
+		},
+		{
+			name:           "declare invalid json",
+			method:         http.MethodPut,
+			path:           "/api/v1/metadata/Alloc",
+			body:           `{`,
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid JSON format"}`,
+		},
+		{
+			name:           "declare unknown type",
+			method:         http.MethodPut,
+			path:           "/api/v1/metadata/Alloc",
+			body:           `{"type":"summary"}`,
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Unknown metric type 'summary'"}`,
+		},
+		{
+			name:   "declare type conflict",
+			method: http.MethodPut,
+			path:   "/api/v1/metadata/Alloc",
+			body:   `{"type":"counter"}`,
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().DeclareMetadata(gomock.Any(), gomock.Any()).
+					Return(metadata.Metadata{}, fmt.Errorf("wrapped: %w", metadata.ErrTypeConflict))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"type":"about:blank","title":"Conflict","status":409,"detail":"Metric 'Alloc' is registered with another type"}`,
+		},
+		{
+			name:   "declare storage error",
+			method: http.MethodPut,
+			path:   "/api/v1/metadata/Alloc",
+			body:   `{"type":"gauge"}`,
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().DeclareMetadata(gomock.Any(), gomock.Any()).Return(metadata.Metadata{}, errors.New("storage error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Failed to declare metadata"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := NewMockHandlerStorage(ctrl)
+			tt.setupMocks(storage)
+
+			h := NewHandler(storage)
+			r := chi.NewRouter()
+			r.Get("/api/v1/metadata", h.MetadataListHandler())
+			r.Get("/api/v1/metadata/{metricName}", h.MetadataHandler())
+			r.Put("/api/v1/metadata/{metricName}", h.DeclareMetadataHandler())
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
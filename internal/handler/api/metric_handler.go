@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/go-chi/chi/v5"
+)
+
+type MetricStorage interface {
+	GaugeGetter
+	CounterGetter
+	HistogramGetter
+}
+
+type metricHandler struct {
+	storage MetricStorage
+}
+
+func newMetricHandler(storage MetricStorage) *metricHandler {
+	return &metricHandler{
+		storage: storage,
+	}
+}
+
+func (h *metricHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	metric := model.Metric{
+		ID:    chi.URLParam(r, "metricName"),
+		MType: model.MetricType(chi.URLParam(r, "metricType")),
+	}
+
+	if !validType(metric.MType) {
+		WriteProblem(w, http.StatusBadRequest, "Unknown metric type '"+string(metric.MType)+"'")
+		return
+	}
+
+	if !lookup(r, h.storage, &metric) {
+		WriteProblem(w, http.StatusNotFound, "Metric '"+metric.ID+"' of type '"+string(metric.MType)+"' not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, metric)
+}
+
+// lookup заполняет значение метрики из хранилища. Возвращает false, если метрика не найдена.
+func lookup(r *http.Request, storage MetricStorage, metric *model.Metric) bool {
+	switch metric.MType {
+	case model.GaugeType:
+		value, exists := storage.GetGauge(r.Context(), metric.ID)
+		if exists {
+			metric.Value = &value
+		}
+		return exists
+	case model.CounterType:
+		value, exists := storage.GetCounter(r.Context(), metric.ID)
+		if exists {
+			metric.Delta = &value
+		}
+		return exists
+	case model.HistogramType:
+		value, exists := storage.GetHistogram(r.Context(), metric.ID)
+		if exists {
+			metric.Histogram = &value
+		}
+		return exists
+	default:
+		return false
+	}
+}
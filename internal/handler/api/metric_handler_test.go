@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetricHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*MockHandlerStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "gauge",
+			path: "/api/v1/metrics/gauge/Alloc",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetGauge(gomock.Any(), "Alloc").Return(1.5, true)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"Alloc","type":"gauge","value":1.5}`,
+		},
+		{
+			name: "counter",
+			path: "/api/v1/metrics/counter/PollCount",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetCounter(gomock.Any(), "PollCount").Return(int64(3), true)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"PollCount","type":"counter","delta":3}`,
+		},
+		{
+			name: "histogram",
+			path: "/api/v1/metrics/histogram/RequestDuration",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetHistogram(gomock.Any(), "RequestDuration").
+					Return(model.Histogram{Bounds: []float64{1}, Counts: []uint64{1, 0}, Count: 1, Sum: 0.5}, true)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"RequestDuration","type":"histogram","histogram":{"bounds":[1],"counts":[1,0],"count":1,"sum":0.5}}`,
+		},
+		{
+			name: "not found",
+			path: "/api/v1/metrics/gauge/Unknown",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetGauge(gomock.Any(), "Unknown").Return(0.0, false)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"Metric 'Unknown' of type 'gauge' not found"}`,
+		},
+		{
+			name:           "unknown type",
+			path:           "/api/v1/metrics/summary/Alloc",
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Unknown metric type 'summary'"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := NewMockHandlerStorage(ctrl)
+			tt.setupMocks(storage)
+
+			r := chi.NewRouter()
+			r.Get("/api/v1/metrics/{metricType}/{metricName}", NewHandler(storage).MetricHandler())
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,571 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/handler/api/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/handler/api/interfaces.go -destination=./internal/handler/api/mocks.go -package=api
+//
+
+// Package api is a generated GoMock package.
+package api
+
+import (
+	context "context"
+	reflect "reflect"
+
+	metadata "github.com/NoobyTheTurtle/metrics/internal/metadata"
+	model "github.com/NoobyTheTurtle/metrics/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGaugesGetter is a mock of GaugesGetter interface.
+type MockGaugesGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockGaugesGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockGaugesGetterMockRecorder is the mock recorder for MockGaugesGetter.
+type MockGaugesGetterMockRecorder struct {
+	mock *MockGaugesGetter
+}
+
+// NewMockGaugesGetter creates a new mock instance.
+func NewMockGaugesGetter(ctrl *gomock.Controller) *MockGaugesGetter {
+	mock := &MockGaugesGetter{ctrl: ctrl}
+	mock.recorder = &MockGaugesGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGaugesGetter) EXPECT() *MockGaugesGetterMockRecorder {
+	return m.recorder
+}
+
+// GetAllGauges mocks base method.
+func (m *MockGaugesGetter) GetAllGauges(ctx context.Context) (map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllGauges", ctx)
+	ret0, _ := ret[0].(map[string]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllGauges indicates an expected call of GetAllGauges.
+func (mr *MockGaugesGetterMockRecorder) GetAllGauges(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGauges", reflect.TypeOf((*MockGaugesGetter)(nil).GetAllGauges), ctx)
+}
+
+// MockCountersGetter is a mock of CountersGetter interface.
+type MockCountersGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCountersGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockCountersGetterMockRecorder is the mock recorder for MockCountersGetter.
+type MockCountersGetterMockRecorder struct {
+	mock *MockCountersGetter
+}
+
+// NewMockCountersGetter creates a new mock instance.
+func NewMockCountersGetter(ctrl *gomock.Controller) *MockCountersGetter {
+	mock := &MockCountersGetter{ctrl: ctrl}
+	mock.recorder = &MockCountersGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCountersGetter) EXPECT() *MockCountersGetterMockRecorder {
+	return m.recorder
+}
+
+// GetAllCounters mocks base method.
+func (m *MockCountersGetter) GetAllCounters(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllCounters", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCounters indicates an expected call of GetAllCounters.
+func (mr *MockCountersGetterMockRecorder) GetAllCounters(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCounters", reflect.TypeOf((*MockCountersGetter)(nil).GetAllCounters), ctx)
+}
+
+// MockHistogramsGetter is a mock of HistogramsGetter interface.
+type MockHistogramsGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramsGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramsGetterMockRecorder is the mock recorder for MockHistogramsGetter.
+type MockHistogramsGetterMockRecorder struct {
+	mock *MockHistogramsGetter
+}
+
+// NewMockHistogramsGetter creates a new mock instance.
+func NewMockHistogramsGetter(ctrl *gomock.Controller) *MockHistogramsGetter {
+	mock := &MockHistogramsGetter{ctrl: ctrl}
+	mock.recorder = &MockHistogramsGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramsGetter) EXPECT() *MockHistogramsGetterMockRecorder {
+	return m.recorder
+}
+
+// GetAllHistograms mocks base method.
+func (m *MockHistogramsGetter) GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllHistograms", ctx)
+	ret0, _ := ret[0].(map[string]model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllHistograms indicates an expected call of GetAllHistograms.
+func (mr *MockHistogramsGetterMockRecorder) GetAllHistograms(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHistograms", reflect.TypeOf((*MockHistogramsGetter)(nil).GetAllHistograms), ctx)
+}
+
+// MockGaugeGetter is a mock of GaugeGetter interface.
+type MockGaugeGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockGaugeGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockGaugeGetterMockRecorder is the mock recorder for MockGaugeGetter.
+type MockGaugeGetterMockRecorder struct {
+	mock *MockGaugeGetter
+}
+
+// NewMockGaugeGetter creates a new mock instance.
+func NewMockGaugeGetter(ctrl *gomock.Controller) *MockGaugeGetter {
+	mock := &MockGaugeGetter{ctrl: ctrl}
+	mock.recorder = &MockGaugeGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGaugeGetter) EXPECT() *MockGaugeGetterMockRecorder {
+	return m.recorder
+}
+
+// GetGauge mocks base method.
+func (m *MockGaugeGetter) GetGauge(ctx context.Context, name string) (float64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGauge", ctx, name)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetGauge indicates an expected call of GetGauge.
+func (mr *MockGaugeGetterMockRecorder) GetGauge(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGauge", reflect.TypeOf((*MockGaugeGetter)(nil).GetGauge), ctx, name)
+}
+
+// MockCounterGetter is a mock of CounterGetter interface.
+type MockCounterGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCounterGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockCounterGetterMockRecorder is the mock recorder for MockCounterGetter.
+type MockCounterGetterMockRecorder struct {
+	mock *MockCounterGetter
+}
+
+// NewMockCounterGetter creates a new mock instance.
+func NewMockCounterGetter(ctrl *gomock.Controller) *MockCounterGetter {
+	mock := &MockCounterGetter{ctrl: ctrl}
+	mock.recorder = &MockCounterGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCounterGetter) EXPECT() *MockCounterGetterMockRecorder {
+	return m.recorder
+}
+
+// GetCounter mocks base method.
+func (m *MockCounterGetter) GetCounter(ctx context.Context, name string) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounter", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetCounter indicates an expected call of GetCounter.
+func (mr *MockCounterGetterMockRecorder) GetCounter(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounter", reflect.TypeOf((*MockCounterGetter)(nil).GetCounter), ctx, name)
+}
+
+// MockHistogramGetter is a mock of HistogramGetter interface.
+type MockHistogramGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramGetterMockRecorder is the mock recorder for MockHistogramGetter.
+type MockHistogramGetterMockRecorder struct {
+	mock *MockHistogramGetter
+}
+
+// NewMockHistogramGetter creates a new mock instance.
+func NewMockHistogramGetter(ctrl *gomock.Controller) *MockHistogramGetter {
+	mock := &MockHistogramGetter{ctrl: ctrl}
+	mock.recorder = &MockHistogramGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramGetter) EXPECT() *MockHistogramGetterMockRecorder {
+	return m.recorder
+}
+
+// GetHistogram mocks base method.
+func (m *MockHistogramGetter) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHistogramGetterMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHistogramGetter)(nil).GetHistogram), ctx, name)
+}
+
+// MockMetadataGetter is a mock of MetadataGetter interface.
+type MockMetadataGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockMetadataGetterMockRecorder is the mock recorder for MockMetadataGetter.
+type MockMetadataGetterMockRecorder struct {
+	mock *MockMetadataGetter
+}
+
+// NewMockMetadataGetter creates a new mock instance.
+func NewMockMetadataGetter(ctrl *gomock.Controller) *MockMetadataGetter {
+	mock := &MockMetadataGetter{ctrl: ctrl}
+	mock.recorder = &MockMetadataGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetadataGetter) EXPECT() *MockMetadataGetterMockRecorder {
+	return m.recorder
+}
+
+// ListMetadata mocks base method.
+func (m *MockMetadataGetter) ListMetadata(ctx context.Context) []metadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx)
+	ret0, _ := ret[0].([]metadata.Metadata)
+	return ret0
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockMetadataGetterMockRecorder) ListMetadata(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockMetadataGetter)(nil).ListMetadata), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockMetadataGetter) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", ctx, name)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockMetadataGetterMockRecorder) Metadata(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockMetadataGetter)(nil).Metadata), ctx, name)
+}
+
+// MockMetadataDeclarer is a mock of MetadataDeclarer interface.
+type MockMetadataDeclarer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataDeclarerMockRecorder
+	isgomock struct{}
+}
+
+// MockMetadataDeclarerMockRecorder is the mock recorder for MockMetadataDeclarer.
+type MockMetadataDeclarerMockRecorder struct {
+	mock *MockMetadataDeclarer
+}
+
+// NewMockMetadataDeclarer creates a new mock instance.
+func NewMockMetadataDeclarer(ctrl *gomock.Controller) *MockMetadataDeclarer {
+	mock := &MockMetadataDeclarer{ctrl: ctrl}
+	mock.recorder = &MockMetadataDeclarerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetadataDeclarer) EXPECT() *MockMetadataDeclarerMockRecorder {
+	return m.recorder
+}
+
+// DeclareMetadata mocks base method.
+func (m_2 *MockMetadataDeclarer) DeclareMetadata(ctx context.Context, m metadata.Metadata) (metadata.Metadata, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "DeclareMetadata", ctx, m)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclareMetadata indicates an expected call of DeclareMetadata.
+func (mr *MockMetadataDeclarerMockRecorder) DeclareMetadata(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclareMetadata", reflect.TypeOf((*MockMetadataDeclarer)(nil).DeclareMetadata), ctx, m)
+}
+
+// MockMetadataStorage is a mock of MetadataStorage interface.
+type MockMetadataStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockMetadataStorageMockRecorder is the mock recorder for MockMetadataStorage.
+type MockMetadataStorageMockRecorder struct {
+	mock *MockMetadataStorage
+}
+
+// NewMockMetadataStorage creates a new mock instance.
+func NewMockMetadataStorage(ctrl *gomock.Controller) *MockMetadataStorage {
+	mock := &MockMetadataStorage{ctrl: ctrl}
+	mock.recorder = &MockMetadataStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetadataStorage) EXPECT() *MockMetadataStorageMockRecorder {
+	return m.recorder
+}
+
+// DeclareMetadata mocks base method.
+func (m_2 *MockMetadataStorage) DeclareMetadata(ctx context.Context, m metadata.Metadata) (metadata.Metadata, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "DeclareMetadata", ctx, m)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclareMetadata indicates an expected call of DeclareMetadata.
+func (mr *MockMetadataStorageMockRecorder) DeclareMetadata(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclareMetadata", reflect.TypeOf((*MockMetadataStorage)(nil).DeclareMetadata), ctx, m)
+}
+
+// ListMetadata mocks base method.
+func (m *MockMetadataStorage) ListMetadata(ctx context.Context) []metadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx)
+	ret0, _ := ret[0].([]metadata.Metadata)
+	return ret0
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockMetadataStorageMockRecorder) ListMetadata(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockMetadataStorage)(nil).ListMetadata), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockMetadataStorage) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", ctx, name)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockMetadataStorageMockRecorder) Metadata(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockMetadataStorage)(nil).Metadata), ctx, name)
+}
+
+// MockHandlerStorage is a mock of HandlerStorage interface.
+type MockHandlerStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockHandlerStorageMockRecorder is the mock recorder for MockHandlerStorage.
+type MockHandlerStorageMockRecorder struct {
+	mock *MockHandlerStorage
+}
+
+// NewMockHandlerStorage creates a new mock instance.
+func NewMockHandlerStorage(ctrl *gomock.Controller) *MockHandlerStorage {
+	mock := &MockHandlerStorage{ctrl: ctrl}
+	mock.recorder = &MockHandlerStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandlerStorage) EXPECT() *MockHandlerStorageMockRecorder {
+	return m.recorder
+}
+
+// DeclareMetadata mocks base method.
+func (m_2 *MockHandlerStorage) DeclareMetadata(ctx context.Context, m metadata.Metadata) (metadata.Metadata, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "DeclareMetadata", ctx, m)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclareMetadata indicates an expected call of DeclareMetadata.
+func (mr *MockHandlerStorageMockRecorder) DeclareMetadata(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclareMetadata", reflect.TypeOf((*MockHandlerStorage)(nil).DeclareMetadata), ctx, m)
+}
+
+// GetAllCounters mocks base method.
+func (m *MockHandlerStorage) GetAllCounters(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllCounters", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCounters indicates an expected call of GetAllCounters.
+func (mr *MockHandlerStorageMockRecorder) GetAllCounters(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCounters", reflect.TypeOf((*MockHandlerStorage)(nil).GetAllCounters), ctx)
+}
+
+// GetAllGauges mocks base method.
+func (m *MockHandlerStorage) GetAllGauges(ctx context.Context) (map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllGauges", ctx)
+	ret0, _ := ret[0].(map[string]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllGauges indicates an expected call of GetAllGauges.
+func (mr *MockHandlerStorageMockRecorder) GetAllGauges(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGauges", reflect.TypeOf((*MockHandlerStorage)(nil).GetAllGauges), ctx)
+}
+
+// GetAllHistograms mocks base method.
+func (m *MockHandlerStorage) GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllHistograms", ctx)
+	ret0, _ := ret[0].(map[string]model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllHistograms indicates an expected call of GetAllHistograms.
+func (mr *MockHandlerStorageMockRecorder) GetAllHistograms(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHistograms", reflect.TypeOf((*MockHandlerStorage)(nil).GetAllHistograms), ctx)
+}
+
+// GetCounter mocks base method.
+func (m *MockHandlerStorage) GetCounter(ctx context.Context, name string) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounter", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetCounter indicates an expected call of GetCounter.
+func (mr *MockHandlerStorageMockRecorder) GetCounter(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounter", reflect.TypeOf((*MockHandlerStorage)(nil).GetCounter), ctx, name)
+}
+
+// GetGauge mocks base method.
+func (m *MockHandlerStorage) GetGauge(ctx context.Context, name string) (float64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGauge", ctx, name)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetGauge indicates an expected call of GetGauge.
+func (mr *MockHandlerStorageMockRecorder) GetGauge(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGauge", reflect.TypeOf((*MockHandlerStorage)(nil).GetGauge), ctx, name)
+}
+
+// GetHistogram mocks base method.
+func (m *MockHandlerStorage) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHandlerStorageMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHandlerStorage)(nil).GetHistogram), ctx, name)
+}
+
+// ListMetadata mocks base method.
+func (m *MockHandlerStorage) ListMetadata(ctx context.Context) []metadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx)
+	ret0, _ := ret[0].([]metadata.Metadata)
+	return ret0
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockHandlerStorageMockRecorder) ListMetadata(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockHandlerStorage)(nil).ListMetadata), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockHandlerStorage) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", ctx, name)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockHandlerStorageMockRecorder) Metadata(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockHandlerStorage)(nil).Metadata), ctx, name)
+}
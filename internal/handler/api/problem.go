@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem описывает ошибку API в формате RFC 7807 (application/problem+json).
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewProblem создает описание ошибки для HTTP статуса.
+// Поле type имеет значение about:blank, поэтому title совпадает с текстом статуса.
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// WriteProblem отправляет ошибку в формате RFC 7807.
+func WriteProblem(w http.ResponseWriter, status int, detail string) {
+	data, _ := json.Marshal(NewProblem(status, detail))
+
+	w.Header().Set("Content-Type", ProblemContentTypeValue)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(data)
+}
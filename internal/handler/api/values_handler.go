@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
+
+// MetricID идентифицирует метрику по имени и типу.
+type MetricID struct {
+	ID    string           `json:"id"`
+	MType model.MetricType `json:"type"`
+}
+
+// ValuesRequest - тело запроса POST /api/v1/values.
+type ValuesRequest struct {
+	Metrics []MetricID `json:"metrics"`
+}
+
+// ValuesResponse - ответ POST /api/v1/values.
+// Metrics содержит найденные метрики в порядке запроса, Missing - не найденные.
+type ValuesResponse struct {
+	Metrics model.Metrics `json:"metrics"`
+	Missing []MetricID    `json:"missing"`
+}
+
+type valuesHandler struct {
+	storage MetricStorage
+}
+
+func newValuesHandler(storage MetricStorage) *valuesHandler {
+	return &valuesHandler{
+		storage: storage,
+	}
+}
+
+func (h *valuesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		WriteProblem(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	var req ValuesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		WriteProblem(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if len(req.Metrics) == 0 {
+		WriteProblem(w, http.StatusBadRequest, "metrics must not be empty")
+		return
+	}
+	if len(req.Metrics) > MaxBatchSize {
+		WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("at most %d metrics can be requested at once", MaxBatchSize))
+		return
+	}
+
+	for i, id := range req.Metrics {
+		if id.ID == "" || !validType(id.MType) {
+			WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("metrics[%d]: id and valid type are required", i))
+			return
+		}
+	}
+
+	resp := ValuesResponse{
+		Metrics: model.Metrics{},
+		Missing: []MetricID{},
+	}
+	for _, id := range req.Metrics {
+		metric := model.Metric{ID: id.ID, MType: id.MType}
+		if lookup(r, h.storage, &metric) {
+			resp.Metrics = append(resp.Metrics, metric)
+		} else {
+			resp.Missing = append(resp.Missing, id)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValuesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(*MockHandlerStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "found and missing metrics",
+			body: `{"metrics":[{"id":"Alloc","type":"gauge"},{"id":"PollCount","type":"counter"},{"id":"Unknown","type":"gauge"}]}`,
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetGauge(gomock.Any(), "Alloc").Return(1.5, true)
+				storage.EXPECT().GetCounter(gomock.Any(), "PollCount").Return(int64(2), true)
+				storage.EXPECT().GetGauge(gomock.Any(), "Unknown").Return(0.0, false)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"metrics":[{"id":"Alloc","type":"gauge","value":1.5},{"id":"PollCount","type":"counter","delta":2}],
+				"missing":[{"id":"Unknown","type":"gauge"}]
+			}`,
+		},
+		{
+			name:           "invalid json",
+			body:           `{`,
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid JSON format"}`,
+		},
+		{
+			name:           "empty request",
+			body:           `{"metrics":[]}`,
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"metrics must not be empty"}`,
+		},
+		{
+			name:           "invalid metric id",
+			body:           `{"metrics":[{"id":"Alloc","type":"gauge"},{"id":"","type":"gauge"}]}`,
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"metrics[1]: id and valid type are required"}`,
+		},
+		{
+			name:           "too many metrics",
+			body:           `{"metrics":[` + strings.Repeat(`{"id":"a","type":"gauge"},`, MaxBatchSize) + `{"id":"a","type":"gauge"}]}`,
+			setupMocks:     func(storage *MockHandlerStorage) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   fmt.Sprintf(`{"type":"about:blank","title":"Bad Request","status":400,"detail":"at most %d metrics can be requested at once"}`, MaxBatchSize),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := NewMockHandlerStorage(ctrl)
+			tt.setupMocks(storage)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/values", strings.NewReader(tt.body))
+			NewHandler(storage).ValuesHandler().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
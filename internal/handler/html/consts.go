@@ -2,4 +2,12 @@ package html
 
 const (
 	ContentTypeValue = "text/html"
+
+	// otherGroup - группа для метрик, префикс которых не встречается у других метрик.
+	otherGroup = "Other"
+
+	sparklineWidth  = 120
+	sparklineHeight = 24
+	chartWidth      = 640
+	chartHeight     = 160
 )
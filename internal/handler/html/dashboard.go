@@ -0,0 +1,99 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+)
+
+var wordPrefix = regexp.MustCompile(`^(?:[A-Z]+[a-z]*|[a-z]+)`)
+
+// groupPrefix возвращает префикс имени метрики для группировки:
+// часть до первого разделителя (_ . : -) или первое слово в CamelCase
+// без завершающих цифр (CPUutilization1 -> CPUutilization, HeapAlloc -> Heap).
+func groupPrefix(name string) string {
+	if idx := strings.IndexAny(name, "_.:-"); idx > 0 {
+		return name[:idx]
+	}
+
+	if prefix := wordPrefix.FindString(name); prefix != "" {
+		return prefix
+	}
+	return otherGroup
+}
+
+// groupRows группирует метрики по префиксу имени.
+// Префиксы, которые встречаются только у одной метрики, попадают в группу Other.
+// Группы упорядочены по имени, Other - последняя.
+func groupRows(rows []metricRow) []groupData {
+	byPrefix := make(map[string][]metricRow)
+	for _, row := range rows {
+		prefix := groupPrefix(row.Name)
+		byPrefix[prefix] = append(byPrefix[prefix], row)
+	}
+
+	var other []metricRow
+	groups := make([]groupData, 0, len(byPrefix))
+	for prefix, metrics := range byPrefix {
+		if len(metrics) < 2 || prefix == otherGroup {
+			other = append(other, metrics...)
+			continue
+		}
+		groups = append(groups, groupData{Prefix: prefix, Metrics: metrics})
+	}
+
+	slices.SortFunc(groups, func(a, b groupData) int {
+		return strings.Compare(a.Prefix, b.Prefix)
+	})
+
+	if len(other) > 0 {
+		slices.SortFunc(other, compareRows)
+		groups = append(groups, groupData{Prefix: otherGroup, Metrics: other})
+	}
+
+	return groups
+}
+
+func compareRows(a, b metricRow) int {
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Type, b.Type)
+}
+
+// sparkline строит SVG график значений размером width x height.
+// Для менее чем двух значений график не строится.
+func sparkline(points []history.Point, width, height int) template.HTML {
+	if len(points) < 2 {
+		return ""
+	}
+
+	minValue, maxValue := points[0].Value, points[0].Value
+	for _, p := range points[1:] {
+		minValue = min(minValue, p.Value)
+		maxValue = max(maxValue, p.Value)
+	}
+
+	const padding = 1.0
+	w := float64(width)
+	h := float64(height) - 2*padding
+
+	coords := make([]string, 0, len(points))
+	for i, p := range points {
+		x := float64(i) * w / float64(len(points)-1)
+		y := padding + h/2
+		if maxValue > minValue {
+			y = padding + h - (p.Value-minValue)/(maxValue-minValue)*h
+		}
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+
+	return template.HTML(fmt.Sprintf(
+		`<svg class="sparkline" width="%d" height="%d" viewBox="0 0 %d %d" preserveAspectRatio="none" aria-hidden="true"><polyline points="%s"/></svg>`,
+		width, height, width, height, strings.Join(coords, " "),
+	))
+}
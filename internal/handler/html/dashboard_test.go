@@ -0,0 +1,95 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_groupPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "CPUutilization1", expected: "CPUutilization"},
+		{name: "CPUutilization12", expected: "CPUutilization"},
+		{name: "HeapAlloc", expected: "Heap"},
+		{name: "HeapSys", expected: "Heap"},
+		{name: "PollCount", expected: "Poll"},
+		{name: "http_requests_total", expected: "http"},
+		{name: "disk.read.bytes", expected: "disk"},
+		{name: "alloc", expected: "alloc"},
+		{name: "_private", expected: "Other"},
+		{name: "42", expected: "Other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, groupPrefix(tt.name))
+		})
+	}
+}
+
+func Test_groupRows(t *testing.T) {
+	rows := []metricRow{
+		{Name: "HeapSys", Type: "gauge"},
+		{Name: "CPUutilization2", Type: "gauge"},
+		{Name: "PollCount", Type: "counter"},
+		{Name: "HeapAlloc", Type: "gauge"},
+		{Name: "CPUutilization1", Type: "gauge"},
+		{Name: "Alloc", Type: "gauge"},
+	}
+
+	groups := groupRows(rows)
+
+	prefixes := make([]string, 0, len(groups))
+	for _, g := range groups {
+		prefixes = append(prefixes, g.Prefix)
+	}
+	assert.Equal(t, []string{"CPUutilization", "Heap", "Other"}, prefixes)
+
+	assert.Equal(t, []metricRow{
+		{Name: "Alloc", Type: "gauge"},
+		{Name: "PollCount", Type: "counter"},
+	}, groups[2].Metrics, "single-member prefixes go to Other sorted by name")
+}
+
+func Test_sparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []history.Point
+		expected string
+	}{
+		{
+			name:     "not enough points",
+			points:   []history.Point{{Value: 1}},
+			expected: "",
+		},
+		{
+			name:     "rising values",
+			points:   []history.Point{{Value: 0}, {Value: 5}, {Value: 10}},
+			expected: `<polyline points="0.0,11.0 5.0,6.0 10.0,1.0"/>`,
+		},
+		{
+			name:     "constant values draw a flat line",
+			points:   []history.Point{{Value: 3}, {Value: 3}},
+			expected: `<polyline points="0.0,6.0 10.0,6.0"/>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svg := string(sparkline(tt.points, 10, 12))
+
+			if tt.expected == "" {
+				assert.Empty(t, svg)
+				return
+			}
+
+			assert.True(t, strings.HasPrefix(svg, `<svg class="sparkline" width="10" height="12"`), svg)
+			assert.Contains(t, svg, tt.expected)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package html
 
 import (
+	"embed"
 	"net/http"
 )
 
+//go:embed static
+var staticFiles embed.FS
+
 type Handler struct {
 	storage HandlerStorage
 }
@@ -18,3 +22,13 @@ func (h *Handler) IndexHandler() http.HandlerFunc {
 	handler := newIndexHandler(h.storage)
 	return handler.ServeHTTP
 }
+
+func (h *Handler) MetricHandler() http.HandlerFunc {
+	handler := newMetricHandler(h.storage)
+	return handler.ServeHTTP
+}
+
+// StaticHandler отдает встроенные в бинарный файл стили и скрипты страницы по путям /static/*.
+func (h *Handler) StaticHandler() http.Handler {
+	return http.FileServerFS(staticFiles)
+}
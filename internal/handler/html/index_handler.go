@@ -1,28 +1,42 @@
 package html
 
 import (
-	_ "embed"
+	"fmt"
 	"html/template"
 	"net/http"
 	"sort"
+	"strconv"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 )
 
-//go:embed templates/index.html
-var indexHTML string
+// metricRow описывает строку таблицы метрик на главной странице.
+type metricRow struct {
+	Name        string
+	Type        string
+	Value       string
+	Unit        string
+	Description string
+	Sparkline   template.HTML
+}
 
-type metricData struct {
-	Name  string
-	Value any
+type groupData struct {
+	Prefix  string
+	Metrics []metricRow
 }
 
 type pageData struct {
-	Gauges   []metricData
-	Counters []metricData
+	Groups []groupData
+	Total  int
 }
 
 type IndexStorage interface {
 	GaugesGetter
 	CountersGetter
+	HistogramsGetter
+	HistoryGetter
+	MetadataGetter
 }
 
 type indexHandler struct {
@@ -35,45 +49,75 @@ func newIndexHandler(storage IndexStorage) *indexHandler {
 	}
 }
 
-func mapMetrics[T any](metrics map[string]T) []metricData {
-	result := make([]metricData, 0, len(metrics))
+// sortedRows строит упорядоченные по имени строки таблицы для метрик одного типа.
+func sortedRows[T any](metrics map[string]T, mType model.MetricType) []metricRow {
+	rows := make([]metricRow, 0, len(metrics))
 	for name, value := range metrics {
-		result = append(result, metricData{
+		rows = append(rows, metricRow{
 			Name:  name,
-			Value: value,
+			Type:  string(mType),
+			Value: formatValue(value),
 		})
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
 	})
-	return result
+	return rows
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case model.Histogram:
+		// число значений идет первым, чтобы таблица сортировалась по нему
+		return fmt.Sprintf("%d obs, sum %s", v.Count, strconv.FormatFloat(v.Sum, 'f', -1, 64))
+	default:
+		return ""
+	}
 }
 
 func (h *indexHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	tmpl, err := template.New("index").Parse(indexHTML)
+	gauges, err := h.storage.GetAllGauges(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	gauges, err := h.storage.GetAllGauges(r.Context())
+	counters, err := h.storage.GetAllCounters(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	counters, err := h.storage.GetAllCounters(r.Context())
+	histograms, err := h.storage.GetAllHistograms(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	meta := make(map[string]metadata.Metadata)
+	for _, m := range h.storage.ListMetadata(r.Context()) {
+		meta[m.Name] = m
+	}
+
+	rows := sortedRows(gauges, model.GaugeType)
+	rows = append(rows, sortedRows(counters, model.CounterType)...)
+	rows = append(rows, sortedRows(histograms, model.HistogramType)...)
+	for i := range rows {
+		rows[i].Unit = meta[rows[i].Name].Unit
+		rows[i].Description = meta[rows[i].Name].Description
+		rows[i].Sparkline = sparkline(h.storage.History(r.Context(), model.MetricType(rows[i].Type), rows[i].Name), sparklineWidth, sparklineHeight)
+	}
+
 	data := pageData{
-		Gauges:   mapMetrics(gauges),
-		Counters: mapMetrics(counters),
+		Groups: groupRows(rows),
+		Total:  len(rows),
 	}
 
-	if err := tmpl.Execute(w, data); err != nil {
+	if err := templates.ExecuteTemplate(w, "index.html", data); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
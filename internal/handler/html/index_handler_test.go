@@ -7,78 +7,37 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/NoobyTheTurtle/metrics/internal/testutil"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
-func Test_mapGauges(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    map[string]float64
-		expected []metricData
-	}{
-		{
-			name:     "empty gauges",
-			input:    map[string]float64{},
-			expected: []metricData{},
-		},
-		{
-			name: "multiple gauges sorted",
-			input: map[string]float64{
-				"Z": 1.1,
-				"A": 2.2,
-				"M": 3.3,
-			},
-			expected: []metricData{
-				{Name: "A", Value: 2.2},
-				{Name: "M", Value: 3.3},
-				{Name: "Z", Value: 1.1},
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := mapMetrics(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
-func Test_mapCounters(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    map[string]int64
-		expected []metricData
-	}{
-		{
-			name:     "empty counters",
-			input:    map[string]int64{},
-			expected: []metricData{},
-		},
-		{
-			name: "multiple counters sorted",
-			input: map[string]int64{
-				"Z": 1,
-				"A": 2,
-				"M": 3,
-			},
-			expected: []metricData{
-				{Name: "A", Value: int64(2)},
-				{Name: "M", Value: int64(3)},
-				{Name: "Z", Value: int64(1)},
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := mapMetrics(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
+func Test_sortedRows(t *testing.T) {
+	t.Run("empty gauges", func(t *testing.T) {
+		assert.Equal(t, []metricRow{}, sortedRows(map[string]float64{}, model.GaugeType))
+	})
+
+	t.Run("multiple gauges sorted", func(t *testing.T) {
+		rows := sortedRows(map[string]float64{"Z": 1.1, "A": 2.2, "M": 3.3}, model.GaugeType)
+		assert.Equal(t, []metricRow{
+			{Name: "A", Type: "gauge", Value: "2.2"},
+			{Name: "M", Type: "gauge", Value: "3.3"},
+			{Name: "Z", Type: "gauge", Value: "1.1"},
+		}, rows)
+	})
+
+	t.Run("multiple counters sorted", func(t *testing.T) {
+		rows := sortedRows(map[string]int64{"Z": 1, "A": 2, "M": 3}, model.CounterType)
+		assert.Equal(t, []metricRow{
+			{Name: "A", Type: "counter", Value: "2"},
+			{Name: "M", Type: "counter", Value: "3"},
+			{Name: "Z", Type: "counter", Value: "1"},
+		}, rows)
+	})
 }
 
 func Test_handler_indexHandler(t *testing.T) {
@@ -107,14 +66,28 @@ func Test_handler_indexHandler(t *testing.T) {
 
 				mockStorage.EXPECT().GetAllGauges(gomock.Any()).Return(gauges, nil)
 				mockStorage.EXPECT().GetAllCounters(gomock.Any()).Return(counters, nil)
+				mockStorage.EXPECT().GetAllHistograms(gomock.Any()).Return(map[string]model.Histogram{
+					"RequestDuration": {Bounds: []float64{1}, Counts: []uint64{2, 1}, Count: 3, Sum: 4.5},
+				}, nil)
+				mockStorage.EXPECT().History(gomock.Any(), model.GaugeType, "Alloc").Return([]history.Point{{Value: 1}, {Value: 15.5}})
+				mockStorage.EXPECT().History(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				mockStorage.EXPECT().ListMetadata(gomock.Any()).Return([]metadata.Metadata{
+					{Name: "Alloc", Type: model.GaugeType, Unit: "bytes", Description: "Allocated heap"},
+				})
 
 				return mockStorage
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedContains: []string{
 				"<h1>Metrics</h1>",
-				"<h2>Gauge</h2>",
-				"<h2>Counter</h2>",
+				`<a href="/metric/gauge/Alloc" title="Allocated heap">Alloc</a>`,
+				`15.5 <span class="unit">bytes</span>`,
+				`<a href="/metric/counter/PollCount">PollCount</a>`,
+				`<svg class="sparkline"`,
+				"<h2>Other <span class=\"count\">4</span></h2>",
+				`<a href="/metric/histogram/RequestDuration">RequestDuration</a>`,
+				`data-value="3 obs, sum 4.5"`,
+				`<script src="/static/dashboard.js"></script>`,
 				"Alloc",
 				"15.5",
 				"BuckHashSys",
@@ -132,14 +105,15 @@ func Test_handler_indexHandler(t *testing.T) {
 
 				mockStorage.EXPECT().GetAllGauges(gomock.Any()).Return(map[string]float64{}, nil)
 				mockStorage.EXPECT().GetAllCounters(gomock.Any()).Return(map[string]int64{}, nil)
+				mockStorage.EXPECT().GetAllHistograms(gomock.Any()).Return(map[string]model.Histogram{}, nil)
+				mockStorage.EXPECT().ListMetadata(gomock.Any()).Return(nil)
 
 				return mockStorage
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedContains: []string{
 				"<h1>Metrics</h1>",
-				"<h2>Gauge</h2>",
-				"<h2>Counter</h2>",
+				"No metrics yet.",
 			},
 		},
 		{
@@ -176,6 +150,19 @@ func Test_handler_indexHandler(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 		},
+		{
+			name:   "error getting histograms",
+			method: http.MethodGet,
+			url:    "/",
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().GetAllGauges(gomock.Any()).Return(map[string]float64{}, nil)
+				mockStorage.EXPECT().GetAllCounters(gomock.Any()).Return(map[string]int64{}, nil)
+				mockStorage.EXPECT().GetAllHistograms(gomock.Any()).Return(nil, errors.New("test error"))
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3,6 +3,9 @@ package html
 import (
 	"context"
 
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/NoobyTheTurtle/metrics/internal/storage/adapter"
 )
 
@@ -14,9 +17,40 @@ type CountersGetter interface {
 	GetAllCounters(ctx context.Context) (map[string]int64, error)
 }
 
+type HistogramsGetter interface {
+	GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error)
+}
+
+type GaugeGetter interface {
+	GetGauge(ctx context.Context, name string) (float64, bool)
+}
+
+type CounterGetter interface {
+	GetCounter(ctx context.Context, name string) (int64, bool)
+}
+
+type HistogramGetter interface {
+	GetHistogram(ctx context.Context, name string) (model.Histogram, bool)
+}
+
+type HistoryGetter interface {
+	History(ctx context.Context, mType model.MetricType, name string) []history.Point
+}
+
+type MetadataGetter interface {
+	Metadata(ctx context.Context, name string) (metadata.Metadata, bool)
+	ListMetadata(ctx context.Context) []metadata.Metadata
+}
+
 type HandlerStorage interface {
 	GaugesGetter
 	CountersGetter
+	HistogramsGetter
+	GaugeGetter
+	CounterGetter
+	HistogramGetter
+	HistoryGetter
+	MetadataGetter
 }
 
 var _ HandlerStorage = (*adapter.MetricStorage)(nil)
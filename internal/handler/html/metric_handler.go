@@ -0,0 +1,118 @@
+package html
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/go-chi/chi/v5"
+)
+
+type metricPageData struct {
+	Name     string
+	Type     string
+	Value    string
+	Metadata metadata.Metadata
+	Chart    template.HTML
+	Min      string
+	Max      string
+	Points   []history.Point
+	Buckets  []bucketRow
+}
+
+// bucketRow описывает корзину гистограммы на странице метрики.
+type bucketRow struct {
+	Le         string
+	Count      uint64
+	Cumulative uint64
+}
+
+func histogramBuckets(h model.Histogram) []bucketRow {
+	rows := make([]bucketRow, 0, len(h.Counts))
+	var cumulative uint64
+	for i, count := range h.Counts {
+		le := "+Inf"
+		if i < len(h.Bounds) {
+			le = formatValue(h.Bounds[i])
+		}
+		cumulative += count
+		rows = append(rows, bucketRow{Le: le, Count: count, Cumulative: cumulative})
+	}
+	return rows
+}
+
+type MetricStorage interface {
+	GaugeGetter
+	CounterGetter
+	HistogramGetter
+	HistoryGetter
+	MetadataGetter
+}
+
+type metricHandler struct {
+	storage MetricStorage
+}
+
+func newMetricHandler(storage MetricStorage) *metricHandler {
+	return &metricHandler{
+		storage: storage,
+	}
+}
+
+// ServeHTTP показывает страницу метрики: текущее значение, график и последние значения.
+func (h *metricHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mType := model.MetricType(chi.URLParam(r, "metricType"))
+	name := chi.URLParam(r, "metricName")
+
+	var value any
+	var exists bool
+	switch mType {
+	case model.GaugeType:
+		value, exists = h.storage.GetGauge(r.Context(), name)
+	case model.CounterType:
+		value, exists = h.storage.GetCounter(r.Context(), name)
+	case model.HistogramType:
+		value, exists = h.storage.GetHistogram(r.Context(), name)
+	default:
+		http.Error(w, "Invalid metric type", http.StatusBadRequest)
+		return
+	}
+
+	if !exists {
+		http.Error(w, "Metric not found", http.StatusNotFound)
+		return
+	}
+
+	points := h.storage.History(r.Context(), mType, name)
+
+	data := metricPageData{
+		Name:  name,
+		Type:  string(mType),
+		Value: formatValue(value),
+		Chart: sparkline(points, chartWidth, chartHeight),
+	}
+	data.Metadata, _ = h.storage.Metadata(r.Context(), name)
+	if histogram, ok := value.(model.Histogram); ok {
+		data.Buckets = histogramBuckets(histogram)
+	}
+
+	if len(points) > 0 {
+		values := make([]float64, len(points))
+		for i, p := range points {
+			values[i] = p.Value
+		}
+		data.Min = formatValue(slices.Min(values))
+		data.Max = formatValue(slices.Max(values))
+	}
+
+	data.Points = slices.Clone(points)
+	slices.Reverse(data.Points)
+
+	if err := templates.ExecuteTemplate(w, "metric.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,142 @@
+package html
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_metricHandler(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		url                string
+		setupMocks         func(*MockHandlerStorage)
+		expectedStatusCode int
+		expectedContains   []string
+	}{
+		{
+			name: "gauge with history",
+			url:  "/metric/gauge/Alloc",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetGauge(gomock.Any(), "Alloc").Return(15.5, true)
+				storage.EXPECT().History(gomock.Any(), model.GaugeType, "Alloc").Return([]history.Point{
+					{Timestamp: ts, Value: 10},
+					{Timestamp: ts.Add(time.Second), Value: 15.5},
+				})
+				storage.EXPECT().Metadata(gomock.Any(), "Alloc").Return(metadata.Metadata{
+					Name: "Alloc", Type: model.GaugeType, Unit: "bytes", Description: "Allocated heap",
+					FirstSeen: ts, LastSeen: ts.Add(time.Second),
+				}, true)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedContains: []string{
+				"<h1>Alloc</h1>",
+				`<span class="value">15.5</span>`,
+				`<svg class="sparkline"`,
+				"min 10 &middot; max 15.5",
+				"2025-01-02 03:04:06",
+				`<span class="unit">bytes</span>`,
+				`<p class="description">Allocated heap</p>`,
+				"first seen 2025-01-02 03:04:05",
+			},
+		},
+		{
+			name: "counter without history",
+			url:  "/metric/counter/PollCount",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetCounter(gomock.Any(), "PollCount").Return(int64(7), true)
+				storage.EXPECT().History(gomock.Any(), model.CounterType, "PollCount").Return(nil)
+				storage.EXPECT().Metadata(gomock.Any(), "PollCount").Return(metadata.Metadata{}, false)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedContains: []string{
+				"<h1>PollCount</h1>",
+				`<span class="value">7</span>`,
+				"Not enough updates since server start",
+			},
+		},
+		{
+			name: "histogram buckets",
+			url:  "/metric/histogram/RequestDuration",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetHistogram(gomock.Any(), "RequestDuration").
+					Return(model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{3, 1, 2}, Count: 6, Sum: 12.5}, true)
+				storage.EXPECT().History(gomock.Any(), model.HistogramType, "RequestDuration").Return(nil)
+				storage.EXPECT().Metadata(gomock.Any(), "RequestDuration").Return(metadata.Metadata{}, false)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedContains: []string{
+				`<span class="value">6 obs, sum 12.5</span>`,
+				"<h2>Buckets</h2>",
+				"<td>&le; 0.1</td>",
+				"<td>&le; &#43;Inf</td>",
+				`<td class="num">4</td>`,
+			},
+		},
+		{
+			name: "unknown metric",
+			url:  "/metric/gauge/Unknown",
+			setupMocks: func(storage *MockHandlerStorage) {
+				storage.EXPECT().GetGauge(gomock.Any(), "Unknown").Return(0.0, false)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "invalid type",
+			url:                "/metric/summary/Alloc",
+			setupMocks:         func(storage *MockHandlerStorage) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := NewMockHandlerStorage(ctrl)
+			tt.setupMocks(storage)
+
+			r := chi.NewRouter()
+			r.Get("/metric/{metricType}/{metricName}", NewHandler(storage).MetricHandler())
+
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			resp, body := testutil.TestRequest(t, server, http.MethodGet, tt.url, "")
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+			for _, expected := range tt.expectedContains {
+				assert.Contains(t, body, expected)
+			}
+		})
+	}
+}
+
+func Test_handler_staticHandler(t *testing.T) {
+	handler := NewHandler(nil).StaticHandler()
+
+	for path, contentType := range map[string]string{
+		"/static/dashboard.css": "text/css",
+		"/static/dashboard.js":  "javascript",
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Header().Get("Content-Type"), contentType, path)
+		assert.NotEmpty(t, w.Body.String(), path)
+	}
+}
@@ -13,6 +13,9 @@ import (
 	context "context"
 	reflect "reflect"
 
+	history "github.com/NoobyTheTurtle/metrics/internal/history"
+	metadata "github.com/NoobyTheTurtle/metrics/internal/metadata"
+	model "github.com/NoobyTheTurtle/metrics/internal/model"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -94,6 +97,253 @@ func (mr *MockCountersGetterMockRecorder) GetAllCounters(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCounters", reflect.TypeOf((*MockCountersGetter)(nil).GetAllCounters), ctx)
 }
 
+// MockHistogramsGetter is a mock of HistogramsGetter interface.
+type MockHistogramsGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramsGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramsGetterMockRecorder is the mock recorder for MockHistogramsGetter.
+type MockHistogramsGetterMockRecorder struct {
+	mock *MockHistogramsGetter
+}
+
+// NewMockHistogramsGetter creates a new mock instance.
+func NewMockHistogramsGetter(ctrl *gomock.Controller) *MockHistogramsGetter {
+	mock := &MockHistogramsGetter{ctrl: ctrl}
+	mock.recorder = &MockHistogramsGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramsGetter) EXPECT() *MockHistogramsGetterMockRecorder {
+	return m.recorder
+}
+
+// GetAllHistograms mocks base method.
+func (m *MockHistogramsGetter) GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllHistograms", ctx)
+	ret0, _ := ret[0].(map[string]model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllHistograms indicates an expected call of GetAllHistograms.
+func (mr *MockHistogramsGetterMockRecorder) GetAllHistograms(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHistograms", reflect.TypeOf((*MockHistogramsGetter)(nil).GetAllHistograms), ctx)
+}
+
+// MockGaugeGetter is a mock of GaugeGetter interface.
+type MockGaugeGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockGaugeGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockGaugeGetterMockRecorder is the mock recorder for MockGaugeGetter.
+type MockGaugeGetterMockRecorder struct {
+	mock *MockGaugeGetter
+}
+
+// NewMockGaugeGetter creates a new mock instance.
+func NewMockGaugeGetter(ctrl *gomock.Controller) *MockGaugeGetter {
+	mock := &MockGaugeGetter{ctrl: ctrl}
+	mock.recorder = &MockGaugeGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGaugeGetter) EXPECT() *MockGaugeGetterMockRecorder {
+	return m.recorder
+}
+
+// GetGauge mocks base method.
+func (m *MockGaugeGetter) GetGauge(ctx context.Context, name string) (float64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGauge", ctx, name)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetGauge indicates an expected call of GetGauge.
+func (mr *MockGaugeGetterMockRecorder) GetGauge(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGauge", reflect.TypeOf((*MockGaugeGetter)(nil).GetGauge), ctx, name)
+}
+
+// MockCounterGetter is a mock of CounterGetter interface.
+type MockCounterGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCounterGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockCounterGetterMockRecorder is the mock recorder for MockCounterGetter.
+type MockCounterGetterMockRecorder struct {
+	mock *MockCounterGetter
+}
+
+// NewMockCounterGetter creates a new mock instance.
+func NewMockCounterGetter(ctrl *gomock.Controller) *MockCounterGetter {
+	mock := &MockCounterGetter{ctrl: ctrl}
+	mock.recorder = &MockCounterGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCounterGetter) EXPECT() *MockCounterGetterMockRecorder {
+	return m.recorder
+}
+
+// GetCounter mocks base method.
+func (m *MockCounterGetter) GetCounter(ctx context.Context, name string) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounter", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetCounter indicates an expected call of GetCounter.
+func (mr *MockCounterGetterMockRecorder) GetCounter(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounter", reflect.TypeOf((*MockCounterGetter)(nil).GetCounter), ctx, name)
+}
+
+// MockHistogramGetter is a mock of HistogramGetter interface.
+type MockHistogramGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramGetterMockRecorder is the mock recorder for MockHistogramGetter.
+type MockHistogramGetterMockRecorder struct {
+	mock *MockHistogramGetter
+}
+
+// NewMockHistogramGetter creates a new mock instance.
+func NewMockHistogramGetter(ctrl *gomock.Controller) *MockHistogramGetter {
+	mock := &MockHistogramGetter{ctrl: ctrl}
+	mock.recorder = &MockHistogramGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramGetter) EXPECT() *MockHistogramGetterMockRecorder {
+	return m.recorder
+}
+
+// GetHistogram mocks base method.
+func (m *MockHistogramGetter) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHistogramGetterMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHistogramGetter)(nil).GetHistogram), ctx, name)
+}
+
+// MockHistoryGetter is a mock of HistoryGetter interface.
+type MockHistoryGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistoryGetterMockRecorder is the mock recorder for MockHistoryGetter.
+type MockHistoryGetterMockRecorder struct {
+	mock *MockHistoryGetter
+}
+
+// NewMockHistoryGetter creates a new mock instance.
+func NewMockHistoryGetter(ctrl *gomock.Controller) *MockHistoryGetter {
+	mock := &MockHistoryGetter{ctrl: ctrl}
+	mock.recorder = &MockHistoryGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryGetter) EXPECT() *MockHistoryGetterMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockHistoryGetter) History(ctx context.Context, mType model.MetricType, name string) []history.Point {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, mType, name)
+	ret0, _ := ret[0].([]history.Point)
+	return ret0
+}
+
+// History indicates an expected call of History.
+func (mr *MockHistoryGetterMockRecorder) History(ctx, mType, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockHistoryGetter)(nil).History), ctx, mType, name)
+}
+
+// MockMetadataGetter is a mock of MetadataGetter interface.
+type MockMetadataGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockMetadataGetterMockRecorder is the mock recorder for MockMetadataGetter.
+type MockMetadataGetterMockRecorder struct {
+	mock *MockMetadataGetter
+}
+
+// NewMockMetadataGetter creates a new mock instance.
+func NewMockMetadataGetter(ctrl *gomock.Controller) *MockMetadataGetter {
+	mock := &MockMetadataGetter{ctrl: ctrl}
+	mock.recorder = &MockMetadataGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetadataGetter) EXPECT() *MockMetadataGetterMockRecorder {
+	return m.recorder
+}
+
+// ListMetadata mocks base method.
+func (m *MockMetadataGetter) ListMetadata(ctx context.Context) []metadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx)
+	ret0, _ := ret[0].([]metadata.Metadata)
+	return ret0
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockMetadataGetterMockRecorder) ListMetadata(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockMetadataGetter)(nil).ListMetadata), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockMetadataGetter) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", ctx, name)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockMetadataGetterMockRecorder) Metadata(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockMetadataGetter)(nil).Metadata), ctx, name)
+}
+
 // MockHandlerStorage is a mock of HandlerStorage interface.
 type MockHandlerStorage struct {
 	ctrl     *gomock.Controller
@@ -147,3 +397,106 @@ func (mr *MockHandlerStorageMockRecorder) GetAllGauges(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGauges", reflect.TypeOf((*MockHandlerStorage)(nil).GetAllGauges), ctx)
 }
+
+// GetAllHistograms mocks base method.
+func (m *MockHandlerStorage) GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllHistograms", ctx)
+	ret0, _ := ret[0].(map[string]model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllHistograms indicates an expected call of GetAllHistograms.
+func (mr *MockHandlerStorageMockRecorder) GetAllHistograms(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHistograms", reflect.TypeOf((*MockHandlerStorage)(nil).GetAllHistograms), ctx)
+}
+
+// GetCounter mocks base method.
+func (m *MockHandlerStorage) GetCounter(ctx context.Context, name string) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounter", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetCounter indicates an expected call of GetCounter.
+func (mr *MockHandlerStorageMockRecorder) GetCounter(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounter", reflect.TypeOf((*MockHandlerStorage)(nil).GetCounter), ctx, name)
+}
+
+// GetGauge mocks base method.
+func (m *MockHandlerStorage) GetGauge(ctx context.Context, name string) (float64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGauge", ctx, name)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetGauge indicates an expected call of GetGauge.
+func (mr *MockHandlerStorageMockRecorder) GetGauge(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGauge", reflect.TypeOf((*MockHandlerStorage)(nil).GetGauge), ctx, name)
+}
+
+// GetHistogram mocks base method.
+func (m *MockHandlerStorage) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHandlerStorageMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHandlerStorage)(nil).GetHistogram), ctx, name)
+}
+
+// History mocks base method.
+func (m *MockHandlerStorage) History(ctx context.Context, mType model.MetricType, name string) []history.Point {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, mType, name)
+	ret0, _ := ret[0].([]history.Point)
+	return ret0
+}
+
+// History indicates an expected call of History.
+func (mr *MockHandlerStorageMockRecorder) History(ctx, mType, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockHandlerStorage)(nil).History), ctx, mType, name)
+}
+
+// ListMetadata mocks base method.
+func (m *MockHandlerStorage) ListMetadata(ctx context.Context) []metadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx)
+	ret0, _ := ret[0].([]metadata.Metadata)
+	return ret0
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockHandlerStorageMockRecorder) ListMetadata(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockHandlerStorage)(nil).ListMetadata), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockHandlerStorage) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", ctx, name)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockHandlerStorageMockRecorder) Metadata(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockHandlerStorage)(nil).Metadata), ctx, name)
+}
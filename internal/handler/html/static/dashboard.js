@@ -0,0 +1,115 @@
+// Поиск, фильтр по типу, сортировка таблиц и автообновление главной страницы метрик.
+(function () {
+    "use strict";
+
+    var search = document.getElementById("search");
+    var typeFilter = document.getElementById("type-filter");
+    var refresh = document.getElementById("refresh");
+    var sortState = { key: "name", dir: "asc" };
+    var timer = null;
+
+    function compare(a, b, key) {
+        if (key === "value") {
+            return parseFloat(a.dataset.value) - parseFloat(b.dataset.value);
+        }
+        return a.dataset[key].localeCompare(b.dataset[key]);
+    }
+
+    function applySort() {
+        document.querySelectorAll("table.metrics").forEach(function (table) {
+            var tbody = table.tBodies[0];
+            var rows = Array.prototype.slice.call(tbody.rows);
+            rows.sort(function (a, b) {
+                var result = compare(a, b, sortState.key);
+                return sortState.dir === "asc" ? result : -result;
+            });
+            rows.forEach(function (row) {
+                tbody.appendChild(row);
+            });
+            table.querySelectorAll("th[data-sort]").forEach(function (th) {
+                if (th.dataset.sort === sortState.key) {
+                    th.dataset.dir = sortState.dir;
+                } else {
+                    delete th.dataset.dir;
+                }
+            });
+        });
+    }
+
+    function applyFilter() {
+        var query = search.value.trim().toLowerCase();
+        var type = typeFilter.value;
+        var visible = 0;
+
+        document.querySelectorAll("section.group").forEach(function (section) {
+            var group = section.dataset.group.toLowerCase();
+            var shown = 0;
+            section.querySelectorAll("tbody tr").forEach(function (row) {
+                var matches = (!query || row.dataset.name.toLowerCase().indexOf(query) !== -1 || group.indexOf(query) !== -1) &&
+                    (!type || row.dataset.type === type);
+                row.hidden = !matches;
+                if (matches) {
+                    shown++;
+                }
+            });
+            section.hidden = shown === 0;
+            visible += shown;
+        });
+
+        document.getElementById("visible").textContent = visible;
+    }
+
+    function bindHeaders() {
+        document.querySelectorAll("table.metrics th[data-sort]").forEach(function (th) {
+            th.addEventListener("click", function () {
+                var key = th.dataset.sort;
+                sortState.dir = sortState.key === key && sortState.dir === "asc" ? "desc" : "asc";
+                sortState.key = key;
+                applySort();
+            });
+        });
+    }
+
+    function reload() {
+        fetch(window.location.href, { credentials: "same-origin", headers: { Accept: "text/html" } })
+            .then(function (response) {
+                if (!response.ok) {
+                    throw new Error(response.status + " " + response.statusText);
+                }
+                return response.text();
+            })
+            .then(function (html) {
+                var doc = new DOMParser().parseFromString(html, "text/html");
+                document.getElementById("groups").innerHTML = doc.getElementById("groups").innerHTML;
+                document.getElementById("total").textContent = doc.getElementById("total").textContent;
+                bindHeaders();
+                applySort();
+                applyFilter();
+            })
+            .catch(function (err) {
+                console.warn("metrics refresh failed:", err);
+            });
+    }
+
+    function schedule() {
+        var seconds = parseInt(refresh.value, 10) || 0;
+        window.localStorage.setItem("metrics.refresh", String(seconds));
+        if (timer) {
+            window.clearInterval(timer);
+            timer = null;
+        }
+        if (seconds > 0) {
+            timer = window.setInterval(reload, seconds * 1000);
+        }
+    }
+
+    search.addEventListener("input", applyFilter);
+    typeFilter.addEventListener("change", applyFilter);
+    refresh.addEventListener("change", schedule);
+
+    refresh.value = window.localStorage.getItem("metrics.refresh") || "0";
+    bindHeaders();
+    applySort();
+    applyFilter();
+    schedule();
+})();
@@ -2,21 +2,35 @@ package handler
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
 	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
 	"github.com/NoobyTheTurtle/metrics/internal/database/postgres"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/api"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/html"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/json"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/openapi"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/plain"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/stream"
+	"github.com/NoobyTheTurtle/metrics/internal/keyring"
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
 	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
 	"github.com/NoobyTheTurtle/metrics/internal/storage/adapter"
+	"github.com/NoobyTheTurtle/metrics/internal/subnet"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 )
 
-// MetricStorage объединяет интерфейсы хранилища для всех типов обработчиков (JSON, HTML, plain text).
+// MetricStorage объединяет интерфейсы хранилища для всех типов обработчиков (JSON, HTML, plain text, stream, API).
 type MetricStorage interface {
+	api.HandlerStorage
 	html.HandlerStorage
 	json.HandlerStorage
 	plain.HandlerStorage
+	stream.HandlerStorage
 }
 
 var (
@@ -27,6 +41,7 @@ var (
 type RouterLogger interface {
 	Info(format string, args ...any)
 	Error(format string, args ...any)
+	With(keysAndValues ...any) *logger.ZapLogger
 }
 
 var (
@@ -48,4 +63,69 @@ type Decrypter interface {
 	Decrypt(data []byte) ([]byte, error)
 }
 
-var _ Decrypter = (*cryptoutil.PrivateKeyProvider)(nil)
+var (
+	_ Decrypter = (*cryptoutil.PrivateKeyProvider)(nil)
+	_ Decrypter = (*keyring.Keyring)(nil)
+	_ Decrypter = (*keyring.Single)(nil)
+)
+
+// TenantResolver определяет арендатора по API ключу
+type TenantResolver interface {
+	Lookup(apiKey string) (string, bool)
+}
+
+var (
+	_ TenantResolver = (*tenant.Registry)(nil)
+	_ TenantResolver = (*MockTenantResolver)(nil)
+)
+
+// Authenticator проверяет bearer токены
+type Authenticator interface {
+	Authenticate(token string) (*auth.Principal, error)
+}
+
+var (
+	_ Authenticator = (*auth.Authenticator)(nil)
+	_ Authenticator = (*MockAuthenticator)(nil)
+)
+
+// SignatureVerifier проверяет подпись агента его публичным ключом
+type SignatureVerifier interface {
+	Verify(agentID string, data []byte, signature string) error
+}
+
+var (
+	_ SignatureVerifier = (*signing.Registry)(nil)
+	_ SignatureVerifier = (*MockSignatureVerifier)(nil)
+)
+
+// RequestValidator проверяет запрос по спецификации OpenAPI
+type RequestValidator interface {
+	ValidateRequest(r *http.Request, pattern string) error
+}
+
+var (
+	_ RequestValidator = (*openapi.Validator)(nil)
+	_ RequestValidator = (*MockRequestValidator)(nil)
+)
+
+// RateLimiter ограничивает частоту запросов на запись от одного источника
+type RateLimiter interface {
+	Allow(source string) (bool, time.Duration)
+}
+
+var (
+	_ RateLimiter = (*limits.RateLimiter)(nil)
+	_ RateLimiter = (*MockRateLimiter)(nil)
+)
+
+// SubnetMatcher проверяет, входит ли адрес клиента в доверенную подсеть
+type SubnetMatcher interface {
+	Contains(ip net.IP) bool
+}
+
+var (
+	_ SubnetMatcher = (*net.IPNet)(nil)
+	_ SubnetMatcher = (*subnet.Trusted)(nil)
+	_ SubnetMatcher = (*MockSubnetMatcher)(nil)
+)
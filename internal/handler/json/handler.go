@@ -2,16 +2,55 @@
 // Реализует REST эндпоинты для отправки и получения метрик в JSON формате.
 package json
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+)
 
 type Handler struct {
-	storage HandlerStorage
+	storage       HandlerStorage
+	maxBatchSize  int
+	batchRejected *selfmetrics.Counter
+	batchSize     *selfmetrics.Histogram
+}
+
+// BatchSizeBuckets - границы корзин гистограммы числа метрик в запросах POST /updates/.
+var BatchSizeBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+// Option задает дополнительные параметры обработчиков.
+type Option func(*Handler)
+
+// WithMaxBatchSize ограничивает число метрик в одном запросе POST /updates/.
+// Отклоненные запросы учитываются в rejected.
+func WithMaxBatchSize(maxBatchSize int, rejected *selfmetrics.Counter) Option {
+	return func(h *Handler) {
+		h.maxBatchSize = maxBatchSize
+		h.batchRejected = rejected
+	}
 }
 
-func NewHandler(storage HandlerStorage) *Handler {
-	return &Handler{
+// WithBatchSizeHistogram включает учет числа метрик в запросах POST /updates/.
+func WithBatchSizeHistogram(batchSize *selfmetrics.Histogram) Option {
+	return func(h *Handler) {
+		h.batchSize = batchSize
+	}
+}
+
+func NewHandler(storage HandlerStorage, opts ...Option) *Handler {
+	h := &Handler{
 		storage: storage,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // UpdateHandler возвращает HTTP обработчик для обновления отдельных метрик.
@@ -31,6 +70,23 @@ func (h *Handler) ValueHandler() http.HandlerFunc {
 // UpdatesHandler возвращает HTTP обработчик для пакетного обновления метрик.
 // Endpoint: POST /updates/
 func (h *Handler) UpdatesHandler() http.HandlerFunc {
-	handler := newUpdatesHandler(h.storage)
+	handler := newUpdatesHandler(h.storage, h.maxBatchSize, h.batchRejected, h.batchSize)
 	return handler.ServeHTTP
 }
+
+// writeUpdateError отвечает на ошибку обновления метрик. Конфликт типов, зарезервированное
+// имя и превышение ограничения числа имен - ошибки клиента, остальные ошибки отвечают кодом 500.
+func writeUpdateError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, metadata.ErrTypeConflict):
+		http.Error(w, "Metric is registered with another type", http.StatusConflict)
+	case errors.Is(err, model.ErrBucketMismatch):
+		http.Error(w, "Histogram buckets do not match stored metric", http.StatusConflict)
+	case errors.Is(err, selfmetrics.ErrReservedName):
+		http.Error(w, "Metric name prefix '"+selfmetrics.Namespace+"' is reserved", http.StatusForbidden)
+	case errors.Is(err, limits.ErrTooManyNames):
+		http.Error(w, "Metric name limit exceeded", http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -27,6 +27,16 @@ type CounterSetter interface {
 	UpdateCounter(ctx context.Context, name string, value int64) (int64, error)
 }
 
+// HistogramGetter предоставляет чтение histogram метрик.
+type HistogramGetter interface {
+	GetHistogram(ctx context.Context, name string) (model.Histogram, bool)
+}
+
+// HistogramSetter предоставляет запись histogram метрик.
+type HistogramSetter interface {
+	UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error)
+}
+
 // BatchUpdater предоставляет пакетное обновление нескольких метрик.
 type BatchUpdater interface {
 	UpdateMetricsBatch(ctx context.Context, metrics model.Metrics) error
@@ -44,10 +54,17 @@ type CounterStorage interface {
 	CounterSetter
 }
 
+// HistogramStorage объединяет операции чтения и записи для histogram метрик.
+type HistogramStorage interface {
+	HistogramGetter
+	HistogramSetter
+}
+
 // HandlerStorage определяет полный интерфейс хранилища для JSON обработчиков.
 type HandlerStorage interface {
 	GaugeStorage
 	CounterStorage
+	HistogramStorage
 	BatchUpdater
 }
 
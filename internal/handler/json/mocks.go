@@ -173,6 +173,84 @@ func (mr *MockCounterSetterMockRecorder) UpdateCounter(ctx, name, value any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCounter", reflect.TypeOf((*MockCounterSetter)(nil).UpdateCounter), ctx, name, value)
 }
 
+// MockHistogramGetter is a mock of HistogramGetter interface.
+type MockHistogramGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramGetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramGetterMockRecorder is the mock recorder for MockHistogramGetter.
+type MockHistogramGetterMockRecorder struct {
+	mock *MockHistogramGetter
+}
+
+// NewMockHistogramGetter creates a new mock instance.
+func NewMockHistogramGetter(ctrl *gomock.Controller) *MockHistogramGetter {
+	mock := &MockHistogramGetter{ctrl: ctrl}
+	mock.recorder = &MockHistogramGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramGetter) EXPECT() *MockHistogramGetterMockRecorder {
+	return m.recorder
+}
+
+// GetHistogram mocks base method.
+func (m *MockHistogramGetter) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHistogramGetterMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHistogramGetter)(nil).GetHistogram), ctx, name)
+}
+
+// MockHistogramSetter is a mock of HistogramSetter interface.
+type MockHistogramSetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramSetterMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramSetterMockRecorder is the mock recorder for MockHistogramSetter.
+type MockHistogramSetterMockRecorder struct {
+	mock *MockHistogramSetter
+}
+
+// NewMockHistogramSetter creates a new mock instance.
+func NewMockHistogramSetter(ctrl *gomock.Controller) *MockHistogramSetter {
+	mock := &MockHistogramSetter{ctrl: ctrl}
+	mock.recorder = &MockHistogramSetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramSetter) EXPECT() *MockHistogramSetterMockRecorder {
+	return m.recorder
+}
+
+// UpdateHistogram mocks base method.
+func (m *MockHistogramSetter) UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHistogram", ctx, name, value)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHistogram indicates an expected call of UpdateHistogram.
+func (mr *MockHistogramSetterMockRecorder) UpdateHistogram(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHistogram", reflect.TypeOf((*MockHistogramSetter)(nil).UpdateHistogram), ctx, name, value)
+}
+
 // MockBatchUpdater is a mock of BatchUpdater interface.
 type MockBatchUpdater struct {
 	ctrl     *gomock.Controller
@@ -319,6 +397,60 @@ func (mr *MockCounterStorageMockRecorder) UpdateCounter(ctx, name, value any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCounter", reflect.TypeOf((*MockCounterStorage)(nil).UpdateCounter), ctx, name, value)
 }
 
+// MockHistogramStorage is a mock of HistogramStorage interface.
+type MockHistogramStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistogramStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockHistogramStorageMockRecorder is the mock recorder for MockHistogramStorage.
+type MockHistogramStorageMockRecorder struct {
+	mock *MockHistogramStorage
+}
+
+// NewMockHistogramStorage creates a new mock instance.
+func NewMockHistogramStorage(ctrl *gomock.Controller) *MockHistogramStorage {
+	mock := &MockHistogramStorage{ctrl: ctrl}
+	mock.recorder = &MockHistogramStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistogramStorage) EXPECT() *MockHistogramStorageMockRecorder {
+	return m.recorder
+}
+
+// GetHistogram mocks base method.
+func (m *MockHistogramStorage) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHistogramStorageMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHistogramStorage)(nil).GetHistogram), ctx, name)
+}
+
+// UpdateHistogram mocks base method.
+func (m *MockHistogramStorage) UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHistogram", ctx, name, value)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHistogram indicates an expected call of UpdateHistogram.
+func (mr *MockHistogramStorageMockRecorder) UpdateHistogram(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHistogram", reflect.TypeOf((*MockHistogramStorage)(nil).UpdateHistogram), ctx, name, value)
+}
+
 // MockHandlerStorage is a mock of HandlerStorage interface.
 type MockHandlerStorage struct {
 	ctrl     *gomock.Controller
@@ -373,6 +505,21 @@ func (mr *MockHandlerStorageMockRecorder) GetGauge(ctx, name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGauge", reflect.TypeOf((*MockHandlerStorage)(nil).GetGauge), ctx, name)
 }
 
+// GetHistogram mocks base method.
+func (m *MockHandlerStorage) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockHandlerStorageMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockHandlerStorage)(nil).GetHistogram), ctx, name)
+}
+
 // UpdateCounter mocks base method.
 func (m *MockHandlerStorage) UpdateCounter(ctx context.Context, name string, value int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -403,6 +550,21 @@ func (mr *MockHandlerStorageMockRecorder) UpdateGauge(ctx, name, value any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGauge", reflect.TypeOf((*MockHandlerStorage)(nil).UpdateGauge), ctx, name, value)
 }
 
+// UpdateHistogram mocks base method.
+func (m *MockHandlerStorage) UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHistogram", ctx, name, value)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHistogram indicates an expected call of UpdateHistogram.
+func (mr *MockHandlerStorageMockRecorder) UpdateHistogram(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHistogram", reflect.TypeOf((*MockHandlerStorage)(nil).UpdateHistogram), ctx, name, value)
+}
+
 // UpdateMetricsBatch mocks base method.
 func (m *MockHandlerStorage) UpdateMetricsBatch(ctx context.Context, metrics model.Metrics) error {
 	m.ctrl.T.Helper()
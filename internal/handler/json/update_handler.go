@@ -10,6 +10,7 @@ import (
 type UpdateStorage interface {
 	GaugeSetter
 	CounterSetter
+	HistogramSetter
 }
 
 type updateHandler struct {
@@ -51,7 +52,7 @@ func (h *updateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		value, err := h.storage.UpdateGauge(r.Context(), metric.ID, *metric.Value)
 		if err != nil {
-			http.Error(w, "Failed to update gauge", http.StatusInternalServerError)
+			writeUpdateError(w, err, "Failed to update gauge")
 			return
 		}
 
@@ -64,11 +65,28 @@ func (h *updateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		value, err := h.storage.UpdateCounter(r.Context(), metric.ID, *metric.Delta)
 		if err != nil {
-			http.Error(w, "Failed to update counter", http.StatusInternalServerError)
+			writeUpdateError(w, err, "Failed to update counter")
 			return
 		}
 
 		metric.Delta = &value
+	case model.HistogramType:
+		if metric.Histogram == nil {
+			http.Error(w, "Histogram field is required for histogram type", http.StatusBadRequest)
+			return
+		}
+		if err := metric.Histogram.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		value, err := h.storage.UpdateHistogram(r.Context(), metric.ID, *metric.Histogram)
+		if err != nil {
+			writeUpdateError(w, err, "Failed to update histogram")
+			return
+		}
+
+		metric.Histogram = &value
 	default:
 		http.Error(w, "Unknown metric type", http.StatusBadRequest)
 		return
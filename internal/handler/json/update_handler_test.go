@@ -2,10 +2,13 @@ package json
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/NoobyTheTurtle/metrics/internal/testutil"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -50,6 +53,49 @@ func Test_updateHandler_ServeHTTP(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   `{"id":"PollCount","type":"counter","delta":30}`,
 		},
+		{
+			name:        "successful histogram update",
+			requestBody: `{"id": "latency", "type": "histogram", "histogram": {"bounds": [0.1, 1], "counts": [1, 1, 0], "count": 2, "sum": 0.55}}`,
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().UpdateHistogram(gomock.Any(), "latency", model.Histogram{
+					Bounds: []float64{0.1, 1}, Counts: []uint64{1, 1, 0}, Count: 2, Sum: 0.55,
+				}).Return(model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{3, 1, 0}, Count: 4, Sum: 0.7}, nil)
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"id":"latency","type":"histogram","histogram":{"bounds":[0.1,1],"counts":[3,1,0],"count":4,"sum":0.7}}`,
+		},
+		{
+			name:        "missing histogram field",
+			requestBody: `{"id": "latency", "type": "histogram"}`,
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				return NewMockHandlerStorage(ctrl)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "Histogram field is required for histogram type\n",
+		},
+		{
+			name:        "invalid histogram",
+			requestBody: `{"id": "latency", "type": "histogram", "histogram": {"bounds": [1, 0.1], "counts": [0, 0, 0]}}`,
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				return NewMockHandlerStorage(ctrl)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   "invalid histogram: bounds must be strictly increasing\n",
+		},
+		{
+			name:        "histogram buckets mismatch",
+			requestBody: `{"id": "latency", "type": "histogram", "histogram": {"bounds": [1], "counts": [0, 0], "count": 0, "sum": 0}}`,
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().UpdateHistogram(gomock.Any(), "latency", gomock.Any()).
+					Return(model.Histogram{}, fmt.Errorf("wrapped: %w", model.ErrBucketMismatch))
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   "Histogram buckets do not match stored metric\n",
+		},
 		{
 			name:        "invalid JSON format",
 			requestBody: `{"id": "HeapObjects", "type": "gauge", "value": 7770.0`,
@@ -135,6 +181,21 @@ func Test_updateHandler_ServeHTTP(t *testing.T) {
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   "Failed to update counter\n",
 		},
+		{
+			name: "gauge type conflict",
+			requestBody: `{
+				"id": "PollCount",
+				"type": "gauge",
+				"value": 1
+			}`,
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().UpdateGauge(gomock.Any(), "PollCount", 1.0).Return(0.0, fmt.Errorf("wrapped: %w", metadata.ErrTypeConflict))
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   "Metric is registered with another type\n",
+		},
 	}
 
 	for _, tt := range tests {
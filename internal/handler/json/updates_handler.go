@@ -1,10 +1,12 @@
 package json
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 )
 
 type UpdatesStorage interface {
@@ -14,12 +16,18 @@ type UpdatesStorage interface {
 }
 
 type updatesHandler struct {
-	storage UpdatesStorage
+	storage      UpdatesStorage
+	maxBatchSize int
+	rejected     *selfmetrics.Counter
+	batchSize    *selfmetrics.Histogram
 }
 
-func newUpdatesHandler(storage UpdatesStorage) *updatesHandler {
+func newUpdatesHandler(storage UpdatesStorage, maxBatchSize int, rejected *selfmetrics.Counter, batchSize *selfmetrics.Histogram) *updatesHandler {
 	return &updatesHandler{
-		storage: storage,
+		storage:      storage,
+		maxBatchSize: maxBatchSize,
+		rejected:     rejected,
+		batchSize:    batchSize,
 	}
 }
 
@@ -42,6 +50,14 @@ func (h *updatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.batchSize.Observe(float64(len(metrics)))
+
+	if h.maxBatchSize > 0 && len(metrics) > h.maxBatchSize {
+		h.rejected.Inc()
+		http.Error(w, fmt.Sprintf("Batch is too large: %d metrics, limit is %d", len(metrics), h.maxBatchSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
 	for _, metric := range metrics {
 		if metric.ID == "" || metric.MType == "" {
 			http.Error(w, "id and type fields are required for all metrics", http.StatusBadRequest)
@@ -59,6 +75,15 @@ func (h *updatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, "delta field is required for counter type", http.StatusBadRequest)
 				return
 			}
+		case model.HistogramType:
+			if metric.Histogram == nil {
+				http.Error(w, "histogram field is required for histogram type", http.StatusBadRequest)
+				return
+			}
+			if err := metric.Histogram.Validate(); err != nil {
+				http.Error(w, fmt.Sprintf("metric '%s': %v", metric.ID, err), http.StatusBadRequest)
+				return
+			}
 		default:
 			http.Error(w, "Unknown metric type", http.StatusBadRequest)
 			return
@@ -67,7 +92,7 @@ func (h *updatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	err = h.storage.UpdateMetricsBatch(r.Context(), metrics)
 	if err != nil {
-		http.Error(w, "Failed to update metrics: "+err.Error(), http.StatusInternalServerError)
+		writeUpdateError(w, err, "Failed to update metrics: "+err.Error())
 		return
 	}
 
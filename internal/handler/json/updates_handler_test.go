@@ -11,6 +11,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -20,7 +23,7 @@ func TestUpdatesHandler_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(`[{"id": "gauge1", "type": "gauge", "value": 42.42}, {"id": "counter1", "type": "counter", "delta": 42}]`)
 
@@ -40,7 +43,7 @@ func TestUpdatesHandler_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(`invalid json`)
 	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
@@ -57,7 +60,7 @@ func TestUpdatesHandler_EmptyMetrics(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(`[]`)
 	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
@@ -95,6 +98,16 @@ func TestUpdatesHandler_ValidationErrors(t *testing.T) {
 			body:     `[{"id": "counter1", "type": "counter"}]`,
 			expected: http.StatusBadRequest,
 		},
+		{
+			name:     "nil histogram",
+			body:     `[{"id": "latency", "type": "histogram"}]`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid histogram",
+			body:     `[{"id": "latency", "type": "histogram", "histogram": {"bounds": [1], "counts": [1], "count": 1, "sum": 0.5}}]`,
+			expected: http.StatusBadRequest,
+		},
 		{
 			name:     "unknown metric type",
 			body:     `[{"id": "unknown1", "type": "unknown"}]`,
@@ -108,7 +121,7 @@ func TestUpdatesHandler_ValidationErrors(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockStorage := NewMockHandlerStorage(ctrl)
-			handler := newUpdatesHandler(mockStorage)
+			handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(tt.body)))
 			r = r.WithContext(context.Background())
@@ -126,7 +139,7 @@ func TestUpdatesHandler_StorageError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(`[{"id": "gauge1", "type": "gauge", "value": 42.42}]`)
 	mockStorage.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Any()).Return(errors.New("storage error")).Times(1)
@@ -140,12 +153,86 @@ func TestUpdatesHandler_StorageError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestUpdatesHandler_TypeConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockHandlerStorage(ctrl)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
+
+	body := []byte(`[{"id": "Alloc", "type": "counter", "delta": 1}]`)
+	mockStorage.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Any()).Return(fmt.Errorf("wrapped: %w", metadata.ErrTypeConflict)).Times(1)
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdatesHandler_MaxBatchSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockHandlerStorage(ctrl)
+	rejected := selfmetrics.NewRegistry().Counter("limits.batch_rejected")
+	handler := newUpdatesHandler(mockStorage, 2, rejected, nil)
+
+	body := []byte(`[{"id":"a","type":"gauge","value":1},{"id":"b","type":"gauge","value":2},{"id":"c","type":"gauge","value":3}]`)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, "Batch is too large: 3 metrics, limit is 2\n", w.Body.String())
+	assert.Equal(t, int64(1), rejected.Value())
+
+	mockStorage.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Len(2)).Return(nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`[{"id":"a","type":"gauge","value":1},{"id":"b","type":"gauge","value":2}]`))))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUpdatesHandler_NameLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockHandlerStorage(ctrl)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
+
+	mockStorage.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Any()).Return(fmt.Errorf("wrapped: %w", limits.ErrTooManyNames))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`[{"id":"new","type":"gauge","value":1}]`))))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "Metric name limit exceeded\n", w.Body.String())
+}
+
+func TestUpdatesHandler_ReservedName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockHandlerStorage(ctrl)
+	batchSize := selfmetrics.NewRegistry().Histogram("http.batch_size", BatchSizeBuckets)
+	handler := newUpdatesHandler(mockStorage, 0, nil, batchSize)
+
+	mockStorage.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Any()).Return(fmt.Errorf("wrapped: %w", selfmetrics.ErrReservedName))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`[{"id":"server.http.requests","type":"counter","delta":1}]`))))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "Metric name prefix 'server.' is reserved\n", w.Body.String())
+	assert.Equal(t, uint64(1), batchSize.Count())
+}
+
 func TestUpdatesHandler_LargePayload(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	var metrics []string
 	for i := 0; i < 100; i++ {
@@ -169,7 +256,7 @@ func TestUpdatesHandler_MixedMetricTypes(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(`[
 		{"id": "gauge1", "type": "gauge", "value": 10.5},
@@ -194,7 +281,7 @@ func TestUpdatesHandler_ZeroValues(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(`[
 		{"id": "gauge_zero", "type": "gauge", "value": 0.0},
@@ -217,7 +304,7 @@ func TestUpdatesHandler_ExtremeValues(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockHandlerStorage(ctrl)
-	handler := newUpdatesHandler(mockStorage)
+	handler := newUpdatesHandler(mockStorage, 0, nil, nil)
 
 	body := []byte(fmt.Sprintf(`[
 		{"id": "gauge_max", "type": "gauge", "value": %g},
@@ -10,6 +10,7 @@ import (
 type ValueStorage interface {
 	GaugeGetter
 	CounterGetter
+	HistogramGetter
 }
 
 type valueHandler struct {
@@ -59,6 +60,14 @@ func (h *valueHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		metric.Delta = &value
+	case model.HistogramType:
+		value, exists := h.storage.GetHistogram(r.Context(), metric.ID)
+		if !exists {
+			http.Error(w, "Histogram not found", http.StatusNotFound)
+			return
+		}
+
+		metric.Histogram = &value
 	default:
 		http.Error(w, "Unknown metric type", http.StatusBadRequest)
 		return
@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/NoobyTheTurtle/metrics/internal/testutil"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +48,18 @@ func Test_valueHandler_ServeHTTP(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   `{"id":"PollCount","type":"counter","delta":30}`,
 		},
+		{
+			name:        "successful histogram retrieval",
+			requestBody: `{"id": "latency", "type": "histogram"}`,
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().GetHistogram(gomock.Any(), "latency").
+					Return(model.Histogram{Bounds: []float64{1}, Counts: []uint64{2, 0}, Count: 2, Sum: 1.5}, true)
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"id":"latency","type":"histogram","histogram":{"bounds":[1],"counts":[2,0],"count":2,"sum":1.5}}`,
+		},
 		{
 			name:        "invalid JSON format",
 			requestBody: `{"id": "HeapObjects", "type": "gauge"`,
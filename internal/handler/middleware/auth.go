@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+)
+
+const bearerPrefix = "Bearer "
+
+// AuthMiddleware проверяет bearer токен из заголовка Authorization и наличие у клиента права scope.
+// Запросы на чтение из браузера могут передать токен в cookie access_token.
+// Если аутентификатор не задан, запросы пропускаются без изменений.
+func AuthMiddleware(authenticator Authenticator, scope string, log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authenticator == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := bearerToken(r)
+			if token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				writeError(w, r, http.StatusUnauthorized, "Bearer token is required")
+				return
+			}
+
+			principal, err := authenticator.Authenticate(token)
+			if err != nil {
+				logger.FromContextOr(r.Context(), log).Info("Authentication failed for request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics", error="invalid_token"`)
+				writeError(w, r, http.StatusUnauthorized, "Invalid bearer token")
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				logger.FromContextOr(r.Context(), log).Info("Subject '%s' from %s lacks scope '%s' for %s", principal.Subject, r.RemoteAddr, scope, r.URL.Path)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics", error="insufficient_scope", scope="`+scope+`"`)
+				writeError(w, r, http.StatusForbidden, "Insufficient scope")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// bearerToken возвращает токен из заголовка Authorization, а если заголовка нет - из cookie.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return readCookie(r, auth.TokenCookie)
+	}
+	token, ok := strings.CutPrefix(header, bearerPrefix)
+	if !ok {
+		return ""
+	}
+	return token
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name              string
+		withAuth          bool
+		method            string
+		authHeader        string
+		cookie            string
+		setupMocks        func(a *MockAuthenticator, logger *MockMiddlewareLogger)
+		expectedStatus    int
+		expectNextCall    bool
+		expectedChallenge bool
+	}{
+		{
+			name:           "no authenticator - should pass",
+			withAuth:       false,
+			setupMocks:     func(a *MockAuthenticator, logger *MockMiddlewareLogger) {},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:              "missing token - should fail",
+			withAuth:          true,
+			setupMocks:        func(a *MockAuthenticator, logger *MockMiddlewareLogger) {},
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: true,
+		},
+		{
+			name:              "non bearer scheme - should fail",
+			withAuth:          true,
+			authHeader:        "Basic dXNlcjpwYXNz",
+			setupMocks:        func(a *MockAuthenticator, logger *MockMiddlewareLogger) {},
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: true,
+		},
+		{
+			name:       "invalid token - should fail",
+			withAuth:   true,
+			authHeader: "Bearer bad",
+			setupMocks: func(a *MockAuthenticator, logger *MockMiddlewareLogger) {
+				a.EXPECT().Authenticate("bad").Return(nil, errors.New("invalid token"))
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: true,
+		},
+		{
+			name:       "insufficient scope - should fail",
+			withAuth:   true,
+			authHeader: "Bearer reader",
+			setupMocks: func(a *MockAuthenticator, logger *MockMiddlewareLogger) {
+				a.EXPECT().Authenticate("reader").Return(&auth.Principal{Subject: "dashboard", Scopes: []string{auth.ScopeRead}}, nil)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus:    http.StatusForbidden,
+			expectedChallenge: true,
+		},
+		{
+			name:       "valid token with scope - should pass",
+			withAuth:   true,
+			authHeader: "Bearer writer",
+			setupMocks: func(a *MockAuthenticator, logger *MockMiddlewareLogger) {
+				a.EXPECT().Authenticate("writer").Return(&auth.Principal{Subject: "agent", Scopes: []string{auth.ScopeWrite}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:     "token from cookie on read - should pass",
+			withAuth: true,
+			method:   http.MethodGet,
+			cookie:   "writer",
+			setupMocks: func(a *MockAuthenticator, logger *MockMiddlewareLogger) {
+				a.EXPECT().Authenticate("writer").Return(&auth.Principal{Subject: "agent", Scopes: []string{auth.ScopeWrite}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:              "token from cookie on write - should fail",
+			withAuth:          true,
+			cookie:            "writer",
+			setupMocks:        func(a *MockAuthenticator, logger *MockMiddlewareLogger) {},
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			authenticator := NewMockAuthenticator(ctrl)
+			logger := NewMockMiddlewareLogger(ctrl)
+			tt.setupMocks(authenticator, logger)
+
+			var a Authenticator
+			if tt.withAuth {
+				a = authenticator
+			}
+
+			var nextCalled bool
+			handler := AuthMiddleware(a, auth.ScopeWrite, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				if tt.withAuth {
+					p, ok := auth.PrincipalFromContext(r.Context())
+					assert.True(t, ok)
+					assert.Equal(t, "agent", p.Subject)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/update/", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: auth.TokenCookie, Value: tt.cookie})
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+			assert.Equal(t, tt.expectedChallenge, rr.Header().Get("WWW-Authenticate") != "")
+		})
+	}
+}
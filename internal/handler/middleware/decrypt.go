@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
 )
 
-// DecryptMiddleware создает middleware для дешифрования тела запроса, если доступен дешифратор
+// DecryptMiddleware создает middleware для дешифрования тела запроса, если доступен дешифратор.
+// Если дешифратор поддерживает несколько ключей, используется ключ из заголовка X-Crypto-Key-ID.
 func DecryptMiddleware(decrypter Decrypter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -15,14 +19,23 @@ func DecryptMiddleware(decrypter Decrypter) func(http.Handler) http.Handler {
 				return
 			}
 
+			_, span := tracer.Start(r.Context(), "middleware.Decrypt")
 			body, err := io.ReadAll(r.Body)
 			if err != nil {
+				tracing.End(span, err)
 				w.WriteHeader(http.StatusBadRequest)
 				io.WriteString(w, "Failed to read request body")
 				return
 			}
 
-			decryptedData, err := decrypter.Decrypt(body)
+			var decryptedData []byte
+			keyed, ok := decrypter.(KeyedDecrypter)
+			if keyID := r.Header.Get(cryptoutil.KeyIDHeader); ok && keyID != "" {
+				decryptedData, err = keyed.DecryptWithKey(keyID, body)
+			} else {
+				decryptedData, err = decrypter.Decrypt(body)
+			}
+			tracing.End(span, err)
 			if err != nil {
 				r.Body = io.NopCloser(bytes.NewReader(body))
 			} else {
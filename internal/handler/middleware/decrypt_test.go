@@ -2,12 +2,16 @@ package middleware
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
 	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
 )
 
@@ -150,3 +154,67 @@ type errorReader struct{}
 func (e *errorReader) Read(p []byte) (n int, err error) {
 	return 0, io.ErrUnexpectedEOF
 }
+
+func TestDecryptMiddleware_KeyID(t *testing.T) {
+	tests := []struct {
+		name         string
+		keyID        string
+		setupMocks   func(d *MockDecrypter, kd *MockKeyedDecrypter)
+		expectedBody string
+	}{
+		{
+			name:  "key id header selects key",
+			keyID: "k2",
+			setupMocks: func(d *MockDecrypter, kd *MockKeyedDecrypter) {
+				kd.EXPECT().DecryptWithKey("k2", []byte("encrypted")).Return([]byte("plain"), nil)
+			},
+			expectedBody: "plain",
+		},
+		{
+			name: "no key id header falls back to decrypt",
+			setupMocks: func(d *MockDecrypter, kd *MockKeyedDecrypter) {
+				d.EXPECT().Decrypt([]byte("encrypted")).Return([]byte("plain"), nil)
+			},
+			expectedBody: "plain",
+		},
+		{
+			name:  "unknown key id passes body through",
+			keyID: "missing",
+			setupMocks: func(d *MockDecrypter, kd *MockKeyedDecrypter) {
+				kd.EXPECT().DecryptWithKey("missing", []byte("encrypted")).Return(nil, errors.New("unknown key id"))
+			},
+			expectedBody: "encrypted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			d := NewMockDecrypter(ctrl)
+			kd := NewMockKeyedDecrypter(ctrl)
+			tt.setupMocks(d, kd)
+
+			decrypter := struct {
+				*MockDecrypter
+				*MockKeyedDecrypter
+			}{d, kd}
+
+			var body []byte
+			handler := DecryptMiddleware(decrypter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewReader([]byte("encrypted")))
+			if tt.keyID != "" {
+				req.Header.Set(cryptoutil.KeyIDHeader, tt.keyID)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// ErrorWriter отправляет клиенту ответ с ошибкой со статусом status и описанием detail.
+type ErrorWriter func(w http.ResponseWriter, status int, detail string)
+
+type errorWriterKey struct{}
+
+// ErrorWriterMiddleware задает формат ответов с ошибками для middleware, которые
+// выполняются после него, например RFC 7807 для маршрутов API.
+// Без него ошибки отправляются текстом через http.Error.
+func ErrorWriterMiddleware(write ErrorWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), errorWriterKey{}, write)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// writeError отправляет ошибку в формате, заданном ErrorWriterMiddleware для запроса.
+func writeError(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	if write, ok := r.Context().Value(errorWriterKey{}).(ErrorWriter); ok {
+		write(w, status, detail)
+		return
+	}
+	http.Error(w, detail, status)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorWriterMiddleware(t *testing.T) {
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, http.StatusTooManyRequests, "Rate limit exceeded")
+	})
+
+	t.Run("plain text by default", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		failing.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "Rate limit exceeded\n", rr.Body.String())
+	})
+
+	t.Run("custom writer", func(t *testing.T) {
+		write := func(w http.ResponseWriter, status int, detail string) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"detail":"` + detail + `"}`))
+		}
+
+		rr := httptest.NewRecorder()
+		ErrorWriterMiddleware(write)(failing).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `{"detail":"Rate limit exceeded"}`, rr.Body.String())
+	})
+}
@@ -46,8 +46,9 @@ func GzipMiddleware(next http.Handler) http.Handler {
 				return
 			}
 
-			r.Body = reader
-			defer reader.Close()
+			body := newTracedReader(r.Context(), "middleware.Gzip.decompress", reader)
+			r.Body = body
+			defer body.Close()
 		}
 
 		contentType := w.Header().Get("Content-Type")
@@ -2,49 +2,177 @@ package middleware
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/NoobyTheTurtle/metrics/internal/hash"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
 )
 
-func HashValidator(key string, logger MiddlewareLogger) func(http.Handler) http.Handler {
+// DefaultMaxClockSkew - допустимое по умолчанию расхождение метки времени запроса с часами сервера.
+const DefaultMaxClockSkew = 5 * time.Minute
+
+type hashOptions struct {
+	strict  bool
+	maxSkew time.Duration
+	nonces  NonceCache
+	keys    HashKeyProvider
+}
+
+// HashOption задает параметры проверки подписи запросов.
+type HashOption func(*hashOptions)
+
+// WithStrictHash включает строгий режим: запросы без подписи, метки времени
+// и nonce отклоняются.
+func WithStrictHash() HashOption {
+	return func(o *hashOptions) {
+		o.strict = true
+	}
+}
+
+// WithReplayProtection задает допустимое расхождение часов и кэш nonce
+// для отклонения повторно отправленных запросов.
+func WithReplayProtection(maxSkew time.Duration, nonces NonceCache) HashOption {
+	return func(o *hashOptions) {
+		o.maxSkew = maxSkew
+		o.nonces = nonces
+	}
+}
+
+// WithHashKeys задает набор ключей с идентификаторами вместо единственного ключа.
+// Запрос проверяется ключом из заголовка X-Key-ID, ответ подписывается основным ключом.
+func WithHashKeys(keys HashKeyProvider) HashOption {
+	return func(o *hashOptions) {
+		o.keys = keys
+	}
+}
+
+// verifyKey возвращает ключ проверки подписи с идентификатором id.
+// Пустой ключ означает, что проверка подписи отключена.
+func (o *hashOptions) verifyKey(key, id string) (string, error) {
+	if o.keys == nil {
+		return key, nil
+	}
+
+	if _, primary := o.keys.PrimaryHashKey(); primary == "" {
+		return "", nil
+	}
+
+	k, ok := o.keys.HashKey(id)
+	if !ok {
+		return "", fmt.Errorf("unknown key id '%s'", id)
+	}
+
+	return k, nil
+}
+
+// signKey возвращает идентификатор и ключ для подписи ответа.
+func (o *hashOptions) signKey(key string) (string, string) {
+	if o.keys == nil {
+		return "", key
+	}
+
+	return o.keys.PrimaryHashKey()
+}
+
+func newHashOptions(opts []HashOption) hashOptions {
+	options := hashOptions{maxSkew: DefaultMaxClockSkew}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func HashValidator(key string, log MiddlewareLogger, opts ...HashOption) func(http.Handler) http.Handler {
+	options := newHashOptions(opts)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, err := options.verifyKey(key, r.Header.Get(hash.KeyIDHeader))
+			if err != nil {
+				logger.FromContextOr(r.Context(), log).Info("Rejected request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				writeError(w, r, http.StatusBadRequest, "Unknown key id")
+				return
+			}
+
 			if key == "" {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			incomingHash := r.Header.Get("HashSHA256")
+			incomingHash := r.Header.Get(hash.Header)
+			timestamp := r.Header.Get(hash.TimestampHeader)
+			nonce := r.Header.Get(hash.NonceHeader)
+
 			if incomingHash == "" {
-				// logger.Info("Request without HashSHA256 header from %s for %s", r.RemoteAddr, r.URL.Path)
-				// http.Error(w, "HashSHA256 header is missing", http.StatusBadRequest)
+				if options.strict {
+					logger.FromContextOr(r.Context(), log).Info("Request without %s header from %s for %s", hash.Header, r.RemoteAddr, r.URL.Path)
+					writeError(w, r, http.StatusBadRequest, "HashSHA256 header is missing")
+					return
+				}
 
-				// Fix for tests
+				// Нестрогий режим пропускает неподписанные запросы для совместимости со старыми агентами
 				next.ServeHTTP(w, r)
 				return
 			}
 
+			signed := timestamp != "" || nonce != ""
+			if options.strict && (timestamp == "" || nonce == "") {
+				logger.FromContextOr(r.Context(), log).Info("Request without timestamp or nonce from %s for %s", r.RemoteAddr, r.URL.Path)
+				writeError(w, r, http.StatusBadRequest, "Timestamp and nonce headers are required")
+				return
+			}
+
+			if signed {
+				if err := checkTimestamp(timestamp, options.maxSkew); err != nil {
+					logger.FromContextOr(r.Context(), log).Info("Rejected request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+					writeError(w, r, http.StatusBadRequest, "Invalid request timestamp")
+					return
+				}
+				if nonce == "" {
+					logger.FromContextOr(r.Context(), log).Info("Request without nonce from %s for %s", r.RemoteAddr, r.URL.Path)
+					writeError(w, r, http.StatusBadRequest, "Nonce header is missing")
+					return
+				}
+			}
+
 			bodyBytes, err := io.ReadAll(r.Body)
 			if err != nil {
-				logger.Error("Failed to read request body from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
-				http.Error(w, "Failed to read request body", http.StatusInternalServerError)
+				logger.FromContextOr(r.Context(), log).Error("Failed to read request body from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				writeError(w, r, http.StatusInternalServerError, "Failed to read request body")
 				return
 			}
 
 			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
-			calculatedHash, err := hash.CalculateSHA256(bodyBytes, key)
+			var calculatedHash string
+			if signed {
+				calculatedHash, err = hash.CalculateSignedSHA256(r.Method, r.URL.RequestURI(), timestamp, nonce, bodyBytes, key)
+			} else {
+				calculatedHash, err = hash.CalculateSHA256(bodyBytes, key)
+			}
 			if err != nil {
-				logger.Error("Failed to calculate hash for request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
-				http.Error(w, "Failed to calculate hash", http.StatusInternalServerError)
+				logger.FromContextOr(r.Context(), log).Error("Failed to calculate hash for request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				writeError(w, r, http.StatusInternalServerError, "Failed to calculate hash")
+				return
+			}
+
+			if !hmac.Equal([]byte(incomingHash), []byte(calculatedHash)) {
+				logger.FromContextOr(r.Context(), log).Info("Hash mismatch for request from %s for %s. Incoming: %s, Calculated: %s", r.RemoteAddr, r.URL.Path, incomingHash, calculatedHash)
+				writeError(w, r, http.StatusBadRequest, "Hash mismatch")
 				return
 			}
 
-			if incomingHash != calculatedHash {
-				logger.Info("Hash mismatch for request from %s for %s. Incoming: %s, Calculated: %s", r.RemoteAddr, r.URL.Path, incomingHash, calculatedHash)
-				http.Error(w, "Hash mismatch", http.StatusBadRequest)
+			// nonce запоминается только после проверки подписи,
+			// чтобы неподписанные запросы не могли заполнить кэш
+			if signed && options.nonces != nil && !options.nonces.Add(nonce) {
+				logger.FromContextOr(r.Context(), log).Info("Replayed request from %s for %s with nonce %s", r.RemoteAddr, r.URL.Path, nonce)
+				writeError(w, r, http.StatusBadRequest, "Replayed request")
 				return
 			}
 
@@ -53,6 +181,26 @@ func HashValidator(key string, logger MiddlewareLogger) func(http.Handler) http.
 	}
 }
 
+// checkTimestamp проверяет, что метка времени в секундах Unix
+// отличается от текущего времени не более чем на maxSkew.
+func checkTimestamp(timestamp string, maxSkew time.Duration) error {
+	if timestamp == "" {
+		return errors.New("timestamp header is missing")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed timestamp '%s'", timestamp)
+	}
+
+	skew := time.Since(time.Unix(seconds, 0))
+	if skew < -maxSkew || skew > maxSkew {
+		return fmt.Errorf("timestamp '%s' is outside of allowed clock skew %s", timestamp, maxSkew)
+	}
+
+	return nil
+}
+
 type hashWriter struct {
 	originalWriter http.ResponseWriter
 	body           *bytes.Buffer
@@ -71,9 +219,12 @@ func (hw *hashWriter) WriteHeader(statusCode int) {
 	hw.statusCode = statusCode
 }
 
-func HashAppender(key string, logger MiddlewareLogger) func(http.Handler) http.Handler {
+func HashAppender(key string, log MiddlewareLogger, opts ...HashOption) func(http.Handler) http.Handler {
+	options := newHashOptions(opts)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyID, key := options.signKey(key)
 			if key == "" {
 				next.ServeHTTP(w, r)
 				return
@@ -82,6 +233,7 @@ func HashAppender(key string, logger MiddlewareLogger) func(http.Handler) http.H
 			hw := &hashWriter{
 				originalWriter: w,
 				body:           bytes.NewBuffer([]byte{}),
+				statusCode:     http.StatusOK,
 			}
 
 			next.ServeHTTP(hw, r)
@@ -89,18 +241,25 @@ func HashAppender(key string, logger MiddlewareLogger) func(http.Handler) http.H
 			responseBody := hw.body.Bytes()
 			calculatedHash, err := hash.CalculateSHA256(responseBody, key)
 			if err != nil {
-				logger.Error("Failed to calculate hash for response to %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				logger.FromContextOr(r.Context(), log).Error("Failed to calculate hash for response to %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
 
-			hw.Header().Set("HashSHA256", calculatedHash)
+			hw.Header().Set(hash.Header, calculatedHash)
+			if keyID != "" {
+				hw.Header().Set(hash.KeyIDHeader, keyID)
+			}
 
 			w.WriteHeader(hw.statusCode)
 
+			if len(responseBody) == 0 {
+				return
+			}
+
 			_, writeErr := w.Write(responseBody)
 			if writeErr != nil {
-				logger.Error("Failed to write response body to %s for %s: %v", r.RemoteAddr, r.URL.Path, writeErr)
+				logger.FromContextOr(r.Context(), log).Error("Failed to write response body to %s for %s: %v", r.RemoteAddr, r.URL.Path, writeErr)
 			}
 		})
 	}
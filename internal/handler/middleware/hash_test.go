@@ -5,7 +5,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -329,3 +331,292 @@ func TestHashWriter_Methods(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, hw.statusCode)
 	})
 }
+
+func TestHashValidator_StrictMode(t *testing.T) {
+	secretKey := "testSecret"
+	body := "test body"
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	sign := func(timestamp, nonce string) string {
+		h, err := hash.CalculateSignedSHA256(http.MethodPost, "/updates/", timestamp, nonce, []byte(body), secretKey)
+		require.NoError(t, err)
+		return h
+	}
+	legacyHash, err := hash.CalculateSHA256([]byte(body), secretKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		strict         bool
+		path           string
+		headers        map[string]string
+		setupMocks     func(logger *MockMiddlewareLogger, nonces *MockNonceCache)
+		expectedStatus int
+		expectNextCall bool
+	}{
+		{
+			name:   "strict - missing hash",
+			strict: true,
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "strict - legacy body only hash",
+			strict:  true,
+			headers: map[string]string{hash.Header: legacyHash},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "strict - valid signature",
+			strict: true,
+			headers: map[string]string{
+				hash.Header:          sign(now, "nonce-1"),
+				hash.TimestampHeader: now,
+				hash.NonceHeader:     "nonce-1",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				nonces.EXPECT().Add("nonce-1").Return(true)
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:   "strict - replayed nonce",
+			strict: true,
+			headers: map[string]string{
+				hash.Header:          sign(now, "nonce-1"),
+				hash.TimestampHeader: now,
+				hash.NonceHeader:     "nonce-1",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				nonces.EXPECT().Add("nonce-1").Return(false)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "strict - stale timestamp",
+			strict: true,
+			headers: map[string]string{
+				hash.Header:          sign(stale, "nonce-2"),
+				hash.TimestampHeader: stale,
+				hash.NonceHeader:     "nonce-2",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "strict - malformed timestamp",
+			strict: true,
+			headers: map[string]string{
+				hash.Header:          sign("yesterday", "nonce-3"),
+				hash.TimestampHeader: "yesterday",
+				hash.NonceHeader:     "nonce-3",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "strict - tampered timestamp",
+			strict: true,
+			headers: map[string]string{
+				hash.Header:          sign(stale, "nonce-4"),
+				hash.TimestampHeader: now,
+				hash.NonceHeader:     "nonce-4",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "strict - signature for another path",
+			strict: true,
+			path:   "/update/",
+			headers: map[string]string{
+				hash.Header:          sign(now, "nonce-6"),
+				hash.TimestampHeader: now,
+				hash.NonceHeader:     "nonce-6",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "non strict - legacy body only hash",
+			headers: map[string]string{hash.Header: legacyHash},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name: "non strict - signed request is checked for replay",
+			headers: map[string]string{
+				hash.Header:          sign(now, "nonce-5"),
+				hash.TimestampHeader: now,
+				hash.NonceHeader:     "nonce-5",
+			},
+			setupMocks: func(logger *MockMiddlewareLogger, nonces *MockNonceCache) {
+				nonces.EXPECT().Add("nonce-5").Return(false)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			logger := NewMockMiddlewareLogger(ctrl)
+			nonces := NewMockNonceCache(ctrl)
+			tt.setupMocks(logger, nonces)
+
+			opts := []HashOption{WithReplayProtection(DefaultMaxClockSkew, nonces)}
+			if tt.strict {
+				opts = append(opts, WithStrictHash())
+			}
+
+			var nextCalled bool
+			handler := HashValidator(secretKey, logger, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			path := tt.path
+			if path == "" {
+				path = "/updates/"
+			}
+			req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+		})
+	}
+}
+
+func TestHashValidator_KeyRotation(t *testing.T) {
+	body := "test body"
+	sign := func(key string) string {
+		h, err := hash.CalculateSHA256([]byte(body), key)
+		require.NoError(t, err)
+		return h
+	}
+
+	tests := []struct {
+		name           string
+		keyID          string
+		signature      string
+		primary        string
+		setupKeys      func(keys *MockHashKeyProvider)
+		expectedStatus int
+		expectNextCall bool
+	}{
+		{
+			name:      "request signed with old key",
+			keyID:     "old",
+			signature: sign("old-secret"),
+			setupKeys: func(keys *MockHashKeyProvider) {
+				keys.EXPECT().PrimaryHashKey().Return("new", "new-secret")
+				keys.EXPECT().HashKey("old").Return("old-secret", true)
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:      "request without key id uses primary key",
+			signature: sign("new-secret"),
+			setupKeys: func(keys *MockHashKeyProvider) {
+				keys.EXPECT().PrimaryHashKey().Return("new", "new-secret")
+				keys.EXPECT().HashKey("").Return("new-secret", true)
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:      "unknown key id",
+			keyID:     "retired",
+			signature: sign("retired-secret"),
+			setupKeys: func(keys *MockHashKeyProvider) {
+				keys.EXPECT().PrimaryHashKey().Return("new", "new-secret")
+				keys.EXPECT().HashKey("retired").Return("", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "no hmac keys configured",
+			signature: "anything",
+			setupKeys: func(keys *MockHashKeyProvider) {
+				keys.EXPECT().PrimaryHashKey().Return("", "")
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			logger := NewMockMiddlewareLogger(ctrl)
+			logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			keys := NewMockHashKeyProvider(ctrl)
+			tt.setupKeys(keys)
+
+			var nextCalled bool
+			handler := HashValidator("", logger, WithHashKeys(keys))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+			req.Header.Set(hash.Header, tt.signature)
+			if tt.keyID != "" {
+				req.Header.Set(hash.KeyIDHeader, tt.keyID)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+		})
+	}
+}
+
+func TestHashAppender_PrimaryKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	logger := NewMockMiddlewareLogger(ctrl)
+	keys := NewMockHashKeyProvider(ctrl)
+	keys.EXPECT().PrimaryHashKey().Return("new", "new-secret")
+
+	handler := HashAppender("", logger, WithHashKeys(keys))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	expectedHash, err := hash.CalculateSHA256([]byte(`{"status":"ok"}`), "new-secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expectedHash, rr.Header().Get(hash.Header))
+	assert.Equal(t, "new", rr.Header().Get(hash.KeyIDHeader))
+}
@@ -1,13 +1,26 @@
 package middleware
 
 import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
 	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/openapi"
+	"github.com/NoobyTheTurtle/metrics/internal/keyring"
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
 	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/replay"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/subnet"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 )
 
 type MiddlewareLogger interface {
 	Info(format string, args ...any)
 	Error(format string, args ...any)
+	With(keysAndValues ...any) *logger.ZapLogger
 }
 
 var (
@@ -20,4 +33,104 @@ type Decrypter interface {
 	Decrypt(data []byte) ([]byte, error)
 }
 
-var _ Decrypter = (*cryptoutil.PrivateKeyProvider)(nil)
+var (
+	_ Decrypter = (*cryptoutil.PrivateKeyProvider)(nil)
+	_ Decrypter = (*keyring.Keyring)(nil)
+	_ Decrypter = (*keyring.Single)(nil)
+)
+
+// KeyedDecrypter дешифрует данные ключом с указанным идентификатором
+type KeyedDecrypter interface {
+	DecryptWithKey(keyID string, data []byte) ([]byte, error)
+}
+
+var (
+	_ KeyedDecrypter = (*keyring.Keyring)(nil)
+	_ KeyedDecrypter = (*MockKeyedDecrypter)(nil)
+)
+
+// HashKeyProvider возвращает HMAC ключи по идентификатору
+type HashKeyProvider interface {
+	HashKey(id string) (string, bool)
+	PrimaryHashKey() (string, string)
+}
+
+var (
+	_ HashKeyProvider = (*keyring.Keyring)(nil)
+	_ HashKeyProvider = (*keyring.Single)(nil)
+	_ HashKeyProvider = (*MockHashKeyProvider)(nil)
+)
+
+// TenantResolver определяет арендатора по API ключу
+type TenantResolver interface {
+	Lookup(apiKey string) (string, bool)
+}
+
+var (
+	_ TenantResolver = (*tenant.Registry)(nil)
+	_ TenantResolver = (*MockTenantResolver)(nil)
+)
+
+// Authenticator проверяет bearer токены
+type Authenticator interface {
+	Authenticate(token string) (*auth.Principal, error)
+}
+
+var (
+	_ Authenticator = (*auth.Authenticator)(nil)
+	_ Authenticator = (*MockAuthenticator)(nil)
+)
+
+// NonceCache запоминает nonce подписанных запросов.
+// Add возвращает false, если nonce уже встречался.
+type NonceCache interface {
+	Add(nonce string) bool
+}
+
+var (
+	_ NonceCache = (*replay.Cache)(nil)
+	_ NonceCache = (*MockNonceCache)(nil)
+)
+
+// SignatureVerifier проверяет подпись агента его публичным ключом
+type SignatureVerifier interface {
+	Verify(agentID string, data []byte, signature string) error
+}
+
+var (
+	_ SignatureVerifier = (*signing.Registry)(nil)
+	_ SignatureVerifier = (*MockSignatureVerifier)(nil)
+)
+
+// RequestValidator проверяет запрос по спецификации API.
+// pattern - шаблон маршрута chi, обработавшего запрос.
+type RequestValidator interface {
+	ValidateRequest(r *http.Request, pattern string) error
+}
+
+var (
+	_ RequestValidator = (*openapi.Validator)(nil)
+	_ RequestValidator = (*MockRequestValidator)(nil)
+)
+
+// RateLimiter ограничивает частоту запросов источника.
+// Если запрос не разрешен, возвращает время до следующей попытки.
+type RateLimiter interface {
+	Allow(source string) (bool, time.Duration)
+}
+
+var (
+	_ RateLimiter = (*limits.RateLimiter)(nil)
+	_ RateLimiter = (*MockRateLimiter)(nil)
+)
+
+// SubnetMatcher проверяет, входит ли адрес клиента в доверенную подсеть
+type SubnetMatcher interface {
+	Contains(ip net.IP) bool
+}
+
+var (
+	_ SubnetMatcher = (*net.IPNet)(nil)
+	_ SubnetMatcher = (*subnet.Trusted)(nil)
+	_ SubnetMatcher = (*MockSubnetMatcher)(nil)
+)
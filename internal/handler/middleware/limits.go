@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+// BodyLimitMiddleware ограничивает размер тела запроса maxBytes байтами
+// и отвечает 413, если тело больше. Тело читается целиком до передачи
+// следующему обработчику, поэтому при установке после распаковки gzip
+// ограничивается и размер распакованного тела.
+// Если maxBytes не задан, запросы пропускаются без изменений.
+func BodyLimitMiddleware(maxBytes int64, rejected *selfmetrics.Counter, log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxBytes <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			r.Body.Close()
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					rejected.Inc()
+					logger.FromContextOr(r.Context(), log).Info("Request body from %s for %s exceeds %d bytes", r.RemoteAddr, r.URL.Path, maxBytes)
+					writeError(w, r, http.StatusRequestEntityTooLarge, "Request body is too large, limit is "+strconv.FormatInt(maxBytes, 10)+" bytes")
+					return
+				}
+				writeError(w, r, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitMiddleware ограничивает частоту запросов от одного источника
+// и отвечает 429 с заголовком Retry-After при превышении.
+// Источником считается арендатор, а для анонимных запросов - IP адрес клиента.
+// Middleware ставится до чтения тела, поэтому подпись агента еще не проверена
+// и не может служить источником.
+// Если ограничитель не задан, запросы пропускаются без изменений.
+func RateLimitMiddleware(limiter RateLimiter, log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			source := requestSource(r)
+			ok, retryAfter := limiter.Allow(source)
+			if !ok {
+				logger.FromContextOr(r.Context(), log).Info("Rate limit exceeded for %s on %s", source, r.URL.Path)
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				writeError(w, r, http.StatusTooManyRequests, "Rate limit exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func requestSource(r *http.Request) string {
+	if tenantID := tenant.FromContext(r.Context()); tenantID != "" {
+		return "tenant:" + tenantID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
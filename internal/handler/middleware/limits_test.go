@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxBytes       int64
+		body           string
+		expectedStatus int
+		expectNextCall bool
+	}{
+		{
+			name:           "no limit",
+			body:           strings.Repeat("a", 100),
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:           "body within limit",
+			maxBytes:       10,
+			body:           "0123456789",
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:           "body over limit",
+			maxBytes:       10,
+			body:           "0123456789a",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			logger := NewMockMiddlewareLogger(ctrl)
+			logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			rejected := selfmetrics.NewRegistry().Counter("limits.body_rejected")
+
+			var nextCalled bool
+			var received string
+			handler := BodyLimitMiddleware(tt.maxBytes, rejected, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/updates/", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+			if tt.expectNextCall {
+				assert.Equal(t, tt.body, received)
+				assert.Equal(t, int64(0), rejected.Value())
+			} else {
+				assert.Equal(t, int64(1), rejected.Value())
+			}
+		})
+	}
+}
+
+func TestBodyLimitMiddleware_DecompressedBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	logger := NewMockMiddlewareLogger(ctrl)
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write(bytes.Repeat([]byte("a"), 1000))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	limit := BodyLimitMiddleware(100, nil, logger)
+	handler := limit(GzipMiddleware(limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next handler must not be called")
+	}))))
+
+	req := httptest.NewRequest(http.MethodPost, "/updates/", &compressed)
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Less(t, compressed.Len(), 100, "compressed body fits the limit")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	limiter := NewMockRateLimiter(ctrl)
+	logger := NewMockMiddlewareLogger(ctrl)
+
+	handler := RateLimitMiddleware(limiter, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		request        func() *http.Request
+		source         string
+		allowed        bool
+		expectedStatus int
+	}{
+		{
+			name: "anonymous request is limited by ip",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/update/", nil)
+				r.RemoteAddr = "10.0.0.1:12345"
+				return r
+			},
+			source:         "ip:10.0.0.1",
+			allowed:        true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "tenant request is limited by tenant",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/update/", nil)
+				return r.WithContext(tenant.WithID(r.Context(), "team-a"))
+			},
+			source:         "tenant:team-a",
+			allowed:        true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unverified agent id is ignored",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/update/", nil)
+				ctx := tenant.WithID(r.Context(), "team-a")
+				return r.WithContext(signing.WithAgentID(ctx, "agent-1"))
+			},
+			source:         "tenant:team-a",
+			allowed:        false,
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.allowed {
+				limiter.EXPECT().Allow(tt.source).Return(true, time.Duration(0))
+			} else {
+				limiter.EXPECT().Allow(tt.source).Return(false, 1500*time.Millisecond)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.request())
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.allowed {
+				assert.Equal(t, "2", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware_NoLimiter(t *testing.T) {
+	handler := RateLimitMiddleware(nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/update/", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"bufio"
+	"errors"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
 )
 
 type (
@@ -28,6 +33,30 @@ func (r *loggingResponseWriter) WriteHeader(statusCode int) {
 	r.responseData.status = statusCode
 }
 
+// Flush передает буферизованные данные клиенту. Нужен для потоковых ответов (SSE).
+func (r *loggingResponseWriter) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack передает управление соединением обработчику. Нужен для WebSocket.
+func (r *loggingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("middleware.loggingResponseWriter.Hijack: response writer does not support hijacking")
+	}
+
+	if r.responseData.status == 0 {
+		r.responseData.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+func (r *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func LogMiddleware(log MiddlewareLogger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		logFn := func(w http.ResponseWriter, r *http.Request) {
@@ -45,6 +74,17 @@ func LogMiddleware(log MiddlewareLogger) func(http.Handler) http.Handler {
 
 			duration := time.Since(start)
 
+			if reqLog, ok := logger.FromContext(r.Context()); ok {
+				reqLog.With(
+					"uri", r.RequestURI,
+					"method", r.Method,
+					"status", responseData.status,
+					"duration", duration,
+					"size", responseData.size,
+				).Info("Request completed")
+				return
+			}
+
 			log.Info(
 				"uri=%s method=%s status=%d duration=%s size=%d",
 				r.RequestURI,
@@ -53,7 +93,6 @@ func LogMiddleware(log MiddlewareLogger) func(http.Handler) http.Handler {
 				duration,
 				responseData.size,
 			)
-
 		}
 		return http.HandlerFunc(logFn)
 	}
@@ -87,3 +87,30 @@ func TestLoggingMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingMiddleware_StreamingSupport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLog := NewMockMiddlewareLogger(ctrl)
+	mockLog.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	handler := LogMiddleware(mockLog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok, "response writer must support flushing")
+		w.Write([]byte("data: 1\n\n"))
+		flusher.Flush()
+
+		_, ok = w.(http.Hijacker)
+		assert.True(t, ok, "response writer must support hijacking")
+
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.Error(t, err, "recorder does not support hijacking")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/stream", nil))
+
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "data: 1\n\n", recorder.Body.String())
+}
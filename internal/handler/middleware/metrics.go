@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// MetricsMiddleware учитывает число запросов и их длительность в метриках сервера.
+// Метрики группируются по методу, шаблону маршрута и коду ответа:
+// http.requests.<метод>.<маршрут>.<код> и http.duration.<метод>.<маршрут>.<код>.
+func MetricsMiddleware(metrics *selfmetrics.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			responseData := &responseData{}
+			lw := loggingResponseWriter{
+				ResponseWriter: w,
+				responseData:   responseData,
+			}
+			next.ServeHTTP(&lw, r)
+
+			status := responseData.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			name := r.Method + "." + routeName(r) + "." + strconv.Itoa(status)
+			metrics.Counter("http.requests." + name).Inc()
+			metrics.Histogram("http.duration."+name, model.DefaultBuckets).Since(start)
+		})
+	}
+}
+
+// routeName возвращает шаблон маршрута запроса в виде части имени метрики,
+// например "update_metricType_metricName_metricValue" для /update/{metricType}/{metricName}/{metricValue}.
+// Шаблон вместо пути не дает именам метрик расти вместе с числом метрик клиентов.
+func routeName(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return "unmatched"
+	}
+
+	pattern := rctx.RoutePattern()
+	if pattern == "" {
+		return "unmatched"
+	}
+
+	pattern = strings.NewReplacer("{", "", "}", "", "*", "").Replace(pattern)
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return "root"
+	}
+
+	return strings.ReplaceAll(pattern, "/", "_")
+}
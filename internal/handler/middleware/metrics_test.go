@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedName string
+	}{
+		{
+			name:         "route with parameters",
+			method:       http.MethodPost,
+			path:         "/update/gauge/Alloc/1.5",
+			expectedName: "POST.update_metricType_metricName_metricValue.200",
+		},
+		{
+			name:         "root",
+			method:       http.MethodGet,
+			path:         "/",
+			expectedName: "GET.root.200",
+		},
+		{
+			name:         "wildcard route",
+			method:       http.MethodGet,
+			path:         "/static/style.css",
+			expectedName: "GET.static.200",
+		},
+		{
+			name:         "error status",
+			method:       http.MethodGet,
+			path:         "/value/gauge/Unknown",
+			expectedName: "GET.value_metricType_metricName.404",
+		},
+		{
+			name:         "unmatched route",
+			method:       http.MethodGet,
+			path:         "/unknown",
+			expectedName: "GET.unmatched.404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := selfmetrics.NewRegistry()
+
+			r := chi.NewRouter()
+			r.Use(MetricsMiddleware(metrics))
+			ok := func(w http.ResponseWriter, r *http.Request) {}
+			r.Post("/update/{metricType}/{metricName}/{metricValue}", ok)
+			r.Get("/", ok)
+			r.Get("/static/*", ok)
+			r.Get("/value/{metricType}/{metricName}", http.NotFound)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, int64(1), metrics.Counter("http.requests."+tt.expectedName).Value())
+			assert.Equal(t, uint64(1), metrics.Histogram("http.duration."+tt.expectedName, nil).Count())
+		})
+	}
+}
@@ -10,8 +10,13 @@
 package middleware
 
 import (
+	net "net"
+	http "net/http"
 	reflect "reflect"
+	time "time"
 
+	auth "github.com/NoobyTheTurtle/metrics/internal/auth"
+	logger "github.com/NoobyTheTurtle/metrics/internal/logger"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -73,6 +78,24 @@ func (mr *MockMiddlewareLoggerMockRecorder) Info(format any, args ...any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockMiddlewareLogger)(nil).Info), varargs...)
 }
 
+// With mocks base method.
+func (m *MockMiddlewareLogger) With(keysAndValues ...any) *logger.ZapLogger {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "With", varargs...)
+	ret0, _ := ret[0].(*logger.ZapLogger)
+	return ret0
+}
+
+// With indicates an expected call of With.
+func (mr *MockMiddlewareLoggerMockRecorder) With(keysAndValues ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockMiddlewareLogger)(nil).With), keysAndValues...)
+}
+
 // MockDecrypter is a mock of Decrypter interface.
 type MockDecrypter struct {
 	ctrl     *gomock.Controller
@@ -111,3 +134,365 @@ func (mr *MockDecrypterMockRecorder) Decrypt(data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockDecrypter)(nil).Decrypt), data)
 }
+
+// MockKeyedDecrypter is a mock of KeyedDecrypter interface.
+type MockKeyedDecrypter struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyedDecrypterMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyedDecrypterMockRecorder is the mock recorder for MockKeyedDecrypter.
+type MockKeyedDecrypterMockRecorder struct {
+	mock *MockKeyedDecrypter
+}
+
+// NewMockKeyedDecrypter creates a new mock instance.
+func NewMockKeyedDecrypter(ctrl *gomock.Controller) *MockKeyedDecrypter {
+	mock := &MockKeyedDecrypter{ctrl: ctrl}
+	mock.recorder = &MockKeyedDecrypterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyedDecrypter) EXPECT() *MockKeyedDecrypterMockRecorder {
+	return m.recorder
+}
+
+// DecryptWithKey mocks base method.
+func (m *MockKeyedDecrypter) DecryptWithKey(keyID string, data []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptWithKey", keyID, data)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptWithKey indicates an expected call of DecryptWithKey.
+func (mr *MockKeyedDecrypterMockRecorder) DecryptWithKey(keyID, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptWithKey", reflect.TypeOf((*MockKeyedDecrypter)(nil).DecryptWithKey), keyID, data)
+}
+
+// MockHashKeyProvider is a mock of HashKeyProvider interface.
+type MockHashKeyProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockHashKeyProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockHashKeyProviderMockRecorder is the mock recorder for MockHashKeyProvider.
+type MockHashKeyProviderMockRecorder struct {
+	mock *MockHashKeyProvider
+}
+
+// NewMockHashKeyProvider creates a new mock instance.
+func NewMockHashKeyProvider(ctrl *gomock.Controller) *MockHashKeyProvider {
+	mock := &MockHashKeyProvider{ctrl: ctrl}
+	mock.recorder = &MockHashKeyProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHashKeyProvider) EXPECT() *MockHashKeyProviderMockRecorder {
+	return m.recorder
+}
+
+// HashKey mocks base method.
+func (m *MockHashKeyProvider) HashKey(id string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey", id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockHashKeyProviderMockRecorder) HashKey(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockHashKeyProvider)(nil).HashKey), id)
+}
+
+// PrimaryHashKey mocks base method.
+func (m *MockHashKeyProvider) PrimaryHashKey() (string, string) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrimaryHashKey")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	return ret0, ret1
+}
+
+// PrimaryHashKey indicates an expected call of PrimaryHashKey.
+func (mr *MockHashKeyProviderMockRecorder) PrimaryHashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrimaryHashKey", reflect.TypeOf((*MockHashKeyProvider)(nil).PrimaryHashKey))
+}
+
+// MockTenantResolver is a mock of TenantResolver interface.
+type MockTenantResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockTenantResolverMockRecorder is the mock recorder for MockTenantResolver.
+type MockTenantResolverMockRecorder struct {
+	mock *MockTenantResolver
+}
+
+// NewMockTenantResolver creates a new mock instance.
+func NewMockTenantResolver(ctrl *gomock.Controller) *MockTenantResolver {
+	mock := &MockTenantResolver{ctrl: ctrl}
+	mock.recorder = &MockTenantResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantResolver) EXPECT() *MockTenantResolverMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockTenantResolver) Lookup(apiKey string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", apiKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockTenantResolverMockRecorder) Lookup(apiKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockTenantResolver)(nil).Lookup), apiKey)
+}
+
+// MockAuthenticator is a mock of Authenticator interface.
+type MockAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthenticatorMockRecorder is the mock recorder for MockAuthenticator.
+type MockAuthenticatorMockRecorder struct {
+	mock *MockAuthenticator
+}
+
+// NewMockAuthenticator creates a new mock instance.
+func NewMockAuthenticator(ctrl *gomock.Controller) *MockAuthenticator {
+	mock := &MockAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthenticator) Authenticate(token string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", token)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthenticatorMockRecorder) Authenticate(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthenticator)(nil).Authenticate), token)
+}
+
+// MockNonceCache is a mock of NonceCache interface.
+type MockNonceCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockNonceCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockNonceCacheMockRecorder is the mock recorder for MockNonceCache.
+type MockNonceCacheMockRecorder struct {
+	mock *MockNonceCache
+}
+
+// NewMockNonceCache creates a new mock instance.
+func NewMockNonceCache(ctrl *gomock.Controller) *MockNonceCache {
+	mock := &MockNonceCache{ctrl: ctrl}
+	mock.recorder = &MockNonceCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNonceCache) EXPECT() *MockNonceCacheMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockNonceCache) Add(nonce string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", nonce)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockNonceCacheMockRecorder) Add(nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockNonceCache)(nil).Add), nonce)
+}
+
+// MockSignatureVerifier is a mock of SignatureVerifier interface.
+type MockSignatureVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignatureVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockSignatureVerifierMockRecorder is the mock recorder for MockSignatureVerifier.
+type MockSignatureVerifierMockRecorder struct {
+	mock *MockSignatureVerifier
+}
+
+// NewMockSignatureVerifier creates a new mock instance.
+func NewMockSignatureVerifier(ctrl *gomock.Controller) *MockSignatureVerifier {
+	mock := &MockSignatureVerifier{ctrl: ctrl}
+	mock.recorder = &MockSignatureVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignatureVerifier) EXPECT() *MockSignatureVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockSignatureVerifier) Verify(agentID string, data []byte, signature string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", agentID, data, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSignatureVerifierMockRecorder) Verify(agentID, data, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSignatureVerifier)(nil).Verify), agentID, data, signature)
+}
+
+// MockRequestValidator is a mock of RequestValidator interface.
+type MockRequestValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockRequestValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockRequestValidatorMockRecorder is the mock recorder for MockRequestValidator.
+type MockRequestValidatorMockRecorder struct {
+	mock *MockRequestValidator
+}
+
+// NewMockRequestValidator creates a new mock instance.
+func NewMockRequestValidator(ctrl *gomock.Controller) *MockRequestValidator {
+	mock := &MockRequestValidator{ctrl: ctrl}
+	mock.recorder = &MockRequestValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRequestValidator) EXPECT() *MockRequestValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateRequest mocks base method.
+func (m *MockRequestValidator) ValidateRequest(r *http.Request, pattern string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRequest", r, pattern)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateRequest indicates an expected call of ValidateRequest.
+func (mr *MockRequestValidatorMockRecorder) ValidateRequest(r, pattern any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRequest", reflect.TypeOf((*MockRequestValidator)(nil).ValidateRequest), r, pattern)
+}
+
+// MockRateLimiter is a mock of RateLimiter interface.
+type MockRateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimiterMockRecorder is the mock recorder for MockRateLimiter.
+type MockRateLimiterMockRecorder struct {
+	mock *MockRateLimiter
+}
+
+// NewMockRateLimiter creates a new mock instance.
+func NewMockRateLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	mock := &MockRateLimiter{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiter) EXPECT() *MockRateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimiter) Allow(source string) (bool, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", source)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(time.Duration)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimiterMockRecorder) Allow(source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimiter)(nil).Allow), source)
+}
+
+// MockSubnetMatcher is a mock of SubnetMatcher interface.
+type MockSubnetMatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubnetMatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockSubnetMatcherMockRecorder is the mock recorder for MockSubnetMatcher.
+type MockSubnetMatcherMockRecorder struct {
+	mock *MockSubnetMatcher
+}
+
+// NewMockSubnetMatcher creates a new mock instance.
+func NewMockSubnetMatcher(ctrl *gomock.Controller) *MockSubnetMatcher {
+	mock := &MockSubnetMatcher{ctrl: ctrl}
+	mock.recorder = &MockSubnetMatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubnetMatcher) EXPECT() *MockSubnetMatcherMockRecorder {
+	return m.recorder
+}
+
+// Contains mocks base method.
+func (m *MockSubnetMatcher) Contains(ip net.IP) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contains", ip)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Contains indicates an expected call of Contains.
+func (mr *MockSubnetMatcherMockRecorder) Contains(ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contains", reflect.TypeOf((*MockSubnetMatcher)(nil).Contains), ip)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/requestid"
+)
+
+// RequestIDMiddleware принимает идентификатор запроса из заголовка X-Request-ID
+// или создает новый, если заголовка нет или он некорректен. Идентификатор
+// возвращается в ответе и сохраняется в контексте вместе с логгером запроса,
+// чтобы записи обработчиков, хранилища и повторных попыток можно было связать.
+func RequestIDMiddleware(log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !requestid.Valid(id) {
+				id = requestid.New()
+			}
+
+			w.Header().Set(requestid.Header, id)
+
+			ctx := requestid.WithID(r.Context(), id)
+			ctx = logger.NewContext(ctx, log.With("request_id", id))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/requestid"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		incomingID string
+		keepID     bool
+	}{
+		{
+			name:       "incoming id is kept",
+			incomingID: "agent-batch-1",
+			keepID:     true,
+		},
+		{
+			name: "missing id is generated",
+		},
+		{
+			name:       "invalid id is replaced",
+			incomingID: strings.Repeat("a", requestid.MaxLength+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			reqLog, err := logger.NewZapLogger("info", false)
+			require.NoError(t, err)
+
+			var loggedID any
+			log := NewMockMiddlewareLogger(ctrl)
+			log.EXPECT().With("request_id", gomock.Any()).DoAndReturn(func(keysAndValues ...any) *logger.ZapLogger {
+				loggedID = keysAndValues[1]
+				return reqLog
+			})
+
+			var ctxID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = requestid.FromContext(r.Context())
+				ctxLog, ok := logger.FromContext(r.Context())
+				assert.True(t, ok)
+				assert.Same(t, reqLog, ctxLog)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", nil)
+			if tt.incomingID != "" {
+				req.Header.Set(requestid.Header, tt.incomingID)
+			}
+			w := httptest.NewRecorder()
+
+			RequestIDMiddleware(log)(next).ServeHTTP(w, req)
+
+			responseID := w.Header().Get(requestid.Header)
+			assert.Equal(t, responseID, ctxID)
+			assert.Equal(t, responseID, loggedID)
+			assert.True(t, requestid.Valid(responseID))
+			if tt.keepID {
+				assert.Equal(t, tt.incomingID, responseID)
+			} else {
+				assert.NotEqual(t, tt.incomingID, responseID)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware_ContextLogger(t *testing.T) {
+	base, err := logger.NewZapLogger("info", false)
+	require.NoError(t, err)
+
+	var reqLog MiddlewareLogger
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqLog = logger.FromContextOr[MiddlewareLogger](r.Context(), nil)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "abc")
+	RequestIDMiddleware(base)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, reqLog)
+	assert.NotSame(t, base, reqLog)
+}
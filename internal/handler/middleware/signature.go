@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/hash"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+)
+
+type signatureOptions struct {
+	required bool
+	maxSkew  time.Duration
+	nonces   NonceCache
+}
+
+// SignatureOption задает параметры проверки подписи агента.
+type SignatureOption func(*signatureOptions)
+
+// WithRequiredSignature включает обязательную подпись: запросы без подписи агента отклоняются.
+func WithRequiredSignature() SignatureOption {
+	return func(o *signatureOptions) {
+		o.required = true
+	}
+}
+
+// WithSignatureReplayProtection задает допустимое расхождение часов и кэш nonce
+// для подписанных агентами запросов. Кэш не должен быть общим с HashValidator,
+// иначе второй проверяющий отклонит уже учтенный nonce.
+func WithSignatureReplayProtection(maxSkew time.Duration, nonces NonceCache) SignatureOption {
+	return func(o *signatureOptions) {
+		o.maxSkew = maxSkew
+		o.nonces = nonces
+	}
+}
+
+// SignatureValidator проверяет подпись Ed25519 агента из заголовка X-Signature.
+// Подпись покрывает метод, URI, метку времени, nonce и тело запроса, ключ выбирается по заголовку X-Agent-ID.
+func SignatureValidator(verifier SignatureVerifier, log MiddlewareLogger, opts ...SignatureOption) func(http.Handler) http.Handler {
+	options := signatureOptions{maxSkew: DefaultMaxClockSkew}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if verifier == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			signature := r.Header.Get(signing.SignatureHeader)
+			if signature == "" {
+				if options.required {
+					logger.FromContextOr(r.Context(), log).Info("Request without agent signature from %s for %s", r.RemoteAddr, r.URL.Path)
+					writeError(w, r, http.StatusUnauthorized, "Agent signature is required")
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			agentID := r.Header.Get(signing.AgentIDHeader)
+			timestamp := r.Header.Get(hash.TimestampHeader)
+			nonce := r.Header.Get(hash.NonceHeader)
+
+			if agentID == "" || nonce == "" {
+				logger.FromContextOr(r.Context(), log).Info("Signed request without agent id or nonce from %s for %s", r.RemoteAddr, r.URL.Path)
+				writeError(w, r, http.StatusBadRequest, "Agent id and nonce headers are required")
+				return
+			}
+
+			if err := checkTimestamp(timestamp, options.maxSkew); err != nil {
+				logger.FromContextOr(r.Context(), log).Info("Rejected request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				writeError(w, r, http.StatusBadRequest, "Invalid request timestamp")
+				return
+			}
+
+			bodyBytes, err := io.ReadAll(r.Body)
+			if err != nil {
+				logger.FromContextOr(r.Context(), log).Error("Failed to read request body from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				writeError(w, r, http.StatusInternalServerError, "Failed to read request body")
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+			if err := verifier.Verify(agentID, hash.SignedPayload(r.Method, r.URL.RequestURI(), timestamp, nonce, bodyBytes), signature); err != nil {
+				logger.FromContextOr(r.Context(), log).Info("Agent signature verification failed for request from %s for %s: %v", r.RemoteAddr, r.URL.Path, err)
+				writeError(w, r, http.StatusUnauthorized, "Invalid agent signature")
+				return
+			}
+
+			if options.nonces != nil && !options.nonces.Add(nonce) {
+				logger.FromContextOr(r.Context(), log).Info("Replayed request from %s for %s with nonce %s", r.RemoteAddr, r.URL.Path, nonce)
+				writeError(w, r, http.StatusBadRequest, "Replayed request")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(signing.WithAgentID(r.Context(), agentID)))
+		})
+	}
+}
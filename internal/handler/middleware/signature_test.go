@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/NoobyTheTurtle/metrics/internal/hash"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+)
+
+func TestSignatureValidator(t *testing.T) {
+	body := `[{"id":"test","type":"gauge","value":1}]`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	payload := hash.SignedPayload(http.MethodPost, "/updates/", now, "nonce", []byte(body))
+
+	signedHeaders := map[string]string{
+		signing.SignatureHeader: "c2lnbmF0dXJl",
+		signing.AgentIDHeader:   "agent-1",
+		hash.TimestampHeader:    now,
+		hash.NonceHeader:        "nonce",
+	}
+
+	tests := []struct {
+		name           string
+		withVerifier   bool
+		required       bool
+		headers        map[string]string
+		setupMocks     func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger)
+		expectedStatus int
+		expectNextCall bool
+	}{
+		{
+			name:           "no verifier - should pass",
+			headers:        map[string]string{},
+			setupMocks:     func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:           "unsigned request in optional mode - should pass",
+			withVerifier:   true,
+			headers:        map[string]string{},
+			setupMocks:     func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:         "unsigned request in required mode - should fail",
+			withVerifier: true,
+			required:     true,
+			headers:      map[string]string{},
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "valid signature - should pass",
+			withVerifier: true,
+			required:     true,
+			headers:      signedHeaders,
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				v.EXPECT().Verify("agent-1", payload, "c2lnbmF0dXJl").Return(nil)
+				nonces.EXPECT().Add("nonce").Return(true)
+			},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:         "invalid signature - should fail",
+			withVerifier: true,
+			headers:      signedHeaders,
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				v.EXPECT().Verify("agent-1", payload, "c2lnbmF0dXJl").Return(signing.ErrInvalidSignature)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown agent - should fail",
+			withVerifier: true,
+			headers:      signedHeaders,
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				v.EXPECT().Verify("agent-1", payload, "c2lnbmF0dXJl").Return(fmt.Errorf("%w: agent-1", signing.ErrUnknownAgent))
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "replayed nonce - should fail",
+			withVerifier: true,
+			headers:      signedHeaders,
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				v.EXPECT().Verify("agent-1", payload, "c2lnbmF0dXJl").Return(nil)
+				nonces.EXPECT().Add("nonce").Return(false)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "missing agent id - should fail",
+			withVerifier: true,
+			headers: map[string]string{
+				signing.SignatureHeader: "c2lnbmF0dXJl",
+				hash.TimestampHeader:    now,
+				hash.NonceHeader:        "nonce",
+			},
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "stale timestamp - should fail",
+			withVerifier: true,
+			headers: map[string]string{
+				signing.SignatureHeader: "c2lnbmF0dXJl",
+				signing.AgentIDHeader:   "agent-1",
+				hash.TimestampHeader:    stale,
+				hash.NonceHeader:        "nonce",
+			},
+			setupMocks: func(v *MockSignatureVerifier, nonces *MockNonceCache, logger *MockMiddlewareLogger) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			verifier := NewMockSignatureVerifier(ctrl)
+			nonces := NewMockNonceCache(ctrl)
+			logger := NewMockMiddlewareLogger(ctrl)
+			tt.setupMocks(verifier, nonces, logger)
+
+			var v SignatureVerifier
+			if tt.withVerifier {
+				v = verifier
+			}
+
+			opts := []SignatureOption{WithSignatureReplayProtection(DefaultMaxClockSkew, nonces)}
+			if tt.required {
+				opts = append(opts, WithRequiredSignature())
+			}
+
+			var nextCalled bool
+			handler := SignatureValidator(v, logger, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				if tt.withVerifier && tt.headers[signing.SignatureHeader] != "" {
+					agentID, ok := signing.AgentIDFromContext(r.Context())
+					assert.True(t, ok)
+					assert.Equal(t, "agent-1", agentID)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", bytes.NewBufferString(body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+		})
+	}
+}
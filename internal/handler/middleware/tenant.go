@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+// TenantMiddleware определяет арендатора по API ключу из заголовка X-API-Key
+// и сохраняет его идентификатор в контексте запроса. Запросы на чтение могут
+// передать ключ в cookie api_key: браузер не добавляет заголовки к переходам
+// по ссылкам и к EventSource, а cookie отправляет сам.
+// Если реестр арендаторов не задан, запросы пропускаются без изменений.
+func TenantMiddleware(resolver TenantResolver, log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if resolver == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			apiKey := r.Header.Get(tenant.APIKeyHeader)
+			if apiKey == "" {
+				apiKey = readCookie(r, tenant.APIKeyCookie)
+			}
+			if apiKey == "" {
+				logger.FromContextOr(r.Context(), log).Info("Request without %s header from %s for %s", tenant.APIKeyHeader, r.RemoteAddr, r.URL.Path)
+				writeError(w, r, http.StatusUnauthorized, "API key is required")
+				return
+			}
+
+			tenantID, ok := resolver.Lookup(apiKey)
+			if !ok {
+				logger.FromContextOr(r.Context(), log).Info("Unknown API key from %s for %s", r.RemoteAddr, r.URL.Path)
+				writeError(w, r, http.StatusUnauthorized, "Invalid API key")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+		})
+	}
+}
+
+// readCookie возвращает значение cookie для запросов на чтение (GET, HEAD).
+// Для остальных методов cookie не учитывается, чтобы чужая страница не могла
+// изменить данные от имени браузера пользователя.
+func readCookie(r *http.Request, name string) string {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return ""
+	}
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
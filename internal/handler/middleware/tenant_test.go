@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		withResolver     bool
+		method           string
+		apiKey           string
+		cookie           string
+		setupMocks       func(resolver *MockTenantResolver, logger *MockMiddlewareLogger)
+		expectedStatus   int
+		expectedTenantID string
+		expectNextCall   bool
+	}{
+		{
+			name:           "no resolver - should pass",
+			withResolver:   false,
+			setupMocks:     func(resolver *MockTenantResolver, logger *MockMiddlewareLogger) {},
+			expectedStatus: http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			name:         "missing api key - should fail",
+			withResolver: true,
+			setupMocks: func(resolver *MockTenantResolver, logger *MockMiddlewareLogger) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectNextCall: false,
+		},
+		{
+			name:         "unknown api key - should fail",
+			withResolver: true,
+			apiKey:       "unknown",
+			setupMocks: func(resolver *MockTenantResolver, logger *MockMiddlewareLogger) {
+				resolver.EXPECT().Lookup("unknown").Return("", false)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectNextCall: false,
+		},
+		{
+			name:         "valid api key - should pass with tenant",
+			withResolver: true,
+			apiKey:       "key-a",
+			setupMocks: func(resolver *MockTenantResolver, logger *MockMiddlewareLogger) {
+				resolver.EXPECT().Lookup("key-a").Return("team-a", true)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedTenantID: "team-a",
+			expectNextCall:   true,
+		},
+		{
+			name:         "api key from cookie on read - should pass with tenant",
+			withResolver: true,
+			cookie:       "key-a",
+			setupMocks: func(resolver *MockTenantResolver, logger *MockMiddlewareLogger) {
+				resolver.EXPECT().Lookup("key-a").Return("team-a", true)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedTenantID: "team-a",
+			expectNextCall:   true,
+		},
+		{
+			name:         "api key from cookie on write - should fail",
+			withResolver: true,
+			method:       http.MethodPost,
+			cookie:       "key-a",
+			setupMocks: func(resolver *MockTenantResolver, logger *MockMiddlewareLogger) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectNextCall: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			resolver := NewMockTenantResolver(ctrl)
+			logger := NewMockMiddlewareLogger(ctrl)
+			tt.setupMocks(resolver, logger)
+
+			var r TenantResolver
+			if tt.withResolver {
+				r = resolver
+			}
+
+			var nextCalled bool
+			var tenantID string
+			handler := TenantMiddleware(r, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				tenantID = tenant.FromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(tenant.APIKeyHeader, tt.apiKey)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: tenant.APIKeyCookie, Value: tt.cookie})
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCall, nextCalled)
+			assert.Equal(t, tt.expectedTenantID, tenantID)
+		})
+	}
+}
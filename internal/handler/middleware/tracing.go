@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("handler/middleware")
+
+// TracingMiddleware начинает серверный span запроса, продолжая трассу из заголовка traceparent.
+// Имя span содержит шаблон маршрута, а не путь, чтобы не зависеть от имен метрик.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		responseData := &responseData{}
+		lw := loggingResponseWriter{
+			ResponseWriter: w,
+			responseData:   responseData,
+		}
+		next.ServeHTTP(&lw, r.WithContext(ctx))
+
+		status := responseData.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+		}
+	})
+}
+
+// tracedReader записывает span от первого чтения до конца тела запроса.
+// Нужен для потоковой обработки тела, например распаковки gzip,
+// которая выполняется при чтении тела следующими обработчиками.
+type tracedReader struct {
+	io.ReadCloser
+	ctx  context.Context
+	name string
+	span trace.Span
+	done bool
+}
+
+func newTracedReader(ctx context.Context, name string, body io.ReadCloser) *tracedReader {
+	return &tracedReader{ReadCloser: body, ctx: ctx, name: name}
+}
+
+func (r *tracedReader) Read(p []byte) (int, error) {
+	if r.span == nil {
+		_, r.span = tracer.Start(r.ctx, r.name)
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		r.end(nil)
+	} else if err != nil {
+		r.end(err)
+	}
+	return n, err
+}
+
+func (r *tracedReader) Close() error {
+	r.end(nil)
+	return r.ReadCloser.Close()
+}
+
+func (r *tracedReader) end(err error) {
+	if r.span == nil || r.done {
+		return
+	}
+	r.done = true
+	tracing.End(r.span, err)
+}
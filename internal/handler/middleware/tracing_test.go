@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	provider := otel.GetTracerProvider()
+	propagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		traceparent    string
+		status         int
+		expectedParent string
+		expectError    bool
+	}{
+		{
+			name:   "new trace",
+			status: http.StatusOK,
+		},
+		{
+			name:           "continues trace from traceparent",
+			traceparent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			status:         http.StatusOK,
+			expectedParent: "00f067aa0ba902b7",
+		},
+		{
+			name:        "server error",
+			status:      http.StatusInternalServerError,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := setupTestTracing(t)
+
+			r := chi.NewRouter()
+			r.Use(TracingMiddleware)
+			r.Post("/update/{metricType}/{metricName}/{metricValue}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/update/gauge/Alloc/1", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "POST /update/{metricType}/{metricName}/{metricValue}", span.Name())
+			if tt.expectedParent != "" {
+				assert.Equal(t, tt.expectedParent, span.Parent().SpanID().String())
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+			} else {
+				assert.False(t, span.Parent().IsValid())
+			}
+			assert.Equal(t, tt.expectError, span.Status().Code == codes.Error)
+		})
+	}
+}
+
+func TestGzipMiddleware_DecompressSpan(t *testing.T) {
+	recorder := setupTestTracing(t)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte(`[{"id":"Alloc","type":"gauge","value":1}]`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	handler := GzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/updates/", &compressed).WithContext(ctx)
+	req.Header.Set("Content-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "middleware.Gzip.decompress", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+)
+
+// TrustedSubnetMiddleware пропускает только запросы из доверенной подсети
+// и отвечает 403 на остальные. Адрес клиента берется из адреса соединения,
+// заголовки вроде X-Real-IP не учитываются: клиент может подставить в них любой адрес.
+// Если подсеть не задана, запросы пропускаются без изменений.
+// Нераспознанный адрес передается в subnet как nil, решение о нем принимает subnet.
+func TrustedSubnetMiddleware(subnet SubnetMatcher, log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subnet == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !subnet.Contains(clientIP(r)) {
+				logger.FromContextOr(r.Context(), log).Info("Request from untrusted address %s for %s", r.RemoteAddr, r.URL.Path)
+				writeError(w, r, http.StatusForbidden, "Forbidden")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return net.ParseIP(r.RemoteAddr)
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTrustedSubnetMiddleware(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("192.168.1.0/24")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		subnet         SubnetMatcher
+		remoteAddr     string
+		realIP         string
+		expectedStatus int
+		expectLog      bool
+	}{
+		{
+			name:           "no subnet - should pass",
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "remote address in subnet",
+			subnet:         subnet,
+			remoteAddr:     "192.168.1.20:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "remote address outside subnet",
+			subnet:         subnet,
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusForbidden,
+			expectLog:      true,
+		},
+		{
+			name:           "spoofed real ip is ignored",
+			subnet:         subnet,
+			remoteAddr:     "10.0.0.1:1234",
+			realIP:         "192.168.1.15",
+			expectedStatus: http.StatusForbidden,
+			expectLog:      true,
+		},
+		{
+			name:           "remote address without port",
+			subnet:         subnet,
+			remoteAddr:     "192.168.1.20",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			logger := NewMockMiddlewareLogger(ctrl)
+			if tt.expectLog {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			w := httptest.NewRecorder()
+
+			TrustedSubnetMiddleware(tt.subnet, logger)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+)
+
+// ValidationMiddleware проверяет параметры и JSON тело запроса по спецификации OpenAPI.
+// Должен стоять после дешифрования и распаковки тела. Если валидатор не задан,
+// запросы пропускаются без изменений.
+func ValidationMiddleware(validator RequestValidator, log MiddlewareLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if validator == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			pattern := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				pattern = rctx.RoutePattern()
+			}
+
+			if err := validator.ValidateRequest(r, pattern); err != nil {
+				logger.FromContextOr(r.Context(), log).Info("Request validation failed for %s %s: %v", r.Method, pattern, err)
+				writeError(w, r, http.StatusBadRequest, "Request validation failed: "+err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
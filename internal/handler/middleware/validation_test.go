@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidationMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		validatorErr   error
+		nilValidator   bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid request",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name:           "invalid request",
+			validatorErr:   errors.New(`property "id" is missing`),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Request validation failed: property \"id\" is missing\n",
+		},
+		{
+			name:           "validation disabled",
+			nilValidator:   true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLogger := NewMockMiddlewareLogger(ctrl)
+			var validator RequestValidator
+			if !tt.nilValidator {
+				mockValidator := NewMockRequestValidator(ctrl)
+				mockValidator.EXPECT().ValidateRequest(gomock.Any(), "/update/{metricType}").Return(tt.validatorErr)
+				validator = mockValidator
+			}
+			if tt.validatorErr != nil {
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+			}
+
+			r := chi.NewRouter()
+			r.With(ValidationMiddleware(validator, mockLogger)).Post("/update/{metricType}", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("ok"))
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/update/gauge", strings.NewReader(`{}`)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -11,9 +11,17 @@ package handler
 
 import (
 	context "context"
+	net "net"
+	http "net/http"
 	reflect "reflect"
+	time "time"
 
+	auth "github.com/NoobyTheTurtle/metrics/internal/auth"
+	history "github.com/NoobyTheTurtle/metrics/internal/history"
+	logger "github.com/NoobyTheTurtle/metrics/internal/logger"
+	metadata "github.com/NoobyTheTurtle/metrics/internal/metadata"
 	model "github.com/NoobyTheTurtle/metrics/internal/model"
+	notify "github.com/NoobyTheTurtle/metrics/internal/notify"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +49,21 @@ func (m *MockMetricStorage) EXPECT() *MockMetricStorageMockRecorder {
 	return m.recorder
 }
 
+// DeclareMetadata mocks base method.
+func (m_2 *MockMetricStorage) DeclareMetadata(ctx context.Context, m metadata.Metadata) (metadata.Metadata, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "DeclareMetadata", ctx, m)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclareMetadata indicates an expected call of DeclareMetadata.
+func (mr *MockMetricStorageMockRecorder) DeclareMetadata(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclareMetadata", reflect.TypeOf((*MockMetricStorage)(nil).DeclareMetadata), ctx, m)
+}
+
 // GetAllCounters mocks base method.
 func (m *MockMetricStorage) GetAllCounters(ctx context.Context) (map[string]int64, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +94,21 @@ func (mr *MockMetricStorageMockRecorder) GetAllGauges(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGauges", reflect.TypeOf((*MockMetricStorage)(nil).GetAllGauges), ctx)
 }
 
+// GetAllHistograms mocks base method.
+func (m *MockMetricStorage) GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllHistograms", ctx)
+	ret0, _ := ret[0].(map[string]model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllHistograms indicates an expected call of GetAllHistograms.
+func (mr *MockMetricStorageMockRecorder) GetAllHistograms(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHistograms", reflect.TypeOf((*MockMetricStorage)(nil).GetAllHistograms), ctx)
+}
+
 // GetCounter mocks base method.
 func (m *MockMetricStorage) GetCounter(ctx context.Context, name string) (int64, bool) {
 	m.ctrl.T.Helper()
@@ -101,6 +139,78 @@ func (mr *MockMetricStorageMockRecorder) GetGauge(ctx, name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGauge", reflect.TypeOf((*MockMetricStorage)(nil).GetGauge), ctx, name)
 }
 
+// GetHistogram mocks base method.
+func (m *MockMetricStorage) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, name)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockMetricStorageMockRecorder) GetHistogram(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockMetricStorage)(nil).GetHistogram), ctx, name)
+}
+
+// History mocks base method.
+func (m *MockMetricStorage) History(ctx context.Context, mType model.MetricType, name string) []history.Point {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, mType, name)
+	ret0, _ := ret[0].([]history.Point)
+	return ret0
+}
+
+// History indicates an expected call of History.
+func (mr *MockMetricStorageMockRecorder) History(ctx, mType, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockMetricStorage)(nil).History), ctx, mType, name)
+}
+
+// ListMetadata mocks base method.
+func (m *MockMetricStorage) ListMetadata(ctx context.Context) []metadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx)
+	ret0, _ := ret[0].([]metadata.Metadata)
+	return ret0
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockMetricStorageMockRecorder) ListMetadata(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockMetricStorage)(nil).ListMetadata), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockMetricStorage) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata", ctx, name)
+	ret0, _ := ret[0].(metadata.Metadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockMetricStorageMockRecorder) Metadata(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockMetricStorage)(nil).Metadata), ctx, name)
+}
+
+// Subscribe mocks base method.
+func (m *MockMetricStorage) Subscribe(ctx context.Context, filter notify.Filter) *notify.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, filter)
+	ret0, _ := ret[0].(*notify.Subscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockMetricStorageMockRecorder) Subscribe(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockMetricStorage)(nil).Subscribe), ctx, filter)
+}
+
 // UpdateCounter mocks base method.
 func (m *MockMetricStorage) UpdateCounter(ctx context.Context, name string, value int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +241,21 @@ func (mr *MockMetricStorageMockRecorder) UpdateGauge(ctx, name, value any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGauge", reflect.TypeOf((*MockMetricStorage)(nil).UpdateGauge), ctx, name, value)
 }
 
+// UpdateHistogram mocks base method.
+func (m *MockMetricStorage) UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHistogram", ctx, name, value)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHistogram indicates an expected call of UpdateHistogram.
+func (mr *MockMetricStorageMockRecorder) UpdateHistogram(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHistogram", reflect.TypeOf((*MockMetricStorage)(nil).UpdateHistogram), ctx, name, value)
+}
+
 // UpdateMetricsBatch mocks base method.
 func (m *MockMetricStorage) UpdateMetricsBatch(ctx context.Context, metrics model.Metrics) error {
 	m.ctrl.T.Helper()
@@ -203,6 +328,24 @@ func (mr *MockRouterLoggerMockRecorder) Info(format any, args ...any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockRouterLogger)(nil).Info), varargs...)
 }
 
+// With mocks base method.
+func (m *MockRouterLogger) With(keysAndValues ...any) *logger.ZapLogger {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "With", varargs...)
+	ret0, _ := ret[0].(*logger.ZapLogger)
+	return ret0
+}
+
+// With indicates an expected call of With.
+func (mr *MockRouterLoggerMockRecorder) With(keysAndValues ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockRouterLogger)(nil).With), keysAndValues...)
+}
+
 // MockDBPinger is a mock of DBPinger interface.
 type MockDBPinger struct {
 	ctrl     *gomock.Controller
@@ -279,3 +422,234 @@ func (mr *MockDecrypterMockRecorder) Decrypt(data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockDecrypter)(nil).Decrypt), data)
 }
+
+// MockTenantResolver is a mock of TenantResolver interface.
+type MockTenantResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockTenantResolverMockRecorder is the mock recorder for MockTenantResolver.
+type MockTenantResolverMockRecorder struct {
+	mock *MockTenantResolver
+}
+
+// NewMockTenantResolver creates a new mock instance.
+func NewMockTenantResolver(ctrl *gomock.Controller) *MockTenantResolver {
+	mock := &MockTenantResolver{ctrl: ctrl}
+	mock.recorder = &MockTenantResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantResolver) EXPECT() *MockTenantResolverMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockTenantResolver) Lookup(apiKey string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", apiKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockTenantResolverMockRecorder) Lookup(apiKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockTenantResolver)(nil).Lookup), apiKey)
+}
+
+// MockAuthenticator is a mock of Authenticator interface.
+type MockAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthenticatorMockRecorder is the mock recorder for MockAuthenticator.
+type MockAuthenticatorMockRecorder struct {
+	mock *MockAuthenticator
+}
+
+// NewMockAuthenticator creates a new mock instance.
+func NewMockAuthenticator(ctrl *gomock.Controller) *MockAuthenticator {
+	mock := &MockAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthenticator) Authenticate(token string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", token)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthenticatorMockRecorder) Authenticate(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthenticator)(nil).Authenticate), token)
+}
+
+// MockSignatureVerifier is a mock of SignatureVerifier interface.
+type MockSignatureVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignatureVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockSignatureVerifierMockRecorder is the mock recorder for MockSignatureVerifier.
+type MockSignatureVerifierMockRecorder struct {
+	mock *MockSignatureVerifier
+}
+
+// NewMockSignatureVerifier creates a new mock instance.
+func NewMockSignatureVerifier(ctrl *gomock.Controller) *MockSignatureVerifier {
+	mock := &MockSignatureVerifier{ctrl: ctrl}
+	mock.recorder = &MockSignatureVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignatureVerifier) EXPECT() *MockSignatureVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockSignatureVerifier) Verify(agentID string, data []byte, signature string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", agentID, data, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSignatureVerifierMockRecorder) Verify(agentID, data, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSignatureVerifier)(nil).Verify), agentID, data, signature)
+}
+
+// MockRequestValidator is a mock of RequestValidator interface.
+type MockRequestValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockRequestValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockRequestValidatorMockRecorder is the mock recorder for MockRequestValidator.
+type MockRequestValidatorMockRecorder struct {
+	mock *MockRequestValidator
+}
+
+// NewMockRequestValidator creates a new mock instance.
+func NewMockRequestValidator(ctrl *gomock.Controller) *MockRequestValidator {
+	mock := &MockRequestValidator{ctrl: ctrl}
+	mock.recorder = &MockRequestValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRequestValidator) EXPECT() *MockRequestValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateRequest mocks base method.
+func (m *MockRequestValidator) ValidateRequest(r *http.Request, pattern string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRequest", r, pattern)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateRequest indicates an expected call of ValidateRequest.
+func (mr *MockRequestValidatorMockRecorder) ValidateRequest(r, pattern any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRequest", reflect.TypeOf((*MockRequestValidator)(nil).ValidateRequest), r, pattern)
+}
+
+// MockRateLimiter is a mock of RateLimiter interface.
+type MockRateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimiterMockRecorder is the mock recorder for MockRateLimiter.
+type MockRateLimiterMockRecorder struct {
+	mock *MockRateLimiter
+}
+
+// NewMockRateLimiter creates a new mock instance.
+func NewMockRateLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	mock := &MockRateLimiter{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiter) EXPECT() *MockRateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimiter) Allow(source string) (bool, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", source)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(time.Duration)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimiterMockRecorder) Allow(source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimiter)(nil).Allow), source)
+}
+
+// MockSubnetMatcher is a mock of SubnetMatcher interface.
+type MockSubnetMatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubnetMatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockSubnetMatcherMockRecorder is the mock recorder for MockSubnetMatcher.
+type MockSubnetMatcherMockRecorder struct {
+	mock *MockSubnetMatcher
+}
+
+// NewMockSubnetMatcher creates a new mock instance.
+func NewMockSubnetMatcher(ctrl *gomock.Controller) *MockSubnetMatcher {
+	mock := &MockSubnetMatcher{ctrl: ctrl}
+	mock.recorder = &MockSubnetMatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubnetMatcher) EXPECT() *MockSubnetMatcherMockRecorder {
+	return m.recorder
+}
+
+// Contains mocks base method.
+func (m *MockSubnetMatcher) Contains(ip net.IP) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contains", ip)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Contains indicates an expected call of Contains.
+func (mr *MockSubnetMatcherMockRecorder) Contains(ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contains", reflect.TypeOf((*MockSubnetMatcher)(nil).Contains), ip)
+}
@@ -0,0 +1,52 @@
+// Package openapi отдает спецификацию OpenAPI 3 сервера метрик, встроенную страницу
+// документации и проверяет входящие запросы на соответствие спецификации.
+//
+// Спецификация хранится в openapi.json и должна описывать каждый маршрут,
+// зарегистрированный в handler.Router.setupRoutes.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+const (
+	ContentTypeValue = "application/json"
+	UIContentType    = "text/html; charset=utf-8"
+)
+
+//go:embed openapi.json
+var specJSON []byte
+
+//go:embed ui/index.html
+var uiHTML []byte
+
+// Spec возвращает документ OpenAPI в формате JSON.
+func Spec() []byte {
+	return specJSON
+}
+
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// SpecHandler отдает документ OpenAPI.
+func (h *Handler) SpecHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeValue)
+		w.WriteHeader(http.StatusOK)
+		w.Write(specJSON)
+	}
+}
+
+// UIHandler отдает страницу документации, которая загружает /openapi.json
+// и не использует внешние ресурсы.
+func (h *Handler) UIHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", UIContentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(uiHTML)
+	}
+}
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSpec(t *testing.T) {
+	doc, err := LoadSpec()
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.NotNil(t, doc.Paths.Value("/update/"))
+	assert.NotNil(t, doc.Components.Schemas["Metric"])
+}
+
+func TestSpecHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHandler().SpecHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ContentTypeValue, w.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+}
+
+func TestUIHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHandler().UIHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, UIContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `fetch("/openapi.json")`)
+	assert.NotContains(t, w.Body.String(), "https://", "documentation page must not load external resources")
+}
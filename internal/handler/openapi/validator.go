@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/go-chi/chi/v5"
+)
+
+// LoadSpec разбирает и проверяет встроенный документ OpenAPI.
+func LoadSpec() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specJSON)
+	if err != nil {
+		return nil, fmt.Errorf("openapi.LoadSpec: failed to parse spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi.LoadSpec: invalid spec: %w", err)
+	}
+
+	return doc, nil
+}
+
+// Validator проверяет параметры и JSON тело запроса по спецификации.
+// Аутентификация не проверяется: ею занимаются middleware роутера.
+type Validator struct {
+	doc     *openapi3.T
+	options *openapi3filter.Options
+}
+
+// NewValidator создает валидатор по встроенной спецификации.
+func NewValidator() (*Validator, error) {
+	doc, err := LoadSpec()
+	if err != nil {
+		return nil, fmt.Errorf("openapi.NewValidator: %w", err)
+	}
+
+	return &Validator{
+		doc: doc,
+		options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// ValidateRequest проверяет запрос, обработанный маршрутом chi с шаблоном pattern.
+// Шаблоны chi совпадают с путями спецификации. Запросы к маршрутам,
+// не описанным в спецификации, не проверяются.
+func (v *Validator) ValidateRequest(r *http.Request, pattern string) error {
+	pattern, pathItem := v.findPath(pattern)
+	if pathItem == nil {
+		return nil
+	}
+
+	operation := pathItem.GetOperation(r.Method)
+	if operation == nil {
+		return nil
+	}
+
+	pathParams := make(map[string]string)
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		for i, key := range rctx.URLParams.Keys {
+			pathParams[key] = rctx.URLParams.Values[i]
+		}
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route: &routers.Route{
+			Spec:      v.doc,
+			Path:      pattern,
+			PathItem:  pathItem,
+			Method:    r.Method,
+			Operation: operation,
+		},
+		Options: v.options,
+	}
+
+	if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+		return describe(err)
+	}
+
+	return nil
+}
+
+// findPath ищет путь спецификации для шаблона маршрута.
+// chi.Context.RoutePattern отбрасывает завершающий слеш (/update/ -> /update),
+// поэтому проверяется и вариант со слешем.
+func (v *Validator) findPath(pattern string) (string, *openapi3.PathItem) {
+	for _, candidate := range []string{pattern, pattern + "/"} {
+		if pathItem := v.doc.Paths.Value(candidate); pathItem != nil {
+			return candidate, pathItem
+		}
+	}
+	return pattern, nil
+}
+
+// describe сокращает ошибку валидации до места и причины, без дампа схемы.
+func describe(err error) error {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return err
+	}
+
+	location := "request body"
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) && reqErr.Parameter != nil {
+		location = fmt.Sprintf("parameter %q in %s", reqErr.Parameter.Name, reqErr.Parameter.In)
+	}
+
+	if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+		location += " at /" + strings.Join(pointer, "/")
+	}
+
+	return fmt.Errorf("%s: %s", location, schemaErr.Reason)
+}
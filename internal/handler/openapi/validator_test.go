@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_ValidateRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		pattern       string
+		path          string
+		body          string
+		contentType   string
+		expectedError string
+	}{
+		{
+			name:        "valid update",
+			method:      http.MethodPost,
+			pattern:     "/update/",
+			path:        "/update/",
+			body:        `{"id":"Alloc","type":"gauge","value":1.5}`,
+			contentType: "application/json",
+		},
+		{
+			name:          "missing required id",
+			method:        http.MethodPost,
+			pattern:       "/update/",
+			path:          "/update/",
+			body:          `{"type":"gauge","value":1.5}`,
+			contentType:   "application/json",
+			expectedError: `property "id" is missing`,
+		},
+		{
+			name:          "unknown metric type",
+			method:        http.MethodPost,
+			pattern:       "/updates/",
+			path:          "/updates/",
+			body:          `[{"id":"Alloc","type":"summary"}]`,
+			contentType:   "application/json",
+			expectedError: "value is not one of the allowed values",
+		},
+		{
+			name:          "wrong value type",
+			method:        http.MethodPost,
+			pattern:       "/update/",
+			path:          "/update/",
+			body:          `{"id":"PollCount","type":"counter","delta":"1"}`,
+			contentType:   "application/json",
+			expectedError: "value must be an integer",
+		},
+		{
+			name:          "empty batch values request",
+			method:        http.MethodPost,
+			pattern:       "/api/v1/values",
+			path:          "/api/v1/values",
+			body:          `{"metrics":[]}`,
+			contentType:   "application/json",
+			expectedError: "minimum number of items is 1",
+		},
+		{
+			name:          "invalid path parameter",
+			method:        http.MethodGet,
+			pattern:       "/api/v1/metrics/{metricType}/{metricName}",
+			path:          "/api/v1/metrics/summary/Alloc",
+			expectedError: `parameter "metricType" in path`,
+		},
+		{
+			name:          "invalid query parameter",
+			method:        http.MethodGet,
+			pattern:       "/api/v1/metrics",
+			path:          "/api/v1/metrics?limit=5000",
+			expectedError: `parameter "limit" in query`,
+		},
+		{
+			name:    "undocumented route is not validated",
+			method:  http.MethodPost,
+			pattern: "/undocumented",
+			path:    "/undocumented",
+			body:    `not json`,
+		},
+	}
+
+	validator, err := NewValidator()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validationErr error
+
+			r := chi.NewRouter()
+			r.MethodFunc(tt.method, tt.pattern, func(w http.ResponseWriter, r *http.Request) {
+				validationErr = validator.ValidateRequest(r, chi.RouteContext(r.Context()).RoutePattern())
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, validationErr)
+				return
+			}
+
+			require.Error(t, validationErr)
+			assert.Contains(t, validationErr.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestValidator_KeepsBody(t *testing.T) {
+	validator, err := NewValidator()
+	require.NoError(t, err)
+
+	body := `{"id":"Alloc","type":"gauge","value":1.5}`
+	req := httptest.NewRequest(http.MethodPost, "/update/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	require.NoError(t, validator.ValidateRequest(req, "/update/"))
+
+	data, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data), "handlers must still be able to read the body")
+}
@@ -2,13 +2,15 @@ package ping
 
 import (
 	"net/http"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
 )
 
 func (h *Handler) PingHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := h.db.Ping(r.Context())
 		if err != nil {
-			h.logger.Error("Database connection failed: %v", err)
+			logger.FromContextOr(r.Context(), h.logger).Error("Database connection failed: %v", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
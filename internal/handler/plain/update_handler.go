@@ -1,9 +1,13 @@
 package plain
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -44,7 +48,7 @@ func (h *updateGaugeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	_, err = h.storage.UpdateGauge(r.Context(), metricName, value)
 	if err != nil {
-		http.Error(w, "Failed to update gauge", http.StatusInternalServerError)
+		writeUpdateError(w, err, "Failed to update gauge")
 		return
 	}
 
@@ -68,9 +72,24 @@ func (h *updateCounterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	_, err = h.storage.UpdateCounter(r.Context(), metricName, value)
 	if err != nil {
-		http.Error(w, "Failed to update counter", http.StatusInternalServerError)
+		writeUpdateError(w, err, "Failed to update counter")
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// writeUpdateError отвечает на ошибку обновления метрик. Конфликт типов, зарезервированное
+// имя и превышение ограничения числа имен - ошибки клиента, остальные ошибки отвечают кодом 500.
+func writeUpdateError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, metadata.ErrTypeConflict):
+		http.Error(w, "Metric is registered with another type", http.StatusConflict)
+	case errors.Is(err, selfmetrics.ErrReservedName):
+		http.Error(w, "Metric name prefix '"+selfmetrics.Namespace+"' is reserved", http.StatusForbidden)
+	case errors.Is(err, limits.ErrTooManyNames):
+		http.Error(w, "Metric name limit exceeded", http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
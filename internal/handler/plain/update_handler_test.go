@@ -2,10 +2,14 @@ package plain
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 	"github.com/NoobyTheTurtle/metrics/internal/testutil"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -133,6 +137,39 @@ func Test_handler_updateHandler(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 		},
+		{
+			name:   "counter type conflict",
+			method: http.MethodPost,
+			url:    "/update/counter/Alloc/1",
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().UpdateCounter(gomock.Any(), "Alloc", int64(1)).Return(int64(0), fmt.Errorf("wrapped: %w", metadata.ErrTypeConflict))
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:   "gauge name limit exceeded",
+			method: http.MethodPost,
+			url:    "/update/gauge/New/1",
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().UpdateGauge(gomock.Any(), "New", 1.0).Return(0.0, fmt.Errorf("wrapped: %w", limits.ErrTooManyNames))
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "reserved gauge name",
+			method: http.MethodPost,
+			url:    "/update/gauge/server.runtime.goroutines/1",
+			setupMocks: func(ctrl *gomock.Controller) *MockHandlerStorage {
+				mockStorage := NewMockHandlerStorage(ctrl)
+				mockStorage.EXPECT().UpdateGauge(gomock.Any(), "server.runtime.goroutines", 1.0).Return(0.0, fmt.Errorf("wrapped: %w", selfmetrics.ErrReservedName))
+				return mockStorage
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,11 +5,16 @@ package handler
 import (
 	"net/http"
 
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/api"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/html"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/json"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/middleware"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/openapi"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/ping"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/plain"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/stream"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 )
@@ -17,18 +22,110 @@ import (
 // Router управляет HTTP маршрутизацией и обработчиками для сервера метрик.
 // Объединяет обработчики разных типов (JSON, HTML, plain text).
 type Router struct {
-	router       chi.Router
-	storage      MetricStorage
-	logger       RouterLogger
-	decrypter    Decrypter
-	pingHandler  *ping.Handler
-	htmlHandler  *html.Handler
-	plainHandler *plain.Handler
-	jsonHandler  *json.Handler
-	serverKey    string
-}
-
-func NewRouter(storage MetricStorage, logger RouterLogger, dbClient DBPinger, serverKey string, decrypter Decrypter) *Router {
+	router        chi.Router
+	storage       MetricStorage
+	logger        RouterLogger
+	decrypter     Decrypter
+	pingHandler   *ping.Handler
+	htmlHandler   *html.Handler
+	plainHandler  *plain.Handler
+	jsonHandler   *json.Handler
+	apiHandler    *api.Handler
+	streamHandler *stream.Handler
+	docsHandler   *openapi.Handler
+	serverKey     string
+	tenants       TenantResolver
+	authenticator Authenticator
+	hashOptions   []middleware.HashOption
+	verifier      SignatureVerifier
+	verifierOpts  []middleware.SignatureOption
+	validator     RequestValidator
+	maxBodySize   int64
+	maxBatchSize  int
+	rateLimiter   RateLimiter
+	metrics       *selfmetrics.Registry
+	trustedSubnet SubnetMatcher
+}
+
+// RouterOption задает дополнительные параметры роутера.
+type RouterOption func(*Router)
+
+// WithAuth включает проверку bearer токенов.
+// Чтение метрик требует права metrics:read, запись - metrics:write.
+func WithAuth(authenticator Authenticator) RouterOption {
+	return func(r *Router) {
+		r.authenticator = authenticator
+	}
+}
+
+// WithHashOptions задает параметры проверки подписи запросов:
+// строгий режим и защиту от повторной отправки.
+func WithHashOptions(opts ...middleware.HashOption) RouterOption {
+	return func(r *Router) {
+		r.hashOptions = append(r.hashOptions, opts...)
+	}
+}
+
+// WithSignatureVerification включает проверку подписи Ed25519 запросов на запись метрик.
+func WithSignatureVerification(verifier SignatureVerifier, opts ...middleware.SignatureOption) RouterOption {
+	return func(r *Router) {
+		r.verifier = verifier
+		r.verifierOpts = opts
+	}
+}
+
+// WithRequestValidation включает проверку JSON запросов по спецификации OpenAPI.
+func WithRequestValidation(validator RequestValidator) RouterOption {
+	return func(r *Router) {
+		r.validator = validator
+	}
+}
+
+// WithTenants включает пространства имен арендаторов.
+// Все запросы к метрикам должны содержать API ключ арендатора.
+func WithTenants(tenants TenantResolver) RouterOption {
+	return func(r *Router) {
+		r.tenants = tenants
+	}
+}
+
+// WithBodyLimit ограничивает размер тела JSON запросов, как полученного,
+// так и после распаковки gzip.
+func WithBodyLimit(maxBytes int64) RouterOption {
+	return func(r *Router) {
+		r.maxBodySize = maxBytes
+	}
+}
+
+// WithMaxBatchSize ограничивает число метрик в одном запросе POST /updates/.
+func WithMaxBatchSize(maxBatchSize int) RouterOption {
+	return func(r *Router) {
+		r.maxBatchSize = maxBatchSize
+	}
+}
+
+// WithRateLimit ограничивает частоту запросов на запись от одного источника.
+func WithRateLimit(limiter RateLimiter) RouterOption {
+	return func(r *Router) {
+		r.rateLimiter = limiter
+	}
+}
+
+// WithTrustedSubnet разрешает запросы только из доверенной подсети.
+func WithTrustedSubnet(subnet SubnetMatcher) RouterOption {
+	return func(r *Router) {
+		r.trustedSubnet = subnet
+	}
+}
+
+// WithSelfMetrics включает учет запросов, их длительности и отклоненных запросов в метриках сервера.
+func WithSelfMetrics(metrics *selfmetrics.Registry) RouterOption {
+	return func(r *Router) {
+		r.metrics = metrics
+	}
+}
+
+func NewRouter(storage MetricStorage, logger RouterLogger, dbClient DBPinger, serverKey string, decrypter Decrypter, opts ...RouterOption) *Router {
 	r := &Router{
 		router:    chi.NewRouter(),
 		storage:   storage,
@@ -37,10 +134,20 @@ func NewRouter(storage MetricStorage, logger RouterLogger, dbClient DBPinger, se
 		decrypter: decrypter,
 	}
 
+	for _, opt := range opts {
+		opt(r)
+	}
+
 	r.htmlHandler = html.NewHandler(storage)
 	r.plainHandler = plain.NewHandler(storage)
-	r.jsonHandler = json.NewHandler(storage)
+	r.jsonHandler = json.NewHandler(storage,
+		json.WithMaxBatchSize(r.maxBatchSize, r.metrics.Counter("limits.batch_rejected")),
+		json.WithBatchSizeHistogram(r.metrics.Histogram("http.batch_size", json.BatchSizeBuckets)),
+	)
+	r.apiHandler = api.NewHandler(storage)
+	r.streamHandler = stream.NewHandler(storage, logger, stream.DefaultHeartbeat)
 	r.pingHandler = ping.NewHandler(dbClient, logger)
+	r.docsHandler = openapi.NewHandler()
 	r.setupMiddlewares()
 	r.setupRoutes()
 
@@ -48,7 +155,15 @@ func NewRouter(storage MetricStorage, logger RouterLogger, dbClient DBPinger, se
 }
 
 func (r *Router) setupMiddlewares() {
+	r.router.Use(middleware.TracingMiddleware)
+	r.router.Use(middleware.RequestIDMiddleware(r.logger))
 	r.router.Use(middleware.LogMiddleware(r.logger))
+	if r.trustedSubnet != nil {
+		r.router.Use(middleware.TrustedSubnetMiddleware(r.trustedSubnet, r.logger))
+	}
+	if r.metrics != nil {
+		r.router.Use(middleware.MetricsMiddleware(r.metrics))
+	}
 	r.router.Mount("/debug", chiMiddleware.Profiler())
 }
 
@@ -56,33 +171,110 @@ func (r *Router) setupRoutes() {
 	// Ping handler
 	r.router.Get("/ping", r.pingHandler.PingHandler())
 
+	// API specification and documentation
+	r.router.Get("/openapi.json", r.docsHandler.SpecHandler())
+	r.router.Get("/docs", r.docsHandler.UIHandler())
+
 	// HTML handlers
 	r.router.Group(func(router chi.Router) {
 		router.Use(middleware.ContentTypeMiddleware(html.ContentTypeValue))
+		router.Use(middleware.TenantMiddleware(r.tenants, r.logger))
+		router.Use(middleware.AuthMiddleware(r.authenticator, auth.ScopeRead, r.logger))
 		router.Use(middleware.GzipMiddleware)
 		router.Get("/", r.htmlHandler.IndexHandler())
+		router.Get("/metric/{metricType}/{metricName}", r.htmlHandler.MetricHandler())
 	})
 
+	// Static assets of HTML pages
+	r.router.Handle("/static/*", r.htmlHandler.StaticHandler())
+
 	// Plain handlers
 	r.router.Group(func(router chi.Router) {
 		router.Use(middleware.ContentTypeMiddleware(plain.ContentTypeValue))
-		router.Get("/value/{metricType}/{metricName}", r.plainHandler.ValueHandler())
-		router.Post("/update/{metricType}/{metricName}/{metricValue}", r.plainHandler.UpdateHandler())
+		router.Use(middleware.TenantMiddleware(r.tenants, r.logger))
+		router.With(middleware.AuthMiddleware(r.authenticator, auth.ScopeRead, r.logger)).
+			Get("/value/{metricType}/{metricName}", r.plainHandler.ValueHandler())
+		router.With(
+			middleware.AuthMiddleware(r.authenticator, auth.ScopeWrite, r.logger),
+			middleware.RateLimitMiddleware(r.rateLimiter, r.logger),
+			middleware.SignatureValidator(r.verifier, r.logger, r.verifierOpts...),
+		).Post("/update/{metricType}/{metricName}/{metricValue}", r.plainHandler.UpdateHandler())
 	})
 
 	// JSON handlers
 	r.router.Group(func(router chi.Router) {
 		router.Use(middleware.ContentTypeMiddleware(json.ContentTypeValue))
-		router.Use(middleware.DecryptMiddleware(r.decrypter))
-		router.Use(middleware.GzipMiddleware)
-		router.Use(middleware.HashValidator(r.serverKey, r.logger))
-		router.Use(middleware.HashAppender(r.serverKey, r.logger))
-		router.Post("/update/", r.jsonHandler.UpdateHandler())
-		router.Post("/updates/", r.jsonHandler.UpdatesHandler())
-		router.Post("/value/", r.jsonHandler.ValueHandler())
+		router.Use(middleware.TenantMiddleware(r.tenants, r.logger))
+
+		router.Group(func(router chi.Router) {
+			router.Use(middleware.AuthMiddleware(r.authenticator, auth.ScopeWrite, r.logger))
+			router.Use(middleware.RateLimitMiddleware(r.rateLimiter, r.logger))
+			router.Use(r.jsonBodyMiddlewares()...)
+			router.Use(middleware.SignatureValidator(r.verifier, r.logger, r.verifierOpts...))
+			router.Use(middleware.ValidationMiddleware(r.validator, r.logger))
+			router.Post("/update/", r.jsonHandler.UpdateHandler())
+			router.Post("/updates/", r.jsonHandler.UpdatesHandler())
+		})
+
+		router.Group(func(router chi.Router) {
+			router.Use(middleware.AuthMiddleware(r.authenticator, auth.ScopeRead, r.logger))
+			router.Use(r.jsonBodyMiddlewares()...)
+			router.Use(middleware.ValidationMiddleware(r.validator, r.logger))
+			router.Post("/value/", r.jsonHandler.ValueHandler())
+		})
+	})
+
+	// API v1 handlers
+	r.router.Route("/api/v1", func(router chi.Router) {
+		router.Use(middleware.ErrorWriterMiddleware(api.WriteProblem))
+		router.Use(middleware.TenantMiddleware(r.tenants, r.logger))
+		router.Use(middleware.AuthMiddleware(r.authenticator, auth.ScopeRead, r.logger))
+		router.Get("/stream", r.streamHandler.SSEHandler())
+		router.Get("/stream/ws", r.streamHandler.WebSocketHandler())
+
+		router.Group(func(router chi.Router) {
+			router.Use(middleware.ContentTypeMiddleware(api.ContentTypeValue))
+			router.Use(middleware.GzipMiddleware)
+			router.Get("/metrics", r.apiHandler.ListHandler())
+			router.Get("/metrics/{metricType}/{metricName}", r.apiHandler.MetricHandler())
+			router.Get("/metadata", r.apiHandler.MetadataListHandler())
+			router.Get("/metadata/{metricName}", r.apiHandler.MetadataHandler())
+		})
+
+		router.Group(func(router chi.Router) {
+			router.Use(middleware.ContentTypeMiddleware(api.ContentTypeValue))
+			router.Use(r.jsonBodyMiddlewares()...)
+			router.Use(middleware.ValidationMiddleware(r.validator, r.logger))
+			router.Post("/values", r.apiHandler.ValuesHandler())
+		})
+
+		router.Group(func(router chi.Router) {
+			router.Use(middleware.ContentTypeMiddleware(api.ContentTypeValue))
+			router.Use(middleware.AuthMiddleware(r.authenticator, auth.ScopeWrite, r.logger))
+			router.Use(middleware.RateLimitMiddleware(r.rateLimiter, r.logger))
+			router.Use(r.jsonBodyMiddlewares()...)
+			router.Use(middleware.ValidationMiddleware(r.validator, r.logger))
+			router.Put("/metadata/{metricName}", r.apiHandler.DeclareMetadataHandler())
+		})
 	})
 }
 
+// jsonBodyMiddlewares возвращает цепочку обработки тела JSON запросов:
+// ограничение размера, дешифрование, распаковку и проверку подписи.
+// Размер проверяется дважды: до дешифрования и после распаковки.
+func (r *Router) jsonBodyMiddlewares() []func(http.Handler) http.Handler {
+	bodyRejected := r.metrics.Counter("limits.body_rejected")
+
+	return []func(http.Handler) http.Handler{
+		middleware.BodyLimitMiddleware(r.maxBodySize, bodyRejected, r.logger),
+		middleware.DecryptMiddleware(r.decrypter),
+		middleware.GzipMiddleware,
+		middleware.BodyLimitMiddleware(r.maxBodySize, bodyRejected, r.logger),
+		middleware.HashValidator(r.serverKey, r.logger, r.hashOptions...),
+		middleware.HashAppender(r.serverKey, r.logger, r.hashOptions...),
+	}
+}
+
 func (r *Router) Handler() http.Handler {
 	return r.router
 }
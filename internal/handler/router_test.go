@@ -1,29 +1,55 @@
 package handler
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/NoobyTheTurtle/metrics/internal/auth"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/api"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/html"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/json"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/middleware"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/openapi"
 	"github.com/NoobyTheTurtle/metrics/internal/handler/plain"
+	"github.com/NoobyTheTurtle/metrics/internal/handler/stream"
+	"github.com/NoobyTheTurtle/metrics/internal/hash"
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
 	model "github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/NoobyTheTurtle/metrics/internal/replay"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 	"github.com/NoobyTheTurtle/metrics/internal/testutil"
 )
 
+// newMockRouterLogger возвращает мок логгера без логгера запроса,
+// поэтому записи middleware и обработчиков приходят в сам мок.
+func newMockRouterLogger(ctrl *gomock.Controller) *MockRouterLogger {
+	log := NewMockRouterLogger(ctrl)
+	log.EXPECT().With(gomock.Any()).Return(nil).AnyTimes()
+	return log
+}
+
 func TestNewRouter(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockStorage := NewMockMetricStorage(ctrl)
-	mockLogger := NewMockRouterLogger(ctrl)
+	mockLogger := newMockRouterLogger(ctrl)
 	mockDBPinger := NewMockDBPinger(ctrl)
 
 	router := NewRouter(mockStorage, mockLogger, mockDBPinger, "", nil)
@@ -43,7 +69,7 @@ func TestRouter_Handler(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := NewMockMetricStorage(ctrl)
-	mockLogger := NewMockRouterLogger(ctrl)
+	mockLogger := newMockRouterLogger(ctrl)
 	mockDBPinger := NewMockDBPinger(ctrl)
 
 	router := NewRouter(mockStorage, mockLogger, mockDBPinger, "", nil)
@@ -70,7 +96,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: "text/plain",
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -87,7 +113,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: "text/plain",
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -105,12 +131,14 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: html.ContentTypeValue,
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
 				mockStorage.EXPECT().GetAllGauges(gomock.Any()).Return(map[string]float64{}, nil)
 				mockStorage.EXPECT().GetAllCounters(gomock.Any()).Return(map[string]int64{}, nil)
+				mockStorage.EXPECT().GetAllHistograms(gomock.Any()).Return(map[string]model.Histogram{}, nil)
+				mockStorage.EXPECT().ListMetadata(gomock.Any()).Return(nil)
 
 				return mockStorage, mockLogger, mockDBPinger
 			},
@@ -123,7 +151,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: plain.ContentTypeValue,
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -140,7 +168,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: plain.ContentTypeValue,
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -158,7 +186,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: json.ContentTypeValue,
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -176,7 +204,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: json.ContentTypeValue,
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -197,7 +225,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: json.ContentTypeValue,
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -207,6 +235,112 @@ func TestRouter_Routes(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			name:        "HTML metric page",
+			method:      http.MethodGet,
+			path:        "/metric/gauge/Alloc",
+			contentType: "text/html",
+			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
+				mockStorage := NewMockMetricStorage(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
+				mockDBPinger := NewMockDBPinger(ctrl)
+
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+				mockStorage.EXPECT().GetGauge(gomock.Any(), "Alloc").Return(1.5, true)
+				mockStorage.EXPECT().History(gomock.Any(), model.GaugeType, "Alloc").Return(nil)
+				mockStorage.EXPECT().Metadata(gomock.Any(), "Alloc").Return(metadata.Metadata{}, false)
+
+				return mockStorage, mockLogger, mockDBPinger
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "Static dashboard asset",
+			method:      http.MethodGet,
+			path:        "/static/dashboard.js",
+			contentType: "text/plain",
+			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
+				mockStorage := NewMockMetricStorage(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
+				mockDBPinger := NewMockDBPinger(ctrl)
+
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+
+				return mockStorage, mockLogger, mockDBPinger
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "API v1 list metrics",
+			method:      http.MethodGet,
+			path:        "/api/v1/metrics?type=gauge&limit=10",
+			contentType: "application/json",
+			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
+				mockStorage := NewMockMetricStorage(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
+				mockDBPinger := NewMockDBPinger(ctrl)
+
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+				mockStorage.EXPECT().GetAllGauges(gomock.Any()).Return(map[string]float64{"Alloc": 1}, nil)
+
+				return mockStorage, mockLogger, mockDBPinger
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "API v1 declare metadata",
+			method:      http.MethodPut,
+			path:        "/api/v1/metadata/Alloc",
+			requestBody: `{"type":"gauge","unit":"bytes"}`,
+			contentType: "application/json",
+			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
+				mockStorage := NewMockMetricStorage(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
+				mockDBPinger := NewMockDBPinger(ctrl)
+
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+				mockStorage.EXPECT().DeclareMetadata(gomock.Any(), metadata.Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes"}).
+					Return(metadata.Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes"}, nil)
+
+				return mockStorage, mockLogger, mockDBPinger
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "API v1 get metric not found",
+			method:      http.MethodGet,
+			path:        "/api/v1/metrics/counter/Unknown",
+			contentType: "application/json",
+			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
+				mockStorage := NewMockMetricStorage(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
+				mockDBPinger := NewMockDBPinger(ctrl)
+
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+				mockStorage.EXPECT().GetCounter(gomock.Any(), "Unknown").Return(int64(0), false)
+
+				return mockStorage, mockLogger, mockDBPinger
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:        "API v1 batch values",
+			method:      http.MethodPost,
+			path:        "/api/v1/values",
+			requestBody: `{"metrics":[{"id":"Alloc","type":"gauge"}]}`,
+			contentType: "application/json",
+			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
+				mockStorage := NewMockMetricStorage(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
+				mockDBPinger := NewMockDBPinger(ctrl)
+
+				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+				mockStorage.EXPECT().GetGauge(gomock.Any(), "Alloc").Return(1.5, true)
+
+				return mockStorage, mockLogger, mockDBPinger
+			},
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			name:        "Route not found",
 			method:      http.MethodGet,
@@ -214,7 +348,7 @@ func TestRouter_Routes(t *testing.T) {
 			contentType: "text/plain",
 			setupMocks: func(ctrl *gomock.Controller) (*MockMetricStorage, *MockRouterLogger, *MockDBPinger) {
 				mockStorage := NewMockMetricStorage(ctrl)
-				mockLogger := NewMockRouterLogger(ctrl)
+				mockLogger := newMockRouterLogger(ctrl)
 				mockDBPinger := NewMockDBPinger(ctrl)
 
 				mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
@@ -248,3 +382,554 @@ func TestRouter_Routes(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_Tenants(t *testing.T) {
+	tests := []struct {
+		name               string
+		apiKey             string
+		setupMocks         func(*MockMetricStorage, *MockRouterLogger, *MockTenantResolver)
+		expectedStatusCode int
+	}{
+		{
+			name: "missing api key",
+			setupMocks: func(storage *MockMetricStorage, logger *MockRouterLogger, tenants *MockTenantResolver) {
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(2)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "unknown api key",
+			apiKey: "unknown",
+			setupMocks: func(storage *MockMetricStorage, logger *MockRouterLogger, tenants *MockTenantResolver) {
+				tenants.EXPECT().Lookup("unknown").Return("", false)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(2)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "known api key scopes storage to tenant",
+			apiKey: "key-a",
+			setupMocks: func(storage *MockMetricStorage, logger *MockRouterLogger, tenants *MockTenantResolver) {
+				tenants.EXPECT().Lookup("key-a").Return("team-a", true)
+				logger.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+				storage.EXPECT().GetGauge(gomock.Any(), "test").DoAndReturn(func(ctx context.Context, name string) (float64, bool) {
+					assert.Equal(t, "team-a", tenant.FromContext(ctx))
+					return 1.5, true
+				})
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := NewMockMetricStorage(ctrl)
+			mockLogger := newMockRouterLogger(ctrl)
+			mockTenants := NewMockTenantResolver(ctrl)
+			tt.setupMocks(mockStorage, mockLogger, mockTenants)
+
+			router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), "", nil, WithTenants(mockTenants))
+
+			req := httptest.NewRequest(http.MethodGet, "/value/gauge/test", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(tenant.APIKeyHeader, tt.apiKey)
+			}
+			rr := httptest.NewRecorder()
+
+			router.Handler().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestRouter_AuthScopes(t *testing.T) {
+	reader := &auth.Principal{Subject: "dashboard", Scopes: []string{auth.ScopeRead}}
+	writer := &auth.Principal{Subject: "agent", Scopes: []string{auth.ScopeWrite}}
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		principal          *auth.Principal
+		setupStorage       func(*MockMetricStorage)
+		expectedStatusCode int
+	}{
+		{
+			name:      "reader can read plain value",
+			method:    http.MethodGet,
+			path:      "/value/gauge/test",
+			principal: reader,
+			setupStorage: func(s *MockMetricStorage) {
+				s.EXPECT().GetGauge(gomock.Any(), "test").Return(1.5, true)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "reader cannot update plain value",
+			method:             http.MethodPost,
+			path:               "/update/gauge/test/1.5",
+			principal:          reader,
+			setupStorage:       func(s *MockMetricStorage) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:      "writer can update json batch",
+			method:    http.MethodPost,
+			path:      "/updates/",
+			body:      `[{"id":"test","type":"counter","delta":1}]`,
+			principal: writer,
+			setupStorage: func(s *MockMetricStorage) {
+				s.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "writer cannot read json value",
+			method:             http.MethodPost,
+			path:               "/value/",
+			body:               `{"id":"test","type":"counter"}`,
+			principal:          writer,
+			setupStorage:       func(s *MockMetricStorage) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "writer cannot read html index",
+			method:             http.MethodGet,
+			path:               "/",
+			principal:          writer,
+			setupStorage:       func(s *MockMetricStorage) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := NewMockMetricStorage(ctrl)
+			mockLogger := newMockRouterLogger(ctrl)
+			mockAuth := NewMockAuthenticator(ctrl)
+
+			tt.setupStorage(mockStorage)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			mockAuth.EXPECT().Authenticate("token").Return(tt.principal, nil)
+
+			router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), "", nil, WithAuth(mockAuth))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+
+			router.Handler().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestRouter_StrictHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockMetricStorage(ctrl)
+	mockLogger := newMockRouterLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStorage.EXPECT().UpdateMetricsBatch(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	const key = "secret"
+	router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), key, nil,
+		WithHashOptions(
+			middleware.WithStrictHash(),
+			middleware.WithReplayProtection(middleware.DefaultMaxClockSkew, replay.NewCache(100, time.Minute)),
+		),
+	)
+
+	body := `[{"id":"test","type":"counter","delta":1}]`
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := hash.CalculateSignedSHA256(http.MethodPost, "/updates/", timestamp, "nonce", []byte(body), key)
+	require.NoError(t, err)
+
+	send := func(signed bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/updates/", strings.NewReader(body))
+		if signed {
+			req.Header.Set(hash.Header, signature)
+			req.Header.Set(hash.TimestampHeader, timestamp)
+			req.Header.Set(hash.NonceHeader, "nonce")
+		}
+		rr := httptest.NewRecorder()
+		router.Handler().ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, send(false), "unsigned request must be rejected")
+	assert.Equal(t, http.StatusOK, send(true))
+	assert.Equal(t, http.StatusBadRequest, send(true), "replayed request must be rejected")
+}
+
+func TestRouter_RequiredSignature(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		setupStorage       func(*MockMetricStorage)
+		expectedStatusCode int
+	}{
+		{
+			name:               "unsigned json batch is rejected",
+			method:             http.MethodPost,
+			path:               "/updates/",
+			body:               `[{"id":"test","type":"counter","delta":1}]`,
+			setupStorage:       func(s *MockMetricStorage) {},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "unsigned plain update is rejected",
+			method:             http.MethodPost,
+			path:               "/update/gauge/test/1.5",
+			setupStorage:       func(s *MockMetricStorage) {},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "reads do not require signature",
+			method: http.MethodGet,
+			path:   "/value/gauge/test",
+			setupStorage: func(s *MockMetricStorage) {
+				s.EXPECT().GetGauge(gomock.Any(), "test").Return(1.5, true)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := NewMockMetricStorage(ctrl)
+			mockLogger := newMockRouterLogger(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+			tt.setupStorage(mockStorage)
+
+			router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), "", nil,
+				WithSignatureVerification(NewMockSignatureVerifier(ctrl), middleware.WithRequiredSignature()),
+			)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.Handler().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func TestRouter_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := notify.NewHub(notify.DefaultBufferSize)
+
+	mockStorage := NewMockMetricStorage(ctrl)
+	mockStorage.EXPECT().Subscribe(gomock.Any(), notify.Filter{Types: []string{"counter"}}).DoAndReturn(
+		func(ctx context.Context, filter notify.Filter) *notify.Subscription {
+			filter.Tenant = tenant.FromContext(ctx)
+			return hub.Subscribe(filter)
+		},
+	)
+
+	mockLogger := newMockRouterLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	mockTenants := NewMockTenantResolver(ctrl)
+	mockTenants.EXPECT().Lookup("key-a").Return("team-a", true)
+
+	router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), "", nil, WithTenants(mockTenants))
+	server := httptest.NewServer(router.Handler())
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/stream?type=counter", nil)
+	require.NoError(t, err)
+	// EventSource в браузере не задает заголовки, ключ приходит в cookie
+	req.AddCookie(&http.Cookie{Name: tenant.APIKeyCookie, Value: "key-a"})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, stream.ContentTypeValue, resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": connected\n", line)
+
+	total := int64(7)
+	hub.Publish(
+		notify.Event{Tenant: "", ID: "PollCount", MType: "counter", Total: &total},
+		notify.Event{Tenant: "team-a", ID: "PollCount", MType: "counter", Total: &total},
+	)
+	hub.Close()
+
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(body), "event: metric"), "only events of the caller tenant are streamed")
+	assert.Contains(t, string(body), `"tenant":"team-a"`)
+	assert.Contains(t, string(body), `"total":7`)
+}
+
+func TestRouter_OpenAPISpecCoversRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := NewRouter(NewMockMetricStorage(ctrl), newMockRouterLogger(ctrl), NewMockDBPinger(ctrl), "", nil)
+
+	doc, err := openapi.LoadSpec()
+	require.NoError(t, err)
+
+	registered := make(map[string]bool)
+	err = chi.Walk(router.router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// Профилировщик и статические файлы не являются частью API,
+		// а шаблоны с * не выражаются путями OpenAPI.
+		if strings.HasPrefix(route, "/debug/") || strings.HasSuffix(route, "/*") {
+			return nil
+		}
+
+		registered[method+" "+route] = true
+
+		pathItem := doc.Paths.Value(route)
+		if assert.NotNil(t, pathItem, "route %s %s is missing from openapi.json", method, route) {
+			assert.NotNil(t, pathItem.GetOperation(method), "operation %s %s is missing from openapi.json", method, route)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, pathItem := range doc.Paths.Map() {
+		for method := range pathItem.Operations() {
+			assert.True(t, registered[method+" "+path], "openapi.json documents %s %s which is not registered", method, path)
+		}
+	}
+}
+
+func TestRouter_RequestValidation(t *testing.T) {
+	validator, err := openapi.NewValidator()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		path               string
+		body               string
+		setupMocks         func(*MockMetricStorage)
+		expectedStatusCode int
+	}{
+		{
+			name: "valid update",
+			path: "/update/",
+			body: `{"id":"Alloc","type":"gauge","value":1.5}`,
+			setupMocks: func(storage *MockMetricStorage) {
+				storage.EXPECT().UpdateGauge(gomock.Any(), "Alloc", 1.5).Return(1.5, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "update with string delta",
+			path:               "/update/",
+			body:               `{"id":"PollCount","type":"counter","delta":"5"}`,
+			setupMocks:         func(storage *MockMetricStorage) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "batch with unknown type",
+			path:               "/updates/",
+			body:               `[{"id":"Alloc","type":"summary","value":1}]`,
+			setupMocks:         func(storage *MockMetricStorage) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "value request without id",
+			path:               "/value/",
+			body:               `{"type":"gauge"}`,
+			setupMocks:         func(storage *MockMetricStorage) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := NewMockMetricStorage(ctrl)
+			tt.setupMocks(mockStorage)
+
+			mockLogger := newMockRouterLogger(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+			router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), "", nil, WithRequestValidation(validator))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			router.Handler().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestRouter_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockMetricStorage(ctrl)
+	mockLogger := newMockRouterLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	metrics := selfmetrics.NewRegistry()
+	router := NewRouter(mockStorage, mockLogger, NewMockDBPinger(ctrl), "", nil,
+		WithBodyLimit(128),
+		WithMaxBatchSize(1),
+		WithRateLimit(limits.NewRateLimiter(1, 1, metrics)),
+		WithSelfMetrics(metrics),
+	)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.0.1:1234"
+		rr := httptest.NewRecorder()
+		router.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post("/value/", `{"id":"`+strings.Repeat("a", 128)+`","type":"gauge"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = post("/updates/", `[{"id":"a","type":"gauge","value":1},{"id":"b","type":"gauge","value":2}]`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = post("/updates/", `[]`)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "the previous batch used the only token")
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+
+	rr = post("/updates/", strings.Repeat("a", 256))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "the rate limit must apply before the body is read")
+
+	assert.Equal(t, int64(1), metrics.Counter("limits.body_rejected").Value())
+	assert.Equal(t, int64(1), metrics.Counter("limits.batch_rejected").Value())
+	assert.Equal(t, int64(2), metrics.Counter("limits.rate_limited").Value())
+}
+
+func TestRouter_APIMiddlewareProblems(t *testing.T) {
+	reader := &auth.Principal{Subject: "dashboard", Scopes: []string{auth.ScopeRead}}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		token          string
+		principal      *auth.Principal
+		rateLimited    bool
+		expectedStatus int
+		expectedType   string
+	}{
+		{
+			name:           "missing token",
+			method:         http.MethodGet,
+			path:           "/api/v1/metrics",
+			expectedStatus: http.StatusUnauthorized,
+			expectedType:   api.ProblemContentTypeValue,
+		},
+		{
+			name:           "insufficient scope",
+			method:         http.MethodPut,
+			path:           "/api/v1/metadata/Alloc",
+			body:           `{"type":"gauge"}`,
+			token:          "token",
+			principal:      reader,
+			expectedStatus: http.StatusForbidden,
+			expectedType:   api.ProblemContentTypeValue,
+		},
+		{
+			name:           "body too large",
+			method:         http.MethodPost,
+			path:           "/api/v1/values",
+			body:           `{"metrics":[{"id":"` + strings.Repeat("a", 128) + `","type":"gauge"}]}`,
+			token:          "token",
+			principal:      reader,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedType:   api.ProblemContentTypeValue,
+		},
+		{
+			name:           "rate limited",
+			method:         http.MethodPut,
+			path:           "/api/v1/metadata/Alloc",
+			body:           `{"type":"gauge"}`,
+			token:          "token",
+			principal:      &auth.Principal{Subject: "agent", Scopes: []string{auth.ScopeRead, auth.ScopeWrite}},
+			rateLimited:    true,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedType:   api.ProblemContentTypeValue,
+		},
+		{
+			name:           "legacy routes keep plain text errors",
+			method:         http.MethodGet,
+			path:           "/value/gauge/Alloc",
+			expectedStatus: http.StatusUnauthorized,
+			expectedType:   "text/plain; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLogger := newMockRouterLogger(ctrl)
+			mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+			mockAuth := NewMockAuthenticator(ctrl)
+			if tt.principal != nil {
+				mockAuth.EXPECT().Authenticate(tt.token).Return(tt.principal, nil).AnyTimes()
+			}
+
+			mockLimiter := NewMockRateLimiter(ctrl)
+			if tt.rateLimited {
+				mockLimiter.EXPECT().Allow(gomock.Any()).Return(false, time.Second)
+			}
+
+			router := NewRouter(NewMockMetricStorage(ctrl), mockLogger, NewMockDBPinger(ctrl), "", nil,
+				WithAuth(mockAuth),
+				WithBodyLimit(64),
+				WithRateLimit(mockLimiter),
+			)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", api.ContentTypeValue)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			router.Handler().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedType, rr.Header().Get("Content-Type"))
+			if tt.expectedType == api.ProblemContentTypeValue {
+				assert.Contains(t, rr.Body.String(), `"status":`+strconv.Itoa(tt.expectedStatus))
+				assert.Contains(t, rr.Body.String(), `"title":"`+http.StatusText(tt.expectedStatus)+`"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package stream
+
+import "time"
+
+const (
+	ContentTypeValue = "text/event-stream"
+
+	// DefaultHeartbeat - интервал отправки heartbeat сообщений подписчикам.
+	DefaultHeartbeat = 15 * time.Second
+
+	typeQueryParam = "type"
+	nameQueryParam = "name"
+)
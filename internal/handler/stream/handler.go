@@ -0,0 +1,75 @@
+// Package stream реализует потоковую отдачу изменений метрик через
+// Server-Sent Events и WebSocket.
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+)
+
+type Handler struct {
+	storage   HandlerStorage
+	logger    StreamLogger
+	heartbeat time.Duration
+}
+
+func NewHandler(storage HandlerStorage, logger StreamLogger, heartbeat time.Duration) *Handler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+
+	return &Handler{
+		storage:   storage,
+		logger:    logger,
+		heartbeat: heartbeat,
+	}
+}
+
+func (h *Handler) SSEHandler() http.HandlerFunc {
+	handler := newSSEHandler(h.storage, h.logger, h.heartbeat)
+	return handler.ServeHTTP
+}
+
+func (h *Handler) WebSocketHandler() http.HandlerFunc {
+	handler := newWSHandler(h.storage, h.logger, h.heartbeat)
+	return handler.ServeHTTP
+}
+
+// parseFilter строит фильтр подписки из параметров запроса type и name.
+// Каждый параметр может повторяться и содержать несколько значений через запятую.
+func parseFilter(r *http.Request) (notify.Filter, error) {
+	query := r.URL.Query()
+
+	filter := notify.Filter{
+		Types: splitValues(query[typeQueryParam]),
+		Names: splitValues(query[nameQueryParam]),
+	}
+
+	for _, t := range filter.Types {
+		if !metadata.ValidType(model.MetricType(t)) {
+			return notify.Filter{}, fmt.Errorf("unknown metric type '%s'", t)
+		}
+	}
+
+	return filter, nil
+}
+
+func splitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for part := range strings.SplitSeq(value, ",") {
+			part = strings.TrimSpace(part)
+			if part != "" && !slices.Contains(result, part) {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package stream
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expected      notify.Filter
+		expectedError bool
+	}{
+		{
+			name:     "no filters",
+			query:    "",
+			expected: notify.Filter{},
+		},
+		{
+			name:  "repeated and comma separated values",
+			query: "?type=gauge&name=Alloc,PollCount&name=Alloc&name=%20HeapSys%20",
+			expected: notify.Filter{
+				Types: []string{"gauge"},
+				Names: []string{"Alloc", "PollCount", "HeapSys"},
+			},
+		},
+		{
+			name:     "histogram type",
+			query:    "?type=histogram,counter",
+			expected: notify.Filter{Types: []string{"histogram", "counter"}},
+		},
+		{
+			name:          "unknown type",
+			query:         "?type=summary",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parseFilter(httptest.NewRequest("GET", "/api/v1/stream"+tt.query, nil))
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+// hubStorage возвращает мок хранилища, подписывающий клиентов на хаб.
+func hubStorage(ctrl *gomock.Controller, hub *notify.Hub) *MockHandlerStorage {
+	storage := NewMockHandlerStorage(ctrl)
+	storage.EXPECT().Subscribe(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filter notify.Filter) *notify.Subscription {
+			return hub.Subscribe(filter)
+		},
+	).AnyTimes()
+	return storage
+}
+
+// droppedSubscription возвращает подписку, отключенную как медленная,
+// с одним непрочитанным событием в буфере.
+func droppedSubscription() *notify.Subscription {
+	hub := notify.NewHub(1)
+	sub := hub.Subscribe(notify.Filter{})
+	value := 1.0
+	hub.Publish(
+		notify.Event{ID: "Alloc", MType: "gauge", Value: &value},
+		notify.Event{ID: "Alloc", MType: "gauge", Value: &value},
+	)
+	return sub
+}
+
+func gaugeEvent(name string, value float64) notify.Event {
+	return notify.Event{ID: name, MType: "gauge", Value: &value}
+}
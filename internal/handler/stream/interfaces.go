@@ -0,0 +1,28 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/NoobyTheTurtle/metrics/internal/storage/adapter"
+)
+
+type Subscriber interface {
+	Subscribe(ctx context.Context, filter notify.Filter) *notify.Subscription
+}
+
+type HandlerStorage interface {
+	Subscriber
+}
+
+var _ HandlerStorage = (*adapter.MetricStorage)(nil)
+var _ HandlerStorage = (*MockHandlerStorage)(nil)
+
+type StreamLogger interface {
+	Info(format string, args ...any)
+	Error(format string, args ...any)
+}
+
+var _ StreamLogger = (*logger.ZapLogger)(nil)
+var _ StreamLogger = (*MockStreamLogger)(nil)
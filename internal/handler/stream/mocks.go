@@ -0,0 +1,152 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/handler/stream/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/handler/stream/interfaces.go -destination=./internal/handler/stream/mocks.go -package=stream
+//
+
+// Package stream is a generated GoMock package.
+package stream
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notify "github.com/NoobyTheTurtle/metrics/internal/notify"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSubscriber is a mock of Subscriber interface.
+type MockSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriberMockRecorder
+	isgomock struct{}
+}
+
+// MockSubscriberMockRecorder is the mock recorder for MockSubscriber.
+type MockSubscriberMockRecorder struct {
+	mock *MockSubscriber
+}
+
+// NewMockSubscriber creates a new mock instance.
+func NewMockSubscriber(ctrl *gomock.Controller) *MockSubscriber {
+	mock := &MockSubscriber{ctrl: ctrl}
+	mock.recorder = &MockSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriber) EXPECT() *MockSubscriberMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockSubscriber) Subscribe(ctx context.Context, filter notify.Filter) *notify.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, filter)
+	ret0, _ := ret[0].(*notify.Subscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockSubscriberMockRecorder) Subscribe(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockSubscriber)(nil).Subscribe), ctx, filter)
+}
+
+// MockHandlerStorage is a mock of HandlerStorage interface.
+type MockHandlerStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockHandlerStorageMockRecorder is the mock recorder for MockHandlerStorage.
+type MockHandlerStorageMockRecorder struct {
+	mock *MockHandlerStorage
+}
+
+// NewMockHandlerStorage creates a new mock instance.
+func NewMockHandlerStorage(ctrl *gomock.Controller) *MockHandlerStorage {
+	mock := &MockHandlerStorage{ctrl: ctrl}
+	mock.recorder = &MockHandlerStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandlerStorage) EXPECT() *MockHandlerStorageMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockHandlerStorage) Subscribe(ctx context.Context, filter notify.Filter) *notify.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, filter)
+	ret0, _ := ret[0].(*notify.Subscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockHandlerStorageMockRecorder) Subscribe(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockHandlerStorage)(nil).Subscribe), ctx, filter)
+}
+
+// MockStreamLogger is a mock of StreamLogger interface.
+type MockStreamLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockStreamLoggerMockRecorder is the mock recorder for MockStreamLogger.
+type MockStreamLoggerMockRecorder struct {
+	mock *MockStreamLogger
+}
+
+// NewMockStreamLogger creates a new mock instance.
+func NewMockStreamLogger(ctrl *gomock.Controller) *MockStreamLogger {
+	mock := &MockStreamLogger{ctrl: ctrl}
+	mock.recorder = &MockStreamLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamLogger) EXPECT() *MockStreamLoggerMockRecorder {
+	return m.recorder
+}
+
+// Error mocks base method.
+func (m *MockStreamLogger) Error(format string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockStreamLoggerMockRecorder) Error(format any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockStreamLogger)(nil).Error), varargs...)
+}
+
+// Info mocks base method.
+func (m *MockStreamLogger) Info(format string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockStreamLoggerMockRecorder) Info(format any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockStreamLogger)(nil).Info), varargs...)
+}
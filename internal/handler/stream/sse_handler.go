@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+)
+
+type sseHandler struct {
+	storage   Subscriber
+	logger    StreamLogger
+	heartbeat time.Duration
+}
+
+func newSSEHandler(storage Subscriber, logger StreamLogger, heartbeat time.Duration) *sseHandler {
+	return &sseHandler{
+		storage:   storage,
+		logger:    logger,
+		heartbeat: heartbeat,
+	}
+}
+
+// ServeHTTP отправляет клиенту события "metric" с изменениями метрик.
+// Каждые heartbeat секунд отправляется комментарий, чтобы прокси не закрывали соединение.
+// Медленный подписчик получает событие "error" и отключается.
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := h.storage.Subscribe(r.Context(), filter)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", ContentTypeValue)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	var id uint64
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-sub.Events():
+			if !ok {
+				h.closeStream(r, w, sub.Err())
+				flusher.Flush()
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.FromContextOr(r.Context(), h.logger).Error("stream.sseHandler.ServeHTTP: failed to marshal event: %v", err)
+				continue
+			}
+
+			id++
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: metric\ndata: %s\n\n", id, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *sseHandler) closeStream(r *http.Request, w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, notify.ErrSlowConsumer) {
+		logger.FromContextOr(r.Context(), h.logger).Info("SSE subscriber dropped: %v", err)
+	}
+
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+}
@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// readSSE читает поток и отправляет в канал непустые строки.
+func readSSE(t *testing.T, resp *http.Response) <-chan string {
+	t.Helper()
+
+	lines := make(chan string, 64)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				lines <- line
+			}
+		}
+	}()
+	return lines
+}
+
+func nextLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+
+	select {
+	case line, ok := <-lines:
+		require.True(t, ok, "stream closed unexpectedly")
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for stream line")
+		return ""
+	}
+}
+
+func TestSSEHandler_Events(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := notify.NewHub(notify.DefaultBufferSize)
+	handler := NewHandler(hubStorage(ctrl, hub), NewMockStreamLogger(ctrl), time.Hour)
+
+	server := httptest.NewServer(handler.SSEHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?type=gauge&name=Alloc")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ContentTypeValue, resp.Header.Get("Content-Type"))
+
+	lines := readSSE(t, resp)
+	assert.Equal(t, ": connected", nextLine(t, lines))
+
+	total := int64(1)
+	hub.Publish(
+		gaugeEvent("HeapSys", 1),
+		notify.Event{ID: "Alloc", MType: "counter", Total: &total},
+		gaugeEvent("Alloc", 2.5),
+	)
+
+	assert.Equal(t, "id: 1", nextLine(t, lines))
+	assert.Equal(t, "event: metric", nextLine(t, lines))
+	data := nextLine(t, lines)
+	assert.True(t, strings.HasPrefix(data, `data: {"id":"Alloc","type":"gauge","value":2.5`), data)
+
+	hub.Close()
+	for range lines {
+	}
+}
+
+func TestSSEHandler_Heartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := notify.NewHub(notify.DefaultBufferSize)
+	defer hub.Close()
+	handler := NewHandler(hubStorage(ctrl, hub), NewMockStreamLogger(ctrl), 10*time.Millisecond)
+
+	server := httptest.NewServer(handler.SSEHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	lines := readSSE(t, resp)
+	assert.Equal(t, ": connected", nextLine(t, lines))
+	assert.Equal(t, ": ping", nextLine(t, lines))
+}
+
+func TestSSEHandler_SlowConsumer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := NewMockHandlerStorage(ctrl)
+	storage.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(droppedSubscription())
+
+	log := NewMockStreamLogger(ctrl)
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+
+	handler := NewHandler(storage, log, time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stream", nil)
+	w := httptest.NewRecorder()
+	handler.SSEHandler().ServeHTTP(w, req)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "event: metric\n")
+	assert.True(t, strings.HasSuffix(body, "event: error\ndata: {\"error\":\""+notify.ErrSlowConsumer.Error()+"\"}\n\n"), body)
+}
+
+func TestSSEHandler_BadFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockHandlerStorage(ctrl), NewMockStreamLogger(ctrl), time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stream?type=unknown", nil)
+	w := httptest.NewRecorder()
+	handler.SSEHandler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
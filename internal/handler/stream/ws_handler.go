@@ -0,0 +1,122 @@
+package stream
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteTimeout = 10 * time.Second
+	wsMaxReadSize  = 512
+)
+
+type wsHandler struct {
+	storage   Subscriber
+	logger    StreamLogger
+	heartbeat time.Duration
+	upgrader  websocket.Upgrader
+}
+
+func newWSHandler(storage Subscriber, logger StreamLogger, heartbeat time.Duration) *wsHandler {
+	return &wsHandler{
+		storage:   storage,
+		logger:    logger,
+		heartbeat: heartbeat,
+	}
+}
+
+// ServeHTTP отправляет клиенту изменения метрик JSON сообщениями WebSocket.
+// Heartbeat реализован ping фреймами: клиент, не ответивший pong за два интервала, отключается.
+// Медленный подписчик отключается с кодом закрытия 1013 (Try Again Later).
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже отправил клиенту ответ с ошибкой.
+		return
+	}
+	defer conn.Close()
+
+	sub := h.storage.Subscribe(r.Context(), filter)
+	defer sub.Close()
+
+	done := h.readLoop(conn)
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				h.closeConn(r, conn, sub.Err())
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readLoop читает входящие фреймы, чтобы обрабатывать pong и закрытие соединения клиентом.
+// Возвращаемый канал закрывается, когда соединение перестает быть доступным для чтения.
+func (h *wsHandler) readLoop(conn *websocket.Conn) <-chan struct{} {
+	done := make(chan struct{})
+
+	conn.SetReadLimit(wsMaxReadSize)
+	conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	})
+
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return done
+}
+
+func (h *wsHandler) closeConn(r *http.Request, conn *websocket.Conn, err error) {
+	code := websocket.CloseNormalClosure
+	switch {
+	case errors.Is(err, notify.ErrSlowConsumer):
+		logger.FromContextOr(r.Context(), h.logger).Info("WebSocket subscriber dropped: %v", err)
+		code = websocket.CloseTryAgainLater
+	case errors.Is(err, notify.ErrClosed):
+		code = websocket.CloseGoingAway
+	}
+
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	}
+
+	msg := websocket.FormatCloseMessage(code, reason)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+}
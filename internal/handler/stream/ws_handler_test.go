@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func dialWS(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + query
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func TestWebSocketHandler_Events(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := notify.NewHub(notify.DefaultBufferSize)
+	handler := NewHandler(hubStorage(ctrl, hub), NewMockStreamLogger(ctrl), time.Hour)
+
+	server := httptest.NewServer(handler.WebSocketHandler())
+	defer server.Close()
+
+	conn := dialWS(t, server, "?name=Alloc")
+	defer conn.Close()
+
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+
+	hub.Publish(gaugeEvent("HeapSys", 1), gaugeEvent("Alloc", 3))
+
+	var event notify.Event
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, "Alloc", event.ID)
+	require.NotNil(t, event.Value)
+	assert.Equal(t, 3.0, *event.Value)
+
+	hub.Close()
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+}
+
+func TestWebSocketHandler_Heartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := notify.NewHub(notify.DefaultBufferSize)
+	defer hub.Close()
+	handler := NewHandler(hubStorage(ctrl, hub), NewMockStreamLogger(ctrl), 10*time.Millisecond)
+
+	server := httptest.NewServer(handler.WebSocketHandler())
+	defer server.Close()
+
+	conn := dialWS(t, server, "")
+	defer conn.Close()
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(data string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no heartbeat ping received")
+	}
+}
+
+func TestWebSocketHandler_SlowConsumer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := NewMockHandlerStorage(ctrl)
+	storage.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(droppedSubscription())
+
+	log := NewMockStreamLogger(ctrl)
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).Times(1)
+
+	handler := NewHandler(storage, log, time.Hour)
+
+	server := httptest.NewServer(handler.WebSocketHandler())
+	defer server.Close()
+
+	conn := dialWS(t, server, "")
+	defer conn.Close()
+
+	var event notify.Event
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, "Alloc", event.ID)
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "unexpected error: %v", err)
+}
+
+func TestWebSocketHandler_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewHandler(NewMockHandlerStorage(ctrl), NewMockStreamLogger(ctrl), time.Hour)
+
+	w := httptest.NewRecorder()
+	handler.WebSocketHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/stream/ws?type=bad", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	storage := NewMockHandlerStorage(ctrl)
+	handler = NewHandler(storage, NewMockStreamLogger(ctrl), time.Hour)
+
+	w = httptest.NewRecorder()
+	handler.WebSocketHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/stream/ws", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "plain HTTP request must be rejected by upgrader")
+}
@@ -8,6 +8,15 @@ import (
 	"fmt"
 )
 
+// Заголовки подписи запроса.
+// Подпись в строгом режиме покрывает метод, URI, метку времени, nonce и тело запроса.
+const (
+	Header          = "HashSHA256"
+	TimestampHeader = "X-Timestamp"
+	NonceHeader     = "X-Nonce"
+	KeyIDHeader     = "X-Key-ID"
+)
+
 var (
 	ErrKeyEmpty = errors.New("key is empty")
 )
@@ -23,3 +32,27 @@ func CalculateSHA256(data []byte, key string) (string, error) {
 	}
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
+
+// SignedPayload возвращает подписываемые данные запроса: метод, URI запроса
+// (путь с параметрами), метку времени, nonce и тело. Метод и URI не дают
+// переслать подписанное тело на другой обработчик.
+// Поля разделяются переводом строки, чтобы исключить неоднозначность склейки.
+func SignedPayload(method, requestURI, timestamp, nonce string, data []byte) []byte {
+	payload := make([]byte, 0, len(method)+len(requestURI)+len(timestamp)+len(nonce)+len(data)+4)
+	payload = append(payload, method...)
+	payload = append(payload, '\n')
+	payload = append(payload, requestURI...)
+	payload = append(payload, '\n')
+	payload = append(payload, timestamp...)
+	payload = append(payload, '\n')
+	payload = append(payload, nonce...)
+	payload = append(payload, '\n')
+	payload = append(payload, data...)
+
+	return payload
+}
+
+// CalculateSignedSHA256 вычисляет HMAC-SHA256 от данных SignedPayload.
+func CalculateSignedSHA256(method, requestURI, timestamp, nonce string, data []byte, key string) (string, error) {
+	return CalculateSHA256(SignedPayload(method, requestURI, timestamp, nonce, data), key)
+}
@@ -60,3 +60,29 @@ func TestCalculateSHA256(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateSignedSHA256(t *testing.T) {
+	key := "secret_key"
+
+	got, err := CalculateSignedSHA256("POST", "/updates/", "1700000000", "abc", []byte("hello world"), key)
+	require.NoError(t, err)
+
+	want, err := CalculateSHA256([]byte("POST\n/updates/\n1700000000\nabc\nhello world"), key)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	other, err := CalculateSignedSHA256("POST", "/updates/", "1700000001", "abc", []byte("hello world"), key)
+	require.NoError(t, err)
+	assert.NotEqual(t, got, other)
+
+	otherPath, err := CalculateSignedSHA256("POST", "/update/", "1700000000", "abc", []byte("hello world"), key)
+	require.NoError(t, err)
+	assert.NotEqual(t, got, otherPath, "signature must depend on request path")
+
+	otherMethod, err := CalculateSignedSHA256("PUT", "/updates/", "1700000000", "abc", []byte("hello world"), key)
+	require.NoError(t, err)
+	assert.NotEqual(t, got, otherMethod, "signature must depend on request method")
+
+	_, err = CalculateSignedSHA256("POST", "/updates/", "1700000000", "abc", []byte("hello world"), "")
+	assert.ErrorIs(t, err, ErrKeyEmpty)
+}
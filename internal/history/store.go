@@ -0,0 +1,99 @@
+// Package history хранит последние значения метрик в кольцевых буферах фиксированного размера.
+// Используется для построения графиков на HTML странице без обращения к основному хранилищу.
+package history
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSize - количество значений, хранимых для каждой метрики по умолчанию.
+const DefaultSize = 60
+
+// DefaultMaxSeries - количество метрик, для которых хранится история, по умолчанию.
+const DefaultMaxSeries = 10000
+
+// Point описывает значение метрики в момент времени.
+type Point struct {
+	Timestamp time.Time `json:"ts"`
+	Value     float64   `json:"value"`
+}
+
+type ring struct {
+	points []Point
+	next   int
+	full   bool
+}
+
+func (r *ring) add(p Point) {
+	r.points[r.next] = p
+	r.next = (r.next + 1) % len(r.points)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring) snapshot() []Point {
+	if !r.full {
+		return append([]Point(nil), r.points[:r.next]...)
+	}
+
+	result := make([]Point, 0, len(r.points))
+	result = append(result, r.points[r.next:]...)
+	return append(result, r.points[:r.next]...)
+}
+
+// Store хранит кольцевой буфер значений для каждого ключа метрики.
+type Store struct {
+	mu        sync.RWMutex
+	size      int
+	maxSeries int
+	series    map[string]*ring
+}
+
+// NewStore создает хранилище с буфером на size значений для каждой метрики
+// и историей не более чем для maxSeries метрик.
+func NewStore(size, maxSeries int) *Store {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	if maxSeries <= 0 {
+		maxSeries = DefaultMaxSeries
+	}
+
+	return &Store{
+		size:      size,
+		maxSeries: maxSeries,
+		series:    make(map[string]*ring),
+	}
+}
+
+// Add добавляет значение метрики. При заполнении буфера вытесняется самое старое значение.
+// Когда история уже хранится для maxSeries метрик, значения новых метрик не сохраняются,
+// чтобы память не росла вместе с числом различных имен.
+func (s *Store) Add(key string, p Point) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		if len(s.series) >= s.maxSeries {
+			return
+		}
+		r = &ring{points: make([]Point, s.size)}
+		s.series[key] = r
+	}
+	r.add(p)
+}
+
+// Get возвращает копию значений метрики в порядке от старых к новым.
+func (s *Store) Get(key string) []Point {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		return nil
+	}
+	return r.snapshot()
+}
@@ -0,0 +1,92 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	start := time.Unix(0, 0)
+	point := func(i int) Point {
+		return Point{Timestamp: start.Add(time.Duration(i) * time.Second), Value: float64(i)}
+	}
+
+	tests := []struct {
+		name     string
+		size     int
+		added    int
+		expected []Point
+	}{
+		{
+			name:     "empty series",
+			size:     3,
+			added:    0,
+			expected: nil,
+		},
+		{
+			name:     "partially filled buffer",
+			size:     3,
+			added:    2,
+			expected: []Point{point(0), point(1)},
+		},
+		{
+			name:     "exactly full buffer",
+			size:     3,
+			added:    3,
+			expected: []Point{point(0), point(1), point(2)},
+		},
+		{
+			name:     "oldest values are evicted",
+			size:     3,
+			added:    5,
+			expected: []Point{point(2), point(3), point(4)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore(tt.size, 0)
+			for i := range tt.added {
+				store.Add("gauge:Alloc", point(i))
+			}
+
+			assert.Equal(t, tt.expected, store.Get("gauge:Alloc"))
+			assert.Nil(t, store.Get("gauge:Other"))
+		})
+	}
+}
+
+func TestStore_GetReturnsCopy(t *testing.T) {
+	store := NewStore(2, 0)
+	store.Add("key", Point{Value: 1})
+
+	points := store.Get("key")
+	points[0].Value = 42
+
+	assert.Equal(t, 1.0, store.Get("key")[0].Value)
+}
+
+func TestNewStore_DefaultSize(t *testing.T) {
+	store := NewStore(0, 0)
+	for i := range DefaultSize + 5 {
+		store.Add("key", Point{Value: float64(i)})
+	}
+
+	points := store.Get("key")
+	assert.Len(t, points, DefaultSize)
+	assert.Equal(t, 5.0, points[0].Value)
+}
+
+func TestStore_MaxSeries(t *testing.T) {
+	store := NewStore(2, 2)
+	store.Add("a", Point{Value: 1})
+	store.Add("b", Point{Value: 2})
+	store.Add("c", Point{Value: 3})
+
+	assert.Nil(t, store.Get("c"), "metrics over the limit must not be tracked")
+
+	store.Add("a", Point{Value: 4})
+	assert.Equal(t, []Point{{Value: 1}, {Value: 4}}, store.Get("a"), "tracked metrics keep updating")
+}
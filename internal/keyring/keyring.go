@@ -0,0 +1,253 @@
+// Package keyring хранит набор активных ключей сервера для ротации без остановки агентов.
+// Каждый HMAC ключ и приватный RSA ключ имеет идентификатор, который агент передает
+// в заголовке запроса. Первый ключ каждого вида в файле считается основным.
+package keyring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+)
+
+var (
+	ErrDuplicateID = errors.New("duplicate key id")
+	ErrEmptySecret = errors.New("empty hmac secret")
+	ErrUnknownKey  = errors.New("unknown key id")
+	ErrNoKeys      = errors.New("no crypto keys configured")
+	ErrMissingKeys = errors.New("required keys are missing")
+)
+
+// HMACKey описывает секрет для подписи запросов.
+type HMACKey struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// CryptoKey описывает путь к приватному RSA ключу для дешифрования запросов.
+type CryptoKey struct {
+	ID         string `json:"id"`
+	PrivateKey string `json:"private_key"`
+}
+
+// Config описывает файл ключей.
+type Config struct {
+	HMACKeys   []HMACKey   `json:"hmac_keys,omitempty"`
+	CryptoKeys []CryptoKey `json:"crypto_keys,omitempty"`
+}
+
+type decrypter struct {
+	id       string
+	provider *cryptoutil.PrivateKeyProvider
+}
+
+type keys struct {
+	hmac       map[string]string
+	primary    HMACKey
+	decrypters []decrypter
+}
+
+type requirements struct {
+	hash   bool
+	crypto bool
+}
+
+func (r requirements) check(k keys) error {
+	if r.hash && k.primary.Secret == "" {
+		return fmt.Errorf("%w: hmac_keys must not be empty when hash key is enabled", ErrMissingKeys)
+	}
+	if r.crypto && len(k.decrypters) == 0 {
+		return fmt.Errorf("%w: crypto_keys must not be empty when crypto key is enabled", ErrMissingKeys)
+	}
+
+	return nil
+}
+
+// Option задает требования к файлу ключей.
+type Option func(*requirements)
+
+// RequireHashKeys требует наличия HMAC ключей в файле, в том числе при перечитывании.
+func RequireHashKeys() Option {
+	return func(r *requirements) {
+		r.hash = true
+	}
+}
+
+// RequireCryptoKeys требует наличия приватных ключей в файле, в том числе при перечитывании.
+func RequireCryptoKeys() Option {
+	return func(r *requirements) {
+		r.crypto = true
+	}
+}
+
+// Keyring хранит активные ключи и позволяет перечитать их из файла.
+type Keyring struct {
+	mu       sync.RWMutex
+	path     string
+	required requirements
+	keys     keys
+}
+
+// New создает набор ключей из настроек.
+func New(cfg Config) (*Keyring, error) {
+	k, err := buildKeys(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("keyring.New: %w", err)
+	}
+
+	return &Keyring{keys: k}, nil
+}
+
+// Load загружает набор ключей из JSON файла.
+func Load(path string, opts ...Option) (*Keyring, error) {
+	var required requirements
+	for _, opt := range opts {
+		opt(&required)
+	}
+
+	k, err := readKeys(path)
+	if err == nil {
+		err = required.check(k)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keyring.Load: %w", err)
+	}
+
+	return &Keyring{path: path, required: required, keys: k}, nil
+}
+
+// Reload перечитывает файл ключей и файлы приватных ключей.
+// При ошибке текущий набор ключей не меняется.
+func (k *Keyring) Reload() error {
+	if k.path == "" {
+		return nil
+	}
+
+	keys, err := readKeys(k.path)
+	if err == nil {
+		err = k.required.check(keys)
+	}
+	if err != nil {
+		return fmt.Errorf("keyring.Keyring.Reload: %w", err)
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+
+	return nil
+}
+
+// HashKey возвращает HMAC секрет по идентификатору ключа.
+// Пустой идентификатор соответствует основному ключу.
+func (k *Keyring) HashKey(id string) (string, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if id == "" {
+		return k.keys.primary.Secret, k.keys.primary.Secret != ""
+	}
+
+	secret, ok := k.keys.hmac[id]
+	return secret, ok
+}
+
+// PrimaryHashKey возвращает идентификатор и секрет основного HMAC ключа.
+func (k *Keyring) PrimaryHashKey() (string, string) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.keys.primary.ID, k.keys.primary.Secret
+}
+
+// Decrypt дешифрует данные, перебирая приватные ключи начиная с основного.
+// Используется для агентов, которые не передают идентификатор ключа.
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	k.mu.RLock()
+	decrypters := k.keys.decrypters
+	k.mu.RUnlock()
+
+	if len(decrypters) == 0 {
+		return nil, fmt.Errorf("keyring.Keyring.Decrypt: %w", ErrNoKeys)
+	}
+
+	var errs []error
+	for _, d := range decrypters {
+		decrypted, err := d.provider.Decrypt(data)
+		if err == nil {
+			return decrypted, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return nil, fmt.Errorf("keyring.Keyring.Decrypt: %w", errors.Join(errs...))
+}
+
+// DecryptWithKey дешифрует данные приватным ключом с указанным идентификатором.
+func (k *Keyring) DecryptWithKey(id string, data []byte) ([]byte, error) {
+	k.mu.RLock()
+	decrypters := k.keys.decrypters
+	k.mu.RUnlock()
+
+	for _, d := range decrypters {
+		if d.id == id {
+			decrypted, err := d.provider.Decrypt(data)
+			if err != nil {
+				return nil, fmt.Errorf("keyring.Keyring.DecryptWithKey: %w", err)
+			}
+			return decrypted, nil
+		}
+	}
+
+	return nil, fmt.Errorf("keyring.Keyring.DecryptWithKey: %w: '%s'", ErrUnknownKey, id)
+}
+
+func readKeys(path string) (keys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keys{}, fmt.Errorf("failed to read keys file '%s': %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return keys{}, fmt.Errorf("failed to parse keys file '%s': %w", path, err)
+	}
+
+	return buildKeys(cfg)
+}
+
+func buildKeys(cfg Config) (keys, error) {
+	k := keys{hmac: make(map[string]string, len(cfg.HMACKeys))}
+
+	for i, key := range cfg.HMACKeys {
+		if key.Secret == "" {
+			return keys{}, fmt.Errorf("%w for key '%s'", ErrEmptySecret, key.ID)
+		}
+		if _, exists := k.hmac[key.ID]; exists {
+			return keys{}, fmt.Errorf("%w: hmac key '%s'", ErrDuplicateID, key.ID)
+		}
+		k.hmac[key.ID] = key.Secret
+		if i == 0 {
+			k.primary = key
+		}
+	}
+
+	seen := make(map[string]struct{}, len(cfg.CryptoKeys))
+	for _, key := range cfg.CryptoKeys {
+		if _, exists := seen[key.ID]; exists {
+			return keys{}, fmt.Errorf("%w: crypto key '%s'", ErrDuplicateID, key.ID)
+		}
+		seen[key.ID] = struct{}{}
+
+		provider, err := cryptoutil.NewPrivateKeyProvider(key.PrivateKey)
+		if err != nil {
+			return keys{}, fmt.Errorf("failed to load crypto key '%s': %w", key.ID, err)
+		}
+		k.decrypters = append(k.decrypters, decrypter{id: key.ID, provider: provider})
+	}
+
+	return k, nil
+}
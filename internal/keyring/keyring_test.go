@@ -0,0 +1,186 @@
+package keyring
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+)
+
+func TestNew_HashKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		expectedErr error
+	}{
+		{
+			name: "valid keys",
+			cfg: Config{HMACKeys: []HMACKey{
+				{ID: "2025-02", Secret: "new"},
+				{ID: "2025-01", Secret: "old"},
+			}},
+		},
+		{
+			name:        "empty secret",
+			cfg:         Config{HMACKeys: []HMACKey{{ID: "k1", Secret: ""}}},
+			expectedErr: ErrEmptySecret,
+		},
+		{
+			name: "duplicate id",
+			cfg: Config{HMACKeys: []HMACKey{
+				{ID: "k1", Secret: "a"},
+				{ID: "k1", Secret: "b"},
+			}},
+			expectedErr: ErrDuplicateID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := New(tt.cfg)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, k)
+				return
+			}
+
+			require.NoError(t, err)
+
+			id, secret := k.PrimaryHashKey()
+			assert.Equal(t, "2025-02", id)
+			assert.Equal(t, "new", secret)
+
+			secret, ok := k.HashKey("2025-01")
+			assert.True(t, ok)
+			assert.Equal(t, "old", secret)
+
+			secret, ok = k.HashKey("")
+			assert.True(t, ok, "empty id must resolve to primary key")
+			assert.Equal(t, "new", secret)
+
+			_, ok = k.HashKey("unknown")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestKeyring_Decrypt(t *testing.T) {
+	dir := t.TempDir()
+	keys := make(map[string]*cryptoutil.PublicKeyProvider)
+	var cryptoKeys []CryptoKey
+	for _, id := range []string{"new", "old"} {
+		priv := filepath.Join(dir, id+".pem")
+		pub := filepath.Join(dir, id+".pub")
+		require.NoError(t, cryptoutil.GenerateKeyPair(priv, pub, 2048))
+
+		encrypter, err := cryptoutil.NewPublicKeyProvider(pub)
+		require.NoError(t, err)
+		keys[id] = encrypter
+		cryptoKeys = append(cryptoKeys, CryptoKey{ID: id, PrivateKey: priv})
+	}
+
+	k, err := New(Config{CryptoKeys: cryptoKeys})
+	require.NoError(t, err)
+
+	for id, encrypter := range keys {
+		encrypted, err := encrypter.Encrypt([]byte("payload-" + id))
+		require.NoError(t, err)
+
+		decrypted, err := k.DecryptWithKey(id, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "payload-"+id, string(decrypted))
+
+		decrypted, err = k.Decrypt(encrypted)
+		require.NoError(t, err, "decrypt without key id must try every key")
+		assert.Equal(t, "payload-"+id, string(decrypted))
+	}
+
+	encrypted, err := keys["old"].Encrypt([]byte("payload"))
+	require.NoError(t, err)
+
+	_, err = k.DecryptWithKey("new", encrypted)
+	assert.Error(t, err)
+
+	_, err = k.DecryptWithKey("missing", encrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	empty, err := New(Config{})
+	require.NoError(t, err)
+	_, err = empty.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrNoKeys)
+}
+
+func TestLoad_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	write(`{"hmac_keys":[{"id":"k1","secret":"one"}]}`)
+
+	k, err := Load(path)
+	require.NoError(t, err)
+
+	id, secret := k.PrimaryHashKey()
+	assert.Equal(t, "k1", id)
+	assert.Equal(t, "one", secret)
+
+	write(`{"hmac_keys":[{"id":"k2","secret":"two"},{"id":"k1","secret":"one"}]}`)
+	require.NoError(t, k.Reload())
+
+	id, secret = k.PrimaryHashKey()
+	assert.Equal(t, "k2", id)
+	assert.Equal(t, "two", secret)
+	_, ok := k.HashKey("k1")
+	assert.True(t, ok)
+
+	write(fmt.Sprintf(`{"crypto_keys":[{"id":"c1","private_key":"%s"}]}`, filepath.Join(t.TempDir(), "missing.pem")))
+	assert.Error(t, k.Reload())
+
+	id, _ = k.PrimaryHashKey()
+	assert.Equal(t, "k2", id, "failed reload must keep previous keys")
+}
+
+func TestLoad_RequiredKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	write(`{"hmac_keys":[{"id":"k1","secret":"one"}]}`)
+
+	_, err := Load(path, RequireHashKeys(), RequireCryptoKeys())
+	assert.ErrorIs(t, err, ErrMissingKeys)
+	assert.ErrorContains(t, err, "crypto_keys")
+
+	k, err := Load(path, RequireHashKeys())
+	require.NoError(t, err)
+
+	write(`{"hmac_keys":[]}`)
+	err = k.Reload()
+	assert.ErrorIs(t, err, ErrMissingKeys)
+	assert.ErrorContains(t, err, "hmac_keys")
+
+	_, secret := k.PrimaryHashKey()
+	assert.Equal(t, "one", secret, "failed reload must keep previous keys")
+
+	_, err = Load(path)
+	assert.NoError(t, err)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load("/path/that/does/not/exist.json")
+	assert.ErrorContains(t, err, "failed to read keys file")
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "failed to parse keys file")
+}
@@ -0,0 +1,82 @@
+package keyring
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+)
+
+// Single хранит единственный HMAC ключ и приватный RSA ключ из конфигурации сервера
+// и позволяет заменить их без перезапуска. В отличие от Keyring, идентификатор ключа
+// в запросе не проверяется: любому идентификатору соответствует один ключ.
+type Single struct {
+	mu        sync.RWMutex
+	hashKey   string
+	decrypter *cryptoutil.PrivateKeyProvider
+}
+
+// NewSingle создает ключи из HMAC секрета и пути к приватному RSA ключу.
+// Пустые значения отключают проверку подписи и дешифрование.
+func NewSingle(hashKey, cryptoKeyPath string) (*Single, error) {
+	s := &Single{}
+	if err := s.Set(hashKey, cryptoKeyPath); err != nil {
+		return nil, fmt.Errorf("keyring.NewSingle: %w", err)
+	}
+
+	return s, nil
+}
+
+// Set заменяет ключи. При ошибке чтения приватного ключа текущие ключи не меняются.
+func (s *Single) Set(hashKey, cryptoKeyPath string) error {
+	var decrypter *cryptoutil.PrivateKeyProvider
+	if cryptoKeyPath != "" {
+		var err error
+		decrypter, err = cryptoutil.NewPrivateKeyProvider(cryptoKeyPath)
+		if err != nil {
+			return fmt.Errorf("keyring.Single.Set: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.hashKey = hashKey
+	s.decrypter = decrypter
+	s.mu.Unlock()
+
+	return nil
+}
+
+// HashKey возвращает HMAC секрет независимо от идентификатора ключа.
+func (s *Single) HashKey(string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.hashKey, s.hashKey != ""
+}
+
+// PrimaryHashKey возвращает HMAC секрет без идентификатора.
+func (s *Single) PrimaryHashKey() (string, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return "", s.hashKey
+}
+
+// Decrypt дешифрует данные приватным ключом.
+// Если ключ не задан, данные возвращаются без изменений.
+func (s *Single) Decrypt(data []byte) ([]byte, error) {
+	s.mu.RLock()
+	decrypter := s.decrypter
+	s.mu.RUnlock()
+
+	if decrypter == nil {
+		return data, nil
+	}
+
+	decrypted, err := decrypter.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("keyring.Single.Decrypt: %w", err)
+	}
+
+	return decrypted, nil
+}
@@ -0,0 +1,51 @@
+package keyring
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+)
+
+func TestSingle(t *testing.T) {
+	dir := t.TempDir()
+	priv := filepath.Join(dir, "key.pem")
+	pub := filepath.Join(dir, "key.pub")
+	require.NoError(t, cryptoutil.GenerateKeyPair(priv, pub, 2048))
+
+	s, err := NewSingle("", "")
+	require.NoError(t, err)
+
+	_, ok := s.HashKey("")
+	assert.False(t, ok, "empty key disables hash validation")
+	plain, err := s.Decrypt([]byte("payload"))
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(plain), "data is passed through without crypto key")
+
+	require.NoError(t, s.Set("secret", priv))
+
+	secret, ok := s.HashKey("any-id")
+	assert.True(t, ok)
+	assert.Equal(t, "secret", secret)
+	id, secret := s.PrimaryHashKey()
+	assert.Empty(t, id)
+	assert.Equal(t, "secret", secret)
+
+	encrypter, err := cryptoutil.NewPublicKeyProvider(pub)
+	require.NoError(t, err)
+	encrypted, err := encrypter.Encrypt([]byte("payload"))
+	require.NoError(t, err)
+	decrypted, err := s.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(decrypted))
+
+	assert.Error(t, s.Set("other", filepath.Join(dir, "missing.pem")))
+	_, secret = s.PrimaryHashKey()
+	assert.Equal(t, "secret", secret, "failed set must keep previous keys")
+
+	_, err = NewSingle("secret", filepath.Join(dir, "missing.pem"))
+	assert.ErrorContains(t, err, "keyring.NewSingle")
+}
@@ -0,0 +1,145 @@
+// Package limits реализует защитные ограничения сервера: число различных имен
+// метрик в целом, на арендатора и на агента, а также частоту запросов от источника.
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+var ErrTooManyNames = errors.New("metric name limit exceeded")
+
+// CardinalityConfig задает ограничения на число различных имен метрик.
+// Нулевое значение означает отсутствие ограничения.
+type CardinalityConfig struct {
+	MaxNames          int
+	MaxNamesPerTenant int
+	MaxNamesPerAgent  int
+}
+
+type agentKey struct {
+	tenant string
+	agent  string
+}
+
+// Cardinality учитывает различные имена метрик и отклоняет новые имена сверх ограничений.
+// Метрики сервера из пространства имен selfmetrics.Namespace не учитываются.
+type Cardinality struct {
+	mu     sync.Mutex
+	cfg    CardinalityConfig
+	total  int
+	names  map[string]map[string]struct{}
+	agents map[agentKey]map[string]struct{}
+
+	rejected *selfmetrics.Counter
+	count    *selfmetrics.Gauge
+}
+
+// NewCardinality создает ограничитель числа имен метрик.
+// Отклонения и текущее число имен учитываются в metrics, если он задан.
+func NewCardinality(cfg CardinalityConfig, metrics *selfmetrics.Registry) *Cardinality {
+	return &Cardinality{
+		cfg:      cfg,
+		names:    make(map[string]map[string]struct{}),
+		agents:   make(map[agentKey]map[string]struct{}),
+		rejected: metrics.Counter("limits.names_rejected"),
+		count:    metrics.Gauge("limits.names"),
+	}
+}
+
+// Seed учитывает имя метрики, уже сохраненное в хранилище, без проверки ограничений.
+func (c *Cardinality) Seed(tenantID, name string) {
+	if selfmetrics.IsReserved(name) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if addName(c.names, tenantID, name) {
+		c.total++
+		c.count.Set(float64(c.total))
+	}
+}
+
+// Admit проверяет, что запись метрик с именами names не превысит ограничений,
+// и учитывает новые имена. Имена принимаются либо все, либо ни одного.
+// Арендатор и агент берутся из контекста.
+func (c *Cardinality) Admit(ctx context.Context, names ...string) error {
+	tenantID := tenant.FromContext(ctx)
+	agentID, hasAgent := signing.AgentIDFromContext(ctx)
+	agent := agentKey{tenant: tenantID, agent: agentID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	known := c.names[tenantID]
+	agentNames := c.agents[agent]
+
+	newNames := make(map[string]struct{})
+	newAgentNames := make(map[string]struct{})
+	for _, name := range names {
+		if selfmetrics.IsReserved(name) {
+			continue
+		}
+		if _, ok := known[name]; !ok {
+			newNames[name] = struct{}{}
+		}
+		if _, ok := agentNames[name]; hasAgent && !ok {
+			newAgentNames[name] = struct{}{}
+		}
+	}
+
+	if len(newNames) == 0 && len(newAgentNames) == 0 {
+		return nil
+	}
+
+	switch {
+	case exceeds(c.cfg.MaxNames, c.total, len(newNames)):
+		return c.reject("%d names in total", c.cfg.MaxNames)
+	case exceeds(c.cfg.MaxNamesPerTenant, len(known), len(newNames)):
+		return c.reject("%d names per tenant", c.cfg.MaxNamesPerTenant)
+	case hasAgent && exceeds(c.cfg.MaxNamesPerAgent, len(agentNames), len(newAgentNames)):
+		return c.reject("%d names per agent", c.cfg.MaxNamesPerAgent)
+	}
+
+	for name := range newNames {
+		addName(c.names, tenantID, name)
+	}
+	for name := range newAgentNames {
+		addName(c.agents, agent, name)
+	}
+	c.total += len(newNames)
+	c.count.Set(float64(c.total))
+
+	return nil
+}
+
+func (c *Cardinality) reject(format string, args ...any) error {
+	c.rejected.Inc()
+	return fmt.Errorf("%w: limit of "+format+" reached", append([]any{ErrTooManyNames}, args...)...)
+}
+
+func exceeds(limit, current, added int) bool {
+	return limit > 0 && current+added > limit
+}
+
+// addName добавляет имя в множество по ключу и возвращает true, если имени там не было.
+func addName[K comparable](sets map[K]map[string]struct{}, key K, name string) bool {
+	set, ok := sets[key]
+	if !ok {
+		set = make(map[string]struct{})
+		sets[key] = set
+	}
+	if _, exists := set[name]; exists {
+		return false
+	}
+	set[name] = struct{}{}
+	return true
+}
@@ -0,0 +1,123 @@
+package limits
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinality_Admit(t *testing.T) {
+	ctx := context.Background()
+	agentA := signing.WithAgentID(ctx, "agent-a")
+	agentB := signing.WithAgentID(ctx, "agent-b")
+	teamA := tenant.WithID(ctx, "team-a")
+
+	tests := []struct {
+		name        string
+		cfg         CardinalityConfig
+		seed        []string
+		admit       func(c *Cardinality) error
+		expectedErr string
+	}{
+		{
+			name: "no limits",
+			admit: func(c *Cardinality) error {
+				return c.Admit(ctx, "a", "b", "c")
+			},
+		},
+		{
+			name: "total limit",
+			cfg:  CardinalityConfig{MaxNames: 2},
+			seed: []string{"a"},
+			admit: func(c *Cardinality) error {
+				require.NoError(t, c.Admit(teamA, "x"))
+				return c.Admit(ctx, "b")
+			},
+			expectedErr: "limit of 2 names in total reached",
+		},
+		{
+			name: "known names are always admitted",
+			cfg:  CardinalityConfig{MaxNames: 2},
+			seed: []string{"a", "b"},
+			admit: func(c *Cardinality) error {
+				return c.Admit(ctx, "a", "b", "a")
+			},
+		},
+		{
+			name: "per tenant limit",
+			cfg:  CardinalityConfig{MaxNamesPerTenant: 2},
+			admit: func(c *Cardinality) error {
+				require.NoError(t, c.Admit(ctx, "a", "b"))
+				require.NoError(t, c.Admit(teamA, "a", "b"))
+				return c.Admit(teamA, "c")
+			},
+			expectedErr: "limit of 2 names per tenant reached",
+		},
+		{
+			name: "per agent limit",
+			cfg:  CardinalityConfig{MaxNamesPerAgent: 2},
+			admit: func(c *Cardinality) error {
+				require.NoError(t, c.Admit(agentA, "a", "b"))
+				require.NoError(t, c.Admit(agentB, "b", "c"))
+				require.NoError(t, c.Admit(ctx, "d"), "unsigned requests are not limited per agent")
+				return c.Admit(agentA, "c")
+			},
+			expectedErr: "limit of 2 names per agent reached",
+		},
+		{
+			name: "batch is admitted as a whole",
+			cfg:  CardinalityConfig{MaxNames: 2},
+			admit: func(c *Cardinality) error {
+				assert.Error(t, c.Admit(ctx, "a", "b", "c"))
+				assert.NoError(t, c.Admit(ctx, "c"), "rejected batch must not consume the budget")
+				return c.Admit(ctx, "a", "b")
+			},
+			expectedErr: "limit of 2 names in total reached",
+		},
+		{
+			name: "server metrics are not counted",
+			cfg:  CardinalityConfig{MaxNames: 1},
+			seed: []string{"a"},
+			admit: func(c *Cardinality) error {
+				return c.Admit(ctx, selfmetrics.Namespace+"limits.names")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := selfmetrics.NewRegistry()
+			c := NewCardinality(tt.cfg, metrics)
+			for _, name := range tt.seed {
+				c.Seed("", name)
+			}
+
+			err := tt.admit(c)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(0), metrics.Counter("limits.names_rejected").Value())
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrTooManyNames)
+			assert.ErrorContains(t, err, tt.expectedErr)
+			assert.Positive(t, metrics.Counter("limits.names_rejected").Value())
+		})
+	}
+}
+
+func TestCardinality_NamesGauge(t *testing.T) {
+	metrics := selfmetrics.NewRegistry()
+	c := NewCardinality(CardinalityConfig{}, metrics)
+
+	c.Seed("", "a")
+	c.Seed("", "a")
+	require.NoError(t, c.Admit(tenant.WithID(context.Background(), "team-a"), "a", "b"))
+
+	assert.Equal(t, 3.0, metrics.Gauge("limits.names").Value())
+}
@@ -0,0 +1,90 @@
+package limits
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout - время без запросов, после которого состояние источника удаляется.
+const idleTimeout = 10 * time.Minute
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter ограничивает частоту запросов отдельно для каждого источника
+// по алгоритму token bucket.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	sources   map[string]*limiterEntry
+	lastSweep time.Time
+	now       func() time.Time
+
+	limited *selfmetrics.Counter
+}
+
+// NewRateLimiter создает ограничитель на perSecond запросов в секунду
+// с допустимым всплеском burst запросов. Если burst не задан,
+// он округляется вверх от perSecond.
+func NewRateLimiter(perSecond float64, burst int, metrics *selfmetrics.Registry) *RateLimiter {
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(perSecond)))
+	}
+
+	return &RateLimiter{
+		limit:   rate.Limit(perSecond),
+		burst:   burst,
+		sources: make(map[string]*limiterEntry),
+		now:     time.Now,
+		limited: metrics.Counter("limits.rate_limited"),
+	}
+}
+
+// Allow проверяет, можно ли выполнить запрос источника source.
+// Если нельзя, возвращает время, через которое стоит повторить запрос.
+func (l *RateLimiter) Allow(source string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	entry, ok := l.sources[source]
+	if !ok {
+		entry = &limiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.sources[source] = entry
+	}
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+
+	reservation.CancelAt(now)
+	l.limited.Inc()
+	return false, delay
+}
+
+// sweep удаляет источники без запросов дольше idleTimeout.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTimeout {
+		return
+	}
+	l.lastSweep = now
+
+	for source, entry := range l.sources {
+		if now.Sub(entry.lastSeen) > idleTimeout {
+			delete(l.sources, source)
+		}
+	}
+}
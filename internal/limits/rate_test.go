@@ -0,0 +1,66 @@
+package limits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	metrics := selfmetrics.NewRegistry()
+	l := NewRateLimiter(1, 2, metrics)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	for range 2 {
+		ok, _ := l.Allow("agent:a")
+		assert.True(t, ok)
+	}
+
+	ok, retryAfter := l.Allow("agent:a")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	ok, _ = l.Allow("agent:b")
+	assert.True(t, ok, "sources are limited independently")
+
+	now = now.Add(time.Second)
+	ok, _ = l.Allow("agent:a")
+	assert.True(t, ok, "token is refilled after a second")
+
+	assert.Equal(t, int64(1), metrics.Counter("limits.rate_limited").Value())
+}
+
+func TestNewRateLimiter_DefaultBurst(t *testing.T) {
+	tests := []struct {
+		perSecond float64
+		expected  int
+	}{
+		{perSecond: 10, expected: 10},
+		{perSecond: 1.2, expected: 2},
+		{perSecond: 0.5, expected: 1},
+		{perSecond: 0, expected: 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NewRateLimiter(tt.perSecond, 0, nil).burst, "rate %v", tt.perSecond)
+	}
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	l := NewRateLimiter(10, 0, nil)
+	assert.Equal(t, 10, l.burst)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.Allow("a")
+	now = now.Add(idleTimeout + time.Second)
+	l.Allow("b")
+
+	assert.Len(t, l.sources, 1)
+	assert.Contains(t, l.sources, "b")
+}
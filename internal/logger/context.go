@@ -0,0 +1,35 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext возвращает контекст с логгером запроса.
+func NewContext(ctx context.Context, l *ZapLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext возвращает логгер запроса из контекста.
+func FromContext(ctx context.Context) (*ZapLogger, bool) {
+	l, ok := ctx.Value(contextKey{}).(*ZapLogger)
+	return l, ok && l != nil
+}
+
+// FromContextOr возвращает логгер запроса из контекста или fallback,
+// если логгера в контексте нет.
+func FromContextOr[L any](ctx context.Context, fallback L) L {
+	if l, ok := FromContext(ctx); ok {
+		if log, ok := any(l).(L); ok {
+			return log
+		}
+	}
+	return fallback
+}
+
+// ErrorContext пишет ошибку в логгер запроса из контекста, если он есть.
+// Нужен там, где ошибка не попадает в возвращаемое значение, например при откате транзакции.
+func ErrorContext(ctx context.Context, format string, args ...any) {
+	if l, ok := FromContext(ctx); ok {
+		l.Error(format, args...)
+	}
+}
@@ -1,11 +1,15 @@
 package logger
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type ZapLogger struct {
 	logger *zap.SugaredLogger
+	level  zap.AtomicLevel
 }
 
 func NewZapLogger(level string, isDev bool) (*ZapLogger, error) {
@@ -33,9 +37,36 @@ func NewZapLogger(level string, isDev bool) (*ZapLogger, error) {
 
 	return &ZapLogger{
 		logger: sugar,
+		level:  lvl,
 	}, nil
 }
 
+// Level возвращает текущий уровень логирования.
+func (l *ZapLogger) Level() string {
+	return l.level.String()
+}
+
+// SetLevel меняет уровень логирования без перезапуска.
+// Уровень общий для логгера и всех логгеров, созданных из него через With.
+func (l *ZapLogger) SetLevel(level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("logger.ZapLogger.SetLevel: %w", err)
+	}
+
+	l.level.SetLevel(lvl)
+	return nil
+}
+
+// With возвращает логгер, который добавляет к каждой записи поля
+// из пар ключ-значение, например With("request_id", id, "tenant", tenantID).
+func (l *ZapLogger) With(keysAndValues ...any) *ZapLogger {
+	return &ZapLogger{
+		logger: l.logger.With(keysAndValues...),
+		level:  l.level,
+	}
+}
+
 func (l *ZapLogger) Debug(format string, args ...any) {
 	l.logger.Debugf(format, args...)
 }
@@ -1,10 +1,13 @@
 package logger
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewZapLogger(t *testing.T) {
@@ -129,3 +132,69 @@ func TestZapLogger_Integration(t *testing.T) {
 		logger.Info("test info message")
 	})
 }
+
+func TestZapLogger_With(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	l := &ZapLogger{logger: zap.New(core).Sugar()}
+
+	l.With("request_id", "abc").Info("Request %s", "completed")
+	l.Info("Without fields")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Request completed", entries[0].Message)
+	assert.Equal(t, map[string]any{"request_id": "abc"}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].ContextMap())
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	l := &ZapLogger{logger: zap.NewNop().Sugar()}
+	got, ok := FromContext(NewContext(context.Background(), l))
+	assert.True(t, ok)
+	assert.Same(t, l, got)
+
+	_, ok = FromContext(NewContext(context.Background(), nil))
+	assert.False(t, ok)
+}
+
+func TestFromContextOr(t *testing.T) {
+	type errorLogger interface {
+		Error(format string, args ...any)
+	}
+
+	fallback := &ZapLogger{logger: zap.NewNop().Sugar()}
+	assert.Same(t, fallback, FromContextOr[errorLogger](context.Background(), fallback))
+
+	l := &ZapLogger{logger: zap.NewNop().Sugar()}
+	assert.Same(t, l, FromContextOr[errorLogger](NewContext(context.Background(), l), fallback))
+}
+
+func TestErrorContext(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	l := &ZapLogger{logger: zap.New(core).Sugar()}
+
+	ErrorContext(context.Background(), "Lost %s", "error")
+	ErrorContext(NewContext(context.Background(), l), "Failed %s", "rollback")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Failed rollback", entries[0].Message)
+}
+
+func TestZapLogger_SetLevel(t *testing.T) {
+	l, err := NewZapLogger("info", false)
+	require.NoError(t, err)
+	child := l.With("request_id", "abc")
+
+	assert.Equal(t, "info", l.Level())
+
+	require.NoError(t, l.SetLevel("debug"))
+	assert.Equal(t, "debug", l.Level())
+	assert.Equal(t, "debug", child.Level(), "level is shared with derived loggers")
+
+	assert.Error(t, l.SetLevel("verbose"))
+	assert.Equal(t, "debug", l.Level())
+}
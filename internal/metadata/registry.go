@@ -0,0 +1,205 @@
+// Package metadata реализует реестр метаданных метрик: тип, единицу измерения,
+// описание и время первого и последнего обновления. Реестр закрепляет за именем
+// метрики ее тип, чтобы одно и то же имя не использовалось как gauge и как counter.
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
+var (
+	ErrTypeConflict = errors.New("metric type conflict")
+	ErrInvalidType  = errors.New("invalid metric type")
+	ErrEmptyName    = errors.New("empty metric name")
+)
+
+// Types - типы метрик, которые закрепляются за именами.
+var Types = []model.MetricType{model.GaugeType, model.CounterType, model.HistogramType}
+
+// ValidType сообщает, поддерживается ли тип метрики.
+func ValidType(mType model.MetricType) bool {
+	return slices.Contains(Types, mType)
+}
+
+// Metadata описывает метрику с заданным именем.
+type Metadata struct {
+	Name        string           `json:"name"`
+	Type        model.MetricType `json:"type"`
+	Unit        string           `json:"unit,omitempty"`
+	Description string           `json:"description,omitempty"`
+	FirstSeen   time.Time        `json:"first_seen,omitzero"`
+	LastSeen    time.Time        `json:"last_seen,omitzero"`
+}
+
+// Declaration описывает метаданные метрики в файле настроек.
+// Пустой Tenant означает пространство имен по умолчанию.
+type Declaration struct {
+	Tenant      string           `json:"tenant,omitempty"`
+	Name        string           `json:"name"`
+	Type        model.MetricType `json:"type"`
+	Unit        string           `json:"unit,omitempty"`
+	Description string           `json:"description,omitempty"`
+}
+
+// Metadata возвращает объявленные метаданные метрики.
+func (d Declaration) Metadata() Metadata {
+	return Metadata{
+		Name:        d.Name,
+		Type:        d.Type,
+		Unit:        d.Unit,
+		Description: d.Description,
+	}
+}
+
+type fileConfig struct {
+	Metrics []Declaration `json:"metrics"`
+}
+
+// LoadDeclarations читает объявления метаданных из JSON файла.
+func LoadDeclarations(path string) ([]Declaration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("metadata.LoadDeclarations: failed to read metadata file '%s': %w", path, err)
+	}
+
+	var cfg fileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("metadata.LoadDeclarations: failed to parse metadata file '%s': %w", path, err)
+	}
+
+	for _, d := range cfg.Metrics {
+		if err := validate(d.Name, d.Type); err != nil {
+			return nil, fmt.Errorf("metadata.LoadDeclarations: %w", err)
+		}
+	}
+
+	return cfg.Metrics, nil
+}
+
+type entryKey struct {
+	tenant string
+	name   string
+}
+
+// Registry хранит метаданные метрик отдельно для каждого арендатора.
+// Арендатор берется из контекста.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[entryKey]Metadata
+	now     func() time.Time
+}
+
+// NewRegistry создает пустой реестр метаданных.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[entryKey]Metadata),
+		now:     time.Now,
+	}
+}
+
+// Declare задает тип, единицу измерения и описание метрики.
+// Если за именем уже закреплен другой тип, возвращается ErrTypeConflict.
+// Время первого и последнего обновления при объявлении не меняется.
+func (r *Registry) Declare(ctx context.Context, m Metadata) (Metadata, error) {
+	if err := validate(m.Name, m.Type); err != nil {
+		return Metadata{}, fmt.Errorf("metadata.Registry.Declare: %w", err)
+	}
+
+	key := entryKey{tenant: tenant.FromContext(ctx), name: m.Name}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, exists := r.entries[key]
+	if exists && current.Type != m.Type {
+		return Metadata{}, fmt.Errorf("metadata.Registry.Declare: %w", conflictError(current, m.Type))
+	}
+
+	current.Name = m.Name
+	current.Type = m.Type
+	current.Unit = m.Unit
+	current.Description = m.Description
+	r.entries[key] = current
+
+	return current, nil
+}
+
+// Observe отмечает обновление метрики и закрепляет за именем ее тип.
+// Если за именем уже закреплен другой тип, возвращается ErrTypeConflict.
+func (r *Registry) Observe(ctx context.Context, name string, mType model.MetricType) error {
+	key := entryKey{tenant: tenant.FromContext(ctx), name: name}
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, exists := r.entries[key]
+	if exists && current.Type != mType {
+		return fmt.Errorf("metadata.Registry.Observe: %w", conflictError(current, mType))
+	}
+
+	if !exists {
+		current = Metadata{Name: name, Type: mType}
+	}
+	if current.FirstSeen.IsZero() {
+		current.FirstSeen = now
+	}
+	current.LastSeen = now
+	r.entries[key] = current
+
+	return nil
+}
+
+// Get возвращает метаданные метрики.
+func (r *Registry) Get(ctx context.Context, name string) (Metadata, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.entries[entryKey{tenant: tenant.FromContext(ctx), name: name}]
+	return m, ok
+}
+
+// List возвращает метаданные всех метрик арендатора, отсортированные по имени.
+func (r *Registry) List(ctx context.Context) []Metadata {
+	id := tenant.FromContext(ctx)
+
+	r.mu.RLock()
+	list := make([]Metadata, 0, len(r.entries))
+	for key, m := range r.entries {
+		if key.tenant == id {
+			list = append(list, m)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func validate(name string, mType model.MetricType) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	if !ValidType(mType) {
+		return fmt.Errorf("%w '%s' for metric '%s'", ErrInvalidType, mType, name)
+	}
+	return nil
+}
+
+func conflictError(current Metadata, requested model.MetricType) error {
+	return fmt.Errorf("%w: metric '%s' is registered as %s, got %s", ErrTypeConflict, current.Name, current.Type, requested)
+}
@@ -0,0 +1,143 @@
+package metadata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Observe(t *testing.T) {
+	r := NewRegistry()
+	ctx := context.Background()
+
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return first }
+	require.NoError(t, r.Observe(ctx, "Alloc", model.GaugeType))
+
+	last := first.Add(time.Minute)
+	r.now = func() time.Time { return last }
+	require.NoError(t, r.Observe(ctx, "Alloc", model.GaugeType))
+
+	m, ok := r.Get(ctx, "Alloc")
+	require.True(t, ok)
+	assert.Equal(t, model.GaugeType, m.Type)
+	assert.Equal(t, first, m.FirstSeen)
+	assert.Equal(t, last, m.LastSeen)
+
+	err := r.Observe(ctx, "Alloc", model.CounterType)
+	assert.ErrorIs(t, err, ErrTypeConflict)
+	assert.ErrorContains(t, err, "registered as gauge, got counter")
+
+	tenantCtx := tenant.WithID(ctx, "team-a")
+	assert.NoError(t, r.Observe(tenantCtx, "Alloc", model.CounterType), "tenants have separate namespaces")
+}
+
+func TestRegistry_Declare(t *testing.T) {
+	tests := []struct {
+		name        string
+		observed    model.MetricType
+		declare     Metadata
+		expectedErr error
+	}{
+		{
+			name:    "new metric",
+			declare: Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes", Description: "Allocated heap"},
+		},
+		{
+			name:     "observed metric with same type",
+			observed: model.GaugeType,
+			declare:  Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes"},
+		},
+		{
+			name:        "observed metric with other type",
+			observed:    model.CounterType,
+			declare:     Metadata{Name: "Alloc", Type: model.GaugeType},
+			expectedErr: ErrTypeConflict,
+		},
+		{
+			name:        "invalid type",
+			declare:     Metadata{Name: "Alloc", Type: "summary"},
+			expectedErr: ErrInvalidType,
+		},
+		{
+			name:        "empty name",
+			declare:     Metadata{Type: model.GaugeType},
+			expectedErr: ErrEmptyName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			ctx := context.Background()
+			if tt.observed != "" {
+				require.NoError(t, r.Observe(ctx, "Alloc", tt.observed))
+			}
+
+			m, err := r.Declare(ctx, tt.declare)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.declare.Unit, m.Unit)
+			assert.Equal(t, tt.declare.Description, m.Description)
+			assert.Equal(t, tt.observed != "", !m.FirstSeen.IsZero())
+
+			stored, ok := r.Get(ctx, tt.declare.Name)
+			require.True(t, ok)
+			assert.Equal(t, m, stored)
+		})
+	}
+}
+
+func TestRegistry_List(t *testing.T) {
+	r := NewRegistry()
+	ctx := context.Background()
+
+	require.NoError(t, r.Observe(ctx, "PollCount", model.CounterType))
+	require.NoError(t, r.Observe(ctx, "Alloc", model.GaugeType))
+	require.NoError(t, r.Observe(tenant.WithID(ctx, "team-a"), "Other", model.GaugeType))
+
+	list := r.List(ctx)
+	require.Len(t, list, 2)
+	assert.Equal(t, "Alloc", list[0].Name)
+	assert.Equal(t, "PollCount", list[1].Name)
+
+	assert.Len(t, r.List(tenant.WithID(ctx, "team-a")), 1)
+}
+
+func TestLoadDeclarations(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "metadata.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"metrics":[
+		{"name":"Alloc","type":"gauge","unit":"bytes","description":"Allocated heap"},
+		{"tenant":"team-a","name":"PollCount","type":"counter"}
+	]}`), 0o600))
+
+	decls, err := LoadDeclarations(path)
+	require.NoError(t, err)
+	require.Len(t, decls, 2)
+	assert.Equal(t, Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes", Description: "Allocated heap"}, decls[0].Metadata())
+	assert.Equal(t, "team-a", decls[1].Tenant)
+
+	_, err = LoadDeclarations(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "failed to read metadata file")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	_, err = LoadDeclarations(path)
+	assert.ErrorContains(t, err, "failed to parse metadata file")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"metrics":[{"name":"Alloc","type":"summary"}]}`), 0o600))
+	_, err = LoadDeclarations(path)
+	assert.ErrorIs(t, err, ErrInvalidType)
+}
@@ -0,0 +1,81 @@
+package metric
+
+import (
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
+
+type HistogramMetric string
+
+// SetHistogramBuckets задает границы корзин гистограммы. Значения, накопленные
+// с прежними границами, сбрасываются. По умолчанию используются model.DefaultBuckets.
+func (m *Metrics) SetHistogramBuckets(name HistogramMetric, bounds []float64) error {
+	h := model.NewHistogram(bounds)
+	if err := h.Validate(); err != nil {
+		return err
+	}
+
+	m.histogramsMu.Lock()
+	defer m.histogramsMu.Unlock()
+
+	if m.histograms == nil {
+		m.histograms = make(map[HistogramMetric]*model.Histogram)
+	}
+	m.histograms[name] = &h
+
+	return nil
+}
+
+// Observe добавляет значение в гистограмму. Сервер складывает полученные
+// гистограммы, поэтому после успешной отправки накопленные значения сбрасываются.
+func (m *Metrics) Observe(name HistogramMetric, value float64) {
+	m.histogramsMu.Lock()
+	defer m.histogramsMu.Unlock()
+
+	if m.histograms == nil {
+		m.histograms = make(map[HistogramMetric]*model.Histogram)
+	}
+
+	h, ok := m.histograms[name]
+	if !ok {
+		created := model.NewHistogram(model.DefaultBuckets)
+		h = &created
+		m.histograms[name] = h
+	}
+
+	h.Observe(value)
+}
+
+// takeHistograms возвращает накопленные значения гистограмм и обнуляет их.
+// Гистограммы без новых значений не возвращаются.
+func (m *Metrics) takeHistograms() map[HistogramMetric]model.Histogram {
+	m.histogramsMu.Lock()
+	defer m.histogramsMu.Unlock()
+
+	taken := make(map[HistogramMetric]model.Histogram)
+	for name, h := range m.histograms {
+		if h.Count == 0 {
+			continue
+		}
+		taken[name] = h.Clone()
+		*h = model.NewHistogram(h.Bounds)
+	}
+
+	return taken
+}
+
+// restoreHistograms возвращает неотправленные значения в гистограммы.
+func (m *Metrics) restoreHistograms(taken map[HistogramMetric]model.Histogram) {
+	m.histogramsMu.Lock()
+	defer m.histogramsMu.Unlock()
+
+	for name, h := range taken {
+		current, ok := m.histograms[name]
+		if !ok {
+			restored := h.Clone()
+			m.histograms[name] = &restored
+			continue
+		}
+		// границы могли измениться через SetHistogramBuckets, тогда старые значения отбрасываются
+		_ = current.Merge(h)
+	}
+}
@@ -0,0 +1,74 @@
+package metric
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := &Metrics{}
+
+	require.NoError(t, m.SetHistogramBuckets("SendDuration", []float64{0.1, 1}))
+	m.Observe("SendDuration", 0.05)
+	m.Observe("SendDuration", 2)
+	m.Observe("Other", 0.3)
+
+	taken := m.takeHistograms()
+	require.Len(t, taken, 2)
+	assert.Equal(t, model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{1, 0, 1}, Count: 2, Sum: 2.05}, taken["SendDuration"])
+	assert.Equal(t, model.DefaultBuckets, taken["Other"].Bounds)
+
+	assert.Empty(t, m.takeHistograms(), "histograms are reset after take")
+
+	m.Observe("SendDuration", 0.5)
+	m.restoreHistograms(taken)
+	restored := m.takeHistograms()
+	assert.Equal(t, []uint64{1, 1, 1}, restored["SendDuration"].Counts)
+	assert.Equal(t, uint64(1), restored["Other"].Count)
+
+	assert.Error(t, m.SetHistogramBuckets("SendDuration", []float64{1, 0.1}))
+}
+
+func TestMetrics_SendMetrics_Histograms(t *testing.T) {
+	status := http.StatusInternalServerError
+	var received model.Metrics
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+	mockLogger.EXPECT().Warn("Failed to send metrics batch: %v", gomock.Any()).Times(1)
+
+	m := &Metrics{serverURL: server.URL, logger: mockLogger, client: &http.Client{}}
+	m.Observe("SendDuration", 0.2)
+
+	m.SendMetrics()
+	require.Len(t, received, 1)
+	assert.Equal(t, model.HistogramType, received[0].MType)
+	assert.Equal(t, uint64(1), received[0].Histogram.Count)
+
+	status = http.StatusOK
+	m.SendMetrics()
+	require.Len(t, received, 1)
+	assert.Equal(t, uint64(1), received[0].Histogram.Count, "failed batch is sent again")
+
+	assert.Empty(t, m.takeHistograms(), "sent values are not kept")
+}
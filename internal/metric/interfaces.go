@@ -3,11 +3,13 @@ package metric
 import (
 	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
 	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
 )
 
 type MetricsLogger interface {
 	Warn(format string, args ...any)
 	Error(format string, args ...any)
+	With(keysAndValues ...any) *logger.ZapLogger
 }
 
 var (
@@ -20,3 +22,13 @@ type Encrypter interface {
 }
 
 var _ Encrypter = (*cryptoutil.PublicKeyProvider)(nil)
+
+// Signer подписывает запросы приватным ключом агента
+type Signer interface {
+	Sign(data []byte) (string, error)
+}
+
+var (
+	_ Signer = (*signing.Signer)(nil)
+	_ Signer = (*MockSigner)(nil)
+)
@@ -1,10 +1,12 @@
 package metric
 
 import (
+	"crypto/tls"
 	"fmt"
-	"math/rand"
 	"net/http"
-	"time"
+	"sync"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 )
 
 type GaugeMetric string
@@ -12,17 +14,91 @@ type GaugeMetric string
 type CounterMetric string
 
 type Metrics struct {
-	Gauges    map[GaugeMetric]float64
+	// valuesMu защищает Gauges и Counters от параллельной записи сборщиками
+	valuesMu sync.RWMutex
+	Gauges   map[GaugeMetric]float64
+	// Counters хранит приращения, еще не доставленные на сервер
 	Counters  map[CounterMetric]int64
 	serverURL string
 	logger    MetricsLogger
-	random    *rand.Rand
 	client    *http.Client
-	key       string
-	encrypter Encrypter
+	apiKey    string
+	authToken string
+	agentID   string
+	signer    Signer
+
+	signedHash bool
+
+	// keysMu защищает ключи, которые можно заменить во время работы агента
+	keysMu      sync.RWMutex
+	key         string
+	keyID       string
+	encrypter   Encrypter
+	cryptoKeyID string
+
+	histogramsMu sync.Mutex
+	histograms   map[HistogramMetric]*model.Histogram
+}
+
+// Option задает дополнительные параметры отправки метрик.
+type Option func(*Metrics)
+
+// WithAPIKey задает API ключ арендатора, который передается в заголовке X-API-Key.
+func WithAPIKey(apiKey string) Option {
+	return func(m *Metrics) {
+		m.apiKey = apiKey
+	}
+}
+
+// WithAuthToken задает bearer токен, который передается в заголовке Authorization.
+func WithAuthToken(token string) Option {
+	return func(m *Metrics) {
+		m.authToken = token
+	}
+}
+
+// WithKeyID задает идентификатор HMAC ключа, который передается в заголовке X-Key-ID.
+func WithKeyID(id string) Option {
+	return func(m *Metrics) {
+		m.keyID = id
+	}
+}
+
+// WithCryptoKeyID задает идентификатор ключа шифрования, который передается в заголовке X-Crypto-Key-ID.
+func WithCryptoKeyID(id string) Option {
+	return func(m *Metrics) {
+		m.cryptoKeyID = id
+	}
+}
+
+// WithSignedHash включает подпись HMAC с меткой времени и nonce, которую проверяют серверы
+// с защитой от повторов. Серверы без нее проверяют HMAC только от тела и отклоняют такие запросы,
+// поэтому сначала обновляются серверы, затем подпись включается на агентах.
+// При подписи запросов ключом Ed25519 этот режим включается всегда.
+func WithSignedHash() Option {
+	return func(m *Metrics) {
+		m.signedHash = true
+	}
+}
+
+// WithSigner включает подпись запросов ключом Ed25519 агента agentID.
+func WithSigner(agentID string, signer Signer) Option {
+	return func(m *Metrics) {
+		m.agentID = agentID
+		m.signer = signer
+	}
 }
 
-func NewMetrics(serverAddress string, log MetricsLogger, useTLS bool, key string, encrypter Encrypter) *Metrics {
+// WithTLSConfig задает настройки TLS клиента, например CA сервера и сертификат агента для mTLS.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(m *Metrics) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg
+		m.client.Transport = transport
+	}
+}
+
+func NewMetrics(serverAddress string, log MetricsLogger, useTLS bool, key string, encrypter Encrypter, opts ...Option) *Metrics {
 	protocol := "http"
 
 	if useTLS {
@@ -31,17 +107,38 @@ func NewMetrics(serverAddress string, log MetricsLogger, useTLS bool, key string
 
 	serverURL := fmt.Sprintf("%s://%s", protocol, serverAddress)
 
-	source := rand.NewSource(time.Now().UnixNano())
-	random := rand.New(source)
-
-	return &Metrics{
+	m := &Metrics{
 		Gauges:    make(map[GaugeMetric]float64),
 		Counters:  make(map[CounterMetric]int64),
 		serverURL: serverURL,
 		logger:    log,
-		random:    random,
 		client:    &http.Client{},
 		key:       key,
 		encrypter: encrypter,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// SetHashKey заменяет HMAC ключ и его идентификатор для следующих отправок.
+func (m *Metrics) SetHashKey(key, keyID string) {
+	m.keysMu.Lock()
+	defer m.keysMu.Unlock()
+
+	m.key = key
+	m.keyID = keyID
+}
+
+// SetEncrypter заменяет шифратор и идентификатор ключа шифрования для следующих отправок.
+// Нулевой шифратор отключает шифрование.
+func (m *Metrics) SetEncrypter(encrypter Encrypter, cryptoKeyID string) {
+	m.keysMu.Lock()
+	defer m.keysMu.Unlock()
+
+	m.encrypter = encrypter
+	m.cryptoKeyID = cryptoKeyID
 }
@@ -12,6 +12,7 @@ package metric
 import (
 	reflect "reflect"
 
+	logger "github.com/NoobyTheTurtle/metrics/internal/logger"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -73,6 +74,24 @@ func (mr *MockMetricsLoggerMockRecorder) Warn(format any, args ...any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockMetricsLogger)(nil).Warn), varargs...)
 }
 
+// With mocks base method.
+func (m *MockMetricsLogger) With(keysAndValues ...any) *logger.ZapLogger {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range keysAndValues {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "With", varargs...)
+	ret0, _ := ret[0].(*logger.ZapLogger)
+	return ret0
+}
+
+// With indicates an expected call of With.
+func (mr *MockMetricsLoggerMockRecorder) With(keysAndValues ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "With", reflect.TypeOf((*MockMetricsLogger)(nil).With), keysAndValues...)
+}
+
 // MockEncrypter is a mock of Encrypter interface.
 type MockEncrypter struct {
 	ctrl     *gomock.Controller
@@ -111,3 +130,42 @@ func (mr *MockEncrypterMockRecorder) Encrypt(data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockEncrypter)(nil).Encrypt), data)
 }
+
+// MockSigner is a mock of Signer interface.
+type MockSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignerMockRecorder
+	isgomock struct{}
+}
+
+// MockSignerMockRecorder is the mock recorder for MockSigner.
+type MockSignerMockRecorder struct {
+	mock *MockSigner
+}
+
+// NewMockSigner creates a new mock instance.
+func NewMockSigner(ctrl *gomock.Controller) *MockSigner {
+	mock := &MockSigner{ctrl: ctrl}
+	mock.recorder = &MockSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigner) EXPECT() *MockSignerMockRecorder {
+	return m.recorder
+}
+
+// Sign mocks base method.
+func (m *MockSigner) Sign(data []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockSignerMockRecorder) Sign(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockSigner)(nil).Sign), data)
+}
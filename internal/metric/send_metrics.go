@@ -3,39 +3,74 @@ package metric
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
 	"github.com/NoobyTheTurtle/metrics/internal/hash"
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
 	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/requestid"
 	"github.com/NoobyTheTurtle/metrics/internal/retry"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = tracing.Tracer("metric")
+
 const (
-	Gauge   = "gauge"
-	Counter = "counter"
+	Gauge     = "gauge"
+	Counter   = "counter"
+	Histogram = "histogram"
 )
 
+// SendMetrics отправляет накопленные метрики одним пакетом.
+// Счетчики и гистограммы уходят как приращения с прошлой успешной отправки:
+// сервер прибавляет их к своим значениям. При ошибке после всех повторов
+// приращения возвращаются и уходят со следующим пакетом. Доставка - at-least-once:
+// если сервер применил пакет, но ответ потерялся, приращение будет учтено дважды.
 func (m *Metrics) SendMetrics() {
-	metrics := m.prepareMetricsBatch()
+	counters := m.takeCounters()
+	histograms := m.takeHistograms()
+	metrics := m.prepareMetricsBatch(counters, histograms)
 	if len(metrics) == 0 {
 		return
 	}
 
+	// Все попытки отправки пакета - дочерние spans одной трассы
+	// и отправляются с одним идентификатором запроса.
+	ctx, span := tracer.Start(context.Background(), "metric.Metrics.SendMetrics")
+	span.SetAttributes(attribute.Int("metrics.batch_size", len(metrics)))
+	ctx = m.withRequestID(ctx, requestid.New())
+
 	op := func() error {
-		return m.SendMetricsBatch(metrics)
+		return m.SendMetricsBatch(ctx, metrics)
 	}
 
-	err := retry.WithRetries(op, retry.RequestErrorChecker)
+	err := retry.WithRetriesContext(ctx, op, retry.RequestErrorChecker)
+	tracing.End(span, err)
 	if err != nil {
-		m.logger.Warn("Failed to send metrics batch: %v", err)
+		m.restoreCounters(counters)
+		m.restoreHistograms(histograms)
+		logger.FromContextOr(ctx, m.logger).Warn("Failed to send metrics batch: %v", err)
 	}
 }
 
-func (m *Metrics) prepareMetricsBatch() model.Metrics {
-	metrics := make(model.Metrics, 0, len(m.Gauges)+len(m.Counters))
+func (m *Metrics) prepareMetricsBatch(counters map[CounterMetric]int64, histograms map[HistogramMetric]model.Histogram) model.Metrics {
+	m.valuesMu.RLock()
+	defer m.valuesMu.RUnlock()
+
+	metrics := make(model.Metrics, 0, len(m.Gauges)+len(counters)+len(histograms))
 
 	for name, value := range m.Gauges {
 		valueCopy := value
@@ -46,7 +81,7 @@ func (m *Metrics) prepareMetricsBatch() model.Metrics {
 		})
 	}
 
-	for name, value := range m.Counters {
+	for name, value := range counters {
 		valueCopy := value
 		metrics = append(metrics, model.Metric{
 			ID:    string(name),
@@ -55,10 +90,22 @@ func (m *Metrics) prepareMetricsBatch() model.Metrics {
 		})
 	}
 
+	for name, value := range histograms {
+		valueCopy := value
+		metrics = append(metrics, model.Metric{
+			ID:        string(name),
+			MType:     Histogram,
+			Histogram: &valueCopy,
+		})
+	}
+
 	return metrics
 }
 
-func compressJSON(data []byte) ([]byte, error) {
+func compressJSON(ctx context.Context, data []byte) ([]byte, error) {
+	_, span := tracer.Start(ctx, "metric.compressJSON")
+	defer span.End()
+
 	var buf bytes.Buffer
 	gzWriter := gzip.NewWriter(&buf)
 
@@ -96,40 +143,89 @@ func readResponseBody(resp *http.Response) (string, error) {
 	return string(body), nil
 }
 
-func (m *Metrics) SendMetricsBatch(metrics model.Metrics) error {
+// withRequestID добавляет в контекст идентификатор запроса и логгер с этим идентификатором.
+func (m *Metrics) withRequestID(ctx context.Context, id string) context.Context {
+	ctx = requestid.WithID(ctx, id)
+	return logger.NewContext(ctx, m.logger.With("request_id", id))
+}
+
+// SendMetricsBatch отправляет пакет метрик одним запросом POST /updates/.
+// Контекст трассировки из ctx передается серверу в заголовке traceparent,
+// идентификатор запроса из ctx - в заголовке X-Request-ID.
+func (m *Metrics) SendMetricsBatch(ctx context.Context, metrics model.Metrics) (err error) {
+	ctx, span := tracer.Start(ctx, "metric.Metrics.SendMetricsBatch")
+	defer func() { tracing.End(span, err) }()
+
 	jsonData, err := metrics.MarshalJSON()
 	if err != nil {
 		return fmt.Errorf("metric.Metrics.SendMetricsBatch: error marshaling metrics batch: %w", err)
 	}
 
-	var hashHeaderValue string
-	if m.key != "" {
-		hash, hashErr := hash.CalculateSHA256(jsonData, m.key)
+	endpoint, err := url.Parse(m.serverURL + "/updates/")
+	if err != nil {
+		return fmt.Errorf("metric.Metrics.SendMetricsBatch: error parsing server url: %w", err)
+	}
+
+	m.keysMu.RLock()
+	key, keyID, encrypter, cryptoKeyID := m.key, m.keyID, m.encrypter, m.cryptoKeyID
+	m.keysMu.RUnlock()
+
+	// подпись Ed25519 передает метку времени и nonce, поэтому HMAC тоже покрывает их
+	signedHash := m.signedHash || m.signer != nil
+
+	var hashHeaderValue, signature, timestamp, nonce string
+	if key != "" && signedHash || m.signer != nil {
+		timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		nonce, err = newNonce()
+		if err != nil {
+			return fmt.Errorf("metric.Metrics.SendMetricsBatch: error generating nonce: %w", err)
+		}
+	}
+
+	if m.signer != nil {
+		signature, err = m.signer.Sign(hash.SignedPayload(http.MethodPost, endpoint.RequestURI(), timestamp, nonce, jsonData))
+		if err != nil {
+			return fmt.Errorf("metric.Metrics.SendMetricsBatch: error signing request: %w", err)
+		}
+	}
+
+	if key != "" {
+		var sum string
+		var hashErr error
+		if signedHash {
+			sum, hashErr = hash.CalculateSignedSHA256(http.MethodPost, endpoint.RequestURI(), timestamp, nonce, jsonData, key)
+		} else {
+			sum, hashErr = hash.CalculateSHA256(jsonData, key)
+		}
 		if hashErr != nil {
-			m.logger.Warn("Failed to calculate SHA256 hash for request: %v", hashErr)
+			logger.FromContextOr(ctx, m.logger).Warn("Failed to calculate SHA256 hash for request: %v", hashErr)
 		} else {
-			hashHeaderValue = hash
+			hashHeaderValue = sum
 		}
 	}
 
-	compressedData, err := compressJSON(jsonData)
+	compressedData, err := compressJSON(ctx, jsonData)
 	if err != nil {
 		return fmt.Errorf("metric.Metrics.SendMetricsBatch: error compressing data: %w", err)
 	}
 
 	var encryptedData []byte
-	if m.encrypter != nil {
-		encryptedData, err = m.encrypter.Encrypt(compressedData)
+	var encrypted bool
+	if encrypter != nil {
+		_, encryptSpan := tracer.Start(ctx, "metric.Metrics.encrypt")
+		encryptedData, err = encrypter.Encrypt(compressedData)
+		tracing.End(encryptSpan, err)
 		if err != nil {
-			m.logger.Warn("Failed to encrypt data: %v", err)
+			logger.FromContextOr(ctx, m.logger).Warn("Failed to encrypt data: %v", err)
 			encryptedData = compressedData
+		} else {
+			encrypted = true
 		}
 	} else {
 		encryptedData = compressedData
 	}
 
-	url := fmt.Sprintf("%s/updates/", m.serverURL)
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(encryptedData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewBuffer(encryptedData))
 	if err != nil {
 		return fmt.Errorf("metric.Metrics.SendMetricsBatch: error creating request: %w", err)
 	}
@@ -139,10 +235,46 @@ func (m *Metrics) SendMetricsBatch(metrics model.Metrics) error {
 	req.Header.Set("Accept-Encoding", "gzip")
 
 	if hashHeaderValue != "" {
-		req.Header.Set("HashSHA256", hashHeaderValue)
+		req.Header.Set(hash.Header, hashHeaderValue)
+		if signedHash {
+			req.Header.Set(hash.TimestampHeader, timestamp)
+			req.Header.Set(hash.NonceHeader, nonce)
+		}
+		if keyID != "" {
+			req.Header.Set(hash.KeyIDHeader, keyID)
+		}
+	}
+
+	if signature != "" {
+		req.Header.Set(signing.SignatureHeader, signature)
+		req.Header.Set(signing.AgentIDHeader, m.agentID)
+		req.Header.Set(hash.TimestampHeader, timestamp)
+		req.Header.Set(hash.NonceHeader, nonce)
+	}
+
+	if encrypted && cryptoKeyID != "" {
+		req.Header.Set(cryptoutil.KeyIDHeader, cryptoKeyID)
+	}
+
+	if m.apiKey != "" {
+		req.Header.Set(tenant.APIKeyHeader, m.apiKey)
 	}
 
-	resp, err := m.client.Do(req)
+	if m.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+m.authToken)
+	}
+
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	sendCtx, sendSpan := tracer.Start(ctx, "metric.Metrics.send")
+	tracing.Inject(sendCtx, req.Header)
+	resp, err := m.client.Do(req.WithContext(sendCtx))
+	if resp != nil {
+		sendSpan.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	tracing.End(sendSpan, err)
 	if err != nil {
 		return fmt.Errorf("metric.Metrics.SendMetricsBatch: error sending request: %w", err)
 	}
@@ -158,3 +290,12 @@ func (m *Metrics) SendMetricsBatch(metrics model.Metrics) error {
 
 	return nil
 }
+
+// newNonce возвращает случайный nonce для подписи запроса.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -3,21 +3,43 @@ package metric
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
 	"github.com/NoobyTheTurtle/metrics/internal/hash"
 	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/requestid"
+	"github.com/NoobyTheTurtle/metrics/internal/signing"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 )
 
+// newMockMetricsLogger возвращает мок логгера без логгера запроса,
+// поэтому записи об ошибках отправки приходят в сам мок.
+func newMockMetricsLogger(ctrl *gomock.Controller) *MockMetricsLogger {
+	log := NewMockMetricsLogger(ctrl)
+	log.EXPECT().With(gomock.Any()).Return(nil).AnyTimes()
+	return log
+}
+
 func TestMetrics_SendMetrics(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -146,7 +168,7 @@ func TestMetrics_SendMetrics(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			mockLogger := NewMockMetricsLogger(ctrl)
+			mockLogger := newMockMetricsLogger(ctrl)
 
 			if tt.statusCode != http.StatusOK {
 				mockLogger.EXPECT().Warn("Failed to send metrics batch: %v", gomock.Any()).Times(1)
@@ -249,7 +271,7 @@ func TestSendMetricsBatch(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			mockLogger := NewMockMetricsLogger(ctrl)
+			mockLogger := newMockMetricsLogger(ctrl)
 
 			metrics := &Metrics{
 				Gauges:    make(map[GaugeMetric]float64),
@@ -259,7 +281,7 @@ func TestSendMetricsBatch(t *testing.T) {
 				client:    &http.Client{},
 			}
 
-			err := metrics.SendMetricsBatch(model.Metrics{tt.metric})
+			err := metrics.SendMetricsBatch(context.Background(), model.Metrics{tt.metric})
 
 			if tt.statusCode == http.StatusOK {
 				assert.NoError(t, err)
@@ -287,7 +309,7 @@ func TestCompressJSON(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			compressed, err := compressJSON(tt.input)
+			compressed, err := compressJSON(context.Background(), tt.input)
 
 			assert.NoError(t, err)
 			assert.NotNil(t, compressed)
@@ -329,7 +351,7 @@ func TestSendMetricsBatch_Success(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := &Metrics{
 		serverURL: server.URL,
@@ -345,7 +367,7 @@ func TestSendMetricsBatch_Success(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.NoError(t, err)
 }
@@ -370,7 +392,7 @@ func TestSendMetricsBatch_EmptyBatch(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := &Metrics{
 		serverURL: server.URL,
@@ -378,7 +400,7 @@ func TestSendMetricsBatch_EmptyBatch(t *testing.T) {
 		client:    &http.Client{},
 	}
 
-	err := metrics.SendMetricsBatch(model.Metrics{})
+	err := metrics.SendMetricsBatch(context.Background(), model.Metrics{})
 
 	assert.NoError(t, err)
 }
@@ -386,7 +408,7 @@ func TestSendMetricsBatch_EmptyBatch(t *testing.T) {
 func TestSendMetricsBatch_NetworkError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := &Metrics{
 		serverURL: "http://invalid-url-that-does-not-exist.local",
@@ -402,7 +424,7 @@ func TestSendMetricsBatch_NetworkError(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error sending request")
@@ -461,7 +483,7 @@ func TestSendMetricsBatch_InvalidResponse(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			mockLogger := NewMockMetricsLogger(ctrl)
+			mockLogger := newMockMetricsLogger(ctrl)
 
 			metrics := &Metrics{
 				serverURL: server.URL,
@@ -477,7 +499,7 @@ func TestSendMetricsBatch_InvalidResponse(t *testing.T) {
 				},
 			}
 
-			err := metrics.SendMetricsBatch(testMetrics)
+			err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), fmt.Sprintf("server returned status code %d", tt.statusCode))
@@ -516,7 +538,7 @@ func TestSendMetricsBatch_WithGzip(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := &Metrics{
 		serverURL: server.URL,
@@ -537,17 +559,201 @@ func TestSendMetricsBatch_WithGzip(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.NoError(t, err)
 }
 
 func TestSendMetricsBatch_WithHash(t *testing.T) {
 	const testKey = "test-secret-key"
+
+	tests := []struct {
+		name       string
+		signedHash bool
+	}{
+		{name: "body only hash by default", signedHash: false},
+		{name: "hash with timestamp and nonce", signedHash: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hashHeader := r.Header.Get("HashSHA256")
+				assert.NotEmpty(t, hashHeader)
+
+				reader, err := gzip.NewReader(r.Body)
+				require.NoError(t, err)
+				defer reader.Close()
+
+				body, err := io.ReadAll(reader)
+				require.NoError(t, err)
+
+				timestamp := r.Header.Get(hash.TimestampHeader)
+				nonce := r.Header.Get(hash.NonceHeader)
+
+				var expectedHash string
+				if tt.signedHash {
+					assert.NotEmpty(t, timestamp)
+					assert.NotEmpty(t, nonce)
+					expectedHash, err = hash.CalculateSignedSHA256(r.Method, r.URL.RequestURI(), timestamp, nonce, body, testKey)
+				} else {
+					assert.Empty(t, timestamp, "servers without replay protection reject timestamped hashes")
+					assert.Empty(t, nonce)
+					expectedHash, err = hash.CalculateSHA256(body, testKey)
+				}
+				require.NoError(t, err)
+				assert.Equal(t, expectedHash, hashHeader)
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockLogger := newMockMetricsLogger(ctrl)
+
+			metrics := &Metrics{
+				serverURL:  server.URL,
+				logger:     mockLogger,
+				client:     &http.Client{},
+				key:        testKey,
+				signedHash: tt.signedHash,
+			}
+
+			testMetrics := model.Metrics{
+				{
+					ID:    "test",
+					MType: Gauge,
+					Value: func() *float64 { v := 1.5; return &v }(),
+				},
+			}
+
+			err := metrics.SendMetricsBatch(context.Background(), testMetrics)
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSendMetricsBatch_WithAPIKey(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		hashHeader := r.Header.Get("HashSHA256")
-		assert.NotEmpty(t, hashHeader)
+		assert.Equal(t, "tenant-key", r.Header.Get(tenant.APIKeyHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+
+	metrics := NewMetrics("localhost", mockLogger, false, "", nil, WithAPIKey("tenant-key"))
+	metrics.serverURL = server.URL
+
+	testMetrics := model.Metrics{
+		{
+			ID:    "test",
+			MType: Gauge,
+			Value: func() *float64 { v := 1.5; return &v }(),
+		},
+	}
+
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
+
+	assert.NoError(t, err)
+}
+
+func TestSendMetricsBatch_WithAuthToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer agent-token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+
+	metrics := NewMetrics("localhost", mockLogger, false, "", nil, WithAuthToken("agent-token"))
+	metrics.serverURL = server.URL
+
+	testMetrics := model.Metrics{
+		{
+			ID:    "test",
+			MType: Gauge,
+			Value: func() *float64 { v := 1.5; return &v }(),
+		},
+	}
+
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
+
+	assert.NoError(t, err)
+}
+
+func TestSendMetricsBatch_WithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotNil(t, r.TLS)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	address := strings.TrimPrefix(server.URL, "https://")
+	metrics := NewMetrics(address, mockLogger, true, "", nil, WithTLSConfig(&tls.Config{RootCAs: roots}))
+
+	testMetrics := model.Metrics{
+		{
+			ID:    "test",
+			MType: Gauge,
+			Value: func() *float64 { v := 1.5; return &v }(),
+		},
+	}
+
+	assert.NoError(t, metrics.SendMetricsBatch(context.Background(), testMetrics))
+
+	untrusted := NewMetrics(address, mockLogger, true, "", nil)
+	assert.Error(t, untrusted.SendMetricsBatch(context.Background(), testMetrics), "server certificate must be verified")
+}
+
+func TestSendMetricsBatch_WithKeyIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "hmac-2", r.Header.Get(hash.KeyIDHeader))
+		assert.Empty(t, r.Header.Get(cryptoutil.KeyIDHeader), "crypto key id must not be sent without encryption")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+
+	metrics := NewMetrics("localhost", mockLogger, false, "secret", nil, WithKeyID("hmac-2"), WithCryptoKeyID("rsa-2"))
+	metrics.serverURL = server.URL
+
+	testMetrics := model.Metrics{
+		{
+			ID:    "test",
+			MType: Gauge,
+			Value: func() *float64 { v := 1.5; return &v }(),
+		},
+	}
 
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
+
+	assert.NoError(t, err)
+}
+
+func TestSendMetricsBatch_WithSigner(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	registry := signing.NewRegistry(map[string]ed25519.PublicKey{"agent-1": publicKey})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		reader, err := gzip.NewReader(r.Body)
 		require.NoError(t, err)
 		defer reader.Close()
@@ -555,9 +761,10 @@ func TestSendMetricsBatch_WithHash(t *testing.T) {
 		body, err := io.ReadAll(reader)
 		require.NoError(t, err)
 
-		expectedHash, err := hash.CalculateSHA256(body, testKey)
-		require.NoError(t, err)
-		assert.Equal(t, expectedHash, hashHeader)
+		assert.Equal(t, "agent-1", r.Header.Get(signing.AgentIDHeader))
+		payload := hash.SignedPayload(r.Method, r.URL.RequestURI(), r.Header.Get(hash.TimestampHeader), r.Header.Get(hash.NonceHeader), body)
+		assert.NoError(t, registry.Verify("agent-1", payload, r.Header.Get(signing.SignatureHeader)))
+		assert.Empty(t, r.Header.Get(hash.Header), "hmac must not be sent without key")
 
 		w.WriteHeader(http.StatusOK)
 	}))
@@ -565,14 +772,10 @@ func TestSendMetricsBatch_WithHash(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
-	metrics := &Metrics{
-		serverURL: server.URL,
-		logger:    mockLogger,
-		client:    &http.Client{},
-		key:       testKey,
-	}
+	metrics := NewMetrics("localhost", mockLogger, false, "", nil, WithSigner("agent-1", signing.NewSigner(privateKey)))
+	metrics.serverURL = server.URL
 
 	testMetrics := model.Metrics{
 		{
@@ -582,11 +785,25 @@ func TestSendMetricsBatch_WithHash(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err = metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.NoError(t, err)
 }
 
+func TestSendMetricsBatch_SignerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+	mockSigner := NewMockSigner(ctrl)
+	mockSigner.EXPECT().Sign(gomock.Any()).Return("", errors.New("signing failed"))
+
+	metrics := NewMetrics("localhost", mockLogger, false, "", nil, WithSigner("agent-1", mockSigner))
+
+	err := metrics.SendMetricsBatch(context.Background(), model.Metrics{})
+
+	assert.ErrorContains(t, err, "error signing request")
+}
+
 func TestSendMetricsBatch_WithEncryption(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		encryptedBody, err := io.ReadAll(r.Body)
@@ -601,7 +818,7 @@ func TestSendMetricsBatch_WithEncryption(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 	mockEncrypter := NewMockEncrypter(ctrl)
 
 	mockEncrypter.EXPECT().
@@ -624,7 +841,7 @@ func TestSendMetricsBatch_WithEncryption(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.NoError(t, err)
 }
@@ -638,7 +855,7 @@ func TestSendMetricsBatch_EncryptionFailure(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 	mockEncrypter := NewMockEncrypter(ctrl)
 
 	encryptError := assert.AnError
@@ -666,7 +883,7 @@ func TestSendMetricsBatch_EncryptionFailure(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.NoError(t, err)
 }
@@ -683,7 +900,7 @@ func TestSendMetricsBatch_HashCalculationFailure(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := &Metrics{
 		serverURL: server.URL,
@@ -700,7 +917,7 @@ func TestSendMetricsBatch_HashCalculationFailure(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.NoError(t, err)
 }
@@ -715,7 +932,7 @@ func TestSendMetricsBatch_ResponseReadError(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := &Metrics{
 		serverURL: server.URL,
@@ -731,8 +948,204 @@ func TestSendMetricsBatch_ResponseReadError(t *testing.T) {
 		},
 	}
 
-	err := metrics.SendMetricsBatch(testMetrics)
+	err := metrics.SendMetricsBatch(context.Background(), testMetrics)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "could not read body")
 }
+
+func TestSendMetricsBatch_Traceparent(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	propagator := otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := &Metrics{
+		serverURL: server.URL,
+		logger:    newMockMetricsLogger(gomock.NewController(t)),
+		client:    &http.Client{},
+	}
+
+	value := 1.5
+	err := metrics.SendMetricsBatch(context.Background(), model.Metrics{{ID: "Alloc", MType: Gauge, Value: &value}})
+	require.NoError(t, err)
+
+	names := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		names[span.Name()] = span
+	}
+	require.Contains(t, names, "metric.Metrics.SendMetricsBatch")
+	require.Contains(t, names, "metric.compressJSON")
+	require.Contains(t, names, "metric.Metrics.send")
+
+	send := names["metric.Metrics.send"].SpanContext()
+	assert.Equal(t, "00-"+send.TraceID().String()+"-"+send.SpanID().String()+"-01", traceparent)
+}
+
+func TestSendMetrics_RequestID(t *testing.T) {
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get(requestid.Header))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := &Metrics{
+		Gauges:    map[GaugeMetric]float64{"Alloc": 1.5},
+		serverURL: server.URL,
+		logger:    newMockMetricsLogger(gomock.NewController(t)),
+		client:    &http.Client{},
+	}
+
+	metrics.SendMetrics()
+	metrics.SendMetrics()
+
+	value := 1.5
+	ctx := requestid.WithID(context.Background(), "batch-1")
+	require.NoError(t, metrics.SendMetricsBatch(ctx, model.Metrics{{ID: "Alloc", MType: Gauge, Value: &value}}))
+
+	require.Len(t, requestIDs, 3)
+	assert.True(t, requestid.Valid(requestIDs[0]))
+	assert.NotEqual(t, requestIDs[0], requestIDs[1], "each batch gets its own request id")
+	assert.Equal(t, "batch-1", requestIDs[2])
+}
+
+func TestMetrics_SetKeys(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLogger := newMockMetricsLogger(ctrl)
+	mockEncrypter := NewMockEncrypter(ctrl)
+	mockEncrypter.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(data []byte) ([]byte, error) {
+		return data, nil
+	}).Times(1)
+
+	metrics := NewMetrics("localhost", mockLogger, false, "old-key", nil, WithKeyID("old"))
+	metrics.serverURL = server.URL
+
+	value := 1.5
+	batch := model.Metrics{{ID: "test", MType: Gauge, Value: &value}}
+
+	require.NoError(t, metrics.SendMetricsBatch(context.Background(), batch))
+	assert.Equal(t, "old", headers.Get(hash.KeyIDHeader))
+	assert.Empty(t, headers.Get(cryptoutil.KeyIDHeader))
+
+	metrics.SetHashKey("new-key", "new")
+	metrics.SetEncrypter(mockEncrypter, "rsa-new")
+
+	require.NoError(t, metrics.SendMetricsBatch(context.Background(), batch))
+	assert.Equal(t, "new", headers.Get(hash.KeyIDHeader))
+	assert.Equal(t, "rsa-new", headers.Get(cryptoutil.KeyIDHeader))
+
+	metrics.SetHashKey("", "")
+	metrics.SetEncrypter(nil, "")
+
+	require.NoError(t, metrics.SendMetricsBatch(context.Background(), batch))
+	assert.Empty(t, headers.Get(hash.Header))
+	assert.Empty(t, headers.Get(cryptoutil.KeyIDHeader))
+}
+
+func TestMetrics_SendMetrics_Counters(t *testing.T) {
+	status := http.StatusInternalServerError
+	var received model.Metrics
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		received = nil
+		require.NoError(t, json.NewDecoder(reader).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+	mockLogger.EXPECT().Warn("Failed to send metrics batch: %v", gomock.Any()).Times(1)
+
+	m := NewMetrics(strings.TrimPrefix(server.URL, "http://"), mockLogger, false, "", nil)
+	m.AddCounter(PollCount, 2)
+
+	m.SendMetrics()
+	require.Len(t, received, 1)
+	assert.Equal(t, int64(2), *received[0].Delta)
+	assert.Equal(t, int64(2), m.Counters[PollCount], "failed delta is kept")
+
+	status = http.StatusOK
+	m.AddCounter(PollCount, 3)
+	m.SendMetrics()
+	require.Len(t, received, 1)
+	assert.Equal(t, int64(5), *received[0].Delta, "failed delta is sent again with new increments")
+
+	m.AddCounter(PollCount, 1)
+	m.SendMetrics()
+	require.Len(t, received, 1)
+	assert.Equal(t, int64(1), *received[0].Delta, "sent delta is not sent again")
+
+	received = nil
+	m.SendMetrics()
+	assert.Empty(t, received, "nothing to send without new increments")
+}
+
+func TestMetrics_SendMetrics_LostResponse(t *testing.T) {
+	var applied int64
+	dropResponse := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		var received model.Metrics
+		require.NoError(t, json.NewDecoder(reader).Decode(&received))
+		for _, metric := range received {
+			if metric.MType == Counter {
+				applied += *metric.Delta
+			}
+		}
+
+		if dropResponse {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+	mockLogger.EXPECT().Warn("Failed to send metrics batch: %v", gomock.Any()).Times(1)
+
+	m := NewMetrics(strings.TrimPrefix(server.URL, "http://"), mockLogger, false, "", nil)
+	m.AddCounter(PollCount, 2)
+
+	m.SendMetrics()
+	assert.Equal(t, int64(2), applied)
+	assert.Equal(t, int64(2), m.Counters[PollCount], "delta without a response is kept")
+
+	dropResponse = false
+	m.SendMetrics()
+	assert.Equal(t, int64(4), applied, "delivery is at-least-once: the applied delta is counted again")
+	assert.Empty(t, m.Counters)
+}
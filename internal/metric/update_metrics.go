@@ -1,28 +1,39 @@
 package metric
 
-import (
-	"runtime"
-)
-
-func (m *Metrics) UpdateMetrics() {
-	m.updateGaugeMemStats()
-	m.updateGaugeRandomValue()
-	m.updateCounters()
+// SetGauge сохраняет значение gauge метрики для следующей отправки.
+func (m *Metrics) SetGauge(name GaugeMetric, value float64) {
+	m.valuesMu.Lock()
+	defer m.valuesMu.Unlock()
+
+	m.Gauges[name] = value
 }
 
-func (m *Metrics) updateGaugeMemStats() {
-	var memStats runtime.MemStats
-	runtime.ReadMemStats(&memStats)
+// AddCounter увеличивает counter метрику на delta.
+func (m *Metrics) AddCounter(name CounterMetric, delta int64) {
+	m.valuesMu.Lock()
+	defer m.valuesMu.Unlock()
 
-	for _, metric := range MemStatsMetrics {
-		m.Gauges[metric.Metric] = metric.GetValue(&memStats)
-	}
+	m.Counters[name] += delta
 }
 
-func (m *Metrics) updateGaugeRandomValue() {
-	m.Gauges[RandomValue] = m.random.Float64()
+// takeCounters забирает накопленные приращения счетчиков для отправки и обнуляет их.
+// Сервер прибавляет полученное значение к счетчику, поэтому отправленное приращение
+// не должно уйти повторно.
+func (m *Metrics) takeCounters() map[CounterMetric]int64 {
+	m.valuesMu.Lock()
+	defer m.valuesMu.Unlock()
+
+	taken := m.Counters
+	m.Counters = make(map[CounterMetric]int64, len(taken))
+	return taken
 }
 
-func (m *Metrics) updateCounters() {
-	m.Counters[PollCount]++
+// restoreCounters возвращает неотправленные приращения в счетчики.
+func (m *Metrics) restoreCounters(taken map[CounterMetric]int64) {
+	m.valuesMu.Lock()
+	defer m.valuesMu.Unlock()
+
+	for name, delta := range taken {
+		m.Counters[name] += delta
+	}
 }
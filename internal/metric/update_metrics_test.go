@@ -1,112 +1,59 @@
 package metric
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
-func TestMetrics_UpdateMetrics(t *testing.T) {
+func TestMetrics_SetGauge(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
+	mockLogger := newMockMetricsLogger(ctrl)
 
 	metrics := NewMetrics("localhost:8080", mockLogger, false, "", nil)
 
-	_, exists := metrics.Gauges[HeapObjects]
-	assert.False(t, exists, "HeapObjects should not exist before update")
+	metrics.SetGauge(Alloc, 1.5)
+	assert.Equal(t, 1.5, metrics.Gauges[Alloc])
 
-	_, exists = metrics.Gauges[RandomValue]
-	assert.False(t, exists, "RandomValue should not exist before update")
-
-	_, exists = metrics.Counters[PollCount]
-	assert.False(t, exists, "PollCount should not exist before update")
-
-	metrics.UpdateMetrics()
-
-	_, exists = metrics.Gauges[HeapObjects]
-	assert.True(t, exists, "HeapObjects should exist after update")
-
-	_, exists = metrics.Gauges[RandomValue]
-	assert.True(t, exists, "RandomValue should exist after update")
-
-	pollCount, exists := metrics.Counters[PollCount]
-	assert.True(t, exists, "PollCount should exist after update")
-	assert.Equal(t, int64(1), pollCount, "PollCount should be incremented to 1")
-}
-
-func TestMetrics_updateGaugeMemStats(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
-
-	metrics := NewMetrics("localhost:8080", mockLogger, false, "", nil)
-
-	metrics.updateGaugeMemStats()
-
-	requiredGauges := []GaugeMetric{
-		Alloc, BuckHashSys, Frees, GCCPUFraction, GCSys, HeapAlloc,
-		HeapIdle, HeapInuse, HeapObjects, HeapReleased, HeapSys,
-		LastGC, Lookups, MCacheInuse, MCacheSys, MSpanInuse,
-		MSpanSys, Mallocs, NextGC, NumForcedGC, NumGC, OtherSys,
-		PauseTotalNs, StackInuse, StackSys, Sys, TotalAlloc,
-	}
-
-	for _, metricName := range requiredGauges {
-		_, exists := metrics.Gauges[metricName]
-		assert.True(t, exists, "%s should exist after updateGaugeMemStats", metricName)
-	}
-
-	_, exists := metrics.Gauges[RandomValue]
-	assert.False(t, exists, "RandomValue should not be set by updateGaugeMemStats")
+	metrics.SetGauge(Alloc, 2.5)
+	assert.Equal(t, 2.5, metrics.Gauges[Alloc], "SetGauge should replace previous value")
 }
 
-func TestMetrics_updateGaugeRandomValue(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockLogger := NewMockMetricsLogger(ctrl)
-
-	metrics := NewMetrics("localhost:8080", mockLogger, false, "", nil)
-
-	metrics.updateGaugeRandomValue()
-
-	randomValue, exists := metrics.Gauges[RandomValue]
-	assert.True(t, exists, "RandomValue should exist after updateGaugeRandomValue")
-	assert.GreaterOrEqual(t, randomValue, 0.0, "RandomValue should be >= 0.0")
-	assert.Less(t, randomValue, 1.0, "RandomValue should be < 1.0")
-
-	firstValue := randomValue
-	metrics.updateGaugeRandomValue()
-	secondValue, exists := metrics.Gauges[RandomValue]
-	assert.True(t, exists, "RandomValue should still exist after second updateGaugeRandomValue")
-
-	assert.NotEqual(t, firstValue, secondValue, "RandomValue should change between calls")
-}
-
-func TestMetrics_updateCounters(t *testing.T) {
+func TestMetrics_AddCounter(t *testing.T) {
 	tests := []struct {
 		name              string
 		initialPollCount  int64
+		delta             int64
 		expectedPollCount int64
 	}{
 		{
 			name:              "increment from zero",
 			initialPollCount:  0,
+			delta:             1,
 			expectedPollCount: 1,
 		},
 		{
 			name:              "increment from positive value",
 			initialPollCount:  42,
+			delta:             1,
 			expectedPollCount: 43,
 		},
+		{
+			name:              "add larger delta",
+			initialPollCount:  5,
+			delta:             10,
+			expectedPollCount: 15,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			mockLogger := NewMockMetricsLogger(ctrl)
+			mockLogger := newMockMetricsLogger(ctrl)
 
 			metrics := NewMetrics("localhost:8080", mockLogger, false, "", nil)
 
@@ -114,11 +61,35 @@ func TestMetrics_updateCounters(t *testing.T) {
 				metrics.Counters[PollCount] = tt.initialPollCount
 			}
 
-			metrics.updateCounters()
+			metrics.AddCounter(PollCount, tt.delta)
 
 			pollCount, exists := metrics.Counters[PollCount]
-			assert.True(t, exists, "PollCount should exist after updateCounters")
-			assert.Equal(t, tt.expectedPollCount, pollCount, "PollCount should be incremented correctly")
+			assert.True(t, exists, "PollCount should exist after AddCounter")
+			assert.Equal(t, tt.expectedPollCount, pollCount)
 		})
 	}
 }
+
+func TestMetrics_ConcurrentUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := newMockMetricsLogger(ctrl)
+
+	metrics := NewMetrics("localhost:8080", mockLogger, false, "", nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				metrics.SetGauge(RandomValue, float64(j))
+				metrics.AddCounter(PollCount, 1)
+				metrics.restoreCounters(metrics.takeCounters())
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1000), metrics.Counters[PollCount])
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+var (
+	ErrInvalidHistogram = errors.New("invalid histogram")
+	ErrBucketMismatch   = errors.New("histogram buckets mismatch")
+)
+
+// DefaultBuckets - границы корзин для длительностей в секундах.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewHistogram создает пустую гистограмму с заданными границами корзин.
+func NewHistogram(bounds []float64) Histogram {
+	return Histogram{
+		Bounds: slices.Clone(bounds),
+		Counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe добавляет значение в гистограмму.
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.Bounds, value)
+	h.Counts[i]++
+	h.Count++
+	h.Sum += value
+}
+
+// Validate проверяет, что границы корзин возрастают, число корзин соответствует
+// числу границ, а общее число значений равно сумме значений корзин.
+func (h Histogram) Validate() error {
+	for i := 1; i < len(h.Bounds); i++ {
+		if h.Bounds[i] <= h.Bounds[i-1] {
+			return fmt.Errorf("%w: bounds must be strictly increasing", ErrInvalidHistogram)
+		}
+	}
+
+	if len(h.Counts) != len(h.Bounds)+1 {
+		return fmt.Errorf("%w: got %d counts for %d bounds, want %d", ErrInvalidHistogram, len(h.Counts), len(h.Bounds), len(h.Bounds)+1)
+	}
+
+	var total uint64
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total != h.Count {
+		return fmt.Errorf("%w: count %d does not match bucket total %d", ErrInvalidHistogram, h.Count, total)
+	}
+
+	return nil
+}
+
+// Merge складывает значения корзин, общее число и сумму значений другой гистограммы.
+// Границы корзин обеих гистограмм должны совпадать.
+func (h *Histogram) Merge(other Histogram) error {
+	if !slices.Equal(h.Bounds, other.Bounds) {
+		return fmt.Errorf("%w: bounds %v, got %v", ErrBucketMismatch, h.Bounds, other.Bounds)
+	}
+
+	for i, c := range other.Counts {
+		h.Counts[i] += c
+	}
+	h.Count += other.Count
+	h.Sum += other.Sum
+
+	return nil
+}
+
+// Clone возвращает копию гистограммы, не разделяющую с ней срезы.
+func (h Histogram) Clone() Histogram {
+	return Histogram{
+		Bounds: slices.Clone(h.Bounds),
+		Counts: slices.Clone(h.Counts),
+		Count:  h.Count,
+		Sum:    h.Sum,
+	}
+}
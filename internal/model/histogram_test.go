@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_Observe(t *testing.T) {
+	h := NewHistogram([]float64{1, 5})
+
+	for _, v := range []float64{0.5, 1, 3, 10} {
+		h.Observe(v)
+	}
+
+	assert.Equal(t, []uint64{2, 1, 1}, h.Counts)
+	assert.Equal(t, uint64(4), h.Count)
+	assert.Equal(t, 14.5, h.Sum)
+	assert.NoError(t, h.Validate())
+}
+
+func TestHistogram_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		histogram   Histogram
+		expectedErr string
+	}{
+		{
+			name:      "valid",
+			histogram: Histogram{Bounds: []float64{1, 2}, Counts: []uint64{1, 0, 2}, Count: 3, Sum: 7},
+		},
+		{
+			name:      "no bounds",
+			histogram: Histogram{Counts: []uint64{2}, Count: 2, Sum: 1},
+		},
+		{
+			name:        "unordered bounds",
+			histogram:   Histogram{Bounds: []float64{2, 1}, Counts: []uint64{0, 0, 0}},
+			expectedErr: "bounds must be strictly increasing",
+		},
+		{
+			name:        "wrong number of counts",
+			histogram:   Histogram{Bounds: []float64{1, 2}, Counts: []uint64{0, 0}},
+			expectedErr: "got 2 counts for 2 bounds, want 3",
+		},
+		{
+			name:        "count mismatch",
+			histogram:   Histogram{Bounds: []float64{1}, Counts: []uint64{1, 1}, Count: 3},
+			expectedErr: "count 3 does not match bucket total 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.histogram.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidHistogram)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	h := Histogram{Bounds: []float64{1, 2}, Counts: []uint64{1, 0, 2}, Count: 3, Sum: 7}
+
+	require.NoError(t, h.Merge(Histogram{Bounds: []float64{1, 2}, Counts: []uint64{0, 4, 1}, Count: 5, Sum: 9}))
+	assert.Equal(t, Histogram{Bounds: []float64{1, 2}, Counts: []uint64{1, 4, 3}, Count: 8, Sum: 16}, h)
+
+	err := h.Merge(Histogram{Bounds: []float64{1, 3}, Counts: []uint64{0, 0, 0}})
+	assert.ErrorIs(t, err, ErrBucketMismatch)
+	assert.Equal(t, uint64(8), h.Count, "failed merge must not change histogram")
+}
+
+func TestHistogram_Clone(t *testing.T) {
+	h := NewHistogram([]float64{1})
+	clone := h.Clone()
+	clone.Observe(0.5)
+
+	assert.Equal(t, uint64(0), h.Counts[0])
+	assert.Equal(t, uint64(1), clone.Counts[0])
+}
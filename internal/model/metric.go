@@ -7,16 +7,29 @@ package model
 type MetricType string
 
 const (
-	GaugeType   MetricType = "gauge"
-	CounterType MetricType = "counter"
+	GaugeType     MetricType = "gauge"
+	CounterType   MetricType = "counter"
+	HistogramType MetricType = "histogram"
 )
 
 // Metric представляет одну метрику с метаданными и значением.
 type Metric struct {
 	ID    string     `json:"id"`              // имя метрики
-	MType MetricType `json:"type"`            // параметр, принимающий значение gauge или counter
+	MType MetricType `json:"type"`            // параметр, принимающий значение gauge, counter или histogram
 	Delta *int64     `json:"delta,omitempty"` // значение метрики в случае передачи counter
 	Value *float64   `json:"value,omitempty"` // значение метрики в случае передачи gauge
+
+	Histogram *Histogram `json:"histogram,omitempty"` // значение метрики в случае передачи histogram
+}
+
+// Histogram описывает распределение значений по корзинам.
+// Counts[i] содержит число значений в корзине с верхней границей Bounds[i],
+// последний элемент Counts - число значений больше последней границы.
+type Histogram struct {
+	Bounds []float64 `json:"bounds"` // верхние границы корзин по возрастанию
+	Counts []uint64  `json:"counts"` // число значений в каждой корзине
+	Count  uint64    `json:"count"`  // общее число значений
+	Sum    float64   `json:"sum"`    // сумма значений
 }
 
 //easyjson:json
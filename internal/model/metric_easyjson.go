@@ -4,7 +4,6 @@ package model
 
 import (
 	json "encoding/json"
-
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
@@ -127,6 +126,16 @@ func easyjson9478868cDecodeGithubComNoobyTheTurtleMetricsInternalModel1(in *jlex
 				}
 				*out.Value = float64(in.Float64())
 			}
+		case "histogram":
+			if in.IsNull() {
+				in.Skip()
+				out.Histogram = nil
+			} else {
+				if out.Histogram == nil {
+					out.Histogram = new(Histogram)
+				}
+				(*out.Histogram).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -161,6 +170,11 @@ func easyjson9478868cEncodeGithubComNoobyTheTurtleMetricsInternalModel1(out *jwr
 		out.RawString(prefix)
 		out.Float64(float64(*in.Value))
 	}
+	if in.Histogram != nil {
+		const prefix string = ",\"histogram\":"
+		out.RawString(prefix)
+		(*in.Histogram).MarshalEasyJSON(out)
+	}
 	out.RawByte('}')
 }
 
@@ -187,3 +201,154 @@ func (v *Metric) UnmarshalJSON(data []byte) error {
 func (v *Metric) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson9478868cDecodeGithubComNoobyTheTurtleMetricsInternalModel1(l, v)
 }
+func easyjson9478868cDecodeGithubComNoobyTheTurtleMetricsInternalModel2(in *jlexer.Lexer, out *Histogram) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "bounds":
+			if in.IsNull() {
+				in.Skip()
+				out.Bounds = nil
+			} else {
+				in.Delim('[')
+				if out.Bounds == nil {
+					if !in.IsDelim(']') {
+						out.Bounds = make([]float64, 0, 8)
+					} else {
+						out.Bounds = []float64{}
+					}
+				} else {
+					out.Bounds = (out.Bounds)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 float64
+					v4 = float64(in.Float64())
+					out.Bounds = append(out.Bounds, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "counts":
+			if in.IsNull() {
+				in.Skip()
+				out.Counts = nil
+			} else {
+				in.Delim('[')
+				if out.Counts == nil {
+					if !in.IsDelim(']') {
+						out.Counts = make([]uint64, 0, 8)
+					} else {
+						out.Counts = []uint64{}
+					}
+				} else {
+					out.Counts = (out.Counts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 uint64
+					v5 = uint64(in.Uint64())
+					out.Counts = append(out.Counts, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "count":
+			out.Count = uint64(in.Uint64())
+		case "sum":
+			out.Sum = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9478868cEncodeGithubComNoobyTheTurtleMetricsInternalModel2(out *jwriter.Writer, in Histogram) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"bounds\":"
+		out.RawString(prefix[1:])
+		if in.Bounds == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v6, v7 := range in.Bounds {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				out.Float64(float64(v7))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"counts\":"
+		out.RawString(prefix)
+		if in.Counts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Counts {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				out.Uint64(uint64(v9))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.Count))
+	}
+	{
+		const prefix string = ",\"sum\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Sum))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Histogram) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9478868cEncodeGithubComNoobyTheTurtleMetricsInternalModel2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Histogram) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9478868cEncodeGithubComNoobyTheTurtleMetricsInternalModel2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Histogram) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9478868cDecodeGithubComNoobyTheTurtleMetricsInternalModel2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Histogram) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9478868cDecodeGithubComNoobyTheTurtleMetricsInternalModel2(l, v)
+}
@@ -33,6 +33,20 @@ func TestMetricEasyJSON(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "Histogram with buckets",
+			metric: Metric{
+				ID:    "TestHistogram",
+				MType: HistogramType,
+				Histogram: &Histogram{
+					Bounds: []float64{0.1, 1},
+					Counts: []uint64{2, 1, 0},
+					Count:  3,
+					Sum:    0.7,
+				},
+			},
+			expectErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,218 @@
+// Package notify реализует хаб уведомлений об изменении метрик.
+// Хранилище публикует каждое успешное обновление, а подписчики (потоки SSE и WebSocket)
+// получают события через собственные буферизованные каналы.
+package notify
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize - размер буфера подписчика по умолчанию.
+const DefaultBufferSize = 256
+
+// ErrSlowConsumer означает, что подписчик не успевал читать события и был отключен.
+var ErrSlowConsumer = errors.New("subscriber is too slow")
+
+// ErrClosed означает, что хаб закрыт.
+var ErrClosed = errors.New("hub is closed")
+
+// Event описывает изменение метрики.
+// Для счетчиков Total содержит итоговое значение после обновления, а не приращение.
+// Для гистограмм Count и Sum содержат общее число и сумму значений после обновления.
+type Event struct {
+	Tenant    string    `json:"tenant,omitempty"`
+	ID        string    `json:"id"`
+	MType     string    `json:"type"`
+	Total     *int64    `json:"total,omitempty"`
+	Value     *float64  `json:"value,omitempty"`
+	Count     *uint64   `json:"count,omitempty"`
+	Sum       *float64  `json:"sum,omitempty"`
+	Timestamp time.Time `json:"ts"`
+}
+
+// Filter ограничивает события, которые получает подписчик.
+// Пустые Types и Names означают все типы и все имена.
+// Подписчик всегда получает события только своего арендатора.
+type Filter struct {
+	Tenant string
+	Types  []string
+	Names  []string
+}
+
+// Match проверяет, подходит ли событие под фильтр.
+func (f Filter) Match(e Event) bool {
+	if e.Tenant != f.Tenant {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.MType) {
+		return false
+	}
+	if len(f.Names) > 0 && !slices.Contains(f.Names, e.ID) {
+		return false
+	}
+	return true
+}
+
+// Subscription - подписка на события хаба.
+type Subscription struct {
+	hub    *Hub
+	filter Filter
+	events chan Event
+
+	once sync.Once
+	err  error
+}
+
+// Events возвращает канал событий. Канал закрывается при отписке,
+// отключении медленного подписчика или закрытии хаба.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err возвращает причину закрытия канала событий.
+// Для подписки, закрытой через Close, возвращает nil.
+func (s *Subscription) Err() error {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+
+	return s.err
+}
+
+// deliver отправляет подходящие под фильтр события без блокировки.
+// Возвращает false, если буфер подписчика заполнен.
+func (s *Subscription) deliver(events []Event) bool {
+	for _, e := range events {
+		if !s.filter.Match(e) {
+			continue
+		}
+
+		select {
+		case s.events <- e:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Close отменяет подписку.
+func (s *Subscription) Close() {
+	s.hub.remove(s, nil)
+}
+
+// Hub рассылает события подписчикам.
+type Hub struct {
+	mu         sync.RWMutex
+	bufferSize int
+	subs       map[*Subscription]struct{}
+	closed     bool
+	dropped    uint64
+}
+
+// NewHub создает хаб с буфером bufferSize событий на подписчика.
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	return &Hub{
+		bufferSize: bufferSize,
+		subs:       make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe создает подписку с фильтром.
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	s := &Subscription{
+		hub:    h,
+		filter: filter,
+		events: make(chan Event, h.bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		s.err = ErrClosed
+		s.once.Do(func() { close(s.events) })
+		return s
+	}
+
+	h.subs[s] = struct{}{}
+
+	return s
+}
+
+// Publish рассылает события подписчикам без блокировки.
+// Подписчик с заполненным буфером отключается с ошибкой ErrSlowConsumer,
+// чтобы медленный клиент не задерживал обновление метрик.
+func (h *Hub) Publish(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for s := range h.subs {
+		if !s.deliver(events) {
+			slow = append(slow, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range slow {
+		h.remove(s, ErrSlowConsumer)
+	}
+}
+
+// Subscribers возвращает количество активных подписчиков.
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subs)
+}
+
+// Dropped возвращает количество подписчиков, отключенных как медленные.
+func (h *Hub) Dropped() uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.dropped
+}
+
+// Close закрывает хаб и все подписки.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for s := range h.subs {
+		h.closeLocked(s, ErrClosed)
+	}
+}
+
+func (h *Hub) remove(s *Subscription, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[s]; !ok {
+		return
+	}
+
+	if errors.Is(err, ErrSlowConsumer) {
+		h.dropped++
+	}
+	h.closeLocked(s, err)
+}
+
+func (h *Hub) closeLocked(s *Subscription, err error) {
+	delete(h.subs, s)
+	s.err = err
+	s.once.Do(func() { close(s.events) })
+}
@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gauge(tenant, id string, v float64) Event {
+	return Event{Tenant: tenant, ID: id, MType: "gauge", Value: &v}
+}
+
+func counter(tenant, id string, d int64) Event {
+	return Event{Tenant: tenant, ID: id, MType: "counter", Total: &d}
+}
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		event    Event
+		expected bool
+	}{
+		{name: "empty filter", filter: Filter{}, event: gauge("", "a", 1), expected: true},
+		{name: "type match", filter: Filter{Types: []string{"gauge"}}, event: gauge("", "a", 1), expected: true},
+		{name: "type mismatch", filter: Filter{Types: []string{"counter"}}, event: gauge("", "a", 1), expected: false},
+		{name: "name match", filter: Filter{Names: []string{"a", "b"}}, event: counter("", "b", 1), expected: true},
+		{name: "name mismatch", filter: Filter{Names: []string{"a"}}, event: counter("", "c", 1), expected: false},
+		{name: "other tenant", filter: Filter{}, event: gauge("team-a", "a", 1), expected: false},
+		{name: "same tenant", filter: Filter{Tenant: "team-a"}, event: gauge("team-a", "a", 1), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.Match(tt.event))
+		})
+	}
+}
+
+func TestHub_PublishSubscribe(t *testing.T) {
+	hub := NewHub(10)
+
+	all := hub.Subscribe(Filter{})
+	gauges := hub.Subscribe(Filter{Types: []string{"gauge"}})
+	tenantSub := hub.Subscribe(Filter{Tenant: "team-a"})
+	assert.Equal(t, 3, hub.Subscribers())
+
+	hub.Publish(gauge("", "g", 1), counter("", "c", 2), gauge("team-a", "g", 3))
+
+	require.Len(t, all.Events(), 2)
+	assert.Equal(t, "g", (<-all.Events()).ID)
+	assert.Equal(t, "c", (<-all.Events()).ID)
+
+	require.Len(t, gauges.Events(), 1)
+	assert.Equal(t, 1.0, *(<-gauges.Events()).Value)
+
+	require.Len(t, tenantSub.Events(), 1)
+	assert.Equal(t, 3.0, *(<-tenantSub.Events()).Value)
+
+	all.Close()
+	_, ok := <-all.Events()
+	assert.False(t, ok)
+	assert.NoError(t, all.Err())
+	assert.Equal(t, 2, hub.Subscribers())
+
+	all.Close()
+}
+
+func TestHub_SlowConsumer(t *testing.T) {
+	hub := NewHub(2)
+
+	slow := hub.Subscribe(Filter{})
+	fast := hub.Subscribe(Filter{})
+
+	for i := range 3 {
+		hub.Publish(counter("", "c", int64(i)))
+		<-fast.Events()
+	}
+
+	var received int
+	for range slow.Events() {
+		received++
+	}
+
+	assert.Equal(t, 2, received, "buffered events must be delivered before close")
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	assert.Equal(t, uint64(1), hub.Dropped())
+	assert.Equal(t, 1, hub.Subscribers())
+
+	hub.Publish(counter("", "c", 4))
+	assert.Len(t, fast.Events(), 1)
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub(1)
+	sub := hub.Subscribe(Filter{})
+
+	hub.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, sub.Err(), ErrClosed)
+
+	late := hub.Subscribe(Filter{})
+	_, ok = <-late.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, late.Err(), ErrClosed)
+
+	hub.Publish(gauge("", "g", 1))
+}
+
+func TestHub_Concurrent(t *testing.T) {
+	hub := NewHub(1000)
+
+	var wg sync.WaitGroup
+	subs := make([]*Subscription, 10)
+	for i := range subs {
+		subs[i] = hub.Subscribe(Filter{})
+	}
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				hub.Publish(gauge("", "g", 1))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range subs {
+		assert.Len(t, s.Events(), 500)
+		s.Close()
+	}
+}
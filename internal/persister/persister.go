@@ -3,20 +3,42 @@ package persister
 import (
 	"context"
 	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 )
 
 type Persister struct {
-	storage  MetricsStorage
-	logger   PersisterLogger
-	interval time.Duration
+	storage      MetricsStorage
+	logger       PersisterLogger
+	interval     time.Duration
+	saveDuration *selfmetrics.Histogram
+	saveErrors   *selfmetrics.Counter
+}
+
+// Option задает дополнительные параметры Persister.
+type Option func(*Persister)
+
+// WithMetrics включает учет длительности и ошибок сохранения в метриках сервера.
+func WithMetrics(metrics *selfmetrics.Registry) Option {
+	return func(p *Persister) {
+		p.saveDuration = metrics.Histogram("persister.save.duration", model.DefaultBuckets)
+		p.saveErrors = metrics.Counter("persister.save.errors")
+	}
 }
 
-func NewPersister(storage MetricsStorage, logger PersisterLogger, storeInterval uint) *Persister {
-	return &Persister{
+func NewPersister(storage MetricsStorage, logger PersisterLogger, storeInterval time.Duration, opts ...Option) *Persister {
+	p := &Persister{
 		storage:  storage,
 		logger:   logger,
-		interval: time.Duration(storeInterval) * time.Second,
+		interval: storeInterval,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 func (p *Persister) Run(ctx context.Context) {
@@ -29,14 +51,14 @@ func (p *Persister) Run(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			p.logger.Info("Persister shutting down, performing final save...")
-			if err := p.storage.SaveToFile(context.Background()); err != nil {
+			if err := p.save(context.Background()); err != nil {
 				p.logger.Error("Failed to perform final save during shutdown: %v", err)
 			} else {
 				p.logger.Info("Final save completed successfully")
 			}
 			return
 		case <-ticker.C:
-			if err := p.storage.SaveToFile(ctx); err != nil {
+			if err := p.save(ctx); err != nil {
 				p.logger.Error("Failed to save metrics: %v", err)
 			} else {
 				p.logger.Info("Successfully saved metrics to file")
@@ -44,3 +66,13 @@ func (p *Persister) Run(ctx context.Context) {
 		}
 	}
 }
+
+func (p *Persister) save(ctx context.Context) error {
+	start := time.Now()
+	err := p.storage.SaveToFile(ctx)
+	p.saveDuration.Since(start)
+	if err != nil {
+		p.saveErrors.Inc()
+	}
+	return err
+}
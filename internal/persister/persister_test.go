@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -17,7 +18,7 @@ func TestNewPersister_ValidParameters(t *testing.T) {
 	mockStorage := NewMockMetricsStorage(ctrl)
 	mockLogger := NewMockPersisterLogger(ctrl)
 
-	persister := NewPersister(mockStorage, mockLogger, 5)
+	persister := NewPersister(mockStorage, mockLogger, 5*time.Second)
 
 	assert.NotNil(t, persister)
 	assert.Equal(t, mockStorage, persister.storage)
@@ -205,3 +206,27 @@ func TestPersister_Run_FinalSaveError(t *testing.T) {
 		t.Fatal("Persister did not handle final save error gracefully")
 	}
 }
+
+func TestPersister_WithMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockMetricsStorage(ctrl)
+	mockLogger := NewMockPersisterLogger(ctrl)
+
+	mockLogger.EXPECT().Info(gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).Times(1)
+
+	mockStorage.EXPECT().SaveToFile(gomock.Any()).Return(errors.New("disk full")).Times(1)
+
+	metrics := selfmetrics.NewRegistry()
+	persister := NewPersister(mockStorage, mockLogger, 1*time.Second, WithMetrics(metrics))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	persister.Run(ctx)
+
+	assert.Equal(t, uint64(1), metrics.Histogram("persister.save.duration", nil).Count())
+	assert.Equal(t, int64(1), metrics.Counter("persister.save.errors").Value())
+}
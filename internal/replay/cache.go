@@ -0,0 +1,85 @@
+// Package replay реализует защиту от повторной отправки подписанных запросов.
+package replay
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+// Cache хранит недавно использованные nonce в ограниченном LRU кэше.
+// Запись удаляется по истечении ttl или при превышении размера кэша.
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+// NewCache создает кэш nonce размером size с временем жизни записей ttl.
+func NewCache(size int, ttl time.Duration) *Cache {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &Cache{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+		now:   time.Now,
+	}
+}
+
+// Add запоминает nonce. Возвращает false, если nonce уже встречался
+// и его запись еще не истекла.
+func (c *Cache) Add(nonce string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+
+	if _, exists := c.items[nonce]; exists {
+		return false
+	}
+
+	if c.order.Len() >= c.size {
+		c.removeElement(c.order.Front())
+	}
+
+	c.items[nonce] = c.order.PushBack(&entry{nonce: nonce, expiresAt: now.Add(c.ttl)})
+
+	return true
+}
+
+// Len возвращает количество записей в кэше.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// evictExpired удаляет истекшие записи. Записи упорядочены по времени
+// добавления, поэтому просмотр останавливается на первой актуальной.
+func (c *Cache) evictExpired(now time.Time) {
+	for el := c.order.Front(); el != nil; el = c.order.Front() {
+		if el.Value.(*entry).expiresAt.After(now) {
+			return
+		}
+		c.removeElement(el)
+	}
+}
+
+func (c *Cache) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).nonce)
+}
@@ -0,0 +1,67 @@
+package replay
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Add(t *testing.T) {
+	c := NewCache(10, time.Minute)
+
+	assert.True(t, c.Add("a"))
+	assert.False(t, c.Add("a"), "repeated nonce must be rejected")
+	assert.True(t, c.Add("b"))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCache_Expiration(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := NewCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	assert.True(t, c.Add("a"))
+
+	now = now.Add(30 * time.Second)
+	assert.False(t, c.Add("a"))
+	assert.True(t, c.Add("b"))
+
+	now = now.Add(31 * time.Second)
+	assert.True(t, c.Add("a"), "expired nonce must be accepted again")
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCache_Bounded(t *testing.T) {
+	c := NewCache(3, time.Hour)
+
+	for i := range 5 {
+		assert.True(t, c.Add(fmt.Sprintf("nonce-%d", i)))
+	}
+
+	assert.Equal(t, 3, c.Len())
+	assert.True(t, c.Add("nonce-0"), "least recently added nonce must be evicted")
+	assert.False(t, c.Add("nonce-4"))
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	c := NewCache(1000, time.Minute)
+
+	var wg sync.WaitGroup
+	accepted := make(chan struct{}, 100)
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Add("same") {
+				accepted <- struct{}{}
+			}
+		}()
+	}
+	wg.Wait()
+	close(accepted)
+
+	assert.Len(t, accepted, 1)
+}
@@ -12,12 +12,14 @@ type Reporter struct {
 	reportInterval time.Duration
 	rateLimit      uint
 	jobChan        chan struct{}
+	intervals      chan time.Duration
+	rateLimits     chan uint
 }
 
 func NewReporter(
 	metrics MetricsReporter,
 	logger ReporterLogger,
-	reportInterval uint,
+	reportInterval time.Duration,
 	rateLimit uint,
 ) *Reporter {
 	if rateLimit == 0 {
@@ -25,19 +27,51 @@ func NewReporter(
 		rateLimit = 1
 	}
 
-	if reportInterval == 0 {
-		logger.Info("reporter.NewReporter: Invalid reportInterval value %d provided, defaulting to 1 second.", reportInterval)
-		reportInterval = 1
+	if reportInterval <= 0 {
+		logger.Info("reporter.NewReporter: Invalid reportInterval value %s provided, defaulting to 1 second.", reportInterval)
+		reportInterval = time.Second
 	}
 
 	return &Reporter{
 		metrics:        metrics,
 		logger:         logger,
-		reportInterval: time.Duration(reportInterval) * time.Second,
+		reportInterval: reportInterval,
 		rateLimit:      rateLimit,
+		intervals:      make(chan time.Duration, 1),
+		rateLimits:     make(chan uint, 1),
 	}
 }
 
+// SetReportInterval меняет период отправки работающего отправителя.
+// Значения меньше или равные нулю заменяются на одну секунду.
+// Вызовы SetReportInterval и SetRateLimit не должны выполняться параллельно.
+func (r *Reporter) SetReportInterval(reportInterval time.Duration) {
+	if reportInterval <= 0 {
+		reportInterval = time.Second
+	}
+
+	select {
+	case <-r.intervals:
+	default:
+	}
+	r.intervals <- reportInterval
+}
+
+// SetRateLimit меняет число воркеров работающего отправителя. Текущие воркеры
+// завершают начатые отправки, после чего запускается пул нового размера.
+// Нулевое значение заменяется на одного воркера.
+func (r *Reporter) SetRateLimit(rateLimit uint) {
+	if rateLimit == 0 {
+		rateLimit = 1
+	}
+
+	select {
+	case <-r.rateLimits:
+	default:
+	}
+	r.rateLimits <- rateLimit
+}
+
 func (r *Reporter) worker(workerID uint, wg *sync.WaitGroup) {
 	defer wg.Done()
 	r.logger.Info("Worker %d: started.", workerID)
@@ -50,13 +84,8 @@ func (r *Reporter) worker(workerID uint, wg *sync.WaitGroup) {
 	r.logger.Info("Worker %d: stopped.", workerID)
 }
 
-func (r *Reporter) RunWithContext(ctx context.Context) {
-	r.logger.Info(
-		"Reporter starting with context. Report interval: %s. Worker pool size: %d.",
-		r.reportInterval.String(),
-		r.rateLimit,
-	)
-
+// startWorkers запускает пул из rateLimit воркеров с новой очередью заданий.
+func (r *Reporter) startWorkers() *sync.WaitGroup {
 	r.jobChan = make(chan struct{}, r.rateLimit)
 
 	var wg sync.WaitGroup
@@ -65,6 +94,18 @@ func (r *Reporter) RunWithContext(ctx context.Context) {
 		go r.worker(i, &wg)
 	}
 
+	return &wg
+}
+
+func (r *Reporter) RunWithContext(ctx context.Context) {
+	r.logger.Info(
+		"Reporter starting with context. Report interval: %s. Worker pool size: %d.",
+		r.reportInterval.String(),
+		r.rateLimit,
+	)
+
+	wg := r.startWorkers()
+
 	ticker := time.NewTicker(r.reportInterval)
 	defer func() {
 		ticker.Stop()
@@ -79,6 +120,16 @@ func (r *Reporter) RunWithContext(ctx context.Context) {
 		case <-ctx.Done():
 			r.logger.Info("Reporter stopping due to context cancellation")
 			return
+		case d := <-r.intervals:
+			r.reportInterval = d
+			ticker.Reset(d)
+			r.logger.Info("Report interval changed to %s", d)
+		case n := <-r.rateLimits:
+			close(r.jobChan)
+			wg.Wait()
+			r.rateLimit = n
+			wg = r.startWorkers()
+			r.logger.Info("Worker pool size changed to %d", n)
 		case <-ticker.C:
 			select {
 			case r.jobChan <- struct{}{}:
@@ -17,7 +17,7 @@ func TestNewReporter_ValidParameters(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 5, 3)
+	reporter := NewReporter(mockMetrics, mockLogger, 5*time.Second, 3)
 
 	assert.NotNil(t, reporter)
 	assert.Equal(t, mockMetrics, reporter.metrics)
@@ -35,7 +35,7 @@ func TestNewReporter_ZeroRateLimit_DefaultsToOne(t *testing.T) {
 
 	mockLogger.EXPECT().Info("reporter.NewReporter: Invalid rateLimit value %d provided, defaulting to 1 worker.", uint(0)).Times(1)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 5, 0)
+	reporter := NewReporter(mockMetrics, mockLogger, 5*time.Second, 0)
 
 	assert.NotNil(t, reporter)
 	assert.Equal(t, uint(1), reporter.rateLimit)
@@ -48,7 +48,7 @@ func TestNewReporter_ZeroReportInterval_DefaultsToOne(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	mockLogger.EXPECT().Info("reporter.NewReporter: Invalid reportInterval value %d provided, defaulting to 1 second.", uint(0)).Times(1)
+	mockLogger.EXPECT().Info("reporter.NewReporter: Invalid reportInterval value %s provided, defaulting to 1 second.", time.Duration(0)).Times(1)
 
 	reporter := NewReporter(mockMetrics, mockLogger, 0, 3)
 
@@ -64,7 +64,7 @@ func TestReporter_Worker_ProcessesJobsAndStopsGracefully(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 5, 1)
+	reporter := NewReporter(mockMetrics, mockLogger, 5*time.Second, 1)
 
 	mockLogger.EXPECT().Info("Worker %d: started.", uint(1)).Times(1)
 	mockMetrics.EXPECT().SendMetrics().Times(1)
@@ -97,7 +97,7 @@ func TestReporter_Worker_ProcessesMultipleJobs(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 5, 1)
+	reporter := NewReporter(mockMetrics, mockLogger, 5*time.Second, 1)
 
 	mockLogger.EXPECT().Info("Worker %d: started.", uint(1)).Times(1)
 	mockMetrics.EXPECT().SendMetrics().Times(3)
@@ -126,7 +126,7 @@ func TestReporter_RunWithContext_GracefulShutdown(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 1, 2)
+	reporter := NewReporter(mockMetrics, mockLogger, 1*time.Second, 2)
 
 	mockLogger.EXPECT().Info("Reporter starting with context. Report interval: %s. Worker pool size: %d.", "1s", uint(2)).Times(1)
 	mockLogger.EXPECT().Info("Worker %d: started.", uint(1)).Times(1)
@@ -165,7 +165,7 @@ func TestReporter_RunWithContext_ImmediateCancellation(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 10, 1)
+	reporter := NewReporter(mockMetrics, mockLogger, 10*time.Second, 1)
 
 	mockLogger.EXPECT().Info("Reporter starting with context. Report interval: %s. Worker pool size: %d.", "10s", uint(1)).Times(1)
 	mockLogger.EXPECT().Info("Worker %d: started.", uint(1)).Times(1)
@@ -197,7 +197,7 @@ func TestReporter_RunWithContext_CancellationWhileSendingJob(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 1, 1)
+	reporter := NewReporter(mockMetrics, mockLogger, 1*time.Second, 1)
 
 	mockLogger.EXPECT().Info("Reporter starting with context. Report interval: %s. Worker pool size: %d.", "1s", uint(1)).Times(1)
 	mockLogger.EXPECT().Info("Worker %d: started.", uint(1)).Times(1)
@@ -235,7 +235,7 @@ func TestReporter_RunWithContext_WorkerPanic(t *testing.T) {
 	mockMetrics := NewMockMetricsReporter(ctrl)
 	mockLogger := NewMockReporterLogger(ctrl)
 
-	reporter := NewReporter(mockMetrics, mockLogger, 1, 1)
+	reporter := NewReporter(mockMetrics, mockLogger, 1*time.Second, 1)
 
 	mockLogger.EXPECT().Info("Reporter starting with context. Report interval: %s. Worker pool size: %d.", "1s", uint(1)).Times(1)
 	mockLogger.EXPECT().Info("Worker %d: started.", uint(1)).Times(1)
@@ -270,3 +270,48 @@ func TestReporter_RunWithContext_WorkerPanic(t *testing.T) {
 		t.Fatal("RunWithContext did not complete after worker panic")
 	}
 }
+
+func TestReporter_RunWithContext_Reconfigure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMetrics := NewMockMetricsReporter(ctrl)
+	mockLogger := NewMockReporterLogger(ctrl)
+
+	reporter := NewReporter(mockMetrics, mockLogger, time.Hour, 1)
+
+	sent := make(chan struct{}, 1)
+	mockLogger.EXPECT().Info("Worker pool size changed to %d", uint(3)).Times(1)
+	mockLogger.EXPECT().Info("Report interval changed to %s", 20*time.Millisecond).Times(1)
+	mockLogger.EXPECT().Info("Worker %d: started.", uint(3)).Times(1)
+	mockLogger.EXPECT().Info("Worker %d: stopped.", uint(3)).Times(1)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Info(gomock.Any()).AnyTimes()
+	mockMetrics.EXPECT().SendMetrics().Do(func() {
+		select {
+		case sent <- struct{}{}:
+		default:
+		}
+	}).MinTimes(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reporter.RunWithContext(ctx)
+	}()
+
+	reporter.SetRateLimit(3)
+	reporter.SetReportInterval(20 * time.Millisecond)
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("reporter did not apply new report interval")
+	}
+
+	cancel()
+	<-done
+	assert.Equal(t, uint(3), reporter.rateLimit)
+	assert.Equal(t, 20*time.Millisecond, reporter.reportInterval)
+}
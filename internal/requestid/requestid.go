@@ -0,0 +1,48 @@
+// Package requestid передает идентификатор запроса между агентом, сервером и их логами.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header - заголовок с идентификатором запроса.
+const Header = "X-Request-ID"
+
+// MaxLength - максимальная длина идентификатора, принимаемого от клиента.
+const MaxLength = 128
+
+type contextKey struct{}
+
+// New возвращает случайный идентификатор запроса.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid проверяет идентификатор, полученный от клиента: непустой, не длиннее MaxLength
+// и из печатных ASCII символов, чтобы его можно было без экранирования писать в логи.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithID возвращает контекст с идентификатором запроса.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса из контекста или пустую строку.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	id := New()
+	assert.Len(t, id, 32)
+	assert.True(t, Valid(id))
+	assert.NotEqual(t, id, New())
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "hex", id: "0123456789abcdef", want: true},
+		{name: "uuid", id: "3f2504e0-4f89-11d3-9a0c-0305e82c3301", want: true},
+		{name: "empty", id: "", want: false},
+		{name: "too long", id: strings.Repeat("a", MaxLength+1), want: false},
+		{name: "space", id: "abc def", want: false},
+		{name: "newline", id: "abc\ndef", want: false},
+		{name: "non ascii", id: "запрос", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Valid(tt.id))
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, "abc", FromContext(WithID(context.Background(), "abc")))
+}
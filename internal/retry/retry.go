@@ -1,7 +1,12 @@
 package retry
 
 import (
+	"context"
+	"sync/atomic"
 	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 )
 
 type (
@@ -11,7 +16,20 @@ type (
 
 var retryDelays = []time.Duration{1 * time.Second, 3 * time.Second, 5 * time.Second}
 
+var retryCounter atomic.Pointer[selfmetrics.Counter]
+
+// SetRetryCounter задает счетчик повторных попыток операций.
+func SetRetryCounter(counter *selfmetrics.Counter) {
+	retryCounter.Store(counter)
+}
+
 func WithRetries(op Operation, checker Checker) error {
+	return WithRetriesContext(context.Background(), op, checker)
+}
+
+// WithRetriesContext выполняет операцию с повторными попытками.
+// Повторы пишутся в логгер запроса из контекста, если он есть.
+func WithRetriesContext(ctx context.Context, op Operation, checker Checker) error {
 	var err error
 	var attempt int
 
@@ -26,6 +44,10 @@ func WithRetries(op Operation, checker Checker) error {
 
 	for _, delay := range retryDelays {
 		attempt++
+		retryCounter.Load().Inc()
+		if log, ok := logger.FromContext(ctx); ok {
+			log.Warn("Retrying operation in %s (attempt %d of %d): %v", delay, attempt, len(retryDelays), err)
+		}
 		time.Sleep(delay)
 
 		err = op()
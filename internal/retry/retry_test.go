@@ -1,12 +1,15 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"net"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
@@ -223,3 +226,47 @@ func TestRequestErrorChecker(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRetries_CountsRetries(t *testing.T) {
+	originalDelays := retryDelays
+	defer func() { retryDelays = originalDelays }()
+	retryDelays = []time.Duration{1 * time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}
+
+	counter := selfmetrics.NewRegistry().Counter("retry.attempts")
+	SetRetryCounter(counter)
+	defer SetRetryCounter(nil)
+
+	errRetryable := errors.New("retryable error")
+	attempts := 0
+	op := func() error {
+		attempts++
+		if attempts < 3 {
+			return errRetryable
+		}
+		return nil
+	}
+
+	assert.NoError(t, WithRetries(op, func(err error) bool { return errors.Is(err, errRetryable) }))
+	assert.Equal(t, int64(2), counter.Value())
+}
+
+func TestWithRetriesContext_ContextLogger(t *testing.T) {
+	originalDelays := retryDelays
+	defer func() { retryDelays = originalDelays }()
+	retryDelays = []time.Duration{1 * time.Millisecond}
+
+	log, err := logger.NewZapLogger("error", false)
+	assert.NoError(t, err)
+	ctx := logger.NewContext(context.Background(), log.With("request_id", "abc"))
+
+	errRetryable := errors.New("retryable error")
+	attempts := 0
+	op := func() error {
+		attempts++
+		return errRetryable
+	}
+
+	err = WithRetriesContext(ctx, op, func(err error) bool { return true })
+	assert.ErrorIs(t, err, errRetryable)
+	assert.Equal(t, 2, attempts)
+}
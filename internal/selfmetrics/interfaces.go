@@ -0,0 +1,25 @@
+package selfmetrics
+
+import (
+	"context"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
+
+type Sink interface {
+	UpdateGauge(ctx context.Context, name string, value float64) (float64, error)
+	UpdateCounter(ctx context.Context, name string, value int64) (int64, error)
+	UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error)
+}
+
+type FlushLogger interface {
+	Error(format string, args ...any)
+}
+
+// Проверка для adapter.MetricStorage привела бы к циклу импорта adapter -> limits -> selfmetrics.
+// var _ Sink = (*adapter.MetricStorage)(nil)
+var _ Sink = (*MockSink)(nil)
+
+var _ FlushLogger = (*logger.ZapLogger)(nil)
+var _ FlushLogger = (*MockFlushLogger)(nil)
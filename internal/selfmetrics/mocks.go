@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/selfmetrics/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/selfmetrics/interfaces.go -destination=./internal/selfmetrics/mocks.go -package=selfmetrics
+//
+
+// Package selfmetrics is a generated GoMock package.
+package selfmetrics
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NoobyTheTurtle/metrics/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSink is a mock of Sink interface.
+type MockSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockSinkMockRecorder
+	isgomock struct{}
+}
+
+// MockSinkMockRecorder is the mock recorder for MockSink.
+type MockSinkMockRecorder struct {
+	mock *MockSink
+}
+
+// NewMockSink creates a new mock instance.
+func NewMockSink(ctrl *gomock.Controller) *MockSink {
+	mock := &MockSink{ctrl: ctrl}
+	mock.recorder = &MockSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSink) EXPECT() *MockSinkMockRecorder {
+	return m.recorder
+}
+
+// UpdateCounter mocks base method.
+func (m *MockSink) UpdateCounter(ctx context.Context, name string, value int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCounter", ctx, name, value)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCounter indicates an expected call of UpdateCounter.
+func (mr *MockSinkMockRecorder) UpdateCounter(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCounter", reflect.TypeOf((*MockSink)(nil).UpdateCounter), ctx, name, value)
+}
+
+// UpdateGauge mocks base method.
+func (m *MockSink) UpdateGauge(ctx context.Context, name string, value float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGauge", ctx, name, value)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGauge indicates an expected call of UpdateGauge.
+func (mr *MockSinkMockRecorder) UpdateGauge(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGauge", reflect.TypeOf((*MockSink)(nil).UpdateGauge), ctx, name, value)
+}
+
+// UpdateHistogram mocks base method.
+func (m *MockSink) UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHistogram", ctx, name, value)
+	ret0, _ := ret[0].(model.Histogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHistogram indicates an expected call of UpdateHistogram.
+func (mr *MockSinkMockRecorder) UpdateHistogram(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHistogram", reflect.TypeOf((*MockSink)(nil).UpdateHistogram), ctx, name, value)
+}
+
+// MockFlushLogger is a mock of FlushLogger interface.
+type MockFlushLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlushLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockFlushLoggerMockRecorder is the mock recorder for MockFlushLogger.
+type MockFlushLoggerMockRecorder struct {
+	mock *MockFlushLogger
+}
+
+// NewMockFlushLogger creates a new mock instance.
+func NewMockFlushLogger(ctrl *gomock.Controller) *MockFlushLogger {
+	mock := &MockFlushLogger{ctrl: ctrl}
+	mock.recorder = &MockFlushLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlushLogger) EXPECT() *MockFlushLoggerMockRecorder {
+	return m.recorder
+}
+
+// Error mocks base method.
+func (m *MockFlushLogger) Error(format string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockFlushLoggerMockRecorder) Error(format any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockFlushLogger)(nil).Error), varargs...)
+}
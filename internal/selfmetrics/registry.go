@@ -0,0 +1,277 @@
+// Package selfmetrics собирает метрики самого сервера и периодически записывает
+// их в хранилище в зарезервированном пространстве имен Namespace,
+// чтобы они были доступны через обычные API чтения.
+package selfmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
+
+// Namespace - префикс имен метрик сервера.
+const Namespace = "server."
+
+// DefaultFlushInterval - период записи метрик сервера в хранилище.
+const DefaultFlushInterval = 10 * time.Second
+
+// ErrReservedName возвращается при попытке клиента записать метрику в пространство имен сервера.
+var ErrReservedName = errors.New("metric name is reserved for server metrics")
+
+// IsReserved проверяет, принадлежит ли имя метрики пространству имен сервера.
+func IsReserved(name string) bool {
+	return strings.HasPrefix(name, Namespace)
+}
+
+type internalKey struct{}
+
+// Internal сообщает, что запись выполняется самим реестром при записи метрик сервера.
+// Только такие записи могут использовать пространство имен Namespace.
+func Internal(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalKey{}).(bool)
+	return internal
+}
+
+// Counter - монотонно растущий счетчик. Методы nil счетчика ничего не делают,
+// поэтому компоненты могут работать без реестра метрик.
+type Counter struct {
+	value   atomic.Int64
+	flushed int64
+}
+
+// Inc увеличивает счетчик на единицу.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add увеличивает счетчик на delta.
+func (c *Counter) Add(delta int64) {
+	if c == nil {
+		return
+	}
+	c.value.Add(delta)
+}
+
+// Value возвращает текущее значение счетчика.
+func (c *Counter) Value() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.value.Load()
+}
+
+// Gauge - значение, которое может как расти, так и уменьшаться.
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set устанавливает значение.
+func (g *Gauge) Set(value float64) {
+	if g == nil {
+		return
+	}
+	g.bits.Store(math.Float64bits(value))
+}
+
+// Value возвращает текущее значение.
+func (g *Gauge) Value() float64 {
+	if g == nil {
+		return 0
+	}
+	return math.Float64frombits(g.bits.Load())
+}
+
+// Histogram накапливает распределение значений между записями в хранилище.
+type Histogram struct {
+	mu      sync.Mutex
+	pending model.Histogram
+}
+
+// Observe добавляет значение в гистограмму.
+func (h *Histogram) Observe(value float64) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending.Observe(value)
+}
+
+// Since добавляет в гистограмму время в секундах, прошедшее с start.
+func (h *Histogram) Since(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Count возвращает число значений, добавленных с прошлой записи в хранилище.
+func (h *Histogram) Count() uint64 {
+	if h == nil {
+		return 0
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.pending.Count
+}
+
+// take возвращает значения, накопленные с прошлой записи, и обнуляет их.
+func (h *Histogram) take() model.Histogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	taken := h.pending
+	h.pending = model.NewHistogram(taken.Bounds)
+	return taken
+}
+
+// restore возвращает незаписанные значения в гистограмму.
+func (h *Histogram) restore(taken model.Histogram) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_ = h.pending.Merge(taken)
+}
+
+// Registry хранит метрики сервера по именам без префикса Namespace.
+type Registry struct {
+	flushMu    sync.Mutex
+	mu         sync.Mutex
+	counters   map[string]*Counter
+	gauges     map[string]*Gauge
+	histograms map[string]*Histogram
+}
+
+// NewRegistry создает пустой реестр метрик сервера.
+func NewRegistry() *Registry {
+	return &Registry{
+		counters:   make(map[string]*Counter),
+		gauges:     make(map[string]*Gauge),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Counter возвращает счетчик с заданным именем, создавая его при первом обращении.
+// Для nil реестра возвращается nil счетчик.
+func (r *Registry) Counter(name string) *Counter {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.counters[name]
+	if !ok {
+		c = &Counter{}
+		r.counters[name] = c
+	}
+	return c
+}
+
+// Gauge возвращает gauge с заданным именем, создавая его при первом обращении.
+// Для nil реестра возвращается nil gauge.
+func (r *Registry) Gauge(name string) *Gauge {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.gauges[name]
+	if !ok {
+		g = &Gauge{}
+		r.gauges[name] = g
+	}
+	return g
+}
+
+// Histogram возвращает гистограмму с заданным именем, создавая ее с границами
+// корзин bounds при первом обращении. Для nil реестра возвращается nil гистограмма.
+func (r *Registry) Histogram(name string, bounds []float64) *Histogram {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.histograms[name]
+	if !ok {
+		h = &Histogram{pending: model.NewHistogram(bounds)}
+		r.histograms[name] = h
+	}
+	return h
+}
+
+// Flush записывает метрики в хранилище. Счетчики и гистограммы записываются
+// приращением с момента прошлой записи, gauge - текущим значением.
+// Запись идет без блокировки реестра: хранилище само может обновлять метрики сервера.
+func (r *Registry) Flush(ctx context.Context, sink Sink) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	counters := maps.Clone(r.counters)
+	gauges := maps.Clone(r.gauges)
+	histograms := maps.Clone(r.histograms)
+	r.mu.Unlock()
+
+	ctx = context.WithValue(ctx, internalKey{}, true)
+
+	for name, c := range counters {
+		value := c.Value()
+		delta := value - c.flushed
+		if delta == 0 {
+			continue
+		}
+		if _, err := sink.UpdateCounter(ctx, Namespace+name, delta); err != nil {
+			return fmt.Errorf("selfmetrics.Registry.Flush: failed to update counter '%s': %w", name, err)
+		}
+		c.flushed = value
+	}
+
+	for name, g := range gauges {
+		if _, err := sink.UpdateGauge(ctx, Namespace+name, g.Value()); err != nil {
+			return fmt.Errorf("selfmetrics.Registry.Flush: failed to update gauge '%s': %w", name, err)
+		}
+	}
+
+	for name, h := range histograms {
+		delta := h.take()
+		if delta.Count == 0 {
+			continue
+		}
+		if _, err := sink.UpdateHistogram(ctx, Namespace+name, delta); err != nil {
+			h.restore(delta)
+			return fmt.Errorf("selfmetrics.Registry.Flush: failed to update histogram '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Run периодически записывает метрики в хранилище до отмены контекста.
+func (r *Registry) Run(ctx context.Context, sink Sink, interval time.Duration, logger FlushLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CollectRuntime()
+			if err := r.Flush(ctx, sink); err != nil {
+				logger.Error("Failed to flush server metrics: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package selfmetrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegistry_Flush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r := NewRegistry()
+	r.Counter("limits.rate_limited").Add(3)
+	r.Gauge("limits.names").Set(42)
+	assert.Same(t, r.Counter("limits.rate_limited"), r.Counter("limits.rate_limited"))
+
+	sink := NewMockSink(ctrl)
+	sink.EXPECT().UpdateCounter(gomock.Any(), "server.limits.rate_limited", int64(3)).Return(int64(3), nil)
+	sink.EXPECT().UpdateGauge(gomock.Any(), "server.limits.names", 42.0).Return(42.0, nil).Times(2)
+	assert.NoError(t, r.Flush(context.Background(), sink))
+
+	// неизменившиеся счетчики не записываются повторно
+	assert.NoError(t, r.Flush(context.Background(), sink))
+
+	r.Counter("limits.rate_limited").Inc()
+	sink.EXPECT().UpdateCounter(gomock.Any(), "server.limits.rate_limited", int64(1)).Return(int64(0), errors.New("storage error"))
+	assert.ErrorContains(t, r.Flush(context.Background(), sink), "storage error")
+
+	sink.EXPECT().UpdateCounter(gomock.Any(), "server.limits.rate_limited", int64(1)).Return(int64(4), nil)
+	sink.EXPECT().UpdateGauge(gomock.Any(), "server.limits.names", 42.0).Return(42.0, nil)
+	assert.NoError(t, r.Flush(context.Background(), sink), "failed delta is retried on the next flush")
+}
+
+func TestRegistry_FlushHistogram(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r := NewRegistry()
+	h := r.Histogram("http.duration", []float64{0.1, 1})
+	assert.Same(t, h, r.Histogram("http.duration", nil))
+	h.Observe(0.05)
+	h.Observe(2)
+
+	expected := model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{1, 0, 1}, Count: 2, Sum: 2.05}
+
+	sink := NewMockSink(ctrl)
+	sink.EXPECT().UpdateHistogram(gomock.Any(), "server.http.duration", expected).
+		DoAndReturn(func(ctx context.Context, _ string, value model.Histogram) (model.Histogram, error) {
+			assert.True(t, Internal(ctx), "flush marks writes as internal")
+			return value, errors.New("storage error")
+		})
+	assert.ErrorContains(t, r.Flush(context.Background(), sink), "storage error")
+
+	sink.EXPECT().UpdateHistogram(gomock.Any(), "server.http.duration", expected).Return(expected, nil)
+	assert.NoError(t, r.Flush(context.Background(), sink), "failed delta is retried on the next flush")
+
+	// пустые гистограммы не записываются
+	assert.NoError(t, r.Flush(context.Background(), sink))
+	assert.False(t, Internal(context.Background()))
+}
+
+func TestRegistry_CollectRuntime(t *testing.T) {
+	r := NewRegistry()
+	r.CollectRuntime()
+
+	assert.Positive(t, r.Gauge("runtime.goroutines").Value())
+	assert.Positive(t, r.Gauge("runtime.heap_alloc_bytes").Value())
+	assert.Positive(t, r.Gauge("runtime.sys_bytes").Value())
+}
+
+func TestNilMetrics(t *testing.T) {
+	var r *Registry
+
+	c := r.Counter("any")
+	c.Inc()
+	assert.Equal(t, int64(0), c.Value())
+
+	g := r.Gauge("any")
+	g.Set(1)
+	assert.Equal(t, 0.0, g.Value())
+
+	h := r.Histogram("any", nil)
+	h.Observe(1)
+	assert.Nil(t, h)
+
+	r.CollectRuntime()
+}
+
+func TestIsReserved(t *testing.T) {
+	assert.True(t, IsReserved("server.limits.names"))
+	assert.False(t, IsReserved("Alloc"))
+	assert.False(t, IsReserved("servers"))
+}
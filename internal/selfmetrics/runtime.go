@@ -0,0 +1,23 @@
+package selfmetrics
+
+import (
+	"runtime"
+	"time"
+)
+
+// CollectRuntime обновляет gauge с числом горутин и статистикой памяти и сборщика мусора.
+func (r *Registry) CollectRuntime() {
+	if r == nil {
+		return
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	r.Gauge("runtime.goroutines").Set(float64(runtime.NumGoroutine()))
+	r.Gauge("runtime.heap_alloc_bytes").Set(float64(memStats.HeapAlloc))
+	r.Gauge("runtime.heap_objects").Set(float64(memStats.HeapObjects))
+	r.Gauge("runtime.sys_bytes").Set(float64(memStats.Sys))
+	r.Gauge("runtime.gc_count").Set(float64(memStats.NumGC))
+	r.Gauge("runtime.gc_pause_total_seconds").Set(time.Duration(memStats.PauseTotalNs).Seconds())
+}
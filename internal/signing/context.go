@@ -0,0 +1,16 @@
+package signing
+
+import "context"
+
+type contextKey struct{}
+
+// WithAgentID возвращает контекст с идентификатором агента, подпись которого проверена.
+func WithAgentID(ctx context.Context, agentID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, agentID)
+}
+
+// AgentIDFromContext возвращает идентификатор агента с проверенной подписью.
+func AgentIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
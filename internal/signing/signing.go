@@ -0,0 +1,191 @@
+// Package signing реализует подпись запросов агентов ключами Ed25519.
+// Каждый агент подписывает запросы своим приватным ключом, а сервер проверяет
+// подпись по реестру разрешенных публичных ключей, индексированному идентификатором агента.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Заголовки подписанного запроса
+const (
+	AgentIDHeader   = "X-Agent-ID"
+	SignatureHeader = "X-Signature"
+)
+
+var (
+	ErrUnknownAgent     = errors.New("unknown agent")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrDuplicateAgent   = errors.New("duplicate agent id")
+	ErrEmptyAgentID     = errors.New("empty agent id")
+)
+
+// Signer подписывает данные приватным ключом агента.
+type Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+// LoadSigner загружает приватный ключ Ed25519 из PEM файла.
+func LoadSigner(path string) (*Signer, error) {
+	key, err := readPEMKey(path, "PRIVATE KEY", x509.ParsePKCS8PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing.LoadSigner: %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing.LoadSigner: key in '%s' is not an Ed25519 private key", path)
+	}
+
+	return &Signer{privateKey: privateKey}, nil
+}
+
+// NewSigner создает подписчика из приватного ключа.
+func NewSigner(privateKey ed25519.PrivateKey) *Signer {
+	return &Signer{privateKey: privateKey}
+}
+
+// Sign возвращает подпись данных в кодировке base64.
+func (s *Signer) Sign(data []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, data)), nil
+}
+
+// Agent описывает агента и путь к его публичному ключу.
+type Agent struct {
+	ID        string `json:"id"`
+	PublicKey string `json:"public_key"`
+}
+
+type fileConfig struct {
+	Agents []Agent `json:"agents"`
+}
+
+// Registry хранит публичные ключи агентов.
+type Registry struct {
+	mu   sync.RWMutex
+	path string
+	keys map[string]ed25519.PublicKey
+}
+
+// NewRegistry создает реестр из публичных ключей, индексированных идентификатором агента.
+func NewRegistry(keys map[string]ed25519.PublicKey) *Registry {
+	return &Registry{keys: keys}
+}
+
+// LoadRegistry загружает реестр публичных ключей агентов из JSON файла.
+func LoadRegistry(path string) (*Registry, error) {
+	keys, err := readRegistry(path)
+	if err != nil {
+		return nil, fmt.Errorf("signing.LoadRegistry: %w", err)
+	}
+
+	return &Registry{path: path, keys: keys}, nil
+}
+
+// Reload перечитывает файл реестра и файлы публичных ключей.
+// При ошибке текущее состояние реестра не меняется.
+func (r *Registry) Reload() error {
+	if r.path == "" {
+		return nil
+	}
+
+	keys, err := readRegistry(r.path)
+	if err != nil {
+		return fmt.Errorf("signing.Registry.Reload: %w", err)
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Verify проверяет подпись данных в кодировке base64 ключом агента agentID.
+func (r *Registry) Verify(agentID string, data []byte, signature string) error {
+	r.mu.RLock()
+	key, ok := r.keys[agentID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrUnknownAgent, agentID)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature: %v", ErrInvalidSignature, err)
+	}
+
+	if !ed25519.Verify(key, data, sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func readRegistry(path string) (map[string]ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent keys file '%s': %w", path, err)
+	}
+
+	var cfg fileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse agent keys file '%s': %w", path, err)
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(cfg.Agents))
+	for _, agent := range cfg.Agents {
+		if agent.ID == "" {
+			return nil, ErrEmptyAgentID
+		}
+		if _, exists := keys[agent.ID]; exists {
+			return nil, fmt.Errorf("%w: '%s'", ErrDuplicateAgent, agent.ID)
+		}
+
+		key, err := readPEMKey(agent.PublicKey, "PUBLIC KEY", x509.ParsePKIXPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s': %w", agent.ID, err)
+		}
+
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("agent '%s': key in '%s' is not an Ed25519 public key", agent.ID, agent.PublicKey)
+		}
+
+		keys[agent.ID] = publicKey
+	}
+
+	return keys, nil
+}
+
+func readPEMKey(path, blockType string, parse func([]byte) (any, error)) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file '%s': %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block from file '%s'", path)
+	}
+
+	if block.Type != blockType {
+		return nil, fmt.Errorf("expected %s in '%s', got %s", blockType, path, block.Type)
+	}
+
+	key, err := parse(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key from '%s': %w", path, err)
+	}
+
+	return key, nil
+}
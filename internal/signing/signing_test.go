@@ -0,0 +1,172 @@
+package signing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NoobyTheTurtle/metrics/internal/cryptoutil"
+)
+
+func generateAgent(t *testing.T, dir, id string) (*Signer, string) {
+	t.Helper()
+
+	privateKeyPath := filepath.Join(dir, id+".pem")
+	publicKeyPath := filepath.Join(dir, id+".pub")
+	require.NoError(t, cryptoutil.GenerateEd25519KeyPair(privateKeyPath, publicKeyPath))
+
+	signer, err := LoadSigner(privateKeyPath)
+	require.NoError(t, err)
+
+	return signer, publicKeyPath
+}
+
+func writeRegistry(t *testing.T, path string, agents map[string]string) {
+	t.Helper()
+
+	var cfg fileConfig
+	for id, key := range agents {
+		cfg.Agents = append(cfg.Agents, Agent{ID: id, PublicKey: key})
+	}
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestRegistry_Verify(t *testing.T) {
+	dir := t.TempDir()
+	signer1, pub1 := generateAgent(t, dir, "agent-1")
+	signer2, pub2 := generateAgent(t, dir, "agent-2")
+
+	path := filepath.Join(dir, "agents.json")
+	writeRegistry(t, path, map[string]string{"agent-1": pub1, "agent-2": pub2})
+
+	registry, err := LoadRegistry(path)
+	require.NoError(t, err)
+
+	data := []byte(`[{"id":"test","type":"gauge","value":1}]`)
+	sig1, err := signer1.Sign(data)
+	require.NoError(t, err)
+	sig2, err := signer2.Sign(data)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		agentID     string
+		data        []byte
+		signature   string
+		expectedErr error
+	}{
+		{name: "valid signature", agentID: "agent-1", data: data, signature: sig1},
+		{name: "another agent", agentID: "agent-2", data: data, signature: sig2},
+		{name: "signature of another agent", agentID: "agent-1", data: data, signature: sig2, expectedErr: ErrInvalidSignature},
+		{name: "tampered data", agentID: "agent-1", data: []byte("tampered"), signature: sig1, expectedErr: ErrInvalidSignature},
+		{name: "malformed signature", agentID: "agent-1", data: data, signature: "not base64!", expectedErr: ErrInvalidSignature},
+		{name: "unknown agent", agentID: "agent-3", data: data, signature: sig1, expectedErr: ErrUnknownAgent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Verify(tt.agentID, tt.data, tt.signature)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	dir := t.TempDir()
+	signer1, pub1 := generateAgent(t, dir, "agent-1")
+	signer2, pub2 := generateAgent(t, dir, "agent-2")
+
+	path := filepath.Join(dir, "agents.json")
+	writeRegistry(t, path, map[string]string{"agent-1": pub1})
+
+	registry, err := LoadRegistry(path)
+	require.NoError(t, err)
+
+	data := []byte("payload")
+	sig2, err := signer2.Sign(data)
+	require.NoError(t, err)
+	assert.ErrorIs(t, registry.Verify("agent-2", data, sig2), ErrUnknownAgent)
+
+	writeRegistry(t, path, map[string]string{"agent-2": pub2})
+	require.NoError(t, registry.Reload())
+	assert.NoError(t, registry.Verify("agent-2", data, sig2))
+
+	sig1, err := signer1.Sign(data)
+	require.NoError(t, err)
+	assert.ErrorIs(t, registry.Verify("agent-1", data, sig1), ErrUnknownAgent, "revoked agent must be rejected")
+
+	writeRegistry(t, path, map[string]string{"agent-3": filepath.Join(dir, "missing.pub")})
+	assert.Error(t, registry.Reload())
+	assert.NoError(t, registry.Verify("agent-2", data, sig2), "failed reload must keep previous keys")
+}
+
+func TestLoadRegistry_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, pub := generateAgent(t, dir, "agent-1")
+
+	tests := []struct {
+		name     string
+		content  string
+		errorMsg string
+	}{
+		{name: "malformed json", content: `{`, errorMsg: "failed to parse agent keys file"},
+		{name: "empty agent id", content: fmt.Sprintf(`{"agents":[{"id":"","public_key":%q}]}`, pub), errorMsg: ErrEmptyAgentID.Error()},
+		{
+			name:     "duplicate agent id",
+			content:  fmt.Sprintf(`{"agents":[{"id":"a","public_key":%q},{"id":"a","public_key":%q}]}`, pub, pub),
+			errorMsg: ErrDuplicateAgent.Error(),
+		},
+		{
+			name:     "private key instead of public",
+			content:  fmt.Sprintf(`{"agents":[{"id":"a","public_key":%q}]}`, filepath.Join(dir, "agent-1.pem")),
+			errorMsg: "expected PUBLIC KEY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "agents.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			_, err := LoadRegistry(path)
+			assert.ErrorContains(t, err, tt.errorMsg)
+		})
+	}
+
+	_, err := LoadRegistry("/path/that/does/not/exist.json")
+	assert.ErrorContains(t, err, "failed to read agent keys file")
+}
+
+func TestLoadSigner_Errors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, cryptoutil.GenerateKeyPair(filepath.Join(dir, "rsa.pem"), filepath.Join(dir, "rsa.pub"), 2048))
+
+	_, err := LoadSigner(filepath.Join(dir, "rsa.pem"))
+	assert.ErrorContains(t, err, "is not an Ed25519 private key")
+
+	_, err = LoadSigner(filepath.Join(dir, "missing.pem"))
+	assert.ErrorContains(t, err, "failed to read key file")
+}
+
+func TestContext(t *testing.T) {
+	_, ok := AgentIDFromContext(context.Background())
+	assert.False(t, ok)
+
+	id, ok := AgentIDFromContext(WithAgentID(context.Background(), "agent-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "agent-1", id)
+}
@@ -4,12 +4,28 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
 	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
 )
 
 func (ms *MetricStorage) UpdateMetricsBatch(ctx context.Context, metrics model.Metrics) error {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.UpdateMetricsBatch", batchAttributes(len(metrics)))
+	defer span.End()
+
+	if err := ms.observeBatch(ctx, metrics); err != nil {
+		return fmt.Errorf("adapter.MetricStorage.UpdateMetricsBatch: %w", err)
+	}
+
 	if ms.dbStorage == nil {
-		return updateMetricsBatch(ctx, ms.storage, metrics)
+		events, err := updateMetricsBatch(ctx, ms.storage, metrics)
+		if err != nil {
+			return err
+		}
+
+		ms.publish(ctx, events...)
+		return nil
 	}
 
 	tx, err := ms.dbStorage.BeginTransaction(ctx)
@@ -17,14 +33,57 @@ func (ms *MetricStorage) UpdateMetricsBatch(ctx context.Context, metrics model.M
 		return fmt.Errorf("adapter.MetricStorage.UpdateMetricsBatch: failed to begin transaction: %w", err)
 	}
 
-	if err := updateMetricsBatch(ctx, tx, metrics); err != nil {
+	events, err := updateMetricsBatch(ctx, tx, metrics)
+	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.ErrorContext(ctx, "Failed to update metrics batch before rollback: %v", err)
 			return fmt.Errorf("adapter.MetricStorage.UpdateMetricsBatch: failed to rollback transaction: %w", rollbackErr)
 		}
 		return fmt.Errorf("adapter.MetricStorage.UpdateMetricsBatch: failed to update metrics batch: %w", err)
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	ms.publish(ctx, events...)
+
+	return nil
+}
+
+// observeBatch закрепляет типы метрик пакета до начала записи, чтобы пакет
+// с конфликтом типов отклонялся целиком. Сначала проверяется весь пакет,
+// и только затем имена учитываются в лимите и типы закрепляются в реестре.
+func (ms *MetricStorage) observeBatch(ctx context.Context, metrics model.Metrics) error {
+	types := make(map[string]model.MetricType, len(metrics))
+	for _, metric := range metrics {
+		if mType, ok := types[metric.ID]; ok && mType != metric.MType {
+			return fmt.Errorf("%w: metric '%s' is sent as both %s and %s", metadata.ErrTypeConflict, metric.ID, mType, metric.MType)
+		}
+		types[metric.ID] = metric.MType
+
+		if err := ms.checkType(ctx, metric.ID, metric.MType); err != nil {
+			return err
+		}
+	}
+
+	if ms.cardinality != nil {
+		names := make([]string, 0, len(types))
+		for name := range types {
+			names = append(names, name)
+		}
+		if err := ms.cardinality.Admit(ctx, names...); err != nil {
+			return err
+		}
+	}
+
+	for _, metric := range metrics {
+		if err := ms.commitType(ctx, metric.ID, metric.MType); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 type BatchStorage interface {
@@ -32,32 +91,54 @@ type BatchStorage interface {
 	Getter
 }
 
-func updateMetricsBatch(ctx context.Context, storage BatchStorage, metrics model.Metrics) error {
+// updateMetricsBatch применяет пакет обновлений и возвращает события об изменениях,
+// которые публикуются только после успешной фиксации всего пакета.
+func updateMetricsBatch(ctx context.Context, storage BatchStorage, metrics model.Metrics) ([]notify.Event, error) {
+	events := make([]notify.Event, 0, len(metrics))
+
 	for _, metric := range metrics {
 		switch metric.MType {
 		case model.GaugeType:
-			key := addPrefix(metric.ID, GaugePrefix)
+			key := metricKey(ctx, metric.ID, GaugePrefix)
 
 			if metric.Value == nil {
-				return fmt.Errorf("adapter.updateMetricsBatch: gauge metric '%s' has nil value", metric.ID)
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: gauge metric '%s' has nil value", metric.ID)
 			}
 
 			if _, err := storage.Set(ctx, key, *metric.Value); err != nil {
-				return fmt.Errorf("adapter.updateMetricsBatch: failed to set gauge metric '%s': %w", metric.ID, err)
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: failed to set gauge metric '%s': %w", metric.ID, err)
 			}
+
+			events = append(events, gaugeEvent(ctx, metric.ID, *metric.Value))
 		case model.CounterType:
 			if metric.Delta == nil {
-				return fmt.Errorf("adapter.updateMetricsBatch: counter metric '%s' has nil delta", metric.ID)
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: counter metric '%s' has nil delta", metric.ID)
 			}
 
-			_, err := updateCounter(ctx, storage, metric.ID, *metric.Delta)
+			value, err := updateCounter(ctx, storage, metric.ID, *metric.Delta)
 			if err != nil {
-				return fmt.Errorf("adapter.updateMetricsBatch: failed to update counter metric '%s': %w", metric.ID, err)
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: failed to update counter metric '%s': %w", metric.ID, err)
+			}
+
+			events = append(events, counterEvent(ctx, metric.ID, value))
+		case model.HistogramType:
+			if metric.Histogram == nil {
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: histogram metric '%s' has nil histogram", metric.ID)
 			}
+			if err := metric.Histogram.Validate(); err != nil {
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: histogram metric '%s': %w", metric.ID, err)
+			}
+
+			value, err := updateHistogram(ctx, storage, metric.ID, *metric.Histogram)
+			if err != nil {
+				return nil, fmt.Errorf("adapter.updateMetricsBatch: failed to update histogram metric '%s': %w", metric.ID, err)
+			}
+
+			events = append(events, histogramEvent(ctx, metric.ID, value))
 		default:
-			return fmt.Errorf("adapter.updateMetricsBatch: unknown metric type '%s' for metric ID '%s'", metric.MType, metric.ID)
+			return nil, fmt.Errorf("adapter.updateMetricsBatch: unknown metric type '%s' for metric ID '%s'", metric.MType, metric.ID)
 		}
 	}
 
-	return nil
+	return events, nil
 }
@@ -197,7 +197,7 @@ func TestUpdateMetricsBatch(t *testing.T) {
 				}
 			}
 
-			err := updateMetricsBatch(ctx, mockStorage, tt.metrics)
+			_, err := updateMetricsBatch(ctx, mockStorage, tt.metrics)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -237,7 +237,7 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{storage: mockStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockStorage.EXPECT().Set(ctx, addPrefix("gauge1", GaugePrefix), 1.23).Return(1.23, nil)
+				mockStorage.EXPECT().Set(gomock.Any(), addPrefix("gauge1", GaugePrefix), 1.23).Return(1.23, nil)
 			},
 			expectedError: false,
 		},
@@ -246,7 +246,7 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{storage: mockStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockStorage.EXPECT().Set(ctx, addPrefix("gauge1", GaugePrefix), 1.23).Return(nil, errors.New("mem error"))
+				mockStorage.EXPECT().Set(gomock.Any(), addPrefix("gauge1", GaugePrefix), 1.23).Return(nil, errors.New("mem error"))
 			},
 			expectedError: true,
 			errContains:   "mem error",
@@ -256,8 +256,8 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{dbStorage: mockDBStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockDBStorage.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
-				mockTx.EXPECT().Set(ctx, addPrefix("gauge1", GaugePrefix), 1.23).Return(1.23, nil)
+				mockDBStorage.EXPECT().BeginTransaction(gomock.Any()).Return(mockTx, nil)
+				mockTx.EXPECT().Set(gomock.Any(), addPrefix("gauge1", GaugePrefix), 1.23).Return(1.23, nil)
 				mockTx.EXPECT().Commit().Return(nil)
 			},
 			expectedError: false,
@@ -267,7 +267,7 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{dbStorage: mockDBStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockDBStorage.EXPECT().BeginTransaction(ctx).Return(nil, errors.New("begin tx error"))
+				mockDBStorage.EXPECT().BeginTransaction(gomock.Any()).Return(nil, errors.New("begin tx error"))
 			},
 			expectedError: true,
 			errContains:   "failed to begin transaction",
@@ -277,8 +277,8 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{dbStorage: mockDBStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockDBStorage.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
-				mockTx.EXPECT().Set(ctx, addPrefix("gauge1", GaugePrefix), 1.23).Return(nil, errors.New("update error"))
+				mockDBStorage.EXPECT().BeginTransaction(gomock.Any()).Return(mockTx, nil)
+				mockTx.EXPECT().Set(gomock.Any(), addPrefix("gauge1", GaugePrefix), 1.23).Return(nil, errors.New("update error"))
 				mockTx.EXPECT().Rollback().Return(nil)
 			},
 			expectedError: true,
@@ -289,8 +289,8 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{dbStorage: mockDBStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockDBStorage.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
-				mockTx.EXPECT().Set(ctx, addPrefix("gauge1", GaugePrefix), 1.23).Return(nil, errors.New("update error"))
+				mockDBStorage.EXPECT().BeginTransaction(gomock.Any()).Return(mockTx, nil)
+				mockTx.EXPECT().Set(gomock.Any(), addPrefix("gauge1", GaugePrefix), 1.23).Return(nil, errors.New("update error"))
 				mockTx.EXPECT().Rollback().Return(errors.New("rollback error"))
 			},
 			expectedError: true,
@@ -301,8 +301,8 @@ func TestMetricStorage_UpdateMetricsBatch(t *testing.T) {
 			ms:      &MetricStorage{dbStorage: mockDBStorage},
 			metrics: mockMetrics,
 			mockSetup: func() {
-				mockDBStorage.EXPECT().BeginTransaction(ctx).Return(mockTx, nil)
-				mockTx.EXPECT().Set(ctx, addPrefix("gauge1", GaugePrefix), 1.23).Return(1.23, nil)
+				mockDBStorage.EXPECT().BeginTransaction(gomock.Any()).Return(mockTx, nil)
+				mockTx.EXPECT().Set(gomock.Any(), addPrefix("gauge1", GaugePrefix), 1.23).Return(1.23, nil)
 				mockTx.EXPECT().Commit().Return(errors.New("commit error"))
 			},
 			expectedError: true,
@@ -1,6 +1,10 @@
 package adapter
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
 
 func convertToFloat64(value any) (float64, bool) {
 	switch v := value.(type) {
@@ -51,3 +55,26 @@ func convertToInt64(value any) (int64, bool) {
 		return i, true
 	}
 }
+
+// convertToHistogram приводит значение хранилища к гистограмме.
+// Из файла гистограмма загружается как map[string]any, поэтому такие значения
+// преобразуются через JSON.
+func convertToHistogram(value any) (model.Histogram, bool) {
+	switch v := value.(type) {
+	case model.Histogram:
+		return v.Clone(), true
+	default:
+		jsonData, err := json.Marshal(value)
+		if err != nil {
+			return model.Histogram{}, false
+		}
+		var h model.Histogram
+		if err := json.Unmarshal(jsonData, &h); err != nil {
+			return model.Histogram{}, false
+		}
+		if h.Validate() != nil {
+			return model.Histogram{}, false
+		}
+		return h, true
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -114,3 +115,50 @@ func TestConvertToInt64(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToHistogram(t *testing.T) {
+	expected := model.Histogram{Bounds: []float64{1}, Counts: []uint64{2, 1}, Count: 3, Sum: 4.5}
+
+	tests := []struct {
+		name       string
+		value      any
+		expectedOk bool
+	}{
+		{
+			name:       "histogram value",
+			value:      expected,
+			expectedOk: true,
+		},
+		{
+			name: "value loaded from file",
+			value: map[string]any{
+				"bounds": []any{float64(1)},
+				"counts": []any{float64(2), float64(1)},
+				"count":  float64(3),
+				"sum":    4.5,
+			},
+			expectedOk: true,
+		},
+		{
+			name:       "invalid histogram",
+			value:      map[string]any{"bounds": []any{float64(1)}},
+			expectedOk: false,
+		},
+		{
+			name:       "number",
+			value:      float64(42),
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := convertToHistogram(tt.value)
+
+			assert.Equal(t, tt.expectedOk, ok)
+			if tt.expectedOk {
+				assert.Equal(t, expected, value)
+			}
+		})
+	}
+}
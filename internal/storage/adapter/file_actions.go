@@ -3,6 +3,9 @@ package adapter
 import "context"
 
 func (ms *MetricStorage) SaveToFile(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.SaveToFile")
+	defer span.End()
+
 	if ms.fileStorage == nil {
 		return nil
 	}
@@ -10,6 +13,9 @@ func (ms *MetricStorage) SaveToFile(ctx context.Context) error {
 }
 
 func (ms *MetricStorage) LoadFromFile(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.LoadFromFile")
+	defer span.End()
+
 	if ms.fileStorage == nil {
 		return nil
 	}
@@ -0,0 +1,122 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+)
+
+func (ms *MetricStorage) GetHistogram(ctx context.Context, name string) (model.Histogram, bool) {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.GetHistogram", metricAttributes(name))
+	defer span.End()
+
+	key := metricKey(ctx, name, HistogramPrefix)
+	value, exists := ms.storage.Get(ctx, key)
+	if !exists {
+		return model.Histogram{}, false
+	}
+
+	return convertToHistogram(value)
+}
+
+// UpdateHistogram добавляет значения гистограммы к сохраненной: корзины,
+// общее число и сумма значений складываются. Границы корзин должны совпадать
+// с сохраненными, иначе возвращается model.ErrBucketMismatch.
+func (ms *MetricStorage) UpdateHistogram(ctx context.Context, name string, value model.Histogram) (model.Histogram, error) {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.UpdateHistogram", metricAttributes(name))
+	defer span.End()
+
+	if err := value.Validate(); err != nil {
+		return model.Histogram{}, fmt.Errorf("adapter.MetricStorage.UpdateHistogram: %w", err)
+	}
+
+	if err := ms.observe(ctx, name, model.HistogramType); err != nil {
+		return model.Histogram{}, fmt.Errorf("adapter.MetricStorage.UpdateHistogram: %w", err)
+	}
+
+	if ms.dbStorage == nil {
+		value, err := updateHistogram(ctx, ms.storage, name, value)
+		if err != nil {
+			return model.Histogram{}, err
+		}
+
+		ms.publish(ctx, histogramEvent(ctx, name, value))
+		return value, nil
+	}
+
+	tx, err := ms.dbStorage.BeginTransaction(ctx)
+	if err != nil {
+		return model.Histogram{}, fmt.Errorf("adapter.MetricStorage.UpdateHistogram: failed to begin transaction: %w", err)
+	}
+
+	value, err = updateHistogram(ctx, tx, name, value)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.ErrorContext(ctx, "Failed to update histogram '%s' before rollback: %v", name, err)
+			return model.Histogram{}, fmt.Errorf("adapter.MetricStorage.UpdateHistogram: failed to rollback transaction: %w", rollbackErr)
+		}
+		return model.Histogram{}, fmt.Errorf("adapter.MetricStorage.UpdateHistogram: failed to update histogram metric during transaction for '%s': %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Histogram{}, fmt.Errorf("adapter.MetricStorage.UpdateHistogram: failed to commit transaction: %w", err)
+	}
+
+	ms.publish(ctx, histogramEvent(ctx, name, value))
+
+	return value, nil
+}
+
+func updateHistogram(ctx context.Context, storage UpdateCounterStorage, name string, value model.Histogram) (model.Histogram, error) {
+	key := metricKey(ctx, name, HistogramPrefix)
+
+	valueToSet := value.Clone()
+	if currentValue, exists := storage.Get(ctx, key); exists {
+		current, ok := convertToHistogram(currentValue)
+		if !ok {
+			return model.Histogram{}, fmt.Errorf("adapter.updateHistogram: failed to convert current value '%v' to histogram for key '%s'", currentValue, key)
+		}
+
+		if err := current.Merge(value); err != nil {
+			return model.Histogram{}, fmt.Errorf("adapter.updateHistogram: failed to merge histogram for key '%s': %w", key, err)
+		}
+		valueToSet = current
+	}
+
+	newValue, err := storage.Set(ctx, key, valueToSet)
+	if err != nil {
+		return model.Histogram{}, fmt.Errorf("adapter.updateHistogram: failed to set histogram metric for key '%s': %w", key, err)
+	}
+
+	newHistogram, ok := convertToHistogram(newValue)
+	if !ok {
+		return model.Histogram{}, fmt.Errorf("adapter.updateHistogram: failed to convert newValue '%v' to histogram for key '%s'", newValue, key)
+	}
+
+	return newHistogram, nil
+}
+
+func (ms *MetricStorage) GetAllHistograms(ctx context.Context) (map[string]model.Histogram, error) {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.GetAllHistograms")
+	defer span.End()
+
+	allMetrics, err := ms.storage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("adapter.MetricStorage.GetAllHistograms: failed to get all histograms: %w", err)
+	}
+	allMetrics = scopeMetrics(ctx, allMetrics)
+	histograms := make(map[string]model.Histogram)
+
+	for key, value := range allMetrics {
+		if hasPrefix(key, HistogramPrefix) {
+			if histogram, ok := convertToHistogram(value); ok {
+				metricName := trimPrefix(key, HistogramPrefix)
+				histograms[metricName] = histogram
+			}
+		}
+	}
+
+	return histograms, nil
+}
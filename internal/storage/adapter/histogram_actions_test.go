@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/NoobyTheTurtle/metrics/internal/storage/memory"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricStorage_UpdateHistogram(t *testing.T) {
+	ms := NewStorage(memory.NewMemoryStorage())
+	ctx := context.Background()
+
+	first := model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{1, 1, 0}, Count: 2, Sum: 0.55}
+	value, err := ms.UpdateHistogram(ctx, "RequestDuration", first)
+	require.NoError(t, err)
+	assert.Equal(t, first, value)
+
+	value, err = ms.UpdateHistogram(ctx, "RequestDuration", model.Histogram{
+		Bounds: []float64{0.1, 1}, Counts: []uint64{0, 2, 1}, Count: 3, Sum: 4.5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{1, 3, 1}, Count: 5, Sum: 5.05}, value)
+
+	stored, ok := ms.GetHistogram(ctx, "RequestDuration")
+	require.True(t, ok)
+	assert.Equal(t, value, stored)
+
+	_, err = ms.UpdateHistogram(ctx, "RequestDuration", model.Histogram{Bounds: []float64{1}, Counts: []uint64{0, 0}})
+	assert.ErrorIs(t, err, model.ErrBucketMismatch)
+
+	_, err = ms.UpdateHistogram(ctx, "RequestDuration", model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{1}})
+	assert.ErrorIs(t, err, model.ErrInvalidHistogram)
+
+	_, err = ms.UpdateGauge(ctx, "RequestDuration", 1)
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict)
+
+	tenantCtx := tenant.WithID(ctx, "team-a")
+	_, ok = ms.GetHistogram(tenantCtx, "RequestDuration")
+	assert.False(t, ok, "tenants have separate namespaces")
+
+	all, err := ms.GetAllHistograms(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.Histogram{"RequestDuration": value}, all)
+}
+
+func TestMetricStorage_UpdateMetricsBatch_Histogram(t *testing.T) {
+	ms := NewStorage(memory.NewMemoryStorage())
+	ctx := context.Background()
+
+	histogram := func(counts ...uint64) *model.Histogram {
+		h := model.Histogram{Bounds: []float64{1}, Counts: counts}
+		for _, c := range counts {
+			h.Count += c
+		}
+		return &h
+	}
+
+	require.NoError(t, ms.UpdateMetricsBatch(ctx, model.Metrics{
+		{ID: "Latency", MType: model.HistogramType, Histogram: histogram(1, 0)},
+		{ID: "Latency", MType: model.HistogramType, Histogram: histogram(2, 3)},
+	}))
+
+	stored, ok := ms.GetHistogram(ctx, "Latency")
+	require.True(t, ok)
+	assert.Equal(t, []uint64{3, 3}, stored.Counts)
+	assert.Equal(t, uint64(6), stored.Count)
+
+	err := ms.UpdateMetricsBatch(ctx, model.Metrics{{ID: "Latency", MType: model.HistogramType}})
+	assert.ErrorContains(t, err, "has nil histogram")
+}
+
+func TestMetricStorage_HistogramEvents(t *testing.T) {
+	ms := NewStorage(memory.NewMemoryStorage())
+	ctx := context.Background()
+
+	sub := ms.Subscribe(ctx, notify.Filter{Types: []string{string(model.HistogramType)}})
+	defer sub.Close()
+
+	_, err := ms.UpdateHistogram(ctx, "RequestDuration", model.Histogram{Bounds: []float64{1}, Counts: []uint64{1, 1}, Count: 2, Sum: 2.5})
+	require.NoError(t, err)
+	require.NoError(t, ms.UpdateMetricsBatch(ctx, model.Metrics{
+		{ID: "RequestDuration", MType: model.HistogramType, Histogram: &model.Histogram{Bounds: []float64{1}, Counts: []uint64{1, 0}, Count: 1, Sum: 0.5}},
+	}))
+	_, err = ms.UpdateHistogram(ctx, "RequestDuration", model.Histogram{Bounds: []float64{2}, Counts: []uint64{1, 0}, Count: 1})
+	require.Error(t, err)
+
+	for _, expected := range []struct {
+		count uint64
+		sum   float64
+	}{{count: 2, sum: 2.5}, {count: 3, sum: 3}} {
+		e := <-sub.Events()
+		assert.Equal(t, "RequestDuration", e.ID)
+		require.NotNil(t, e.Count)
+		require.NotNil(t, e.Sum)
+		assert.Equal(t, expected.count, *e.Count, "histogram events carry the total count")
+		assert.Equal(t, expected.sum, *e.Sum)
+	}
+	assert.Empty(t, sub.Events(), "rejected update is not published")
+
+	points := ms.History(ctx, model.HistogramType, "RequestDuration")
+	require.Len(t, points, 2)
+	assert.Equal(t, 2.0, points[0].Value)
+	assert.Equal(t, 3.0, points[1].Value)
+}
@@ -0,0 +1,132 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+)
+
+// Instrument включает учет длительности и ошибок операций хранилища в метриках сервера.
+// Метрики записываются с именами storage.<backend>.<операция>.duration и .errors.
+func (ms *MetricStorage) Instrument(metrics *selfmetrics.Registry, backend string) {
+	if metrics == nil {
+		return
+	}
+
+	recorder := &storageRecorder{metrics: metrics, backend: backend}
+
+	switch {
+	case ms.dbStorage != nil:
+		db := &instrumentedDatabaseStorage{
+			instrumentedStorage: instrumentedStorage{storage: ms.dbStorage, recorder: recorder},
+			db:                  ms.dbStorage,
+		}
+		ms.storage, ms.dbStorage = db, db
+	case ms.fileStorage != nil:
+		fs := &instrumentedFileStorage{
+			instrumentedStorage: instrumentedStorage{storage: ms.fileStorage, recorder: recorder},
+			file:                ms.fileStorage,
+		}
+		ms.storage, ms.fileStorage = fs, fs
+	default:
+		ms.storage = &instrumentedStorage{storage: ms.storage, recorder: recorder}
+	}
+}
+
+type storageRecorder struct {
+	metrics *selfmetrics.Registry
+	backend string
+}
+
+func (r *storageRecorder) record(op string, start time.Time, err error) {
+	name := "storage." + r.backend + "." + op
+	r.metrics.Histogram(name+".duration", model.DefaultBuckets).Since(start)
+	if err != nil {
+		r.metrics.Counter(name + ".errors").Inc()
+	}
+}
+
+type instrumentedStorage struct {
+	storage  Storage
+	recorder *storageRecorder
+}
+
+func (s *instrumentedStorage) Get(ctx context.Context, key string) (any, bool) {
+	start := time.Now()
+	value, ok := s.storage.Get(ctx, key)
+	s.recorder.record("get", start, nil)
+	return value, ok
+}
+
+func (s *instrumentedStorage) Set(ctx context.Context, key string, value any) (any, error) {
+	start := time.Now()
+	stored, err := s.storage.Set(ctx, key, value)
+	s.recorder.record("set", start, err)
+	return stored, err
+}
+
+func (s *instrumentedStorage) GetAll(ctx context.Context) (map[string]any, error) {
+	start := time.Now()
+	all, err := s.storage.GetAll(ctx)
+	s.recorder.record("get_all", start, err)
+	return all, err
+}
+
+type instrumentedFileStorage struct {
+	instrumentedStorage
+	file FileStorage
+}
+
+func (s *instrumentedFileStorage) SaveToFile(ctx context.Context) error {
+	start := time.Now()
+	err := s.file.SaveToFile(ctx)
+	s.recorder.record("save", start, err)
+	return err
+}
+
+func (s *instrumentedFileStorage) LoadFromFile(ctx context.Context) error {
+	start := time.Now()
+	err := s.file.LoadFromFile(ctx)
+	s.recorder.record("load", start, err)
+	return err
+}
+
+type instrumentedDatabaseStorage struct {
+	instrumentedStorage
+	db DatabaseStorage
+}
+
+func (s *instrumentedDatabaseStorage) BeginTransaction(ctx context.Context) (TransactionalStorage, error) {
+	start := time.Now()
+	tx, err := s.db.BeginTransaction(ctx)
+	s.recorder.record("begin", start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedTransaction{
+		instrumentedStorage: instrumentedStorage{storage: tx, recorder: s.recorder},
+		tx:                  tx,
+	}, nil
+}
+
+type instrumentedTransaction struct {
+	instrumentedStorage
+	tx TransactionalStorage
+}
+
+func (t *instrumentedTransaction) Commit() error {
+	start := time.Now()
+	err := t.tx.Commit()
+	t.recorder.record("commit", start, err)
+	return err
+}
+
+func (t *instrumentedTransaction) Rollback() error {
+	start := time.Now()
+	err := t.tx.Rollback()
+	t.recorder.record("rollback", start, err)
+	return err
+}
@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetricStorage_Instrument(t *testing.T) {
+	t.Run("database", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		db := NewMockDatabaseStorage(ctrl)
+		tx := NewMockTransactionalStorage(ctrl)
+		// проверка типа по хранилищу перед первой записью метрики
+		db.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, false).AnyTimes()
+		db.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+		tx.EXPECT().Get(gomock.Any(), "counter:PollCount").Return(int64(1), true)
+		tx.EXPECT().Set(gomock.Any(), "counter:PollCount", int64(2)).Return(nil, errors.New("db error"))
+		tx.EXPECT().Rollback().Return(nil)
+
+		metrics := selfmetrics.NewRegistry()
+		ms := NewDatabaseStorage(db)
+		ms.Instrument(metrics, "postgres")
+
+		_, err := ms.UpdateCounter(context.Background(), "PollCount", 1)
+		require.Error(t, err)
+
+		for _, op := range []string{"begin", "get", "set", "rollback"} {
+			assert.Positive(t, metrics.Histogram("storage.postgres."+op+".duration", nil).Count(), op)
+		}
+		assert.Equal(t, int64(1), metrics.Counter("storage.postgres.set.errors").Value())
+		assert.Equal(t, int64(0), metrics.Counter("storage.postgres.rollback.errors").Value())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fs := NewMockFileStorage(ctrl)
+		fs.EXPECT().SaveToFile(gomock.Any()).Return(errors.New("disk full"))
+		fs.EXPECT().GetAll(gomock.Any()).Return(map[string]any{}, nil)
+
+		metrics := selfmetrics.NewRegistry()
+		ms := NewFileStorage(fs)
+		ms.Instrument(metrics, "file")
+
+		assert.Error(t, ms.SaveToFile(context.Background()))
+		_, err := ms.GetAllGauges(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), metrics.Counter("storage.file.save.errors").Value())
+		assert.Equal(t, uint64(1), metrics.Histogram("storage.file.get_all.duration", nil).Count())
+	})
+
+	t.Run("nil registry", func(t *testing.T) {
+		ms := &MetricStorage{}
+		ms.Instrument(nil, "memory")
+		assert.Nil(t, ms.storage)
+	})
+}
@@ -59,12 +59,16 @@ type DatabaseStorage interface {
 
 var _ Storage = (*memory.MemoryStorage)(nil)
 var _ Storage = (*MockStorage)(nil)
+var _ Storage = (*instrumentedStorage)(nil)
 
 var _ FileStorage = (*file.FileStorage)(nil)
 var _ FileStorage = (*MockFileStorage)(nil)
+var _ FileStorage = (*instrumentedFileStorage)(nil)
 
 // var _ DatabaseStorage = (*postgres.PostgresStorage)(nil)
 var _ DatabaseStorage = (*MockDatabaseStorage)(nil)
+var _ DatabaseStorage = (*instrumentedDatabaseStorage)(nil)
 
 // var _ TransactionalStorage = (*postgres.PostgresStorage)(nil)
 var _ TransactionalStorage = (*MockTransactionalStorage)(nil)
+var _ TransactionalStorage = (*instrumentedTransaction)(nil)
@@ -0,0 +1,139 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+)
+
+// Metadata возвращает метаданные метрики.
+func (ms *MetricStorage) Metadata(ctx context.Context, name string) (metadata.Metadata, bool) {
+	if ms.metadata == nil {
+		return metadata.Metadata{}, false
+	}
+	return ms.metadata.Get(ctx, name)
+}
+
+// ListMetadata возвращает метаданные всех метрик арендатора.
+func (ms *MetricStorage) ListMetadata(ctx context.Context) []metadata.Metadata {
+	if ms.metadata == nil {
+		return nil
+	}
+	return ms.metadata.List(ctx)
+}
+
+// DeclareMetadata задает тип, единицу измерения и описание метрики.
+// Объявление отклоняется с metadata.ErrTypeConflict, если в хранилище
+// уже есть значение метрики с тем же именем и другим типом.
+func (ms *MetricStorage) DeclareMetadata(ctx context.Context, m metadata.Metadata) (metadata.Metadata, error) {
+	if ms.metadata == nil {
+		return metadata.Metadata{}, fmt.Errorf("adapter.MetricStorage.DeclareMetadata: metadata registry is not configured")
+	}
+
+	if err := ms.lockStoredType(ctx, m.Name, m.Type); err != nil {
+		return metadata.Metadata{}, fmt.Errorf("adapter.MetricStorage.DeclareMetadata: %w", err)
+	}
+
+	declared, err := ms.metadata.Declare(ctx, m)
+	if err != nil {
+		return metadata.Metadata{}, fmt.Errorf("adapter.MetricStorage.DeclareMetadata: %w", err)
+	}
+
+	return declared, nil
+}
+
+// observe закрепляет за именем метрики тип перед записью значения
+// и учитывает имя в ограничении числа различных имен.
+// Имя учитывается только после проверки типа, чтобы отклоненный запрос не занимал лимит.
+func (ms *MetricStorage) observe(ctx context.Context, name string, mType model.MetricType) error {
+	if err := ms.checkType(ctx, name, mType); err != nil {
+		return err
+	}
+
+	if ms.metadata == nil || !metadata.ValidType(mType) {
+		return nil
+	}
+
+	if ms.cardinality != nil {
+		if err := ms.cardinality.Admit(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	return ms.commitType(ctx, name, mType)
+}
+
+// checkType проверяет, что метрику можно записать с типом mType, ничего не меняя.
+// Метрики неизвестных типов пропускаются: их отклоняет само обновление.
+// Имена из пространства имен метрик сервера доступны только реестру selfmetrics.
+func (ms *MetricStorage) checkType(ctx context.Context, name string, mType model.MetricType) error {
+	if selfmetrics.IsReserved(name) && !selfmetrics.Internal(ctx) {
+		return fmt.Errorf("%w: '%s'", selfmetrics.ErrReservedName, name)
+	}
+
+	if ms.metadata == nil || !metadata.ValidType(mType) {
+		return nil
+	}
+
+	if current, known := ms.metadata.Get(ctx, name); known {
+		if current.Type != mType {
+			return fmt.Errorf("%w: metric '%s' is registered as %s, got %s", metadata.ErrTypeConflict, name, current.Type, mType)
+		}
+		return nil
+	}
+
+	if stored, ok := ms.storedType(ctx, name, mType); ok {
+		return fmt.Errorf("%w: metric '%s' is stored as %s, got %s", metadata.ErrTypeConflict, name, stored, mType)
+	}
+
+	return nil
+}
+
+// commitType закрепляет за именем метрики тип в реестре после успешной проверки.
+func (ms *MetricStorage) commitType(ctx context.Context, name string, mType model.MetricType) error {
+	if ms.metadata == nil || !metadata.ValidType(mType) {
+		return nil
+	}
+
+	if err := ms.lockStoredType(ctx, name, mType); err != nil {
+		return err
+	}
+
+	return ms.metadata.Observe(ctx, name, mType)
+}
+
+// storedType возвращает тип, отличный от mType, с которым метрика уже сохранена в хранилище.
+func (ms *MetricStorage) storedType(ctx context.Context, name string, mType model.MetricType) (model.MetricType, bool) {
+	for _, other := range metadata.Types {
+		if other == mType {
+			continue
+		}
+		if _, exists := ms.storage.Get(ctx, metricKey(ctx, name, typePrefix(other))); exists {
+			return other, true
+		}
+	}
+	return "", false
+}
+
+// lockStoredType переносит в реестр тип метрики, значение которой было сохранено
+// до запуска сервера, например восстановлено из файла или базы данных.
+// Хранилище проверяется только для имен, которых еще нет в реестре.
+func (ms *MetricStorage) lockStoredType(ctx context.Context, name string, mType model.MetricType) error {
+	if _, known := ms.metadata.Get(ctx, name); known {
+		return nil
+	}
+
+	other, ok := ms.storedType(ctx, name, mType)
+	if !ok {
+		return nil
+	}
+
+	if _, err := ms.metadata.Declare(ctx, metadata.Metadata{Name: name, Type: other}); err != nil {
+		return err
+	}
+
+	return ms.metadata.Observe(ctx, name, mType)
+}
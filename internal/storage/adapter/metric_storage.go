@@ -1,9 +1,26 @@
 package adapter
 
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NoobyTheTurtle/metrics/internal/history"
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
+)
+
 type MetricStorage struct {
 	storage     Storage
 	fileStorage FileStorage
 	dbStorage   DatabaseStorage
+	hub         *notify.Hub
+	history     *history.Store
+	metadata    *metadata.Registry
+	cardinality *limits.Cardinality
 }
 
 func NewStorage(storage Storage) *MetricStorage {
@@ -11,6 +28,9 @@ func NewStorage(storage Storage) *MetricStorage {
 		storage:     storage,
 		fileStorage: nil,
 		dbStorage:   nil,
+		hub:         notify.NewHub(notify.DefaultBufferSize),
+		history:     history.NewStore(history.DefaultSize, history.DefaultMaxSeries),
+		metadata:    metadata.NewRegistry(),
 	}
 }
 
@@ -19,6 +39,9 @@ func NewFileStorage(fileStorage FileStorage) *MetricStorage {
 		storage:     fileStorage,
 		fileStorage: fileStorage,
 		dbStorage:   nil,
+		hub:         notify.NewHub(notify.DefaultBufferSize),
+		history:     history.NewStore(history.DefaultSize, history.DefaultMaxSeries),
+		metadata:    metadata.NewRegistry(),
 	}
 }
 
@@ -27,5 +50,119 @@ func NewDatabaseStorage(dbStorage DatabaseStorage) *MetricStorage {
 		storage:     dbStorage,
 		fileStorage: nil,
 		dbStorage:   dbStorage,
+		hub:         notify.NewHub(notify.DefaultBufferSize),
+		history:     history.NewStore(history.DefaultSize, history.DefaultMaxSeries),
+		metadata:    metadata.NewRegistry(),
+	}
+}
+
+// Subscribe подписывает на изменения метрик. Арендатор фильтра берется из контекста.
+func (ms *MetricStorage) Subscribe(ctx context.Context, filter notify.Filter) *notify.Subscription {
+	filter.Tenant = tenant.FromContext(ctx)
+	return ms.hub.Subscribe(filter)
+}
+
+// CloseSubscriptions закрывает все подписки на изменения метрик.
+func (ms *MetricStorage) CloseSubscriptions() {
+	ms.hub.Close()
+}
+
+func gaugeEvent(ctx context.Context, name string, value float64) notify.Event {
+	return notify.Event{
+		Tenant:    tenant.FromContext(ctx),
+		ID:        name,
+		MType:     string(model.GaugeType),
+		Value:     &value,
+		Timestamp: time.Now(),
+	}
+}
+
+func counterEvent(ctx context.Context, name string, value int64) notify.Event {
+	return notify.Event{
+		Tenant:    tenant.FromContext(ctx),
+		ID:        name,
+		MType:     string(model.CounterType),
+		Total:     &value,
+		Timestamp: time.Now(),
+	}
+}
+
+// histogramEvent сообщает общее число и сумму значений гистограммы,
+// по ним подписчик видит, что в гистограмму добавлены значения.
+func histogramEvent(ctx context.Context, name string, value model.Histogram) notify.Event {
+	count, sum := value.Count, value.Sum
+	return notify.Event{
+		Tenant:    tenant.FromContext(ctx),
+		ID:        name,
+		MType:     string(model.HistogramType),
+		Count:     &count,
+		Sum:       &sum,
+		Timestamp: time.Now(),
+	}
+}
+
+// LimitCardinality включает ограничение числа различных имен метрик.
+// Имена метрик, уже сохраненных в хранилище, учитываются сразу.
+func (ms *MetricStorage) LimitCardinality(ctx context.Context, cardinality *limits.Cardinality) error {
+	allMetrics, err := ms.storage.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("adapter.MetricStorage.LimitCardinality: failed to get stored metrics: %w", err)
+	}
+
+	for key := range allMetrics {
+		if tenantID, _, name, ok := splitKey(key); ok {
+			cardinality.Seed(tenantID, name)
+		}
+	}
+
+	ms.cardinality = cardinality
+	return nil
+}
+
+// History возвращает последние значения метрики в порядке от старых к новым.
+// Для счетчиков возвращаются итоговые значения после каждого обновления.
+func (ms *MetricStorage) History(ctx context.Context, mType model.MetricType, name string) []history.Point {
+	if ms.history == nil {
+		return nil
+	}
+	return ms.history.Get(metricKey(ctx, name, typePrefix(mType)))
+}
+
+// publish сохраняет события в истории значений и отправляет их подписчикам,
+// если хранилище создано с хабом уведомлений.
+func (ms *MetricStorage) publish(ctx context.Context, events ...notify.Event) {
+	if ms.history != nil {
+		for _, e := range events {
+			ms.history.Add(metricKey(ctx, e.ID, typePrefix(model.MetricType(e.MType))), eventPoint(e))
+		}
+	}
+
+	if ms.hub == nil {
+		return
+	}
+	ms.hub.Publish(events...)
+}
+
+func typePrefix(mType model.MetricType) Prefix {
+	switch mType {
+	case model.CounterType:
+		return CounterPrefix
+	case model.HistogramType:
+		return HistogramPrefix
+	default:
+		return GaugePrefix
+	}
+}
+
+func eventPoint(e notify.Event) history.Point {
+	p := history.Point{Timestamp: e.Timestamp}
+	switch {
+	case e.Value != nil:
+		p.Value = *e.Value
+	case e.Total != nil:
+		p.Value = float64(*e.Total)
+	case e.Count != nil:
+		p.Value = float64(*e.Count)
 	}
+	return p
 }
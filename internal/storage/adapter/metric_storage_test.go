@@ -1,9 +1,18 @@
 package adapter
 
 import (
+	"context"
 	"testing"
 
+	"github.com/NoobyTheTurtle/metrics/internal/limits"
+	"github.com/NoobyTheTurtle/metrics/internal/metadata"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
+	"github.com/NoobyTheTurtle/metrics/internal/notify"
+	"github.com/NoobyTheTurtle/metrics/internal/selfmetrics"
+	"github.com/NoobyTheTurtle/metrics/internal/storage/memory"
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -126,3 +135,192 @@ func TestMetricStorage_StorageType_Identification(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricStorage_SubscribeAndHistory(t *testing.T) {
+	ms := NewStorage(memory.NewMemoryStorage())
+	ctx := context.Background()
+	tenantCtx := tenant.WithID(ctx, "team-a")
+
+	all := ms.Subscribe(ctx, notify.Filter{})
+	counters := ms.Subscribe(ctx, notify.Filter{Types: []string{string(model.CounterType)}})
+	tenantSub := ms.Subscribe(tenantCtx, notify.Filter{})
+	defer tenantSub.Close()
+
+	_, err := ms.UpdateGauge(ctx, "Alloc", 1.5)
+	require.NoError(t, err)
+	_, err = ms.UpdateCounter(ctx, "PollCount", 2)
+	require.NoError(t, err)
+
+	delta := int64(3)
+	require.NoError(t, ms.UpdateMetricsBatch(ctx, model.Metrics{
+		{ID: "PollCount", MType: model.CounterType, Delta: &delta},
+	}))
+
+	_, err = ms.UpdateGauge(tenantCtx, "Alloc", 7)
+	require.NoError(t, err)
+
+	e := <-all.Events()
+	assert.Equal(t, "Alloc", e.ID)
+	assert.Equal(t, string(model.GaugeType), e.MType)
+	require.NotNil(t, e.Value)
+	assert.Equal(t, 1.5, *e.Value)
+
+	for _, expected := range []int64{2, 5} {
+		e = <-counters.Events()
+		assert.Equal(t, "PollCount", e.ID)
+		require.NotNil(t, e.Total)
+		assert.Equal(t, expected, *e.Total, "counter events carry the total value")
+	}
+
+	e = <-tenantSub.Events()
+	assert.Equal(t, "team-a", e.Tenant)
+	assert.Equal(t, 7.0, *e.Value)
+
+	points := ms.History(ctx, model.CounterType, "PollCount")
+	require.Len(t, points, 2)
+	assert.Equal(t, 2.0, points[0].Value)
+	assert.Equal(t, 5.0, points[1].Value)
+	assert.Len(t, ms.History(tenantCtx, model.GaugeType, "Alloc"), 1)
+	assert.Empty(t, ms.History(ctx, model.GaugeType, "Unknown"))
+
+	ms.CloseSubscriptions()
+
+	for range all.Events() {
+	}
+	assert.ErrorIs(t, all.Err(), notify.ErrClosed)
+	_, ok := <-counters.Events()
+	assert.False(t, ok)
+}
+
+func TestMetricStorage_TypeLocking(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.NewMemoryStorage()
+	_, err := mem.Set(ctx, "counter:Restored", int64(1))
+	require.NoError(t, err)
+
+	ms := NewStorage(mem)
+
+	_, err = ms.UpdateGauge(ctx, "Alloc", 1)
+	require.NoError(t, err)
+
+	_, err = ms.UpdateCounter(ctx, "Alloc", 1)
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict)
+
+	_, err = ms.UpdateGauge(ctx, "Restored", 1)
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict, "type of a restored metric is locked too")
+
+	value := 2.0
+	delta := int64(1)
+	err = ms.UpdateMetricsBatch(ctx, model.Metrics{
+		{ID: "Batch", MType: model.GaugeType, Value: &value},
+		{ID: "Batch", MType: model.CounterType, Delta: &delta},
+	})
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict)
+	_, exists := ms.GetGauge(ctx, "Batch")
+	assert.False(t, exists, "conflicting batch is rejected as a whole")
+
+	_, err = ms.UpdateGauge(tenant.WithID(ctx, "team-a"), "Alloc", 1)
+	assert.NoError(t, err)
+	_, err = ms.UpdateCounter(tenant.WithID(ctx, "team-b"), "Alloc", 1)
+	assert.NoError(t, err)
+
+	declared, err := ms.DeclareMetadata(ctx, metadata.Metadata{Name: "Alloc", Type: model.GaugeType, Unit: "bytes"})
+	require.NoError(t, err)
+	assert.Equal(t, "bytes", declared.Unit)
+	assert.False(t, declared.FirstSeen.IsZero())
+
+	_, err = ms.DeclareMetadata(ctx, metadata.Metadata{Name: "Restored", Type: model.GaugeType})
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict)
+
+	m, ok := ms.Metadata(ctx, "Restored")
+	require.True(t, ok)
+	assert.Equal(t, model.CounterType, m.Type)
+	assert.Len(t, ms.ListMetadata(ctx), 2)
+}
+
+func TestMetricStorage_LimitCardinality(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.NewMemoryStorage()
+	_, err := mem.Set(ctx, "gauge:Restored", 1.0)
+	require.NoError(t, err)
+
+	ms := NewStorage(mem)
+	require.NoError(t, ms.LimitCardinality(ctx, limits.NewCardinality(limits.CardinalityConfig{MaxNames: 2}, nil)))
+
+	_, err = ms.UpdateGauge(ctx, "Alloc", 1)
+	require.NoError(t, err)
+	_, err = ms.UpdateGauge(ctx, "Restored", 2)
+	require.NoError(t, err)
+
+	_, err = ms.UpdateCounter(ctx, "PollCount", 1)
+	assert.ErrorIs(t, err, limits.ErrTooManyNames)
+
+	value := 1.0
+	err = ms.UpdateMetricsBatch(ctx, model.Metrics{
+		{ID: "Alloc", MType: model.GaugeType, Value: &value},
+		{ID: "Sys", MType: model.GaugeType, Value: &value},
+	})
+	assert.ErrorIs(t, err, limits.ErrTooManyNames)
+
+	_, exists := ms.GetGauge(ctx, "Sys")
+	assert.False(t, exists)
+	_, exists = ms.Metadata(ctx, "PollCount")
+	assert.False(t, exists, "rejected names are not registered")
+}
+
+func TestMetricStorage_RejectedUpdatesKeepState(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.NewMemoryStorage()
+	_, err := mem.Set(ctx, "gauge:Restored", 1.0)
+	require.NoError(t, err)
+
+	ms := NewStorage(mem)
+	require.NoError(t, ms.LimitCardinality(ctx, limits.NewCardinality(limits.CardinalityConfig{MaxNames: 3}, nil)))
+
+	_, err = ms.UpdateGauge(ctx, "Alloc", 1)
+	require.NoError(t, err)
+
+	value := 1.0
+	delta := int64(1)
+	err = ms.UpdateMetricsBatch(ctx, model.Metrics{
+		{ID: "Sys", MType: model.GaugeType, Value: &value},
+		{ID: "Alloc", MType: model.CounterType, Delta: &delta},
+	})
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict)
+	_, exists := ms.Metadata(ctx, "Sys")
+	assert.False(t, exists, "entries before a conflict are not registered")
+
+	_, err = ms.UpdateCounter(ctx, "Restored", 1)
+	assert.ErrorIs(t, err, metadata.ErrTypeConflict)
+
+	// отклоненные Sys и Restored не заняли лимит имен
+	_, err = ms.UpdateGauge(ctx, "HeapSys", 1)
+	assert.NoError(t, err)
+}
+
+func TestMetricStorage_ReservedNames(t *testing.T) {
+	ctx := context.Background()
+	ms := NewStorage(memory.NewMemoryStorage())
+
+	metrics := selfmetrics.NewRegistry()
+	ms.Instrument(metrics, "memory")
+
+	_, err := ms.UpdateGauge(ctx, "server.runtime.goroutines", 1)
+	assert.ErrorIs(t, err, selfmetrics.ErrReservedName)
+
+	delta := int64(1)
+	err = ms.UpdateMetricsBatch(ctx, model.Metrics{{ID: "server.http.requests", MType: model.CounterType, Delta: &delta}})
+	assert.ErrorIs(t, err, selfmetrics.ErrReservedName)
+
+	metrics.Counter("http.requests").Inc()
+	metrics.Histogram("http.duration", model.DefaultBuckets).Observe(0.1)
+	require.NoError(t, metrics.Flush(ctx, ms))
+
+	value, ok := ms.GetCounter(ctx, "server.http.requests")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), value)
+	h, ok := ms.GetHistogram(ctx, "server.http.duration")
+	require.True(t, ok)
+	assert.Equal(t, uint64(1), h.Count)
+	assert.Positive(t, metrics.Histogram("storage.memory.set.duration", nil).Count(), "flush goes through the instrumented storage")
+}
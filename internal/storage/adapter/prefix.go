@@ -1,15 +1,20 @@
 package adapter
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 )
 
 type Prefix string
 
 const (
-	GaugePrefix   Prefix = "gauge:"
-	CounterPrefix Prefix = "counter:"
+	GaugePrefix     Prefix = "gauge:"
+	CounterPrefix   Prefix = "counter:"
+	HistogramPrefix Prefix = "histogram:"
+	TenantPrefix    Prefix = "tenant:"
 )
 
 func addPrefix(name string, prefix Prefix) string {
@@ -23,3 +28,56 @@ func trimPrefix(key string, prefix Prefix) string {
 func hasPrefix(key string, prefix Prefix) bool {
 	return strings.HasPrefix(key, string(prefix))
 }
+
+// namespacePrefix возвращает префикс пространства имен арендатора из контекста.
+// Для пространства по умолчанию префикс пустой.
+func namespacePrefix(ctx context.Context) Prefix {
+	id := tenant.FromContext(ctx)
+	if id == "" {
+		return ""
+	}
+	return Prefix(fmt.Sprintf("%s%s:", TenantPrefix, id))
+}
+
+// metricKey строит ключ хранилища для метрики с учетом арендатора.
+func metricKey(ctx context.Context, name string, prefix Prefix) string {
+	return string(namespacePrefix(ctx)) + addPrefix(name, prefix)
+}
+
+// splitKey разбирает ключ хранилища на арендатора, префикс типа и имя метрики.
+func splitKey(key string) (tenantID string, prefix Prefix, name string, ok bool) {
+	if hasPrefix(key, TenantPrefix) {
+		rest := trimPrefix(key, TenantPrefix)
+		id, tail, found := strings.Cut(rest, ":")
+		if !found {
+			return "", "", "", false
+		}
+		tenantID, key = id, tail
+	}
+
+	for _, p := range []Prefix{GaugePrefix, CounterPrefix, HistogramPrefix} {
+		if hasPrefix(key, p) {
+			return tenantID, p, trimPrefix(key, p), true
+		}
+	}
+
+	return "", "", "", false
+}
+
+// scopeMetrics оставляет только ключи пространства имен арендатора из контекста
+// и убирает из них префикс арендатора.
+func scopeMetrics(ctx context.Context, metrics map[string]any) map[string]any {
+	ns := namespacePrefix(ctx)
+	scoped := make(map[string]any, len(metrics))
+
+	for key, value := range metrics {
+		switch {
+		case ns == "" && !hasPrefix(key, TenantPrefix):
+			scoped[key] = value
+		case ns != "" && hasPrefix(key, ns):
+			scoped[trimPrefix(key, ns)] = value
+		}
+	}
+
+	return scoped
+}
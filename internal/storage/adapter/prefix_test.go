@@ -1,9 +1,12 @@
 package adapter
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/NoobyTheTurtle/metrics/internal/tenant"
 )
 
 func TestAddPrefix(t *testing.T) {
@@ -134,3 +137,104 @@ func TestHasPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		tenantID       string
+		metricName     string
+		prefix         Prefix
+		expectedResult string
+	}{
+		{
+			name:           "default namespace",
+			metricName:     "testMetric",
+			prefix:         GaugePrefix,
+			expectedResult: "gauge:testMetric",
+		},
+		{
+			name:           "tenant namespace",
+			tenantID:       "team-a",
+			metricName:     "testMetric",
+			prefix:         CounterPrefix,
+			expectedResult: "tenant:team-a:counter:testMetric",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tenant.WithID(context.Background(), tt.tenantID)
+			assert.Equal(t, tt.expectedResult, metricKey(ctx, tt.metricName, tt.prefix))
+		})
+	}
+}
+
+func TestScopeMetrics(t *testing.T) {
+	all := map[string]any{
+		"gauge:shared":                   1.0,
+		"counter:shared":                 int64(1),
+		"tenant:team-a:gauge:shared":     2.0,
+		"tenant:team-a:counter:requests": int64(2),
+		"tenant:team-b:gauge:shared":     3.0,
+	}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		expected map[string]any
+	}{
+		{
+			name: "default namespace hides tenant keys",
+			expected: map[string]any{
+				"gauge:shared":   1.0,
+				"counter:shared": int64(1),
+			},
+		},
+		{
+			name:     "tenant sees only own keys",
+			tenantID: "team-a",
+			expected: map[string]any{
+				"gauge:shared":     2.0,
+				"counter:requests": int64(2),
+			},
+		},
+		{
+			name:     "unknown tenant sees nothing",
+			tenantID: "team-c",
+			expected: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tenant.WithID(context.Background(), tt.tenantID)
+			assert.Equal(t, tt.expected, scopeMetrics(ctx, all))
+		})
+	}
+}
+
+func TestSplitKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		tenantID string
+		prefix   Prefix
+		name     string
+		ok       bool
+	}{
+		{key: "gauge:Alloc", prefix: GaugePrefix, name: "Alloc", ok: true},
+		{key: "counter:PollCount", prefix: CounterPrefix, name: "PollCount", ok: true},
+		{key: "tenant:team-a:gauge:a:b", tenantID: "team-a", prefix: GaugePrefix, name: "a:b", ok: true},
+		{key: "tenant:team-a", ok: false},
+		{key: "unknown:Alloc", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			tenantID, prefix, name, ok := splitKey(tt.key)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.tenantID, tenantID)
+			assert.Equal(t, tt.prefix, prefix)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+}
@@ -3,10 +3,16 @@ package adapter
 import (
 	"context"
 	"fmt"
+
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 )
 
 func (ms *MetricStorage) GetGauge(ctx context.Context, name string) (float64, bool) {
-	key := addPrefix(name, GaugePrefix)
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.GetGauge", metricAttributes(name))
+	defer span.End()
+
+	key := metricKey(ctx, name, GaugePrefix)
 	value, exists := ms.storage.Get(ctx, key)
 	if !exists {
 		return 0, false
@@ -16,7 +22,14 @@ func (ms *MetricStorage) GetGauge(ctx context.Context, name string) (float64, bo
 }
 
 func (ms *MetricStorage) UpdateGauge(ctx context.Context, name string, value float64) (float64, error) {
-	key := addPrefix(name, GaugePrefix)
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.UpdateGauge", metricAttributes(name))
+	defer span.End()
+
+	if err := ms.observe(ctx, name, model.GaugeType); err != nil {
+		return 0, fmt.Errorf("adapter.MetricStorage.UpdateGauge: %w", err)
+	}
+
+	key := metricKey(ctx, name, GaugePrefix)
 	newValue, err := ms.storage.Set(ctx, key, value)
 	if err != nil {
 		return 0, fmt.Errorf("adapter.MetricStorage.UpdateGauge: failed to update gauge metric '%s': %w", name, err)
@@ -27,14 +40,20 @@ func (ms *MetricStorage) UpdateGauge(ctx context.Context, name string, value flo
 		return 0, fmt.Errorf("adapter.MetricStorage.UpdateGauge: failed to convert newValue '%v' to float64", newValue)
 	}
 
+	ms.publish(ctx, gaugeEvent(ctx, name, newValueFloat64))
+
 	return newValueFloat64, nil
 }
 
 func (ms *MetricStorage) GetAllGauges(ctx context.Context) (map[string]float64, error) {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.GetAllGauges")
+	defer span.End()
+
 	allMetrics, err := ms.storage.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("adapter.MetricStorage.GetAllGauges: failed to get all gauges: %w", err)
 	}
+	allMetrics = scopeMetrics(ctx, allMetrics)
 	gauges := make(map[string]float64)
 
 	for key, value := range allMetrics {
@@ -50,7 +69,10 @@ func (ms *MetricStorage) GetAllGauges(ctx context.Context) (map[string]float64,
 }
 
 func (ms *MetricStorage) GetCounter(ctx context.Context, name string) (int64, bool) {
-	key := addPrefix(name, CounterPrefix)
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.GetCounter", metricAttributes(name))
+	defer span.End()
+
+	key := metricKey(ctx, name, CounterPrefix)
 	value, exists := ms.storage.Get(ctx, key)
 	if !exists {
 		return 0, false
@@ -60,8 +82,21 @@ func (ms *MetricStorage) GetCounter(ctx context.Context, name string) (int64, bo
 }
 
 func (ms *MetricStorage) UpdateCounter(ctx context.Context, name string, value int64) (int64, error) {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.UpdateCounter", metricAttributes(name))
+	defer span.End()
+
+	if err := ms.observe(ctx, name, model.CounterType); err != nil {
+		return 0, fmt.Errorf("adapter.MetricStorage.UpdateCounter: %w", err)
+	}
+
 	if ms.dbStorage == nil {
-		return updateCounter(ctx, ms.storage, name, value)
+		value, err := updateCounter(ctx, ms.storage, name, value)
+		if err != nil {
+			return 0, err
+		}
+
+		ms.publish(ctx, counterEvent(ctx, name, value))
+		return value, nil
 	}
 
 	tx, err := ms.dbStorage.BeginTransaction(ctx)
@@ -72,6 +107,7 @@ func (ms *MetricStorage) UpdateCounter(ctx context.Context, name string, value i
 	value, err = updateCounter(ctx, tx, name, value)
 	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.ErrorContext(ctx, "Failed to update counter '%s' before rollback: %v", name, err)
 			return 0, fmt.Errorf("adapter.MetricStorage.UpdateCounter: failed to rollback transaction: %w", rollbackErr)
 		}
 		return 0, fmt.Errorf("adapter.MetricStorage.UpdateCounter: failed to update counter metric during transaction for '%s': %w", name, err)
@@ -81,6 +117,8 @@ func (ms *MetricStorage) UpdateCounter(ctx context.Context, name string, value i
 		return 0, fmt.Errorf("adapter.MetricStorage.UpdateCounter: failed to commit transaction: %w", err)
 	}
 
+	ms.publish(ctx, counterEvent(ctx, name, value))
+
 	return value, nil
 }
 
@@ -90,7 +128,7 @@ type UpdateCounterStorage interface {
 }
 
 func updateCounter(ctx context.Context, storage UpdateCounterStorage, name string, value int64) (int64, error) {
-	key := addPrefix(name, CounterPrefix)
+	key := metricKey(ctx, name, CounterPrefix)
 
 	currentValue, exists := storage.Get(ctx, key)
 	var valueToSet = value
@@ -118,10 +156,14 @@ func updateCounter(ctx context.Context, storage UpdateCounterStorage, name strin
 }
 
 func (ms *MetricStorage) GetAllCounters(ctx context.Context) (map[string]int64, error) {
+	ctx, span := tracer.Start(ctx, "adapter.MetricStorage.GetAllCounters")
+	defer span.End()
+
 	allMetrics, err := ms.storage.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("adapter.MetricStorage.GetAllCounters: failed to get all counters: %w", err)
 	}
+	allMetrics = scopeMetrics(ctx, allMetrics)
 	counters := make(map[string]int64)
 
 	for key, value := range allMetrics {
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("storage/adapter")
+
+// metricAttributes возвращает атрибуты span операции с одной метрикой.
+func metricAttributes(name string) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("metric.name", name))
+}
+
+// batchAttributes возвращает атрибуты span пакетного обновления.
+func batchAttributes(size int) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.Int("metrics.batch_size", size))
+}
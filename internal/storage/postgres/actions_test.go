@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,9 +25,9 @@ func TestPostgresStorage_Get(t *testing.T) {
 			name: "get existing float value",
 			key:  "floatKey",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(42.5, nil)
-				mock.ExpectQuery("SELECT value_float, value_int FROM metrics WHERE key = \\$1").
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(42.5, nil, nil)
+				mock.ExpectQuery("SELECT value_float, value_int, value_json FROM metrics WHERE key = \\$1").
 					WithArgs("floatKey").
 					WillReturnRows(rows)
 			},
@@ -37,20 +38,33 @@ func TestPostgresStorage_Get(t *testing.T) {
 			name: "get existing int value",
 			key:  "intKey",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(nil, 42)
-				mock.ExpectQuery("SELECT value_float, value_int FROM metrics WHERE key = \\$1").
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(nil, 42, nil)
+				mock.ExpectQuery("SELECT value_float, value_int, value_json FROM metrics WHERE key = \\$1").
 					WithArgs("intKey").
 					WillReturnRows(rows)
 			},
 			expectedValue: int64(42),
 			expectedFound: true,
 		},
+		{
+			name: "get existing histogram value",
+			key:  "histogramKey",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(nil, nil, []byte(`{"bounds":[1],"counts":[2,1],"count":3,"sum":4.5}`))
+				mock.ExpectQuery("SELECT value_float, value_int, value_json FROM metrics WHERE key = \\$1").
+					WithArgs("histogramKey").
+					WillReturnRows(rows)
+			},
+			expectedValue: model.Histogram{Bounds: []float64{1}, Counts: []uint64{2, 1}, Count: 3, Sum: 4.5},
+			expectedFound: true,
+		},
 		{
 			name: "get non-existing value",
 			key:  "notFound",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT value_float, value_int FROM metrics WHERE key = \\$1").
+				mock.ExpectQuery("SELECT value_float, value_int, value_json FROM metrics WHERE key = \\$1").
 					WithArgs("notFound").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -97,10 +111,10 @@ func TestPostgresStorage_Set(t *testing.T) {
 			key:   "floatKey",
 			value: 42.5,
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(42.5, nil)
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(42.5, nil, nil)
 				mock.ExpectQuery("INSERT INTO metrics").
-					WithArgs("floatKey", sql.NullFloat64{Float64: 42.5, Valid: true}, sql.NullInt64{}).
+					WithArgs("floatKey", sql.NullFloat64{Float64: 42.5, Valid: true}, sql.NullInt64{}, nil).
 					WillReturnRows(rows)
 			},
 			expectedValue: 42.5,
@@ -111,10 +125,10 @@ func TestPostgresStorage_Set(t *testing.T) {
 			key:   "intKey",
 			value: int64(42),
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(nil, 42)
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(nil, 42, nil)
 				mock.ExpectQuery("INSERT INTO metrics").
-					WithArgs("intKey", sql.NullFloat64{}, sql.NullInt64{Int64: 42, Valid: true}).
+					WithArgs("intKey", sql.NullFloat64{}, sql.NullInt64{Int64: 42, Valid: true}, nil).
 					WillReturnRows(rows)
 			},
 			expectedValue: int64(42),
@@ -126,7 +140,7 @@ func TestPostgresStorage_Set(t *testing.T) {
 			value: 42.5,
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery("INSERT INTO metrics").
-					WithArgs("errorKey", sql.NullFloat64{Float64: 42.5, Valid: true}, sql.NullInt64{}).
+					WithArgs("errorKey", sql.NullFloat64{Float64: 42.5, Valid: true}, sql.NullInt64{}, nil).
 					WillReturnError(errors.New("database error"))
 			},
 			expectedValue: nil,
@@ -180,10 +194,10 @@ func TestPostgresStorage_GetAll(t *testing.T) {
 		{
 			name: "get all metrics",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-					AddRow("floatKey", 42.5, nil).
-					AddRow("intKey", nil, 42)
-				mock.ExpectQuery("SELECT key, value_float, value_int FROM metrics").
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+					AddRow("floatKey", 42.5, nil, nil).
+					AddRow("intKey", nil, 42, nil)
+				mock.ExpectQuery("SELECT key, value_float, value_int, value_json FROM metrics").
 					WillReturnRows(rows)
 			},
 			expectedData: map[string]any{
@@ -195,8 +209,8 @@ func TestPostgresStorage_GetAll(t *testing.T) {
 		{
 			name: "empty result",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"})
-				mock.ExpectQuery("SELECT key, value_float, value_int FROM metrics").
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"})
+				mock.ExpectQuery("SELECT key, value_float, value_int, value_json FROM metrics").
 					WillReturnRows(rows)
 			},
 			expectedData:  map[string]any{},
@@ -205,7 +219,7 @@ func TestPostgresStorage_GetAll(t *testing.T) {
 		{
 			name: "database error",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery("SELECT key, value_float, value_int FROM metrics").
+				mock.ExpectQuery("SELECT key, value_float, value_int, value_json FROM metrics").
 					WillReturnError(errors.New("database error"))
 			},
 			expectedData:  nil,
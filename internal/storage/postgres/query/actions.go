@@ -3,21 +3,27 @@ package query
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
+	"github.com/NoobyTheTurtle/metrics/internal/logger"
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/NoobyTheTurtle/metrics/internal/retry"
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
 )
 
 const (
 	getMetricQuery = `
-		SELECT value_float, value_int
+		SELECT value_float, value_int, value_json
 		FROM metrics
 		WHERE key = $1;
 	`
 )
 
 func (q *query) GetMetric(ctx context.Context, key string) (any, bool) {
+	ctx, span := tracer.Start(ctx, "query.GetMetric", queryAttributes("SELECT")...)
+
 	var metric Metric
 	var val any
 	var exists bool
@@ -40,14 +46,23 @@ func (q *query) GetMetric(ctx context.Context, key string) (any, bool) {
 		case metric.ValueInt.Valid:
 			val = metric.ValueInt.Int64
 			exists = true
+		case metric.ValueJSON != nil:
+			histogram, err := decodeHistogram(metric.ValueJSON)
+			if err != nil {
+				return fmt.Errorf("query.GetMetric: %w", err)
+			}
+			val = histogram
+			exists = true
 		default:
 			exists = false
 		}
 		return nil
 	}
 
-	retryErr := retry.WithRetries(op, retry.PgErrorChecker)
+	retryErr := retry.WithRetriesContext(ctx, op, retry.PgErrorChecker)
+	tracing.End(span, retryErr)
 	if retryErr != nil {
+		logger.ErrorContext(ctx, "Failed to get metric '%s': %v", key, retryErr)
 		return nil, false
 	}
 
@@ -56,17 +71,18 @@ func (q *query) GetMetric(ctx context.Context, key string) (any, bool) {
 
 const (
 	setMetricQuery = `
-		INSERT INTO metrics (key, value_float, value_int)
-		VALUES ($1, $2, $3)
+		INSERT INTO metrics (key, value_float, value_int, value_json)
+		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (key) DO UPDATE
-		SET value_float = $2, value_int = $3
-		RETURNING key, value_float, value_int
+		SET value_float = $2, value_int = $3, value_json = $4
+		RETURNING key, value_float, value_int, value_json
 	`
 )
 
 func (q *query) SetMetric(ctx context.Context, key string, value any) (any, error) {
 	var valueFloat sql.NullFloat64
 	var valueInt sql.NullInt64
+	var valueJSON any
 	var resultValue any
 
 	switch v := value.(type) {
@@ -76,14 +92,22 @@ func (q *query) SetMetric(ctx context.Context, key string, value any) (any, erro
 	case int64:
 		valueInt.Int64 = v
 		valueInt.Valid = true
+	case model.Histogram:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("query.SetMetric: failed to marshal histogram: %w", err)
+		}
+		valueJSON = string(data)
 	default:
 		return nil, fmt.Errorf("query.SetMetric: unsupported value type '%T'", value)
 	}
 
+	ctx, span := tracer.Start(ctx, "query.SetMetric", queryAttributes("INSERT")...)
+
 	op := func() error {
 		var result Metric
 		resultValue = nil
-		row := q.executor.QueryRowxContext(ctx, setMetricQuery, key, valueFloat, valueInt)
+		row := q.executor.QueryRowxContext(ctx, setMetricQuery, key, valueFloat, valueInt, valueJSON)
 		if err := row.StructScan(&result); err != nil {
 			return fmt.Errorf("query.SetMetric: StructScan failed: %w", err)
 		}
@@ -93,14 +117,22 @@ func (q *query) SetMetric(ctx context.Context, key string, value any) (any, erro
 			resultValue = result.ValueFloat.Float64
 		case result.ValueInt.Valid:
 			resultValue = result.ValueInt.Int64
+		case result.ValueJSON != nil:
+			histogram, err := decodeHistogram(result.ValueJSON)
+			if err != nil {
+				return fmt.Errorf("query.SetMetric: %w", err)
+			}
+			resultValue = histogram
 		default:
 			return fmt.Errorf("query.SetMetric: invalid result from database")
 		}
 		return nil
 	}
 
-	err := retry.WithRetries(op, retry.PgErrorChecker)
+	err := retry.WithRetriesContext(ctx, op, retry.PgErrorChecker)
+	tracing.End(span, err)
 	if err != nil {
+		logger.ErrorContext(ctx, "Failed to set metric '%s': %v", key, err)
 		return nil, fmt.Errorf("query.SetMetric: operation failed after retries: %w", err)
 	}
 
@@ -109,12 +141,14 @@ func (q *query) SetMetric(ctx context.Context, key string, value any) (any, erro
 
 const (
 	getAllMetricsQuery = `
-		SELECT key, value_float, value_int
+		SELECT key, value_float, value_int, value_json
 		FROM metrics;
 	`
 )
 
 func (q *query) GetAllMetrics(ctx context.Context) (map[string]any, error) {
+	ctx, span := tracer.Start(ctx, "query.GetAllMetrics", queryAttributes("SELECT")...)
+
 	var metrics []Metric
 	resultData := make(map[string]any)
 
@@ -132,15 +166,32 @@ func (q *query) GetAllMetrics(ctx context.Context) (map[string]any, error) {
 				resultData[m.Key] = m.ValueFloat.Float64
 			case m.ValueInt.Valid:
 				resultData[m.Key] = m.ValueInt.Int64
+			case m.ValueJSON != nil:
+				histogram, err := decodeHistogram(m.ValueJSON)
+				if err != nil {
+					return fmt.Errorf("query.GetAllMetrics: %w", err)
+				}
+				resultData[m.Key] = histogram
 			}
 		}
 		return nil
 	}
 
-	err := retry.WithRetries(op, retry.PgErrorChecker)
+	err := retry.WithRetriesContext(ctx, op, retry.PgErrorChecker)
+	tracing.End(span, err)
 	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get all metrics: %v", err)
 		return nil, fmt.Errorf("query.GetAllMetrics: operation failed after retries: %w", err)
 	}
 
 	return resultData, nil
 }
+
+// decodeHistogram разбирает гистограмму, сохраненную в колонке value_json.
+func decodeHistogram(data []byte) (model.Histogram, error) {
+	var histogram model.Histogram
+	if err := json.Unmarshal(data, &histogram); err != nil {
+		return model.Histogram{}, fmt.Errorf("failed to unmarshal histogram: %w", err)
+	}
+	return histogram, nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NoobyTheTurtle/metrics/internal/model"
 	"github.com/NoobyTheTurtle/metrics/internal/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -112,6 +113,18 @@ func TestQuery_SetMetric(t *testing.T) {
 		require.Equal(t, 200.2, metric.ValueFloat.Float64)
 	})
 
+	t.Run("set histogram metric", func(t *testing.T) {
+		histogram := model.Histogram{Bounds: []float64{0.1, 1}, Counts: []uint64{1, 2, 0}, Count: 3, Sum: 1.25}
+
+		result, err := query.SetMetric(ctx, "new_histogram", histogram)
+		require.NoError(t, err)
+		require.Equal(t, histogram, result)
+
+		value, exists := query.GetMetric(ctx, "new_histogram")
+		require.True(t, exists)
+		require.Equal(t, histogram, value)
+	})
+
 	t.Run("set metric with unsupported type", func(t *testing.T) {
 		_, err := query.SetMetric(ctx, "invalid_type", "string_value")
 		require.Error(t, err)
@@ -6,4 +6,5 @@ type Metric struct {
 	Key        string          `db:"key"`
 	ValueFloat sql.NullFloat64 `db:"value_float"`
 	ValueInt   sql.NullInt64   `db:"value_int"`
+	ValueJSON  []byte          `db:"value_json"`
 }
@@ -0,0 +1,21 @@
+package query
+
+import (
+	"github.com/NoobyTheTurtle/metrics/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("storage/postgres/query")
+
+// queryAttributes возвращает атрибуты span запроса к PostgreSQL.
+func queryAttributes(operation string) []trace.SpanStartOption {
+	return []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", "metrics"),
+		),
+	}
+}
@@ -45,9 +45,9 @@ func TestPostgresTransaction_Get(t *testing.T) {
 			name: "get existing float metric",
 			key:  "gauge:test_metric",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(42.5, nil)
-				mock.ExpectQuery(`SELECT value_float, value_int FROM metrics WHERE key = \$1`).
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(42.5, nil, nil)
+				mock.ExpectQuery(`SELECT value_float, value_int, value_json FROM metrics WHERE key = \$1`).
 					WithArgs("gauge:test_metric").
 					WillReturnRows(rows)
 			},
@@ -58,9 +58,9 @@ func TestPostgresTransaction_Get(t *testing.T) {
 			name: "get existing int metric",
 			key:  "counter:test_counter",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(nil, 100)
-				mock.ExpectQuery(`SELECT value_float, value_int FROM metrics WHERE key = \$1`).
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(nil, 100, nil)
+				mock.ExpectQuery(`SELECT value_float, value_int, value_json FROM metrics WHERE key = \$1`).
 					WithArgs("counter:test_counter").
 					WillReturnRows(rows)
 			},
@@ -71,7 +71,7 @@ func TestPostgresTransaction_Get(t *testing.T) {
 			name: "get non-existing metric",
 			key:  "gauge:nonexistent",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT value_float, value_int FROM metrics WHERE key = \$1`).
+				mock.ExpectQuery(`SELECT value_float, value_int, value_json FROM metrics WHERE key = \$1`).
 					WithArgs("gauge:nonexistent").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -82,7 +82,7 @@ func TestPostgresTransaction_Get(t *testing.T) {
 			name: "database error",
 			key:  "gauge:error_metric",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT value_float, value_int FROM metrics WHERE key = \$1`).
+				mock.ExpectQuery(`SELECT value_float, value_int, value_json FROM metrics WHERE key = \$1`).
 					WithArgs("gauge:error_metric").
 					WillReturnError(errors.New("database connection error"))
 			},
@@ -93,9 +93,9 @@ func TestPostgresTransaction_Get(t *testing.T) {
 			name: "both values null",
 			key:  "gauge:null_metric",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-					AddRow(nil, nil)
-				mock.ExpectQuery(`SELECT value_float, value_int FROM metrics WHERE key = \$1`).
+				rows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+					AddRow(nil, nil, nil)
+				mock.ExpectQuery(`SELECT value_float, value_int, value_json FROM metrics WHERE key = \$1`).
 					WithArgs("gauge:null_metric").
 					WillReturnRows(rows)
 			},
@@ -147,10 +147,10 @@ func TestPostgresTransaction_Set(t *testing.T) {
 			key:   "gauge:test_metric",
 			value: 42.5,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-					AddRow("gauge:test_metric", 42.5, nil)
-				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3 RETURNING key, value_float, value_int`).
-					WithArgs("gauge:test_metric", 42.5, nil).
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+					AddRow("gauge:test_metric", 42.5, nil, nil)
+				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int, value_json\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3, value_json = \$4 RETURNING key, value_float, value_int, value_json`).
+					WithArgs("gauge:test_metric", 42.5, nil, nil).
 					WillReturnRows(rows)
 			},
 			expectedValue: 42.5,
@@ -161,10 +161,10 @@ func TestPostgresTransaction_Set(t *testing.T) {
 			key:   "counter:test_counter",
 			value: int64(100),
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-					AddRow("counter:test_counter", nil, 100)
-				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3 RETURNING key, value_float, value_int`).
-					WithArgs("counter:test_counter", nil, int64(100)).
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+					AddRow("counter:test_counter", nil, 100, nil)
+				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int, value_json\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3, value_json = \$4 RETURNING key, value_float, value_int, value_json`).
+					WithArgs("counter:test_counter", nil, int64(100), nil).
 					WillReturnRows(rows)
 			},
 			expectedValue: int64(100),
@@ -183,8 +183,8 @@ func TestPostgresTransaction_Set(t *testing.T) {
 			key:   "gauge:error_metric",
 			value: 42.5,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3 RETURNING key, value_float, value_int`).
-					WithArgs("gauge:error_metric", 42.5, nil).
+				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int, value_json\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3, value_json = \$4 RETURNING key, value_float, value_int, value_json`).
+					WithArgs("gauge:error_metric", 42.5, nil, nil).
 					WillReturnError(errors.New("database connection error"))
 			},
 			expectedValue: nil,
@@ -195,10 +195,10 @@ func TestPostgresTransaction_Set(t *testing.T) {
 			key:   "gauge:invalid_result",
 			value: 42.5,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-					AddRow("gauge:invalid_result", nil, nil)
-				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3 RETURNING key, value_float, value_int`).
-					WithArgs("gauge:invalid_result", 42.5, nil).
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+					AddRow("gauge:invalid_result", nil, nil, nil)
+				mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int, value_json\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3, value_json = \$4 RETURNING key, value_float, value_int, value_json`).
+					WithArgs("gauge:invalid_result", 42.5, nil, nil).
 					WillReturnRows(rows)
 			},
 			expectedValue: nil,
@@ -250,11 +250,11 @@ func TestPostgresTransaction_GetAll(t *testing.T) {
 		{
 			name: "get all metrics successfully",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-					AddRow("gauge:metric1", 42.5, nil).
-					AddRow("counter:metric2", nil, 100).
-					AddRow("gauge:metric3", 10.0, nil)
-				mock.ExpectQuery(`SELECT key, value_float, value_int FROM metrics`).
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+					AddRow("gauge:metric1", 42.5, nil, nil).
+					AddRow("counter:metric2", nil, 100, nil).
+					AddRow("gauge:metric3", 10.0, nil, nil)
+				mock.ExpectQuery(`SELECT key, value_float, value_int, value_json FROM metrics`).
 					WillReturnRows(rows)
 			},
 			expectedResult: map[string]any{
@@ -267,8 +267,8 @@ func TestPostgresTransaction_GetAll(t *testing.T) {
 		{
 			name: "empty result set",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"})
-				mock.ExpectQuery(`SELECT key, value_float, value_int FROM metrics`).
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"})
+				mock.ExpectQuery(`SELECT key, value_float, value_int, value_json FROM metrics`).
 					WillReturnRows(rows)
 			},
 			expectedResult: map[string]any{},
@@ -277,7 +277,7 @@ func TestPostgresTransaction_GetAll(t *testing.T) {
 		{
 			name: "database error",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT key, value_float, value_int FROM metrics`).
+				mock.ExpectQuery(`SELECT key, value_float, value_int, value_json FROM metrics`).
 					WillReturnError(errors.New("database connection error"))
 			},
 			expectedResult: nil,
@@ -286,11 +286,11 @@ func TestPostgresTransaction_GetAll(t *testing.T) {
 		{
 			name: "metrics with both values null (should be filtered out)",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-					AddRow("gauge:valid_metric", 42.5, nil).
-					AddRow("gauge:invalid_metric", nil, nil).
-					AddRow("counter:valid_counter", nil, 100)
-				mock.ExpectQuery(`SELECT key, value_float, value_int FROM metrics`).
+				rows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+					AddRow("gauge:valid_metric", 42.5, nil, nil).
+					AddRow("gauge:invalid_metric", nil, nil, nil).
+					AddRow("counter:valid_counter", nil, 100, nil)
+				mock.ExpectQuery(`SELECT key, value_float, value_int, value_json FROM metrics`).
 					WillReturnRows(rows)
 			},
 			expectedResult: map[string]any{
@@ -458,19 +458,19 @@ func TestPostgresTransaction_IntegrationScenario(t *testing.T) {
 
 		ctx := context.Background()
 
-		setRows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-			AddRow("gauge:test_metric", 42.5, nil)
-		mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3 RETURNING key, value_float, value_int`).
-			WithArgs("gauge:test_metric", 42.5, nil).
+		setRows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+			AddRow("gauge:test_metric", 42.5, nil, nil)
+		mock.ExpectQuery(`INSERT INTO metrics \(key, value_float, value_int, value_json\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(key\) DO UPDATE SET value_float = \$2, value_int = \$3, value_json = \$4 RETURNING key, value_float, value_int, value_json`).
+			WithArgs("gauge:test_metric", 42.5, nil, nil).
 			WillReturnRows(setRows)
 
 		value, err := pt.Set(ctx, "gauge:test_metric", 42.5)
 		assert.NoError(t, err)
 		assert.Equal(t, 42.5, value)
 
-		getRows := sqlmock.NewRows([]string{"value_float", "value_int"}).
-			AddRow(42.5, nil)
-		mock.ExpectQuery(`SELECT value_float, value_int FROM metrics WHERE key = \$1`).
+		getRows := sqlmock.NewRows([]string{"value_float", "value_int", "value_json"}).
+			AddRow(42.5, nil, nil)
+		mock.ExpectQuery(`SELECT value_float, value_int, value_json FROM metrics WHERE key = \$1`).
 			WithArgs("gauge:test_metric").
 			WillReturnRows(getRows)
 
@@ -478,10 +478,10 @@ func TestPostgresTransaction_IntegrationScenario(t *testing.T) {
 		assert.True(t, found)
 		assert.Equal(t, 42.5, retrievedValue)
 
-		getAllRows := sqlmock.NewRows([]string{"key", "value_float", "value_int"}).
-			AddRow("gauge:test_metric", 42.5, nil).
-			AddRow("counter:test_counter", nil, 100)
-		mock.ExpectQuery(`SELECT key, value_float, value_int FROM metrics`).
+		getAllRows := sqlmock.NewRows([]string{"key", "value_float", "value_int", "value_json"}).
+			AddRow("gauge:test_metric", 42.5, nil, nil).
+			AddRow("counter:test_counter", nil, 100, nil)
+		mock.ExpectQuery(`SELECT key, value_float, value_int, value_json FROM metrics`).
 			WillReturnRows(getAllRows)
 
 		allMetrics, err := pt.GetAll(ctx)
@@ -0,0 +1,51 @@
+// Package subnet хранит доверенную подсеть сервера, которую можно заменить без перезапуска.
+package subnet
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Trusted - доверенная подсеть. Пустая подсеть пропускает любые адреса.
+type Trusted struct {
+	mu     sync.RWMutex
+	subnet *net.IPNet
+}
+
+// New создает доверенную подсеть из записи CIDR. Пустая запись отключает проверку.
+func New(cidr string) (*Trusted, error) {
+	t := &Trusted{}
+	if err := t.Set(cidr); err != nil {
+		return nil, fmt.Errorf("subnet.New: %w", err)
+	}
+
+	return t, nil
+}
+
+// Set заменяет подсеть. При ошибке разбора текущая подсеть не меняется.
+func (t *Trusted) Set(cidr string) error {
+	var subnet *net.IPNet
+	if cidr != "" {
+		var err error
+		_, subnet, err = net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("subnet.Trusted.Set: %w", err)
+		}
+	}
+
+	t.mu.Lock()
+	t.subnet = subnet
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Contains сообщает, входит ли адрес в подсеть.
+// Если подсеть не задана, подходит любой адрес, в том числе нераспознанный.
+func (t *Trusted) Contains(ip net.IP) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.subnet == nil || t.subnet.Contains(ip)
+}
@@ -0,0 +1,27 @@
+package subnet
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrusted(t *testing.T) {
+	trusted, err := New("")
+	require.NoError(t, err)
+	assert.True(t, trusted.Contains(net.ParseIP("10.0.0.1")))
+	assert.True(t, trusted.Contains(nil), "empty subnet accepts unparsed addresses")
+
+	require.NoError(t, trusted.Set("192.168.1.0/24"))
+	assert.True(t, trusted.Contains(net.ParseIP("192.168.1.15")))
+	assert.False(t, trusted.Contains(net.ParseIP("10.0.0.1")))
+	assert.False(t, trusted.Contains(nil))
+
+	assert.Error(t, trusted.Set("not-a-cidr"))
+	assert.True(t, trusted.Contains(net.ParseIP("192.168.1.15")), "failed set must keep previous subnet")
+
+	_, err = New("not-a-cidr")
+	assert.ErrorContains(t, err, "subnet.New")
+}
@@ -0,0 +1,17 @@
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+// WithID возвращает контекст с идентификатором арендатора.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext возвращает идентификатор арендатора из контекста.
+// Пустая строка означает пространство имен по умолчанию.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,129 @@
+// Package tenant реализует пространства имен арендаторов (tenants) сервера метрик.
+// Каждый арендатор идентифицируется своими API ключами, а все его метрики
+// хранятся в отдельном пространстве ключей хранилища.
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+)
+
+const (
+	// APIKeyHeader - заголовок запроса, в котором клиент передает API ключ арендатора.
+	APIKeyHeader = "X-API-Key"
+	// APIKeyCookie - cookie с API ключом арендатора для запросов на чтение из браузера.
+	APIKeyCookie = "api_key"
+)
+
+var (
+	ErrInvalidID    = errors.New("invalid tenant id")
+	ErrDuplicateKey = errors.New("duplicate api key")
+	ErrEmptyKey     = errors.New("empty api key")
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Tenant описывает арендатора и список его API ключей.
+type Tenant struct {
+	ID      string   `json:"id"`
+	APIKeys []string `json:"api_keys"`
+}
+
+type fileConfig struct {
+	Tenants []Tenant `json:"tenants"`
+}
+
+// Registry хранит соответствие API ключей арендаторам.
+type Registry struct {
+	mu   sync.RWMutex
+	path string
+	keys map[string]string
+}
+
+// NewRegistry создает реестр арендаторов из списка.
+func NewRegistry(tenants []Tenant) (*Registry, error) {
+	keys, err := buildKeys(tenants)
+	if err != nil {
+		return nil, fmt.Errorf("tenant.NewRegistry: %w", err)
+	}
+
+	return &Registry{keys: keys}, nil
+}
+
+// LoadRegistry загружает реестр арендаторов из JSON файла.
+func LoadRegistry(path string) (*Registry, error) {
+	keys, err := readKeys(path)
+	if err != nil {
+		return nil, fmt.Errorf("tenant.LoadRegistry: %w", err)
+	}
+
+	return &Registry{path: path, keys: keys}, nil
+}
+
+// Reload перечитывает файл, из которого был загружен реестр.
+// При ошибке текущее состояние реестра не меняется.
+func (r *Registry) Reload() error {
+	if r.path == "" {
+		return nil
+	}
+
+	keys, err := readKeys(r.path)
+	if err != nil {
+		return fmt.Errorf("tenant.Registry.Reload: %w", err)
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Lookup возвращает идентификатор арендатора по API ключу.
+func (r *Registry) Lookup(apiKey string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.keys[apiKey]
+	return id, ok
+}
+
+func readKeys(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file '%s': %w", path, err)
+	}
+
+	var cfg fileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file '%s': %w", path, err)
+	}
+
+	return buildKeys(cfg.Tenants)
+}
+
+func buildKeys(tenants []Tenant) (map[string]string, error) {
+	keys := make(map[string]string)
+
+	for _, t := range tenants {
+		if !idPattern.MatchString(t.ID) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidID, t.ID)
+		}
+
+		for _, key := range t.APIKeys {
+			if key == "" {
+				return nil, fmt.Errorf("%w for tenant '%s'", ErrEmptyKey, t.ID)
+			}
+			if owner, exists := keys[key]; exists {
+				return nil, fmt.Errorf("%w for tenants '%s' and '%s'", ErrDuplicateKey, owner, t.ID)
+			}
+			keys[key] = t.ID
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,121 @@
+package tenant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		tenants     []Tenant
+		expectedErr error
+	}{
+		{
+			name: "valid tenants",
+			tenants: []Tenant{
+				{ID: "team-a", APIKeys: []string{"key-a1", "key-a2"}},
+				{ID: "team_b", APIKeys: []string{"key-b"}},
+			},
+		},
+		{
+			name:        "invalid tenant id",
+			tenants:     []Tenant{{ID: "team:a", APIKeys: []string{"key"}}},
+			expectedErr: ErrInvalidID,
+		},
+		{
+			name:        "empty tenant id",
+			tenants:     []Tenant{{ID: "", APIKeys: []string{"key"}}},
+			expectedErr: ErrInvalidID,
+		},
+		{
+			name:        "empty api key",
+			tenants:     []Tenant{{ID: "team-a", APIKeys: []string{""}}},
+			expectedErr: ErrEmptyKey,
+		},
+		{
+			name: "duplicate api key",
+			tenants: []Tenant{
+				{ID: "team-a", APIKeys: []string{"key"}},
+				{ID: "team-b", APIKeys: []string{"key"}},
+			},
+			expectedErr: ErrDuplicateKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := NewRegistry(tt.tenants)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, registry)
+				return
+			}
+
+			require.NoError(t, err)
+			for _, tenant := range tt.tenants {
+				for _, key := range tenant.APIKeys {
+					id, ok := registry.Lookup(key)
+					assert.True(t, ok)
+					assert.Equal(t, tenant.ID, id)
+				}
+			}
+
+			_, ok := registry.Lookup("unknown")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"tenants":[{"id":"team-a","api_keys":["key-a"]}]}`), 0o600))
+
+	registry, err := LoadRegistry(path)
+	require.NoError(t, err)
+
+	id, ok := registry.Lookup("key-a")
+	assert.True(t, ok)
+	assert.Equal(t, "team-a", id)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"tenants":[{"id":"team-b","api_keys":["key-b"]}]}`), 0o600))
+	require.NoError(t, registry.Reload())
+
+	_, ok = registry.Lookup("key-a")
+	assert.False(t, ok)
+	id, ok = registry.Lookup("key-b")
+	assert.True(t, ok)
+	assert.Equal(t, "team-b", id)
+
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+	assert.Error(t, registry.Reload())
+
+	id, ok = registry.Lookup("key-b")
+	assert.True(t, ok, "failed reload must keep previous state")
+	assert.Equal(t, "team-b", id)
+}
+
+func TestLoadRegistry_Errors(t *testing.T) {
+	_, err := LoadRegistry("/path/that/does/not/exist.json")
+	assert.ErrorContains(t, err, "failed to read tenants file")
+
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+
+	_, err = LoadRegistry(path)
+	assert.ErrorContains(t, err, "failed to parse tenants file")
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", FromContext(ctx))
+
+	ctx = WithID(ctx, "team-a")
+	assert.Equal(t, "team-a", FromContext(ctx))
+}
@@ -77,6 +77,7 @@ func (pc *PostgresContainer) CreateMetricsTable(ctx context.Context) error {
 			key         VARCHAR(255) NOT NULL,
 			value_float DOUBLE PRECISION NULL,
 			value_int   BIGINT NULL,
+			value_json  JSONB NULL,
 			UNIQUE(key)
 		);
 	`)
@@ -0,0 +1,154 @@
+// Package tracing настраивает трассировку OpenTelemetry для агента и сервера.
+// Контекст трассировки передается между ними в заголовке W3C traceparent.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// Экспортеры трассировки.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Config задает параметры трассировки.
+type Config struct {
+	ServiceName string
+	// Exporter - один из ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile.
+	// Пустое значение равносильно ExporterNone.
+	Exporter string
+	// Endpoint - адрес OTLP/HTTP коллектора: host:port без TLS или URL.
+	Endpoint string
+	// FilePath - файл, в который ExporterFile дописывает spans в формате JSON.
+	FilePath string
+	// SampleRatio - доля записываемых трасс, начатых этим процессом.
+	// Решение вызывающей стороны из traceparent соблюдается всегда.
+	SampleRatio float64
+}
+
+// ShutdownFunc отправляет накопленные spans и останавливает экспортер.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup устанавливает глобальные TracerProvider и пропагатор W3C Trace Context.
+// Пропагатор устанавливается и без экспортера, чтобы сервер передавал traceparent дальше.
+func Setup(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	exporter, closer, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("tracing.Setup: %w", err)
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		if err != nil {
+			return fmt.Errorf("tracing.Shutdown: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return nil, nil, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithInsecure()}
+		if strings.Contains(cfg.Endpoint, "://") {
+			opts = []otlptracehttp.Option{otlptracehttp.WithEndpointURL(cfg.Endpoint)}
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterFile:
+		if cfg.FilePath == "" {
+			return nil, nil, fmt.Errorf("trace file path is required for '%s' exporter", ExporterFile)
+		}
+		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trace file '%s': %w", cfg.FilePath, err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		return exporter, file, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter '%s'", cfg.Exporter)
+	}
+}
+
+// Tracer возвращает трассировщик пакета. Трассировщик берет глобальный TracerProvider
+// при каждом вызове Start, поэтому его можно сохранить в переменной пакета до вызова Setup.
+func Tracer(pkg string) trace.Tracer {
+	return packageTracer{name: "github.com/NoobyTheTurtle/metrics/internal/" + pkg}
+}
+
+type packageTracer struct {
+	embedded.Tracer
+	name string
+}
+
+func (t packageTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(t.name).Start(ctx, spanName, opts...)
+}
+
+// End завершает span и отмечает в нем ошибку, если она есть.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject записывает контекст трассировки в заголовки исходящего запроса.
+func Inject(ctx context.Context, header map[string][]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract возвращает контекст с трассировкой из заголовков входящего запроса.
+func Extract(ctx context.Context, header map[string][]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		expectedErr string
+	}{
+		{
+			name: "no exporter",
+			cfg:  Config{Exporter: ExporterNone},
+		},
+		{
+			name: "empty exporter",
+			cfg:  Config{},
+		},
+		{
+			name: "otlp exporter",
+			cfg:  Config{Exporter: ExporterOTLP, Endpoint: "localhost:4318", SampleRatio: 1},
+		},
+		{
+			name: "otlp exporter with url",
+			cfg:  Config{Exporter: ExporterOTLP, Endpoint: "https://collector.example.com/v1/traces", SampleRatio: 1},
+		},
+		{
+			name:        "file exporter without path",
+			cfg:         Config{Exporter: ExporterFile},
+			expectedErr: "trace file path is required",
+		},
+		{
+			name:        "file exporter with unwritable path",
+			cfg:         Config{Exporter: ExporterFile, FilePath: filepath.Join(t.TempDir(), "missing", "traces.json")},
+			expectedErr: "failed to open trace file",
+		},
+		{
+			name:        "unknown exporter",
+			cfg:         Config{Exporter: "jaeger"},
+			expectedErr: "unknown trace exporter 'jaeger'",
+		},
+	}
+
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), tt.cfg)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), Config{
+		ServiceName: "metrics-test",
+		Exporter:    ExporterFile,
+		FilePath:    path,
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	_, span := Tracer("tracing").Start(context.Background(), "test.operation")
+	End(span, errors.New("operation failed"))
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"test.operation"`)
+	assert.Contains(t, string(data), "operation failed")
+	assert.Contains(t, string(data), "metrics-test")
+}
+
+func TestInjectExtract(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	header := http.Header{}
+	Inject(ctx, header)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get("traceparent"))
+
+	extracted := trace.SpanContextFromContext(Extract(context.Background(), header))
+	assert.Equal(t, traceID, extracted.TraceID())
+	assert.True(t, extracted.IsRemote())
+}
@@ -6,12 +6,13 @@ import (
 )
 
 type BuildInfo struct {
-	Version string
-	Date    string
-	Commit  string
+	Version string `json:"version"`
+	Date    string `json:"date"`
+	Commit  string `json:"commit"`
 }
 
-func PrintBuildInfo(version, date, commit string) {
+// NewBuildInfo возвращает сведения о сборке, заменяя незаданные значения на N/A.
+func NewBuildInfo(version, date, commit string) BuildInfo {
 	info := BuildInfo{
 		Version: version,
 		Date:    date,
@@ -28,6 +29,12 @@ func PrintBuildInfo(version, date, commit string) {
 		info.Commit = "N/A"
 	}
 
+	return info
+}
+
+func PrintBuildInfo(version, date, commit string) {
+	info := NewBuildInfo(version, date, commit)
+
 	fmt.Fprintf(os.Stdout, "Build version: %s\n", info.Version)
 	fmt.Fprintf(os.Stdout, "Build date: %s\n", info.Date)
 	fmt.Fprintf(os.Stdout, "Build commit: %s\n", info.Commit)
@@ -78,3 +78,7 @@ func TestBuildInfo_Structure(t *testing.T) {
 	assert.Equal(t, "2024-01-01", info.Date)
 	assert.Equal(t, "abc123", info.Commit)
 }
+
+func TestNewBuildInfo(t *testing.T) {
+	assert.Equal(t, BuildInfo{Version: "1.0.0", Date: "N/A", Commit: "N/A"}, NewBuildInfo("1.0.0", "", ""))
+}
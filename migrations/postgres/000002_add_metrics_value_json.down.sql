@@ -0,0 +1 @@
+ALTER TABLE metrics DROP COLUMN IF EXISTS value_json;
\ No newline at end of file
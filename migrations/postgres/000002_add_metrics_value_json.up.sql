@@ -0,0 +1 @@
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS value_json JSONB NULL;
\ No newline at end of file